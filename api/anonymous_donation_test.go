@@ -2,8 +2,10 @@ package api
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,6 +14,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kholodihor/charity/challenge"
 	mockdb "github.com/kholodihor/charity/db/mock"
 	db "github.com/kholodihor/charity/db/sqlc"
 	"github.com/kholodihor/charity/util"
@@ -152,3 +155,117 @@ func TestCreateAnonymousDonationAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestAnonymousDonationChallengeAPI(t *testing.T) {
+	goal := randomGoal()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	config := util.Config{
+		TokenSymmetricKey:    util.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		MaxAnonymousDonation: 1000000, // $10,000
+		RateLimitPerMinute:   100,
+		ChallengeProvider:    util.ChallengeProviderProofOfWork,
+		ChallengeSecret:      util.RandomString(32),
+		ChallengeThreshold:   10000, // $100
+		ChallengeDifficulty:  4,
+		ChallengeDuration:    time.Minute,
+	}
+	server, err := NewServer(config, store)
+	require.NoError(t, err)
+
+	goalID := goal.ID
+	donate := func(amount int64, challengeResponse string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(gin.H{
+			"goal_id":            goalID,
+			"amount":             amount,
+			"challenge_response": challengeResponse,
+		})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/donations/anonymous", bytes.NewReader(body))
+		require.NoError(t, err)
+
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	newChallenge := func() challenge.Challenge {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/donations/anonymous/challenge", nil)
+		require.NoError(t, err)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var rsp challenge.Challenge
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Equal(t, 4, rsp.Difficulty)
+		return rsp
+	}
+
+	solve := func(c challenge.Challenge) string {
+		for counter := 0; ; counter++ {
+			response := fmt.Sprintf("%s:%d", c.Challenge, counter)
+			if challenge.LeadingZeroBits(sha256.Sum256([]byte(response))) >= c.Difficulty {
+				return response
+			}
+		}
+	}
+
+	store.EXPECT().
+		GetGoal(gomock.Any(), gomock.Eq(goal.ID)).
+		Times(5).
+		Return(goal, nil)
+	store.EXPECT().
+		GetGoal(gomock.Any(), gomock.Eq(goal.ID+1)).
+		Times(1).
+		Return(db.Goal{}, sql.ErrNoRows)
+	store.EXPECT().
+		DonateToGoalTx(gomock.Any(), gomock.Any()).
+		Times(2).
+		Return(db.DonateToGoalTxResult{}, nil)
+
+	// Donations up to the threshold need no challenge
+	recorder := donate(10000, "")
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	// Larger donations are rejected without a valid solution
+	recorder = donate(50000, "")
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+
+	tampered := newChallenge()
+	tampered.Challenge = "x" + tampered.Challenge[1:] // nonces are hex, so this always changes it
+	recorder = donate(50000, solve(tampered))
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+
+	// A solved challenge is accepted once
+	response := solve(newChallenge())
+
+	// Requests failing validation don't use up the challenge
+	goalID = goal.ID + 1
+	recorder = donate(50000, response)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	goalID = goal.ID
+	recorder = donate(50000, response)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	recorder = donate(50000, response)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestDonationChallengeNotIssued(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/donations/anonymous/challenge", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/ical"
+	"github.com/kholodihor/charity/token"
+)
+
+const (
+	// calendarFeedHistory is how long feeds keep listing events after they took place
+	calendarFeedHistory = 30 * 24 * time.Hour
+	// calendarFeedMaxEvents caps the events and cancellations listed in a feed
+	calendarFeedMaxEvents = 1000
+	// cancelledEventDuration is the length given to cancelled events, which don't keep their end time
+	cancelledEventDuration = 2 * time.Hour
+	calendarProdID        = "-//Charity//Events//EN"
+	// calendarFileSuffix ends calendar URLs, so apps recognize them as calendars
+	calendarFileSuffix = ".ics"
+)
+
+type calendarFeedResponse struct {
+	// URL is secret and only returned when the feed is created
+	URL string `json:"url"`
+}
+
+// calendarUIDDomain returns the host event UIDs are qualified with, which must never change
+// or calendar apps would duplicate every event
+func (server *Server) calendarUIDDomain() string {
+	publicURL, err := url.Parse(server.config.PublicURL)
+	if err != nil || publicURL.Hostname() == "" {
+		return "localhost"
+	}
+	return publicURL.Hostname()
+}
+
+func (server *Server) newCalendarEvent(eventID int64, sequence int32, name, place string, start, end time.Time, status string) ical.Event {
+	return ical.Event{
+		UID:      fmt.Sprintf("event-%d@%s", eventID, server.calendarUIDDomain()),
+		Sequence: sequence,
+		Start:    start,
+		End:      end,
+		Summary:  name,
+		Location: place,
+		Status:   status,
+	}
+}
+
+// calendarStatus returns the iCalendar status of an event: postponed events are tentative
+// until they are given a new date
+func calendarStatus(status string) string {
+	switch status {
+	case db.EventStatusCancelled:
+		return ical.StatusCancelled
+	case db.EventStatusPostponed:
+		return ical.StatusTentative
+	}
+	return ical.StatusConfirmed
+}
+
+func (server *Server) newEventCalendarEvent(event db.Event) ical.Event {
+	return server.newCalendarEvent(event.ID, event.Sequence, event.Name, event.Place, event.Date, event.EndsAt, calendarStatus(event.Status))
+}
+
+func (server *Server) newCancelledCalendarEvent(cancellation db.EventCancellation) ical.Event {
+	return server.newCalendarEvent(
+		cancellation.EventID,
+		cancellation.Sequence,
+		cancellation.Name,
+		cancellation.Place,
+		cancellation.Date,
+		cancellation.Date.Add(cancelledEventDuration),
+		ical.StatusCancelled,
+	)
+}
+
+func writeCalendar(ctx *gin.Context, calendar ical.Calendar) {
+	var buf bytes.Buffer
+	err := calendar.Encode(&buf, time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Data(http.StatusOK, ical.ContentType, buf.Bytes())
+}
+
+// GET /events/:id.ics
+func (server *Server) getEventCalendar(ctx *gin.Context, idStr string) {
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	calendar := ical.Calendar{ProdID: calendarProdID}
+
+	event, err := server.store.GetEvent(ctx, id)
+	if err == nil {
+		calendar.Name = event.Name
+		calendar.Events = []ical.Event{server.newEventCalendarEvent(event)}
+		writeCalendar(ctx, calendar)
+		return
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// A deleted event is served as cancelled, so subscribed calendars remove it
+	cancellation, err := server.store.GetEventCancellation(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	calendar.Name = cancellation.Name
+	calendar.Events = []ical.Event{server.newCancelledCalendarEvent(cancellation)}
+	writeCalendar(ctx, calendar)
+}
+
+// GET /events.ics
+func (server *Server) getEventsCalendar(ctx *gin.Context) {
+	since := time.Now().Add(-calendarFeedHistory)
+
+	events, err := server.store.ListCalendarEvents(ctx, db.ListCalendarEventsParams{
+		Since:    since,
+		RowLimit: calendarFeedMaxEvents,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	cancellations, err := server.store.ListEventCancellations(ctx, db.ListEventCancellationsParams{
+		Since:    since,
+		RowLimit: calendarFeedMaxEvents,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	calendar := ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   server.config.OrganizationName,
+	}
+	for _, event := range events {
+		calendar.Events = append(calendar.Events, server.newEventCalendarEvent(event))
+	}
+	for _, cancellation := range cancellations {
+		calendar.Events = append(calendar.Events, server.newCancelledCalendarEvent(cancellation))
+	}
+
+	writeCalendar(ctx, calendar)
+}
+
+// generateCalendarToken returns a new random feed token and the hash it is stored as
+func generateCalendarToken() (string, string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", "", err
+	}
+
+	calendarToken := base64.RawURLEncoding.EncodeToString(key)
+	return calendarToken, hashCalendarToken(calendarToken), nil
+}
+
+func hashCalendarToken(calendarToken string) string {
+	hash := sha256.Sum256([]byte(calendarToken))
+	return hex.EncodeToString(hash[:])
+}
+
+// POST /users/me/calendar
+func (server *Server) createCalendarFeed(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	calendarToken, tokenHash, err := generateCalendarToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	_, err = server.store.UpsertCalendarToken(ctx, db.UpsertCalendarTokenParams{
+		UserID:    authPayload.UserID,
+		TokenHash: tokenHash,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	feedURL := strings.TrimSuffix(server.config.PublicURL, "/") + "/calendar/" + calendarToken + calendarFileSuffix
+	ctx.JSON(http.StatusCreated, calendarFeedResponse{URL: feedURL})
+}
+
+// DELETE /users/me/calendar
+func (server *Server) deleteCalendarFeed(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	err := server.store.DeleteCalendarToken(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// GET /calendar/:token
+func (server *Server) getBookingsCalendar(ctx *gin.Context) {
+	calendarToken := strings.TrimSuffix(ctx.Param("token"), calendarFileSuffix)
+
+	feed, err := server.store.GetCalendarTokenByHash(ctx, hashCalendarToken(calendarToken))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "calendar not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	since := time.Now().Add(-calendarFeedHistory)
+
+	bookings, err := server.store.ListUserBookings(ctx, db.ListUserBookingsParams{
+		UserID: feed.UserID,
+		Limit:  calendarFeedMaxEvents,
+		Offset: 0,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	cancellations, err := server.store.ListUserEventCancellations(ctx, db.ListUserEventCancellationsParams{
+		UserID:   feed.UserID,
+		Since:    since,
+		RowLimit: calendarFeedMaxEvents,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	calendar := ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   "My bookings",
+	}
+	for _, booking := range bookings {
+		if booking.EventDate.Before(since) {
+			continue
+		}
+		calendar.Events = append(calendar.Events, server.newCalendarEvent(
+			booking.EventID,
+			booking.EventSequence,
+			booking.EventName,
+			booking.EventPlace,
+			booking.EventDate,
+			booking.EventEndsAt,
+			calendarStatus(booking.EventStatus),
+		))
+	}
+	for _, cancellation := range cancellations {
+		calendar.Events = append(calendar.Events, server.newCancelledCalendarEvent(cancellation))
+	}
+
+	// The URL is the only credential, so keep it out of shared caches
+	ctx.Header("Cache-Control", "private, no-store")
+	writeCalendar(ctx, calendar)
+}
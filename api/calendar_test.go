@@ -0,0 +1,383 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/ical"
+	"github.com/kholodihor/charity/token"
+	"github.com/stretchr/testify/require"
+)
+
+func randomEventCancellation(event db.Event, userID pgtype.Int8) db.EventCancellation {
+	return db.EventCancellation{
+		ID:          event.ID + 1,
+		EventID:     event.ID,
+		UserID:      userID,
+		Name:        event.Name,
+		Place:       event.Place,
+		Date:        event.Date,
+		Sequence:    event.Sequence + 1,
+		CancelledAt: event.CreatedAt,
+	}
+}
+
+// requireCalendar checks that recorder holds a calendar with exactly the given UIDs and statuses
+func requireCalendar(t *testing.T, recorder *httptest.ResponseRecorder, statuses map[string]string) {
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, ical.ContentType, recorder.Header().Get("Content-Type"))
+
+	body := strings.ReplaceAll(recorder.Body.String(), "\r\n ", "")
+	require.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n"))
+	require.Equal(t, len(statuses), strings.Count(body, "BEGIN:VEVENT"))
+
+	for _, event := range strings.Split(body, "BEGIN:VEVENT")[1:] {
+		var uid, status string
+		for _, line := range strings.Split(event, "\r\n") {
+			if value, ok := strings.CutPrefix(line, "UID:"); ok {
+				uid = value
+			}
+			if value, ok := strings.CutPrefix(line, "STATUS:"); ok {
+				status = value
+			}
+		}
+		require.Contains(t, statuses, uid)
+		require.Equal(t, statuses[uid], status)
+	}
+}
+
+func eventUID(eventID int64) string {
+	return fmt.Sprintf("event-%d@charity.example", eventID)
+}
+
+func newCalendarTestServer(t *testing.T, store db.Store) *Server {
+	server := newTestServer(t, store)
+	server.config.PublicURL = "https://charity.example/api"
+	return server
+}
+
+func TestGetEventCalendarAPI(t *testing.T) {
+	event := randomEvent()
+	event.Sequence = 2
+
+	testCases := []struct {
+		name          string
+		url           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			url:  fmt.Sprintf("/events/%d.ics", event.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(event.ID)).
+					Times(1).
+					Return(event, nil)
+				store.EXPECT().
+					GetEventCancellation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				requireCalendar(t, recorder, map[string]string{eventUID(event.ID): ical.StatusConfirmed})
+				require.Contains(t, recorder.Body.String(), "SEQUENCE:2\r\n")
+				require.Contains(t, recorder.Body.String(), "DTSTART:20230102T120000Z\r\n")
+			},
+		},
+		{
+			name: "Deleted",
+			url:  fmt.Sprintf("/events/%d.ics", event.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(event.ID)).
+					Times(1).
+					Return(db.Event{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetEventCancellation(gomock.Any(), gomock.Eq(event.ID)).
+					Times(1).
+					Return(randomEventCancellation(event, pgtype.Int8{}), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				requireCalendar(t, recorder, map[string]string{eventUID(event.ID): ical.StatusCancelled})
+				require.Contains(t, recorder.Body.String(), "SEQUENCE:3\r\n")
+			},
+		},
+		{
+			name: "NotFound",
+			url:  fmt.Sprintf("/events/%d.ics", event.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(event.ID)).
+					Times(1).
+					Return(db.Event{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetEventCancellation(gomock.Any(), gomock.Eq(event.ID)).
+					Times(1).
+					Return(db.EventCancellation{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidID",
+			url:  "/events/abc.ics",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newCalendarTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetEventsCalendarAPI(t *testing.T) {
+	upcoming := randomEvent()
+	deleted := randomEvent()
+	deleted.ID = upcoming.ID + 1
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListCalendarEvents(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ any, arg db.ListCalendarEventsParams) ([]db.Event, error) {
+			require.WithinDuration(t, time.Now().Add(-calendarFeedHistory), arg.Since, time.Minute)
+			require.Equal(t, int32(calendarFeedMaxEvents), arg.RowLimit)
+			return []db.Event{upcoming}, nil
+		})
+	store.EXPECT().
+		ListEventCancellations(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.EventCancellation{randomEventCancellation(deleted, pgtype.Int8{})}, nil)
+
+	server := newCalendarTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/events.ics", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	requireCalendar(t, recorder, map[string]string{
+		eventUID(upcoming.ID): ical.StatusConfirmed,
+		eventUID(deleted.ID):  ical.StatusCancelled,
+	})
+}
+
+func TestCalendarFeedAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	booked := randomEvent()
+	booked.Date = time.Now().Add(24 * time.Hour).UTC()
+	cancelled := randomEvent()
+	cancelled.ID = booked.ID + 1
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	var tokenHash string
+	store.EXPECT().
+		UpsertCalendarToken(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ any, arg db.UpsertCalendarTokenParams) (db.CalendarToken, error) {
+			require.Equal(t, user.ID, arg.UserID)
+			tokenHash = arg.TokenHash
+			return db.CalendarToken{UserID: arg.UserID, TokenHash: arg.TokenHash}, nil
+		})
+
+	server := newCalendarTestServer(t, store)
+
+	// Create the feed
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/me/calendar", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	var feed calendarFeedResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &feed)
+	require.NoError(t, err)
+
+	calendarToken, ok := strings.CutPrefix(feed.URL, "https://charity.example/api/calendar/")
+	require.True(t, ok, feed.URL)
+	calendarToken, ok = strings.CutSuffix(calendarToken, ".ics")
+	require.True(t, ok, feed.URL)
+	// Only the hash of the token is stored
+	require.NotEqual(t, calendarToken, tokenHash)
+	require.Equal(t, hashCalendarToken(calendarToken), tokenHash)
+
+	// Read it with the secret URL alone
+	store.EXPECT().
+		GetCalendarTokenByHash(gomock.Any(), gomock.Eq(tokenHash)).
+		Times(1).
+		Return(db.CalendarToken{UserID: user.ID, TokenHash: tokenHash}, nil)
+	store.EXPECT().
+		ListUserBookings(gomock.Any(), gomock.Eq(db.ListUserBookingsParams{
+			UserID: user.ID,
+			Limit:  calendarFeedMaxEvents,
+			Offset: 0,
+		})).
+		Times(1).
+		Return([]db.ListUserBookingsRow{
+			{
+				ID:            1,
+				UserID:        pgtype.Int8{Int64: user.ID, Valid: true},
+				EventID:       booked.ID,
+				EventName:     booked.Name,
+				EventPlace:    booked.Place,
+				EventDate:     booked.Date,
+				EventSequence: booked.Sequence,
+			},
+			// Events that took place too long ago are left out
+			{
+				ID:         2,
+				UserID:     pgtype.Int8{Int64: user.ID, Valid: true},
+				EventID:    booked.ID + 2,
+				EventName:  "Past",
+				EventPlace: "Kyiv",
+				EventDate:  time.Now().Add(-2 * calendarFeedHistory),
+			},
+		}, nil)
+	store.EXPECT().
+		ListUserEventCancellations(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ any, arg db.ListUserEventCancellationsParams) ([]db.EventCancellation, error) {
+			require.Equal(t, user.ID, arg.UserID)
+			return []db.EventCancellation{
+				randomEventCancellation(cancelled, pgtype.Int8{Int64: user.ID, Valid: true}),
+			}, nil
+		})
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/calendar/"+calendarToken+".ics", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+
+	requireCalendar(t, recorder, map[string]string{
+		eventUID(booked.ID):    ical.StatusConfirmed,
+		eventUID(cancelled.ID): ical.StatusCancelled,
+	})
+	require.Equal(t, "private, no-store", recorder.Header().Get("Cache-Control"))
+}
+
+func TestGetBookingsCalendarNotFoundAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetCalendarTokenByHash(gomock.Any(), gomock.Eq(hashCalendarToken("unknown"))).
+		Times(1).
+		Return(db.CalendarToken{}, db.ErrRecordNotFound)
+	store.EXPECT().
+		ListUserBookings(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	server := newCalendarTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/calendar/unknown.ics", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestDeleteCalendarFeedAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteCalendarToken(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteCalendarToken(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newCalendarTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, "/users/me/calendar", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
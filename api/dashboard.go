@@ -0,0 +1,146 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/kholodihor/charity/db/sqlc"
+)
+
+// dashboardDefaultDays is the length of the dashboard period when no range is given
+const dashboardDefaultDays = 30
+
+// dashboardMaxDays caps the length of the dashboard period
+const dashboardMaxDays = 366 * 5
+
+type dashboardRequest struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// dashboardMetric compares a value with the one of the previous period
+type dashboardMetric struct {
+	Current       int64    `json:"current"`
+	Previous      int64    `json:"previous"`
+	ChangePercent *float64 `json:"change_percent"`
+}
+
+type dashboardResponse struct {
+	From            string          `json:"from"`
+	To              string          `json:"to"`
+	PreviousFrom    string          `json:"previous_from"`
+	PreviousTo      string          `json:"previous_to"`
+	ActiveGoals     int64           `json:"active_goals"`
+	TotalRaised     dashboardMetric `json:"total_raised"`
+	DonationCount   dashboardMetric `json:"donation_count"`
+	AverageDonation dashboardMetric `json:"average_donation"`
+	NewUsers        dashboardMetric `json:"new_users"`
+	EventsHeld      dashboardMetric `json:"events_held"`
+	EventAttendees  dashboardMetric `json:"event_attendees"`
+	TotalRefunded   dashboardMetric `json:"total_refunded"`
+	RefundCount     dashboardMetric `json:"refund_count"`
+}
+
+// GET /admin/dashboard
+func (server *Server) getDashboard(ctx *gin.Context) {
+	var req dashboardRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Both ends of the range are inclusive UTC days
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if req.To != "" {
+		var err error
+		to, err = time.Parse(exportDateFormat, req.To)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date in YYYY-MM-DD format"})
+			return
+		}
+	}
+
+	from := to.AddDate(0, 0, 1-dashboardDefaultDays)
+	if req.From != "" {
+		var err error
+		from, err = time.Parse(exportDateFormat, req.From)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date in YYYY-MM-DD format"})
+			return
+		}
+	}
+
+	if to.Before(from) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+
+	end := to.AddDate(0, 0, 1)
+	days := int(end.Sub(from).Hours() / 24)
+	if days > dashboardMaxDays {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "the date range is too long", "max_days": dashboardMaxDays})
+		return
+	}
+	previousFrom := from.AddDate(0, 0, -days)
+
+	current, err := server.store.GetDashboardTotals(ctx, db.GetDashboardTotalsParams{
+		FromDay: pgtype.Date{Time: from, Valid: true},
+		ToDay:   pgtype.Date{Time: end, Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	previous, err := server.store.GetDashboardTotals(ctx, db.GetDashboardTotalsParams{
+		FromDay: pgtype.Date{Time: previousFrom, Valid: true},
+		ToDay:   pgtype.Date{Time: from, Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	activeGoals, err := server.store.CountActiveGoals(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dashboardResponse{
+		From:            from.Format(exportDateFormat),
+		To:              to.Format(exportDateFormat),
+		PreviousFrom:    previousFrom.Format(exportDateFormat),
+		PreviousTo:      from.AddDate(0, 0, -1).Format(exportDateFormat),
+		ActiveGoals:     activeGoals,
+		TotalRaised:     newDashboardMetric(current.DonationAmount, previous.DonationAmount),
+		DonationCount:   newDashboardMetric(current.DonationCount, previous.DonationCount),
+		AverageDonation: newDashboardMetric(averageDonation(current), averageDonation(previous)),
+		NewUsers:        newDashboardMetric(current.NewUsers, previous.NewUsers),
+		EventsHeld:      newDashboardMetric(current.EventsHeld, previous.EventsHeld),
+		EventAttendees:  newDashboardMetric(current.Attendees, previous.Attendees),
+		TotalRefunded:   newDashboardMetric(current.RefundAmount, previous.RefundAmount),
+		RefundCount:     newDashboardMetric(current.RefundCount, previous.RefundCount),
+	})
+}
+
+// newDashboardMetric leaves the change empty when there is nothing to compare with
+func newDashboardMetric(current, previous int64) dashboardMetric {
+	metric := dashboardMetric{Current: current, Previous: previous}
+	if previous != 0 {
+		change := math.Round(float64(current-previous)*10000/float64(previous)) / 100
+		metric.ChangePercent = &change
+	}
+	return metric
+}
+
+func averageDonation(totals db.GetDashboardTotalsRow) int64 {
+	if totals.DonationCount == 0 {
+		return 0
+	}
+	return int64(math.Round(float64(totals.DonationAmount) / float64(totals.DonationCount)))
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func dashboardDays(from, to string) db.GetDashboardTotalsParams {
+	fromDay, _ := time.Parse(exportDateFormat, from)
+	toDay, _ := time.Parse(exportDateFormat, to)
+	return db.GetDashboardTotalsParams{
+		FromDay: pgtype.Date{Time: fromDay, Valid: true},
+		ToDay:   pgtype.Date{Time: toDay, Valid: true},
+	}
+}
+
+func TestGetDashboardAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?from=2024-03-01&to=2024-03-10",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDashboardTotals(gomock.Any(), gomock.Eq(dashboardDays("2024-03-01", "2024-03-11"))).
+					Times(1).
+					Return(db.GetDashboardTotalsRow{DonationCount: 4, DonationAmount: 30000, NewUsers: 5, EventsHeld: 1, Attendees: 12, RefundCount: 1, RefundAmount: 2500}, nil)
+				store.EXPECT().
+					GetDashboardTotals(gomock.Any(), gomock.Eq(dashboardDays("2024-02-20", "2024-03-01"))).
+					Times(1).
+					Return(db.GetDashboardTotalsRow{DonationCount: 2, DonationAmount: 20000, NewUsers: 0}, nil)
+				store.EXPECT().CountActiveGoals(gomock.Any()).Times(1).Return(int64(3), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response dashboardResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+
+				require.Equal(t, "2024-02-20", response.PreviousFrom)
+				require.Equal(t, "2024-02-29", response.PreviousTo)
+				require.Equal(t, int64(3), response.ActiveGoals)
+
+				require.Equal(t, int64(30000), response.TotalRaised.Current)
+				require.Equal(t, 50.0, *response.TotalRaised.ChangePercent)
+				require.Equal(t, int64(7500), response.AverageDonation.Current)
+				require.Equal(t, -25.0, *response.AverageDonation.ChangePercent)
+
+				// No change is reported against an empty previous period
+				require.Nil(t, response.NewUsers.ChangePercent)
+				require.Equal(t, int64(12), response.EventAttendees.Current)
+				require.Equal(t, int64(2500), response.TotalRefunded.Current)
+				require.Equal(t, int64(1), response.RefundCount.Current)
+			},
+		},
+		{
+			name:  "InvalidRange",
+			query: "?from=2024-03-10&to=2024-03-01",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDashboardTotals(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidDate",
+			query: "?from=yesterday",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDashboardTotals(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDashboardTotals(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetDashboardTotalsRow{}, pgx.ErrTxClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/dashboard"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -2,11 +2,13 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kholodihor/charity/challenge"
 	db "github.com/kholodihor/charity/db/sqlc"
 	"github.com/kholodihor/charity/token"
 )
@@ -94,8 +96,9 @@ func (server *Server) createDonation(ctx *gin.Context) {
 }
 
 type createAnonymousDonationRequest struct {
-	GoalID int64 `json:"goal_id" binding:"required"`
-	Amount int64 `json:"amount" binding:"required,min=1"`
+	GoalID            int64  `json:"goal_id" binding:"required"`
+	Amount            int64  `json:"amount" binding:"required,min=1"`
+	ChallengeResponse string `json:"challenge_response"`
 }
 
 // POST /donations/anonymous
@@ -126,6 +129,23 @@ func (server *Server) createAnonymousDonation(ctx *gin.Context) {
 		return
 	}
 
+	// Require a solved challenge for anonymous donations above the threshold
+	if server.verifier != nil && req.Amount > server.config.ChallengeThreshold {
+		// Verified only once the request is otherwise valid, since this uses up the challenge
+		err = server.verifier.Verify(ctx, req.ChallengeResponse, ctx.ClientIP())
+		if err != nil {
+			if isChallengeError(err) {
+				ctx.JSON(http.StatusForbidden, gin.H{
+					"error":               err.Error(),
+					"challenge_threshold": server.config.ChallengeThreshold,
+				})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
 	// Create anonymous donation using transaction
 	arg := db.DonateToGoalTxParams{
 		GoalID: req.GoalID,
@@ -145,6 +165,30 @@ func (server *Server) createAnonymousDonation(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, newDonationResponse(result.Donation))
 }
 
+// GET /donations/anonymous/challenge
+func (server *Server) getDonationChallenge(ctx *gin.Context) {
+	issuer, ok := server.verifier.(challenge.Issuer)
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "challenge is not issued by this server"})
+		return
+	}
+
+	newChallenge, err := issuer.NewChallenge()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newChallenge)
+}
+
+func isChallengeError(err error) bool {
+	return errors.Is(err, challenge.ErrMissingResponse) ||
+		errors.Is(err, challenge.ErrInvalidResponse) ||
+		errors.Is(err, challenge.ErrExpiredResponse) ||
+		errors.Is(err, challenge.ErrUsedResponse)
+}
+
 // GET /donations/:id
 func (server *Server) getDonation(ctx *gin.Context) {
 	idStr := ctx.Param("id")
@@ -0,0 +1,398 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/pubsub"
+)
+
+const (
+	// donationFeedReplay is how many recent donations a new connection gets by default
+	donationFeedReplay = 20
+	// donationFeedMaxReplay is the most recent donations a connection can ask for
+	donationFeedMaxReplay = 100
+	// donationFeedSentWindow is how many of the donations published last are remembered,
+	// so catching up after missed notifications doesn't publish them twice
+	donationFeedSentWindow = 2 * donationFeedMaxReplay
+
+	// donationFeedBuffer is how many donations can queue up for a connection before it is dropped as too slow
+	donationFeedBuffer = 256
+
+	// donationFeedWriteWait is how long a single write to a connection may take
+	donationFeedWriteWait = 10 * time.Second
+	// donationFeedPongWait is how long a connection may stay silent before it is considered gone
+	donationFeedPongWait = 60 * time.Second
+	// donationFeedPingPeriod is how often connections are pinged, shorter than the pong wait
+	donationFeedPingPeriod = donationFeedPongWait * 9 / 10
+	// donationFeedMaxMessage is the largest message read from a client, which has nothing to send but control frames
+	donationFeedMaxMessage = 512
+
+	donationFeedMessageType = "donation"
+
+	// donationFeedAnonymousName is shown for anonymous donations
+	donationFeedAnonymousName = "Anonymous"
+)
+
+// The feed carries public data only, so any site may embed it
+var donationFeedUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+type donationFeedRequest struct {
+	GoalID  int64 `form:"goal_id" binding:"omitempty,min=1"`
+	EventID int64 `form:"event_id" binding:"omitempty,min=1"`
+	Replay  *int  `form:"replay" binding:"omitempty,min=0"`
+}
+
+type donationFeedItem struct {
+	ID          int64     `json:"id"`
+	GoalID      int64     `json:"goal_id"`
+	GoalTitle   string    `json:"goal_title"`
+	Amount      int64     `json:"amount"`
+	DonorName   string    `json:"donor_name"`
+	IsAnonymous bool      `json:"is_anonymous"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type donationFeedMessage struct {
+	Type string `json:"type"`
+	// Replay is set for the recent donations sent when the connection opens
+	Replay   bool             `json:"replay"`
+	Donation donationFeedItem `json:"donation"`
+}
+
+// newDonationFeedItem redacts the donor of anonymous donations and of donors hidden from leaderboards.
+// Offline donations are named only if the import marked the donor's name as public.
+func newDonationFeedItem(donation db.GetDonationFeedItemRow) donationFeedItem {
+	name := donationFeedAnonymousName
+	if !donation.IsAnonymous {
+		name = leaderboardDefaultName
+		if donation.UserName.Valid && donation.UserName.String != "" && !donation.HideDonor {
+			name = donation.UserName.String
+		} else if donation.OfflineDonorName.Valid && donation.OfflineDonorName.String != "" {
+			name = donation.OfflineDonorName.String
+		}
+	}
+
+	return donationFeedItem{
+		ID:          donation.ID,
+		GoalID:      donation.GoalID,
+		GoalTitle:   donation.GoalTitle,
+		Amount:      donation.Amount,
+		DonorName:   name,
+		IsAnonymous: donation.IsAnonymous,
+		CreatedAt:   donation.CreatedAt,
+	}
+}
+
+// donationFeedClient is a connection open on the feed
+type donationFeedClient struct {
+	// goalID limits the connection to the donations of a goal, zero for every goal
+	goalID int64
+	// send is closed once the client is dropped
+	send chan donationFeedMessage
+}
+
+// donationFeedHub fans donations out to the feed connections open on this replica
+type donationFeedHub struct {
+	mutex   sync.Mutex
+	clients map[*donationFeedClient]struct{}
+	// sent holds the donations published last, oldest first. IDs are taken before the donation commits,
+	// so donations can be published out of order and the newest ID doesn't tell which were missed.
+	sent    []int64
+	sentIDs map[int64]struct{}
+}
+
+func newDonationFeedHub() *donationFeedHub {
+	return &donationFeedHub{
+		clients: make(map[*donationFeedClient]struct{}),
+		sentIDs: make(map[int64]struct{}),
+	}
+}
+
+// register adds a client receiving the donations to goalID, or to every goal if it is zero
+func (hub *donationFeedHub) register(goalID int64) *donationFeedClient {
+	client := &donationFeedClient{
+		goalID: goalID,
+		send:   make(chan donationFeedMessage, donationFeedBuffer),
+	}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	hub.clients[client] = struct{}{}
+	return client
+}
+
+// unregister removes a client unless it was already dropped
+func (hub *donationFeedHub) unregister(client *donationFeedClient) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	if _, ok := hub.clients[client]; ok {
+		delete(hub.clients, client)
+		close(client.send)
+	}
+}
+
+// empty reports whether no clients are connected
+func (hub *donationFeedHub) empty() bool {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	return len(hub.clients) == 0
+}
+
+// oldest returns the oldest donation the hub remembers publishing, or zero if it hasn't published any
+func (hub *donationFeedHub) oldest() int64 {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	var oldest int64
+	for _, id := range hub.sent {
+		if oldest == 0 || id < oldest {
+			oldest = id
+		}
+	}
+	return oldest
+}
+
+// publish queues a donation for every client following its goal, unless it was published recently.
+// Publishing never blocks: a client whose queue is full is dropped, so one slow connection can't hold up the others.
+func (hub *donationFeedHub) publish(donation donationFeedItem) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	if _, ok := hub.sentIDs[donation.ID]; ok {
+		return
+	}
+	hub.sentIDs[donation.ID] = struct{}{}
+	hub.sent = append(hub.sent, donation.ID)
+	if len(hub.sent) > donationFeedSentWindow {
+		delete(hub.sentIDs, hub.sent[0])
+		hub.sent = hub.sent[1:]
+	}
+
+	message := donationFeedMessage{
+		Type:     donationFeedMessageType,
+		Donation: donation,
+	}
+	for client := range hub.clients {
+		if client.goalID != 0 && client.goalID != donation.GoalID {
+			continue
+		}
+
+		select {
+		case client.send <- message:
+		default:
+			delete(hub.clients, client)
+			close(client.send)
+		}
+	}
+}
+
+// GET /donations/feed
+func (server *Server) streamDonationFeed(ctx *gin.Context) {
+	var req donationFeedRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Replay != nil && *req.Replay > donationFeedMaxReplay {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("replay can't exceed %d", donationFeedMaxReplay)})
+		return
+	}
+
+	if req.GoalID != 0 && req.EventID != 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "goal_id and event_id can't be combined"})
+		return
+	}
+
+	goalID := req.GoalID
+	if req.EventID != 0 {
+		event, err := server.store.GetEvent(ctx, req.EventID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if !event.GoalID.Valid {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "event isn't raising money for a goal"})
+			return
+		}
+		goalID = event.GoalID.Int64
+	} else if goalID != 0 {
+		_, err := server.store.GetGoal(ctx, goalID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "goal not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	replay := donationFeedReplay
+	if req.Replay != nil {
+		replay = *req.Replay
+	}
+
+	conn, err := donationFeedUpgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		// Upgrade has already replied with an error
+		return
+	}
+	defer conn.Close()
+
+	// Register before loading the replay so a donation committed meanwhile isn't missed
+	client := server.donationFeed.register(goalID)
+	defer server.donationFeed.unregister(client)
+
+	// replayed holds the donations replayed, so live donations that were also replayed are skipped
+	replayed := make(map[int64]struct{}, replay)
+	if replay > 0 {
+		donations, err := server.store.ListDonationFeed(ctx, db.ListDonationFeedParams{
+			GoalID:   pgtype.Int8{Int64: goalID, Valid: goalID != 0},
+			RowLimit: int32(replay),
+		})
+		if err != nil {
+			log.Printf("load donation feed replay failed: %v", err)
+			closeDonationFeed(conn, websocket.CloseInternalServerErr, "")
+			return
+		}
+
+		for i := len(donations) - 1; i >= 0; i-- {
+			message := donationFeedMessage{
+				Type:     donationFeedMessageType,
+				Replay:   true,
+				Donation: newDonationFeedItem(db.GetDonationFeedItemRow(donations[i])),
+			}
+			conn.SetWriteDeadline(time.Now().Add(donationFeedWriteWait))
+			if err := conn.WriteJSON(message); err != nil {
+				return
+			}
+			replayed[message.Donation.ID] = struct{}{}
+		}
+	}
+
+	closed := make(chan struct{})
+	go readDonationFeed(conn, closed)
+
+	ping := time.NewTicker(donationFeedPingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case message, ok := <-client.send:
+			conn.SetWriteDeadline(time.Now().Add(donationFeedWriteWait))
+			if !ok {
+				closeDonationFeed(conn, websocket.CloseTryAgainLater, "client too slow")
+				return
+			}
+			if _, ok := replayed[message.Donation.ID]; ok {
+				// The hub publishes a donation once, so it can't be skipped again
+				delete(replayed, message.Donation.ID)
+				continue
+			}
+			if err := conn.WriteJSON(message); err != nil {
+				return
+			}
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(donationFeedWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readDonationFeed reads from a connection until it fails, which handles pongs and close frames,
+// then closes closed
+func readDonationFeed(conn *websocket.Conn, closed chan<- struct{}) {
+	defer close(closed)
+
+	conn.SetReadLimit(donationFeedMaxMessage)
+	conn.SetReadDeadline(time.Now().Add(donationFeedPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(donationFeedPongWait))
+	})
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func closeDonationFeed(conn *websocket.Conn, code int, text string) {
+	message := websocket.FormatCloseMessage(code, text)
+	_ = conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(donationFeedWriteWait))
+}
+
+// StreamDonations pushes every new donation to the open feed connections whenever receiver gets its ID,
+// until ctx is done. Every replica runs it on its own listener, so feeds get donations taken by any replica.
+func (server *Server) StreamDonations(ctx context.Context, receiver pubsub.Receiver) {
+	receiveIDNotifications(ctx, receiver, "donation", server.publishDonation, server.resyncDonationFeed)
+}
+
+func (server *Server) publishDonation(ctx context.Context, donationID int64) {
+	if server.donationFeed.empty() {
+		return
+	}
+
+	donation, err := server.store.GetDonationFeedItem(ctx, donationID)
+	if err != nil {
+		if ctx.Err() == nil && !errors.Is(err, db.ErrRecordNotFound) {
+			log.Printf("load donation %d for the feed failed: %v", donationID, err)
+		}
+		return
+	}
+
+	server.donationFeed.publish(newDonationFeedItem(donation))
+}
+
+// resyncDonationFeed publishes the recent donations the hub hasn't, up to the replay maximum.
+// Donations older than those the hub remembers publishing are left out, as they are no longer news.
+func (server *Server) resyncDonationFeed(ctx context.Context) {
+	oldest := server.donationFeed.oldest()
+	if oldest == 0 || server.donationFeed.empty() {
+		return
+	}
+
+	donations, err := server.store.ListDonationFeed(ctx, db.ListDonationFeedParams{
+		RowLimit: donationFeedMaxReplay,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("load missed donations for the feed failed: %v", err)
+		}
+		return
+	}
+
+	for i := len(donations) - 1; i >= 0; i-- {
+		if donations[i].ID > oldest {
+			server.donationFeed.publish(newDonationFeedItem(db.GetDonationFeedItemRow(donations[i])))
+		}
+	}
+}
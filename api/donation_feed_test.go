@@ -0,0 +1,335 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomDonationFeedItem(goal db.Goal) db.GetDonationFeedItemRow {
+	return db.GetDonationFeedItemRow{
+		ID:        util.RandomInt(1, 1000),
+		GoalID:    goal.ID,
+		GoalTitle: goal.Title,
+		Amount:    util.RandomMoney(),
+		CreatedAt: time.Now().Truncate(time.Second),
+		UserName:  pgtype.Text{String: util.RandomOwner(), Valid: true},
+	}
+}
+
+// dialDonationFeed opens the donation feed of a test HTTP server
+func dialDonationFeed(t *testing.T, httpServer *httptest.Server, query string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/donations/feed" + query
+	conn, response, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readDonationFeedMessage(t *testing.T, conn *websocket.Conn) donationFeedMessage {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	var message donationFeedMessage
+	require.NoError(t, conn.ReadJSON(&message))
+	require.Equal(t, donationFeedMessageType, message.Type)
+	return message
+}
+
+func TestStreamDonationFeedAPI(t *testing.T) {
+	goal := randomGoal()
+	older := randomDonationFeedItem(goal)
+	older.ID = 10
+	newer := randomDonationFeedItem(goal)
+	newer.ID = 11
+	newer.IsAnonymous = true
+	live := randomDonationFeedItem(goal)
+	live.ID = 12
+	live.HideDonor = true
+	// late took its ID before the replayed donations but committed after them
+	late := randomDonationFeedItem(goal)
+	late.ID = 9
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(goal, nil)
+	store.EXPECT().
+		ListDonationFeed(gomock.Any(), gomock.Eq(db.ListDonationFeedParams{
+			GoalID:   pgtype.Int8{Int64: goal.ID, Valid: true},
+			RowLimit: 2,
+		})).
+		Times(1).
+		Return([]db.ListDonationFeedRow{db.ListDonationFeedRow(newer), db.ListDonationFeedRow(older)}, nil)
+	store.EXPECT().GetDonationFeedItem(gomock.Any(), gomock.Eq(newer.ID)).Times(1).Return(newer, nil)
+	store.EXPECT().GetDonationFeedItem(gomock.Any(), gomock.Eq(live.ID)).Times(1).Return(live, nil)
+	store.EXPECT().GetDonationFeedItem(gomock.Any(), gomock.Eq(late.ID)).Times(1).Return(late, nil)
+
+	server := newTestServer(t, store)
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	receiver := &fakeReceiver{payloads: make(chan string)}
+	go server.StreamDonations(ctx, receiver)
+
+	conn := dialDonationFeed(t, httpServer, fmt.Sprintf("?goal_id=%d&replay=2", goal.ID))
+
+	// Replay is sent oldest first
+	message := readDonationFeedMessage(t, conn)
+	require.True(t, message.Replay)
+	require.Equal(t, older.ID, message.Donation.ID)
+	require.Equal(t, older.UserName.String, message.Donation.DonorName)
+	require.Equal(t, goal.Title, message.Donation.GoalTitle)
+
+	message = readDonationFeedMessage(t, conn)
+	require.True(t, message.Replay)
+	require.Equal(t, newer.ID, message.Donation.ID)
+	require.Equal(t, donationFeedAnonymousName, message.Donation.DonorName)
+
+	// A donation already replayed isn't sent again
+	receiver.payloads <- strconv.FormatInt(newer.ID, 10)
+	receiver.payloads <- strconv.FormatInt(live.ID, 10)
+
+	message = readDonationFeedMessage(t, conn)
+	require.False(t, message.Replay)
+	require.Equal(t, live.ID, message.Donation.ID)
+	require.Equal(t, live.Amount, message.Donation.Amount)
+	require.Equal(t, leaderboardDefaultName, message.Donation.DonorName)
+
+	receiver.payloads <- strconv.FormatInt(late.ID, 10)
+
+	message = readDonationFeedMessage(t, conn)
+	require.False(t, message.Replay)
+	require.Equal(t, late.ID, message.Donation.ID)
+}
+
+func TestStreamDonationFeedByEventAPI(t *testing.T) {
+	goal := randomGoal()
+	event := randomEvent()
+	event.GoalID = pgtype.Int8{Int64: goal.ID, Valid: true}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+	store.EXPECT().GetGoal(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().ListDonationFeed(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	conn := dialDonationFeed(t, httpServer, fmt.Sprintf("?event_id=%d&replay=0", event.ID))
+	require.Eventually(t, func() bool { return !server.donationFeed.empty() }, time.Second, 10*time.Millisecond)
+
+	// Only donations to the event's goal are sent
+	server.donationFeed.publish(donationFeedItem{ID: 1, GoalID: goal.ID + 1})
+	server.donationFeed.publish(donationFeedItem{ID: 2, GoalID: goal.ID})
+
+	message := readDonationFeedMessage(t, conn)
+	require.Equal(t, int64(2), message.Donation.ID)
+	require.Equal(t, goal.ID, message.Donation.GoalID)
+}
+
+func TestStreamDonationFeedErrorsAPI(t *testing.T) {
+	goal := randomGoal()
+	event := randomEvent()
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "GoalNotFound",
+			query: fmt.Sprintf("?goal_id=%d", goal.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(db.Goal{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "EventNotFound",
+			query: fmt.Sprintf("?event_id=%d", event.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(db.Event{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "EventWithoutGoal",
+			query: fmt.Sprintf("?event_id=%d", event.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: fmt.Sprintf("?goal_id=%d", goal.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(db.Goal{}, pgx.ErrTxClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:  "GoalAndEvent",
+			query: fmt.Sprintf("?goal_id=%d&event_id=%d", goal.ID, event.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "ReplayTooLarge",
+			query: "?replay=101",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDonationFeed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "NotWebSocket",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListDonationFeed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/donations/feed"+tc.query, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+
+			// Failed connections don't stay registered
+			require.True(t, server.donationFeed.empty())
+		})
+	}
+}
+
+func TestDonationFeedHub(t *testing.T) {
+	hub := newDonationFeedHub()
+
+	all := hub.register(0)
+	slow := hub.register(1)
+	other := hub.register(2)
+
+	// A client that falls behind is dropped instead of blocking the others
+	for i := 1; i <= donationFeedBuffer+1; i++ {
+		hub.publish(donationFeedItem{ID: int64(i), GoalID: 1})
+		<-all.send
+	}
+	require.Empty(t, other.send)
+
+	for range donationFeedBuffer {
+		<-slow.send
+	}
+	_, ok := <-slow.send
+	require.False(t, ok)
+
+	// Unregistering a dropped client is a no-op
+	hub.unregister(slow)
+	hub.unregister(all)
+	hub.unregister(other)
+	require.True(t, hub.empty())
+}
+
+func TestDonationFeedHubPublishesOnce(t *testing.T) {
+	hub := newDonationFeedHub()
+	require.Zero(t, hub.oldest())
+
+	client := hub.register(0)
+	defer hub.unregister(client)
+
+	// Donations can be published out of order, but each only once
+	for _, id := range []int64{5, 3, 5, 4, 3} {
+		hub.publish(donationFeedItem{ID: id})
+	}
+	require.Len(t, client.send, 3)
+	require.Equal(t, int64(3), hub.oldest())
+	for _, id := range []int64{5, 3, 4} {
+		require.Equal(t, id, (<-client.send).Donation.ID)
+	}
+
+	// Only the donations published last are remembered
+	for i := range donationFeedSentWindow {
+		hub.publish(donationFeedItem{ID: int64(100 + i)})
+		<-client.send
+	}
+	require.Equal(t, int64(100), hub.oldest())
+	hub.publish(donationFeedItem{ID: 5})
+	require.Equal(t, int64(5), (<-client.send).Donation.ID)
+}
+
+func TestNewDonationFeedItem(t *testing.T) {
+	goal := randomGoal()
+
+	donation := randomDonationFeedItem(goal)
+	require.Equal(t, donation.UserName.String, newDonationFeedItem(donation).DonorName)
+
+	donation.IsAnonymous = true
+	require.Equal(t, donationFeedAnonymousName, newDonationFeedItem(donation).DonorName)
+
+	donation.IsAnonymous = false
+	donation.UserName = pgtype.Text{}
+	require.Equal(t, leaderboardDefaultName, newDonationFeedItem(donation).DonorName)
+
+	// Offline donations name the donor recorded with them if the import made the name public,
+	// otherwise the feed queries leave it out
+	donation.OfflineDonorName = pgtype.Text{String: util.RandomOwner(), Valid: true}
+	require.Equal(t, donation.OfflineDonorName.String, newDonationFeedItem(donation).DonorName)
+
+	donation.IsAnonymous = true
+	require.Equal(t, donationFeedAnonymousName, newDonationFeedItem(donation).DonorName)
+}
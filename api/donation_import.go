@@ -0,0 +1,321 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/token"
+	"github.com/kholodihor/charity/util"
+)
+
+// Limits of a single donation import upload
+const (
+	importMaxBytes       = 10 << 20
+	importMaxRows        = 10000
+	importDefaultChunk   = 100
+	importMaxExternalRef = 100
+)
+
+// Import modes: everything in one transaction, or one transaction per chunk
+const (
+	importModeAtomic  = "atomic"
+	importModeChunked = "chunked"
+)
+
+// Columns of the import CSV
+const (
+	importColumnRef        = "external_ref"
+	importColumnGoalID     = "goal_id"
+	importColumnAmount     = "amount"
+	importColumnDonatedAt  = "donated_at"
+	importColumnMethod     = "method"
+	importColumnName       = "donor_name"
+	importColumnNamePublic = "donor_name_public"
+	importColumnEmail      = "donor_email"
+	importColumnAnonymous  = "is_anonymous"
+)
+
+var importRequiredColumns = []string{
+	importColumnRef,
+	importColumnGoalID,
+	importColumnAmount,
+	importColumnDonatedAt,
+	importColumnMethod,
+}
+
+type importDonationsRequest struct {
+	Mode      string `form:"mode" binding:"omitempty,oneof=atomic chunked"`
+	ChunkSize int    `form:"chunk_size" binding:"omitempty,min=1,max=1000"`
+	DryRun    bool   `form:"dry_run"`
+}
+
+type importRowError struct {
+	Line        int    `json:"line"`
+	ExternalRef string `json:"external_ref,omitempty"`
+	Error       string `json:"error"`
+}
+
+type importDonationsResponse struct {
+	TotalRows      int              `json:"total_rows"`
+	Imported       int              `json:"imported"`
+	Skipped        int              `json:"skipped"`
+	Completed      bool             `json:"completed"`
+	DryRun         bool             `json:"dry_run,omitempty"`
+	ResumeFromLine int              `json:"resume_from_line,omitempty"`
+	Errors         []importRowError `json:"errors"`
+}
+
+// POST /admin/donations/import
+func (server *Server) importDonations(ctx *gin.Context) {
+	var req importDonationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = importModeAtomic
+	}
+	if req.ChunkSize == 0 {
+		req.ChunkSize = importDefaultChunk
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, importMaxBytes)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "a CSV file must be uploaded in the file field"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	rows, rowErrors, err := parseDonationImport(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	response := importDonationsResponse{
+		TotalRows: len(rows) + len(rowErrors),
+		DryRun:    req.DryRun,
+		Errors:    rowErrors,
+	}
+
+	// Nothing is written unless every row is valid
+	if len(rowErrors) > 0 {
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+	if req.DryRun {
+		response.Completed = true
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	chunkSize := len(rows)
+	if req.Mode == importModeChunked {
+		chunkSize = req.ChunkSize
+	}
+
+	for start := 0; start < len(rows); start += chunkSize {
+		chunk := rows[start:min(start+chunkSize, len(rows))]
+
+		result, err := server.store.ImportDonationsTx(ctx, db.ImportDonationsTxParams{
+			ImportedBy: authPayload.UserID,
+			Rows:       chunk,
+		})
+		if err != nil {
+			var rowErr *db.ImportRowError
+			if !errors.As(err, &rowErr) {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+
+			response.Errors = append(response.Errors, importRowError{
+				Line:        rowErr.Line,
+				ExternalRef: rowErr.ExternalRef,
+				Error:       rowErr.Err.Error(),
+			})
+			if req.Mode == importModeChunked {
+				response.ResumeFromLine = chunk[0].Line
+			}
+			ctx.JSON(http.StatusUnprocessableEntity, response)
+			return
+		}
+
+		response.Imported += len(result.Donations)
+		response.Skipped += len(result.Skipped)
+	}
+
+	response.Completed = true
+	ctx.JSON(http.StatusOK, response)
+}
+
+// parseDonationImport reads and validates an import CSV. Invalid rows are returned as row errors,
+// while an unreadable file or a bad header is returned as an error.
+func parseDonationImport(r io.Reader) ([]db.ImportDonationRow, []importRowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("CSV file is empty")
+		}
+		return nil, nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("CSV header is missing the %s column", name)
+		}
+	}
+
+	rows := []db.ImportDonationRow{}
+	rowErrors := []importRowError{}
+	refs := make(map[string]int)
+	now := time.Now()
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) || !errors.Is(err, csv.ErrFieldCount) {
+				return nil, nil, err
+			}
+			rowErrors = append(rowErrors, importRowError{Line: parseErr.StartLine, Error: "wrong number of fields"})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		if len(rows)+len(rowErrors) >= importMaxRows {
+			return nil, nil, fmt.Errorf("CSV file has more than %d rows", importMaxRows)
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row, err := parseImportRow(field, now)
+		row.Line = line
+		if err == nil {
+			if first, ok := refs[row.ExternalRef]; ok {
+				err = fmt.Errorf("external_ref already used on line %d", first)
+			}
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, importRowError{Line: line, ExternalRef: row.ExternalRef, Error: err.Error()})
+			continue
+		}
+
+		refs[row.ExternalRef] = line
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrors, nil
+}
+
+func parseImportRow(field func(string) string, now time.Time) (db.ImportDonationRow, error) {
+	row := db.ImportDonationRow{
+		ExternalRef: field(importColumnRef),
+		Method:      strings.ToLower(field(importColumnMethod)),
+	}
+
+	if row.ExternalRef == "" {
+		return row, errors.New("external_ref is required")
+	}
+	if len(row.ExternalRef) > importMaxExternalRef {
+		return row, fmt.Errorf("external_ref must be at most %d characters", importMaxExternalRef)
+	}
+
+	goalID, err := strconv.ParseInt(field(importColumnGoalID), 10, 64)
+	if err != nil || goalID <= 0 {
+		return row, errors.New("goal_id must be a positive integer")
+	}
+	row.GoalID = goalID
+
+	amount, err := util.ParseCents(field(importColumnAmount))
+	if err != nil || amount <= 0 {
+		return row, errors.New("amount must be a positive decimal with at most two decimal places")
+	}
+	row.Amount = amount
+
+	donatedAt, err := parseDateOrTime(field(importColumnDonatedAt))
+	if err != nil {
+		return row, errors.New("donated_at must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+	}
+	if donatedAt.After(now) {
+		return row, errors.New("donated_at must not be in the future")
+	}
+	row.DonatedAt = donatedAt
+
+	if row.Method != db.OfflineMethodCash && row.Method != db.OfflineMethodBankTransfer {
+		return row, fmt.Errorf("method must be %s or %s", db.OfflineMethodCash, db.OfflineMethodBankTransfer)
+	}
+
+	if name := field(importColumnName); name != "" {
+		row.DonorName = pgtype.Text{String: name, Valid: true}
+	}
+
+	if public := field(importColumnNamePublic); public != "" {
+		row.DonorNamePublic, err = strconv.ParseBool(public)
+		if err != nil {
+			return row, errors.New("donor_name_public must be true or false")
+		}
+	}
+
+	if email := field(importColumnEmail); email != "" {
+		if _, err := mail.ParseAddress(email); err != nil {
+			return row, errors.New("donor_email is not a valid email address")
+		}
+		row.DonorEmail = pgtype.Text{String: email, Valid: true}
+	}
+
+	if anonymous := field(importColumnAnonymous); anonymous != "" {
+		row.IsAnonymous, err = strconv.ParseBool(anonymous)
+		if err != nil {
+			return row, errors.New("is_anonymous must be true or false")
+		}
+	}
+
+	return row, nil
+}
+
+// parseDateOrTime parses a YYYY-MM-DD date or an RFC 3339 timestamp
+func parseDateOrTime(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+const importTestCSV = `external_ref,goal_id,amount,donated_at,method,donor_name,donor_name_public,donor_email,is_anonymous
+CASH-1,7,25.50,2024-03-01,cash,Jane Doe,true,jane@email.com,false
+BANK-1,7,100,2024-03-02T10:00:00Z,bank_transfer,John Doe,,,true
+BANK-2,8,0.05,2024-03-03,Bank_Transfer,,,,
+`
+
+func newImportRequest(t *testing.T, query string, content string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", "donations.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	request, err := http.NewRequest(http.MethodPost, "/admin/donations/import"+query, body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return request
+}
+
+func decodeImportResponse(t *testing.T, recorder *httptest.ResponseRecorder) importDonationsResponse {
+	var response importDonationsResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	return response
+}
+
+func TestImportDonationsAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	testCases := []struct {
+		name          string
+		query         string
+		content       string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "Atomic",
+			content: importTestCSV,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ImportDonationsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.ImportDonationsTxParams) (db.ImportDonationsTxResult, error) {
+						require.Equal(t, admin.ID, arg.ImportedBy)
+						require.Len(t, arg.Rows, 3)
+
+						require.Equal(t, db.ImportDonationRow{
+							Line:            2,
+							ExternalRef:     "CASH-1",
+							GoalID:          7,
+							Amount:          2550,
+							DonatedAt:       time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+							Method:          db.OfflineMethodCash,
+							DonorName:       arg.Rows[0].DonorName,
+							DonorEmail:      arg.Rows[0].DonorEmail,
+							DonorNamePublic: true,
+						}, arg.Rows[0])
+						require.Equal(t, "Jane Doe", arg.Rows[0].DonorName.String)
+						require.Equal(t, "jane@email.com", arg.Rows[0].DonorEmail.String)
+
+						require.True(t, arg.Rows[1].IsAnonymous)
+						// Donor names stay private unless the import says otherwise
+						require.False(t, arg.Rows[1].DonorNamePublic)
+						require.Equal(t, int64(10000), arg.Rows[1].Amount)
+						require.Equal(t, db.OfflineMethodBankTransfer, arg.Rows[2].Method)
+						require.Equal(t, int64(5), arg.Rows[2].Amount)
+
+						return db.ImportDonationsTxResult{
+							Donations: []db.Donation{{ID: 1}, {ID: 2}},
+							Skipped:   []string{"BANK-2"},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				response := decodeImportResponse(t, recorder)
+				require.True(t, response.Completed)
+				require.Equal(t, 3, response.TotalRows)
+				require.Equal(t, 2, response.Imported)
+				require.Equal(t, 1, response.Skipped)
+				require.Empty(t, response.Errors)
+			},
+		},
+		{
+			name:    "ValidationErrors",
+			content: "external_ref,goal_id,amount,donated_at,method\nA,7,12.345,2024-03-01,cash\nB,x,1,2024-03-01,cash\nA,7,1,2024-03-01,cheque\nC,7,1,2999-01-01,cash\nD,7\nA,7,1,2024-03-01,cash\n",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportDonationsTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				response := decodeImportResponse(t, recorder)
+				require.False(t, response.Completed)
+				require.Equal(t, 6, response.TotalRows)
+
+				lines := []int{}
+				for _, rowErr := range response.Errors {
+					lines = append(lines, rowErr.Line)
+				}
+				require.Equal(t, []int{2, 3, 4, 5, 6}, lines)
+				require.Contains(t, response.Errors[0].Error, "amount")
+				require.Contains(t, response.Errors[1].Error, "goal_id")
+				require.Contains(t, response.Errors[2].Error, "method")
+				require.Contains(t, response.Errors[3].Error, "future")
+			},
+		},
+		{
+			name:    "DuplicateRef",
+			content: "external_ref,goal_id,amount,donated_at,method\nA,7,1,2024-03-01,cash\nA,7,2,2024-03-01,cash\n",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportDonationsTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				response := decodeImportResponse(t, recorder)
+				require.Len(t, response.Errors, 1)
+				require.Equal(t, 3, response.Errors[0].Line)
+				require.Contains(t, response.Errors[0].Error, "line 2")
+			},
+		},
+		{
+			name:    "MissingColumn",
+			content: "external_ref,goal_id,amount,method\nA,7,1,cash\n",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportDonationsTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "donated_at")
+			},
+		},
+		{
+			name:    "DryRun",
+			query:   "?dry_run=true",
+			content: importTestCSV,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportDonationsTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				response := decodeImportResponse(t, recorder)
+				require.True(t, response.DryRun)
+				require.True(t, response.Completed)
+				require.Zero(t, response.Imported)
+			},
+		},
+		{
+			name:    "AtomicRowError",
+			content: importTestCSV,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ImportDonationsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportDonationsTxResult{}, &db.ImportRowError{Line: 4, ExternalRef: "BANK-2", Err: db.ErrGoalNotFound})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				response := decodeImportResponse(t, recorder)
+				require.False(t, response.Completed)
+				require.Zero(t, response.Imported)
+				require.Zero(t, response.ResumeFromLine)
+				require.Equal(t, []importRowError{{Line: 4, ExternalRef: "BANK-2", Error: db.ErrGoalNotFound.Error()}}, response.Errors)
+			},
+		},
+		{
+			name:    "ChunkedResume",
+			query:   "?mode=chunked&chunk_size=2",
+			content: importTestCSV,
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().
+						ImportDonationsTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ImportDonationsTxResult{Donations: []db.Donation{{ID: 1}, {ID: 2}}}, nil),
+					store.EXPECT().
+						ImportDonationsTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.ImportDonationsTxResult{}, &db.ImportRowError{Line: 4, ExternalRef: "BANK-2", Err: db.ErrInactiveGoal}),
+				)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				response := decodeImportResponse(t, recorder)
+				require.False(t, response.Completed)
+				require.Equal(t, 2, response.Imported)
+				require.Equal(t, 4, response.ResumeFromLine)
+				require.Len(t, response.Errors, 1)
+			},
+		},
+		{
+			name:    "DefaultChunkSize",
+			query:   "?mode=chunked&chunk_size=0",
+			content: importTestCSV,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportDonationsTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ImportDonationsTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				// A zero chunk size falls back to the default
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidMode",
+			query:   "?mode=parallel",
+			content: importTestCSV,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportDonationsTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request := newImportRequest(t, tc.query, tc.content)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
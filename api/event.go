@@ -2,79 +2,239 @@ package api
 
 import (
 	"database/sql"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/recurrence"
 	"github.com/kholodihor/charity/token"
+	"github.com/kholodihor/charity/util"
 )
 
 type createEventRequest struct {
-	Name  string    `json:"name" binding:"required"`
-	Place string    `json:"place" binding:"required"`
+	Name string `json:"name" binding:"required"`
+	// Place defaults to the address of the venue
+	Place string    `json:"place" binding:"required_without=VenueID"`
 	Date  time.Time `json:"date" binding:"required"`
+	// VenueID links the event to a venue, which shows it on maps and in nearby searches
+	VenueID *int64 `json:"venue_id" binding:"omitempty,min=1"`
+	// EndsAt defaults to defaultEventDuration after the start
+	EndsAt *time.Time `json:"ends_at"`
+	// TimeZone is the IANA time zone of the venue, the venue's or UTC by default
+	TimeZone string `json:"time_zone"`
+	// GoalID links the event to the goal it raises money for
+	GoalID *int64 `json:"goal_id" binding:"omitempty,min=1"`
+	// TicketPrice in cents is donated to the goal by everyone booking; 0 makes the event free
+	TicketPrice int64 `json:"ticket_price" binding:"min=0"`
+	// MaxPartySize caps how many people one booking can be for; unlimited by default
+	MaxPartySize *int32 `json:"max_party_size" binding:"omitempty,min=1"`
 }
 
 type updateEventRequest struct {
 	Name  *string    `json:"name"`
 	Place *string    `json:"place"`
 	Date  *time.Time `json:"date"`
+	// Without EndsAt the end moves with Date, keeping the length of the event
+	EndsAt   *time.Time `json:"ends_at"`
+	TimeZone *string    `json:"time_zone"`
+	// VenueID moves the event to another venue, and its address becomes the place unless one is given
+	VenueID *int64 `json:"venue_id" binding:"omitempty,min=1"`
+	GoalID  *int64 `json:"goal_id" binding:"omitempty,min=1"`
+	// TicketPrice in cents; setting a price requires the event to have a goal
+	TicketPrice *int64 `json:"ticket_price" binding:"omitempty,min=0"`
+	// RRule replaces the recurrence rule of the series with the following or all scope
+	RRule *string `json:"rrule"`
+	// MaxPartySize can't be set for occurrences of a series
+	MaxPartySize *int32 `json:"max_party_size" binding:"omitempty,min=1"`
 }
 
 type eventResponse struct {
-	ID        int64  `json:"id"`
-	Name      string `json:"name"`
-	Place     string `json:"place"`
-	Date      string `json:"date"`
-	CreatedAt string `json:"created_at"`
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Place string `json:"place"`
+	// Date and EndsAt are in UTC, LocalDate and LocalEndsAt in the venue's time zone
+	Date            string `json:"date"`
+	EndsAt          string `json:"ends_at"`
+	LocalDate       string `json:"local_date"`
+	LocalEndsAt     string `json:"local_ends_at"`
+	TimeZone        string `json:"time_zone"`
+	DurationMinutes int64  `json:"duration_minutes"`
+	CreatedAt       string `json:"created_at"`
+	GoalID          *int64 `json:"goal_id,omitempty"`
+	// TicketPrice in cents, 0 for free events
+	TicketPrice int64 `json:"ticket_price"`
+	// SeriesID and RecurrenceID identify occurrences of a recurring series
+	SeriesID     *int64  `json:"series_id,omitempty"`
+	RecurrenceID *string `json:"recurrence_id,omitempty"`
+	VenueID      *int64  `json:"venue_id,omitempty"`
+	// Status is scheduled, postponed, rescheduled or cancelled, and StatusReason says why it changed
+	Status       string `json:"status"`
+	StatusReason string `json:"status_reason,omitempty"`
+	// MaxPartySize is how many people one booking can be for, unlimited if left out
+	MaxPartySize *int32 `json:"max_party_size,omitempty"`
+	// DistanceKm is the distance to the venue in nearby searches
+	DistanceKm *float64 `json:"distance_km,omitempty"`
 }
 
 type eventBookingResponse struct {
-	ID       int64  `json:"id"`
-	UserID   int64  `json:"user_id"`
-	EventID  int64  `json:"event_id"`
-	BookedAt string `json:"booked_at"`
+	ID               int64  `json:"id"`
+	UserID           int64  `json:"user_id"`
+	EventID          int64  `json:"event_id"`
+	BookedAt         string `json:"booked_at"`
+	RemindersEnabled bool   `json:"reminders_enabled"`
+	// PartySize is how many people the booking is for, and PartyNames who they are besides the booker
+	PartySize  int32    `json:"party_size"`
+	PartyNames []string `json:"party_names"`
+	// DonationID is the donation that paid for the tickets of a paid event
+	DonationID *int64 `json:"donation_id,omitempty"`
+}
+
+type bookEventRequest struct {
+	// PartySize is how many people the booking is for, the booker included, 1 by default
+	PartySize int32 `json:"party_size" binding:"omitempty,min=1"`
+	// PartyNames names the people booked for besides the booker
+	PartyNames []string `json:"party_names" binding:"omitempty,dive,required,max=100"`
+}
+
+// errPaidEventWithoutGoal rejects ticket prices on events without a goal to donate them to
+const errPaidEventWithoutGoal = "paid events need a goal_id to donate ticket sales to"
+
+// errEventEndsBeforeStart rejects events that don't end after they start
+const errEventEndsBeforeStart = "ends_at must be after the start of the event"
+
+// defaultEventDuration is the length of events created without an end time
+const defaultEventDuration = 2 * time.Hour
+
+type ticketRefundResponse struct {
+	EventID    int64     `json:"event_id"`
+	Amount     int64     `json:"amount"`
+	RefundedAt time.Time `json:"refunded_at"`
+}
+
+type updateBookingRemindersRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
 }
 
 type eventBookingWithUserResponse struct {
-	ID        int64  `json:"id"`
-	UserID    int64  `json:"user_id"`
-	EventID   int64  `json:"event_id"`
-	BookedAt  string `json:"booked_at"`
-	UserName  string `json:"user_name"`
-	UserEmail string `json:"user_email"`
+	ID int64 `json:"id"`
+	// UserID is left out for guest bookings, which are named by the guest's name and email
+	UserID     *int64   `json:"user_id,omitempty"`
+	Guest      bool     `json:"guest"`
+	EventID    int64    `json:"event_id"`
+	BookedAt   string   `json:"booked_at"`
+	UserName   string   `json:"user_name"`
+	UserEmail  string   `json:"user_email"`
+	PartySize  int32    `json:"party_size"`
+	PartyNames []string `json:"party_names"`
 }
 
 type userBookingWithEventResponse struct {
-	ID         int64  `json:"id"`
-	UserID     int64  `json:"user_id"`
-	EventID    int64  `json:"event_id"`
-	BookedAt   string `json:"booked_at"`
-	EventName  string `json:"event_name"`
-	EventPlace string `json:"event_place"`
-	EventDate  string `json:"event_date"`
+	ID               int64  `json:"id"`
+	UserID           int64  `json:"user_id"`
+	EventID          int64  `json:"event_id"`
+	BookedAt         string `json:"booked_at"`
+	RemindersEnabled bool   `json:"reminders_enabled"`
+	PartySize        int32  `json:"party_size"`
+	EventName        string `json:"event_name"`
+	EventPlace       string `json:"event_place"`
+	EventDate        string `json:"event_date"`
+	EventEndsAt      string `json:"event_ends_at"`
+	// EventLocalDate is the start in the venue's time zone
+	EventLocalDate string `json:"event_local_date"`
+	EventTimeZone  string `json:"event_time_zone"`
+	EventStatus    string `json:"event_status"`
 }
 
 func newEventResponse(event db.Event) eventResponse {
-	return eventResponse{
-		ID:        event.ID,
-		Name:      event.Name,
-		Place:     event.Place,
-		Date:      event.Date.Format("2006-01-02T15:04:05Z"),
-		CreatedAt: event.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	response := eventResponse{
+		ID:              event.ID,
+		Name:            event.Name,
+		Place:           event.Place,
+		Date:            event.Date.UTC().Format(time.RFC3339),
+		EndsAt:          event.EndsAt.UTC().Format(time.RFC3339),
+		LocalDate:       util.InTimeZone(event.Date, event.TimeZone).Format(time.RFC3339),
+		LocalEndsAt:     util.InTimeZone(event.EndsAt, event.TimeZone).Format(time.RFC3339),
+		TimeZone:        event.TimeZone,
+		DurationMinutes: int64(event.EndsAt.Sub(event.Date) / time.Minute),
+		CreatedAt:       event.CreatedAt.UTC().Format(time.RFC3339),
+		TicketPrice:     event.TicketPrice,
+		Status:          event.Status,
+		StatusReason:    event.StatusReason,
+	}
+	if event.GoalID.Valid {
+		goalID := event.GoalID.Int64
+		response.GoalID = &goalID
+	}
+	if event.SeriesID.Valid && event.RecurrenceID.Valid {
+		seriesID := event.SeriesID.Int64
+		recurrenceID := event.RecurrenceID.Time.UTC().Format(time.RFC3339)
+		response.SeriesID = &seriesID
+		response.RecurrenceID = &recurrenceID
+	}
+	if event.VenueID.Valid {
+		venueID := event.VenueID.Int64
+		response.VenueID = &venueID
+	}
+	if event.MaxPartySize.Valid {
+		maxPartySize := event.MaxPartySize.Int32
+		response.MaxPartySize = &maxPartySize
+	}
+	return response
+}
+
+// checkEventGoal responds with an error and returns false if the goal an event links to doesn't exist
+func (server *Server) checkEventGoal(ctx *gin.Context, goalID *int64) bool {
+	if goalID == nil {
+		return true
 	}
+
+	_, err := server.store.GetGoal(ctx, *goalID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "goal not found"})
+			return false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+	return true
 }
 
-func newEventBookingResponse(booking db.EventBooking) eventBookingResponse {
-	return eventBookingResponse{
-		ID:       booking.ID,
-		UserID:   booking.UserID,
-		EventID:  booking.EventID,
-		BookedAt: booking.BookedAt.Format("2006-01-02T15:04:05Z"),
+// checkEventTimes responds with an error and returns false if an event's time zone isn't an IANA
+// time zone or it doesn't end after it starts
+func checkEventTimes(ctx *gin.Context, start, end time.Time, timeZone string) bool {
+	if _, err := util.LoadTimeZone(timeZone); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return false
 	}
+	if !end.After(start) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errEventEndsBeforeStart})
+		return false
+	}
+	return true
+}
+
+func newEventBookingResponse(booking db.EventBooking) eventBookingResponse {
+	response := eventBookingResponse{
+		ID:               booking.ID,
+		UserID:           booking.UserID.Int64,
+		EventID:          booking.EventID,
+		BookedAt:         booking.BookedAt.UTC().Format(time.RFC3339),
+		RemindersEnabled: booking.RemindersEnabled,
+		PartySize:        booking.PartySize,
+		PartyNames:       booking.PartyNames,
+	}
+	if booking.DonationID.Valid {
+		donationID := booking.DonationID.Int64
+		response.DonationID = &donationID
+	}
+	return response
 }
 
 func newEventBookingWithUserResponse(booking db.ListEventBookingsRow) eventBookingWithUserResponse {
@@ -82,28 +242,51 @@ func newEventBookingWithUserResponse(booking db.ListEventBookingsRow) eventBooki
 	if booking.UserName.Valid {
 		userName = booking.UserName.String
 	}
-	return eventBookingWithUserResponse{
-		ID:        booking.ID,
-		UserID:    booking.UserID,
-		EventID:   booking.EventID,
-		BookedAt:  booking.BookedAt.Format("2006-01-02T15:04:05Z"),
-		UserName:  userName,
-		UserEmail: booking.UserEmail,
+	response := eventBookingWithUserResponse{
+		ID:         booking.ID,
+		Guest:      !booking.UserID.Valid,
+		EventID:    booking.EventID,
+		BookedAt:   booking.BookedAt.UTC().Format(time.RFC3339),
+		UserName:   userName,
+		UserEmail:  booking.UserEmail,
+		PartySize:  booking.PartySize,
+		PartyNames: booking.PartyNames,
+	}
+	if booking.UserID.Valid {
+		userID := booking.UserID.Int64
+		response.UserID = &userID
 	}
+	return response
 }
 
 func newUserBookingWithEventResponse(booking db.ListUserBookingsRow) userBookingWithEventResponse {
 	return userBookingWithEventResponse{
-		ID:         booking.ID,
-		UserID:     booking.UserID,
-		EventID:    booking.EventID,
-		BookedAt:   booking.BookedAt.Format("2006-01-02T15:04:05Z"),
-		EventName:  booking.EventName,
-		EventPlace: booking.EventPlace,
-		EventDate:  booking.EventDate.Format("2006-01-02T15:04:05Z"),
+		ID:               booking.ID,
+		UserID:           booking.UserID.Int64,
+		EventID:          booking.EventID,
+		BookedAt:         booking.BookedAt.UTC().Format(time.RFC3339),
+		RemindersEnabled: booking.RemindersEnabled,
+		PartySize:        booking.PartySize,
+		EventName:        booking.EventName,
+		EventPlace:       booking.EventPlace,
+		EventDate:        booking.EventDate.UTC().Format(time.RFC3339),
+		EventEndsAt:      booking.EventEndsAt.UTC().Format(time.RFC3339),
+		EventLocalDate:   util.InTimeZone(booking.EventDate, booking.EventTimeZone).Format(time.RFC3339),
+		EventTimeZone:    booking.EventTimeZone,
+		EventStatus:      booking.EventStatus,
 	}
 }
 
+// checkParty responds with an error and returns false if more people are named than a party
+// of size has besides the booker
+func checkParty(ctx *gin.Context, size int32, names []string) bool {
+	if len(names) >= int(size) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "party_names can only name the people booked for besides the booker"})
+		return false
+	}
+	return true
+}
+
 // POST /events
 func (server *Server) createEvent(ctx *gin.Context) {
 	var req createEventRequest
@@ -112,10 +295,61 @@ func (server *Server) createEvent(ctx *gin.Context) {
 		return
 	}
 
+	if req.TicketPrice > 0 && req.GoalID == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errPaidEventWithoutGoal})
+		return
+	}
+
+	var venueID pgtype.Int8
+	if req.VenueID != nil {
+		venue, ok := server.getEventVenue(ctx, *req.VenueID)
+		if !ok {
+			return
+		}
+		venueID = pgtype.Int8{Int64: venue.ID, Valid: true}
+		if req.Place == "" {
+			req.Place = venuePlace(venue)
+		}
+		if req.TimeZone == "" {
+			req.TimeZone = venue.TimeZone
+		}
+	}
+
+	if req.TimeZone == "" {
+		req.TimeZone = "UTC"
+	}
+	endsAt := req.Date.Add(defaultEventDuration)
+	if req.EndsAt != nil {
+		endsAt = *req.EndsAt
+	}
+	if !checkEventTimes(ctx, req.Date, endsAt, req.TimeZone) {
+		return
+	}
+
+	if !server.checkEventGoal(ctx, req.GoalID) {
+		return
+	}
+
 	arg := db.CreateEventParams{
-		Name:  req.Name,
-		Place: req.Place,
-		Date:  req.Date,
+		Name:        req.Name,
+		Place:       req.Place,
+		Date:        req.Date,
+		TicketPrice: req.TicketPrice,
+		EndsAt:      endsAt,
+		TimeZone:    req.TimeZone,
+		VenueID:     venueID,
+	}
+	if req.MaxPartySize != nil {
+		arg.MaxPartySize = pgtype.Int4{
+			Int32: *req.MaxPartySize,
+			Valid: true,
+		}
+	}
+	if req.GoalID != nil {
+		arg.GoalID = pgtype.Int8{
+			Int64: *req.GoalID,
+			Valid: true,
+		}
 	}
 
 	event, err := server.store.CreateEvent(ctx, arg)
@@ -130,6 +364,12 @@ func (server *Server) createEvent(ctx *gin.Context) {
 // GET /events/:id
 func (server *Server) getEvent(ctx *gin.Context) {
 	idStr := ctx.Param("id")
+	// Gin can't route /events/:id.ics on its own, so calendar requests arrive here
+	if eventID, ok := strings.CutSuffix(idStr, calendarFileSuffix); ok {
+		server.getEventCalendar(ctx, eventID)
+		return
+	}
+
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
@@ -165,6 +405,11 @@ func (server *Server) listEvents(ctx *gin.Context) {
 		offset = 0
 	}
 
+	if near := ctx.Query("near"); near != "" {
+		server.listEventsNear(ctx, near, upcoming, int32(limit), int32(offset))
+		return
+	}
+
 	var events []db.Event
 
 	if upcoming {
@@ -211,6 +456,78 @@ func (server *Server) updateEvent(ctx *gin.Context) {
 		return
 	}
 
+	scope, ok := seriesScope(ctx)
+	if !ok {
+		return
+	}
+
+	if req.RRule != nil {
+		if scope == db.SeriesScopeThis {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "rrule can only be changed for following or all occurrences"})
+			return
+		}
+		if _, err := recurrence.Parse(*req.RRule); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	if !server.checkEventGoal(ctx, req.GoalID) {
+		return
+	}
+
+	if req.VenueID != nil {
+		venue, ok := server.getEventVenue(ctx, *req.VenueID)
+		if !ok {
+			return
+		}
+		if req.Place == nil {
+			place := venuePlace(venue)
+			req.Place = &place
+		}
+	}
+
+	event, err := server.store.GetEvent(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if req.TicketPrice != nil && *req.TicketPrice > 0 && req.GoalID == nil && !event.GoalID.Valid {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errPaidEventWithoutGoal})
+		return
+	}
+
+	if !event.SeriesID.Valid && scope != db.SeriesScopeThis {
+		ctx.JSON(http.StatusBadRequest, errorResponse(db.ErrNotInSeries))
+		return
+	}
+
+	if event.SeriesID.Valid && req.MaxPartySize != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "max_party_size can't be set for occurrences of a series"})
+		return
+	}
+
+	start, end, timeZone := event.Date, event.EndsAt, event.TimeZone
+	if req.Date != nil {
+		// Moving the start moves the end with it unless a new end is given
+		start = *req.Date
+		end = end.Add(start.Sub(event.Date))
+	}
+	if req.EndsAt != nil {
+		end = *req.EndsAt
+	}
+	if req.TimeZone != nil {
+		timeZone = *req.TimeZone
+	}
+	if !checkEventTimes(ctx, start, end, timeZone) {
+		return
+	}
+
 	arg := db.UpdateEventParams{
 		ID: id,
 	}
@@ -236,10 +553,88 @@ func (server *Server) updateEvent(ctx *gin.Context) {
 		}
 	}
 
-	event, err := server.store.UpdateEvent(ctx, arg)
+	if req.GoalID != nil {
+		arg.GoalID = pgtype.Int8{
+			Int64: *req.GoalID,
+			Valid: true,
+		}
+	}
+
+	if req.TicketPrice != nil {
+		arg.TicketPrice = pgtype.Int8{
+			Int64: *req.TicketPrice,
+			Valid: true,
+		}
+	}
+
+	if req.Date != nil || req.EndsAt != nil {
+		arg.EndsAt = pgtype.Timestamptz{
+			Time:  end,
+			Valid: true,
+		}
+	}
+
+	if req.TimeZone != nil {
+		arg.TimeZone = pgtype.Text{
+			String: *req.TimeZone,
+			Valid:  true,
+		}
+	}
+
+	if req.VenueID != nil {
+		arg.VenueID = pgtype.Int8{
+			Int64: *req.VenueID,
+			Valid: true,
+		}
+	}
+
+	if req.MaxPartySize != nil {
+		arg.MaxPartySize = pgtype.Int4{
+			Int32: *req.MaxPartySize,
+			Valid: true,
+		}
+	}
+
+	// Occurrences of a series are changed through the series, so later series changes know about it
+	if event.SeriesID.Valid {
+		seriesArg := db.UpdateEventSeriesTxParams{
+			EventID:     id,
+			Scope:       scope,
+			Name:        arg.Name,
+			Place:       arg.Place,
+			Date:        arg.Date,
+			GoalID:      arg.GoalID,
+			TicketPrice: arg.TicketPrice,
+			EndsAt:      arg.EndsAt,
+			TimeZone:    arg.TimeZone,
+			VenueID:     arg.VenueID,
+			Horizon:     server.eventSeriesHorizon(),
+		}
+		if req.RRule != nil {
+			seriesArg.Rrule = pgtype.Text{
+				String: *req.RRule,
+				Valid:  true,
+			}
+		}
+
+		result, err := server.store.UpdateEventSeriesTx(ctx, seriesArg)
+		if err != nil {
+			eventSeriesErrorResponse(ctx, err)
+			return
+		}
+
+		if scope == db.SeriesScopeThis {
+			ctx.JSON(http.StatusOK, newEventResponse(result.Events[0]))
+			return
+		}
+		ctx.JSON(http.StatusOK, newEventSeriesResponse(result.Series, result.Events))
+		return
+	}
+
+	event, err = server.store.UpdateEvent(ctx, arg)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
 			return
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -258,10 +653,54 @@ func (server *Server) deleteEvent(ctx *gin.Context) {
 		return
 	}
 
-	err = server.store.DeleteEvent(ctx, id)
+	scope, ok := seriesScope(ctx)
+	if !ok {
+		return
+	}
+
+	event, err := server.store.GetEvent(ctx, id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if event.SeriesID.Valid {
+		err = server.store.CancelEventSeriesTx(ctx, db.CancelEventSeriesTxParams{
+			EventID: id,
+			Scope:   scope,
+		})
+		if err != nil {
+			eventSeriesErrorResponse(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusNoContent, nil)
+		return
+	}
+
+	if scope != db.SeriesScopeThis {
+		ctx.JSON(http.StatusBadRequest, errorResponse(db.ErrNotInSeries))
+		return
+	}
+
+	// Deleting would drop bookings without telling anyone, so booked events are cancelled instead
+	bookings, err := server.store.ListAllEventBookings(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if len(bookings) > 0 {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "event has bookings, cancel it instead"})
+		return
+	}
+
+	err = server.store.DeleteEventTx(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrVolunteerHoursLogged) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
 			return
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -280,28 +719,50 @@ func (server *Server) bookEvent(ctx *gin.Context) {
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if eventID < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
 
-	// Check if event exists
-	_, err = server.store.GetEvent(ctx, eventID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+	// The body is optional, and booking without one books for the user alone
+	var req bookEventRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+	if req.PartySize == 0 {
+		req.PartySize = 1
+	}
+	if !checkParty(ctx, req.PartySize, req.PartyNames) {
 		return
 	}
 
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
 	arg := db.BookEventParams{
-		UserID:  authPayload.UserID,
-		EventID: eventID,
+		UserID:     authPayload.UserID,
+		EventID:    eventID,
+		PartySize:  req.PartySize,
+		PartyNames: req.PartyNames,
 	}
 
-	booking, err := server.store.BookEvent(ctx, arg)
+	// The transaction checks the event exists and pays for its ticket if it has a price
+	booking, err := server.store.BookEventTx(ctx, arg)
 	if err != nil {
-		// Check for unique constraint violation (user already booked this event)
-		ctx.JSON(http.StatusConflict, gin.H{"error": "event already booked by user"})
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case db.ErrorCode(err) == db.UniqueViolation:
+			ctx.JSON(http.StatusConflict, gin.H{"error": "event already booked by user"})
+		case errors.Is(err, db.ErrInsufficientBalance), errors.Is(err, db.ErrPartyTooLarge):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, db.ErrInactiveGoal), errors.Is(err, db.ErrEventHasNoGoal), errors.Is(err, db.ErrEventCancelled):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
 		return
 	}
 
@@ -319,20 +780,65 @@ func (server *Server) cancelEventBooking(ctx *gin.Context) {
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
-	arg := db.CancelEventBookingParams{
-		UserID:  authPayload.UserID,
-		EventID: eventID,
+	arg := db.CancelEventBookingTxParams{
+		UserID:       authPayload.UserID,
+		EventID:      eventID,
+		RefundCutoff: server.config.TicketRefundCutoff,
 	}
 
-	err = server.store.CancelEventBooking(ctx, arg)
+	result, err := server.store.CancelEventBookingTx(ctx, arg)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	if result.Refund != nil {
+		ctx.JSON(http.StatusOK, ticketRefundResponse{
+			EventID:    result.Refund.EventID,
+			Amount:     result.Refund.Amount,
+			RefundedAt: result.Refund.RefundedAt,
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusNoContent, nil)
 }
 
+// PUT /events/:id/book/reminders
+func (server *Server) updateBookingReminders(ctx *gin.Context) {
+	eventID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateBookingRemindersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.UpdateEventBookingRemindersParams{
+		UserID:           authPayload.UserID,
+		EventID:          eventID,
+		RemindersEnabled: *req.Enabled,
+	}
+
+	booking, err := server.store.UpdateEventBookingReminders(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newEventBookingResponse(booking))
+}
+
 // GET /events/:id/bookings
 func (server *Server) listEventBookings(ctx *gin.Context) {
 	eventIDStr := ctx.Param("id")
@@ -342,6 +848,11 @@ func (server *Server) listEventBookings(ctx *gin.Context) {
 		return
 	}
 
+	if eventID < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
 	limitStr := ctx.DefaultQuery("limit", "10")
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil {
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/recurrence"
+	"github.com/kholodihor/charity/util"
+)
+
+type createEventSeriesRequest struct {
+	Name string `json:"name" binding:"required"`
+	// Place defaults to the address of the venue
+	Place    string    `json:"place" binding:"required_without=VenueID"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	// VenueID links every occurrence to a venue
+	VenueID *int64 `json:"venue_id" binding:"omitempty,min=1"`
+	// EndsAt is the end of the first occurrence, defaultEventDuration after it starts by default
+	EndsAt *time.Time `json:"ends_at"`
+	// TimeZone is the IANA time zone of the venue, which occurrences keep their local time of day in.
+	// It defaults to the venue's time zone or UTC.
+	TimeZone string `json:"time_zone"`
+	// RRule is an RFC 5545 recurrence rule such as "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=10"
+	RRule       string `json:"rrule" binding:"required"`
+	GoalID      *int64 `json:"goal_id" binding:"omitempty,min=1"`
+	TicketPrice int64  `json:"ticket_price" binding:"min=0"`
+}
+
+type eventSeriesResponse struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Place    string `json:"place"`
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at"`
+	// LocalStartsAt is the start of the first occurrence in the venue's time zone
+	LocalStartsAt string `json:"local_starts_at"`
+	TimeZone      string `json:"time_zone"`
+	RRule         string `json:"rrule"`
+	GoalID        *int64 `json:"goal_id,omitempty"`
+	VenueID       *int64 `json:"venue_id,omitempty"`
+	TicketPrice   int64  `json:"ticket_price"`
+	// Exdates are the starts of cancelled occurrences
+	Exdates   []string `json:"exdates"`
+	CreatedAt string   `json:"created_at"`
+	// Occurrences are the upcoming occurrences, or the ones a change affected
+	Occurrences []eventResponse `json:"occurrences"`
+}
+
+func newEventSeriesResponse(series db.EventSeries, events []db.Event) eventSeriesResponse {
+	response := eventSeriesResponse{
+		ID:            series.ID,
+		Name:          series.Name,
+		Place:         series.Place,
+		StartsAt:      series.StartsAt.UTC().Format(time.RFC3339),
+		EndsAt:        series.EndsAt.UTC().Format(time.RFC3339),
+		LocalStartsAt: util.InTimeZone(series.StartsAt, series.TimeZone).Format(time.RFC3339),
+		TimeZone:      series.TimeZone,
+		RRule:         series.Rrule,
+		TicketPrice:   series.TicketPrice,
+		Exdates:       make([]string, len(series.Exdates)),
+		CreatedAt:     series.CreatedAt.UTC().Format(time.RFC3339),
+		Occurrences:   make([]eventResponse, len(events)),
+	}
+	if series.GoalID.Valid {
+		goalID := series.GoalID.Int64
+		response.GoalID = &goalID
+	}
+	if series.VenueID.Valid {
+		venueID := series.VenueID.Int64
+		response.VenueID = &venueID
+	}
+	for i, exdate := range series.Exdates {
+		response.Exdates[i] = exdate.UTC().Format(time.RFC3339)
+	}
+	for i, event := range events {
+		response.Occurrences[i] = newEventResponse(event)
+	}
+	return response
+}
+
+// seriesScope returns the scope of a change to an event from the scope query parameter
+func seriesScope(ctx *gin.Context) (string, bool) {
+	scope := ctx.DefaultQuery("scope", db.SeriesScopeThis)
+	switch scope {
+	case db.SeriesScopeThis, db.SeriesScopeFollowing, db.SeriesScopeAll:
+		return scope, true
+	}
+	ctx.JSON(http.StatusBadRequest, gin.H{"error": "scope must be this, following or all"})
+	return "", false
+}
+
+// eventSeriesHorizon is how far ahead occurrences of series are created
+func (server *Server) eventSeriesHorizon() time.Time {
+	return time.Now().Add(server.config.EventSeriesHorizon)
+}
+
+// eventSeriesErrorResponse responds with the status of an error from the series transactions
+func eventSeriesErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, recurrence.ErrInvalidRule), errors.Is(err, db.ErrNotInSeries):
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	case errors.Is(err, db.ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+	case db.ErrorCode(err) == db.UniqueViolation:
+		ctx.JSON(http.StatusConflict, gin.H{"error": "an occurrence of the series already starts at that time"})
+	case errors.Is(err, db.ErrVolunteerHoursLogged):
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+// POST /event-series
+func (server *Server) createEventSeries(ctx *gin.Context) {
+	var req createEventSeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := recurrence.Parse(req.RRule); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.TicketPrice > 0 && req.GoalID == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errPaidEventWithoutGoal})
+		return
+	}
+
+	var venueID pgtype.Int8
+	if req.VenueID != nil {
+		venue, ok := server.getEventVenue(ctx, *req.VenueID)
+		if !ok {
+			return
+		}
+		venueID = pgtype.Int8{Int64: venue.ID, Valid: true}
+		if req.Place == "" {
+			req.Place = venuePlace(venue)
+		}
+		if req.TimeZone == "" {
+			req.TimeZone = venue.TimeZone
+		}
+	}
+
+	if req.TimeZone == "" {
+		req.TimeZone = "UTC"
+	}
+	endsAt := req.StartsAt.Add(defaultEventDuration)
+	if req.EndsAt != nil {
+		endsAt = *req.EndsAt
+	}
+	if !checkEventTimes(ctx, req.StartsAt, endsAt, req.TimeZone) {
+		return
+	}
+
+	if !server.checkEventGoal(ctx, req.GoalID) {
+		return
+	}
+
+	arg := db.CreateEventSeriesTxParams{
+		Name:        req.Name,
+		Place:       req.Place,
+		TicketPrice: req.TicketPrice,
+		StartsAt:    req.StartsAt,
+		EndsAt:      endsAt,
+		TimeZone:    req.TimeZone,
+		VenueID:     venueID,
+		Rrule:       req.RRule,
+		Horizon:     server.eventSeriesHorizon(),
+	}
+	if req.GoalID != nil {
+		arg.GoalID = pgtype.Int8{
+			Int64: *req.GoalID,
+			Valid: true,
+		}
+	}
+
+	result, err := server.store.CreateEventSeriesTx(ctx, arg)
+	if err != nil {
+		eventSeriesErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newEventSeriesResponse(result.Series, result.Events))
+}
+
+// GET /event-series/:id
+func (server *Server) getEventSeries(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	limit, err := strconv.ParseInt(ctx.DefaultQuery("limit", "10"), 10, 32)
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	series, err := server.store.GetEventSeries(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "event series not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	events, err := server.store.ListEventSeriesOccurrences(ctx, db.ListEventSeriesOccurrencesParams{
+		SeriesID: series.ID,
+		Since:    time.Now(),
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newEventSeriesResponse(series, events))
+}
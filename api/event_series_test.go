@@ -0,0 +1,770 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/token"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomEventSeries() (db.EventSeries, []db.Event) {
+	fixedTime, _ := time.Parse(time.RFC3339, "2023-01-01T12:00:00Z")
+	series := db.EventSeries{
+		ID:                util.RandomInt(1, 1000),
+		Name:              util.RandomString(10),
+		Place:             util.RandomString(15),
+		StartsAt:          fixedTime.Add(time.Hour * 24),
+		EndsAt:            fixedTime.Add(time.Hour * 26),
+		TimeZone:          "Europe/Kyiv",
+		Rrule:             "FREQ=WEEKLY;COUNT=3",
+		Exdates:           []time.Time{},
+		MaterializedUntil: fixedTime.Add(time.Hour * 24 * 90),
+		CreatedAt:         fixedTime,
+	}
+
+	events := make([]db.Event, 3)
+	for i := range events {
+		start := series.StartsAt.AddDate(0, 0, 7*i)
+		events[i] = db.Event{
+			ID:           util.RandomInt(1, 1000),
+			Name:         series.Name,
+			Place:        series.Place,
+			Date:         start,
+			EndsAt:       start.Add(2 * time.Hour),
+			TimeZone:     series.TimeZone,
+			CreatedAt:    fixedTime,
+			SeriesID:     pgtype.Int8{Int64: series.ID, Valid: true},
+			RecurrenceID: pgtype.Timestamptz{Time: start, Valid: true},
+		}
+	}
+	return series, events
+}
+
+func requireBodyMatchEventSeries(t *testing.T, body *bytes.Buffer, series db.EventSeries, events []db.Event) {
+	var response eventSeriesResponse
+	require.NoError(t, json.Unmarshal(body.Bytes(), &response))
+
+	require.Equal(t, series.ID, response.ID)
+	require.Equal(t, series.Name, response.Name)
+	require.Equal(t, series.Rrule, response.RRule)
+	require.Equal(t, series.StartsAt, parseTime(t, response.StartsAt))
+	require.Len(t, response.Occurrences, len(events))
+	for i, event := range events {
+		require.Equal(t, event.ID, response.Occurrences[i].ID)
+		require.Equal(t, series.ID, *response.Occurrences[i].SeriesID)
+		require.Equal(t, event.RecurrenceID.Time, parseTime(t, *response.Occurrences[i].RecurrenceID))
+	}
+}
+
+func TestCreateEventSeriesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.Role = util.StaffRole
+
+	donor, _ := randomUser(t)
+	donor.ID = user.ID + 1
+	donor.Role = util.DonorRole
+
+	series, events := randomEventSeries()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name":      series.Name,
+				"place":     series.Place,
+				"starts_at": series.StartsAt.Format(time.RFC3339),
+				"rrule":     series.Rrule,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateEventSeriesTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateEventSeriesTxParams) (db.EventSeriesTxResult, error) {
+						require.Equal(t, series.Name, arg.Name)
+						require.Equal(t, series.Place, arg.Place)
+						require.True(t, series.StartsAt.Equal(arg.StartsAt))
+						require.Equal(t, series.Rrule, arg.Rrule)
+						require.False(t, arg.GoalID.Valid)
+						return db.EventSeriesTxResult{Series: series, Events: events}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchEventSeries(t, recorder.Body, series, events)
+			},
+		},
+		{
+			name: "InvalidRule",
+			body: gin.H{
+				"name":      series.Name,
+				"place":     series.Place,
+				"starts_at": series.StartsAt.Format(time.RFC3339),
+				"rrule":     "FREQ=HOURLY",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateEventSeriesTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PaidWithoutGoal",
+			body: gin.H{
+				"name":         series.Name,
+				"place":        series.Place,
+				"starts_at":    series.StartsAt.Format(time.RFC3339),
+				"rrule":        series.Rrule,
+				"ticket_price": 1000,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateEventSeriesTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"name":      series.Name,
+				"place":     series.Place,
+				"starts_at": series.StartsAt.Format(time.RFC3339),
+				"rrule":     series.Rrule,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateEventSeriesTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventSeriesTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"name":      series.Name,
+				"place":     series.Place,
+				"starts_at": series.StartsAt.Format(time.RFC3339),
+				"rrule":     series.Rrule,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateEventSeriesTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NotStaff",
+			body: gin.H{
+				"name":      series.Name,
+				"place":     series.Place,
+				"starts_at": series.StartsAt.Format(time.RFC3339),
+				"rrule":     series.Rrule,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, donor.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+				store.EXPECT().
+					CreateEventSeriesTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/event-series", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetEventSeriesAPI(t *testing.T) {
+	series, events := randomEventSeries()
+
+	testCases := []struct {
+		name          string
+		seriesID      int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			seriesID: series.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEventSeries(gomock.Any(), gomock.Eq(series.ID)).
+					Times(1).
+					Return(series, nil)
+
+				store.EXPECT().
+					ListEventSeriesOccurrences(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(events, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchEventSeries(t, recorder.Body, series, events)
+			},
+		},
+		{
+			name:     "NotFound",
+			seriesID: series.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEventSeries(gomock.Any(), gomock.Eq(series.ID)).
+					Times(1).
+					Return(db.EventSeries{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					ListEventSeriesOccurrences(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/event-series/%d", tc.seriesID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateEventOccurrenceAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.Role = util.StaffRole
+
+	donor, _ := randomUser(t)
+	donor.ID = user.ID + 1
+	donor.Role = util.DonorRole
+
+	series, events := randomEventSeries()
+	event := events[1]
+	single := randomEvent()
+	newName := util.RandomString(10)
+
+	renamed := event
+	renamed.Name = newName
+	renamed.Detached = true
+
+	testCases := []struct {
+		name          string
+		userID        int64
+		eventID       int64
+		query         string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "This",
+			userID:  user.ID,
+			eventID: event.ID,
+			body:    gin.H{"name": newName},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(event.ID)).
+					Times(1).
+					Return(event, nil)
+
+				store.EXPECT().
+					UpdateEventSeriesTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateEventSeriesTxParams) (db.EventSeriesTxResult, error) {
+						require.Equal(t, event.ID, arg.EventID)
+						require.Equal(t, db.SeriesScopeThis, arg.Scope)
+						require.Equal(t, pgtype.Text{String: newName, Valid: true}, arg.Name)
+						return db.EventSeriesTxResult{Series: series, Events: []db.Event{renamed}}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchEvent(t, recorder.Body, renamed)
+			},
+		},
+		{
+			name:    "Following",
+			userID:  user.ID,
+			eventID: event.ID,
+			query:   "?scope=following",
+			body:    gin.H{"name": newName, "rrule": "FREQ=WEEKLY;COUNT=2"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(event.ID)).
+					Times(1).
+					Return(event, nil)
+
+				store.EXPECT().
+					UpdateEventSeriesTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateEventSeriesTxParams) (db.EventSeriesTxResult, error) {
+						require.Equal(t, db.SeriesScopeFollowing, arg.Scope)
+						require.Equal(t, pgtype.Text{String: "FREQ=WEEKLY;COUNT=2", Valid: true}, arg.Rrule)
+						return db.EventSeriesTxResult{Series: series, Events: events[1:]}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchEventSeries(t, recorder.Body, series, events[1:])
+			},
+		},
+		{
+			name:    "RuleForThis",
+			userID:  user.ID,
+			eventID: event.ID,
+			body:    gin.H{"rrule": "FREQ=DAILY"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidScope",
+			userID:  user.ID,
+			eventID: event.ID,
+			query:   "?scope=some",
+			body:    gin.H{"name": newName},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "NotInSeries",
+			userID:  user.ID,
+			eventID: single.ID,
+			query:   "?scope=all",
+			body:    gin.H{"name": newName},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return(single, nil)
+
+				store.EXPECT().
+					UpdateEventSeriesTx(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					UpdateEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "MovesEnd",
+			userID:  user.ID,
+			eventID: single.ID,
+			body:    gin.H{"date": single.Date.Add(time.Hour).Format(time.RFC3339)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return(single, nil)
+
+				arg := db.UpdateEventParams{
+					ID:     single.ID,
+					Date:   pgtype.Timestamptz{Time: single.Date.Add(time.Hour), Valid: true},
+					EndsAt: pgtype.Timestamptz{Time: single.EndsAt.Add(time.Hour), Valid: true},
+				}
+				store.EXPECT().
+					UpdateEvent(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(single, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "EndsBeforeStart",
+			userID:  user.ID,
+			eventID: single.ID,
+			body:    gin.H{"ends_at": single.Date.Add(-time.Hour).Format(time.RFC3339)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return(single, nil)
+
+				store.EXPECT().
+					UpdateEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "SingleEvent",
+			userID:  user.ID,
+			eventID: single.ID,
+			body:    gin.H{"name": newName},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return(single, nil)
+
+				arg := db.UpdateEventParams{
+					ID:   single.ID,
+					Name: pgtype.Text{String: newName, Valid: true},
+				}
+				store.EXPECT().
+					UpdateEvent(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(single, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "NotStaff",
+			userID:  donor.ID,
+			eventID: single.ID,
+			body:    gin.H{"name": newName},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+				store.EXPECT().
+					UpdateEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/events/%d%s", tc.eventID, tc.query)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.userID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteEventOccurrenceAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.Role = util.StaffRole
+
+	donor, _ := randomUser(t)
+	donor.ID = user.ID + 1
+	donor.Role = util.DonorRole
+
+	_, events := randomEventSeries()
+	event := events[1]
+	single := randomEvent()
+
+	testCases := []struct {
+		name          string
+		userID        int64
+		eventID       int64
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "Following",
+			userID:  user.ID,
+			eventID: event.ID,
+			query:   "?scope=following",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(event.ID)).
+					Times(1).
+					Return(event, nil)
+
+				arg := db.CancelEventSeriesTxParams{
+					EventID: event.ID,
+					Scope:   db.SeriesScopeFollowing,
+				}
+				store.EXPECT().
+					CancelEventSeriesTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name:    "SingleEvent",
+			userID:  user.ID,
+			eventID: single.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return(single, nil)
+
+				store.EXPECT().
+					ListAllEventBookings(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return([]db.EventBooking{}, nil)
+
+				store.EXPECT().
+					DeleteEventTx(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name:    "SingleEventHoursLogged",
+			userID:  user.ID,
+			eventID: single.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return(single, nil)
+
+				store.EXPECT().
+					ListAllEventBookings(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return([]db.EventBooking{}, nil)
+
+				store.EXPECT().
+					DeleteEventTx(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return(db.ErrVolunteerHoursLogged)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:    "SingleEventBooked",
+			userID:  user.ID,
+			eventID: single.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return(single, nil)
+
+				// Booked events must be cancelled, so their bookers are told
+				store.EXPECT().
+					ListAllEventBookings(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return([]db.EventBooking{randomEventBooking(util.RandomInt(1, 1000), single.ID)}, nil)
+
+				store.EXPECT().
+					DeleteEventTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:    "NotInSeries",
+			userID:  user.ID,
+			eventID: single.ID,
+			query:   "?scope=all",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(single.ID)).
+					Times(1).
+					Return(single, nil)
+
+				store.EXPECT().
+					DeleteEventTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "NotFound",
+			userID:  user.ID,
+			eventID: event.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(event.ID)).
+					Times(1).
+					Return(db.Event{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					CancelEventSeriesTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "NotStaff",
+			userID:  donor.ID,
+			eventID: single.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+				store.EXPECT().
+					DeleteEventTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/events/%d%s", tc.eventID, tc.query)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.userID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
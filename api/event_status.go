@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/kholodihor/charity/db/sqlc"
+)
+
+type cancelEventRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type postponeEventRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+	// Date reschedules the event; without it the event is postponed until a new date is given
+	Date *time.Time `json:"date"`
+	// EndsAt defaults to keeping the length of the event
+	EndsAt *time.Time `json:"ends_at"`
+	// ReleaseBookings cancels the bookings and refunds paid tickets instead of keeping them for the new date
+	ReleaseBookings bool `json:"release_bookings"`
+}
+
+type eventStatusResponse struct {
+	Event eventResponse `json:"event"`
+	// ReleasedBookings is how many bookings were cancelled, and Refunds the paid tickets given back
+	ReleasedBookings int                    `json:"released_bookings"`
+	Refunds          []ticketRefundResponse `json:"refunds"`
+}
+
+// changeEventStatus runs the status change transaction and responds with the changed event
+func (server *Server) changeEventStatus(ctx *gin.Context, arg db.ChangeEventStatusTxParams) {
+	result, err := server.store.ChangeEventStatusTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, db.ErrEventCancelled), errors.Is(err, db.ErrEventStarted):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	response := eventStatusResponse{
+		Event:            newEventResponse(result.Event),
+		ReleasedBookings: len(result.Released),
+		Refunds:          make([]ticketRefundResponse, len(result.Refunds)),
+	}
+	for i, refund := range result.Refunds {
+		response.Refunds[i] = ticketRefundResponse{
+			EventID:    refund.EventID,
+			Amount:     refund.Amount,
+			RefundedAt: refund.RefundedAt,
+		}
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// POST /events/:id/cancel
+func (server *Server) cancelEvent(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req cancelEventRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	server.changeEventStatus(ctx, db.ChangeEventStatusTxParams{
+		EventID: id,
+		Status:  db.EventStatusCancelled,
+		Reason:  req.Reason,
+	})
+}
+
+// POST /events/:id/postpone
+func (server *Server) postponeEvent(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req postponeEventRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ChangeEventStatusTxParams{
+		EventID:         id,
+		Status:          db.EventStatusPostponed,
+		Reason:          req.Reason,
+		ReleaseBookings: req.ReleaseBookings,
+	}
+
+	if req.Date == nil {
+		if req.EndsAt != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ends_at needs a new date"})
+			return
+		}
+		server.changeEventStatus(ctx, arg)
+		return
+	}
+
+	if !req.Date.After(time.Now()) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "date must be in the future"})
+		return
+	}
+
+	event, err := server.store.GetEvent(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	endsAt := req.Date.Add(event.EndsAt.Sub(event.Date))
+	if req.EndsAt != nil {
+		endsAt = *req.EndsAt
+	}
+	if !checkEventTimes(ctx, *req.Date, endsAt, event.TimeZone) {
+		return
+	}
+
+	arg.Status = db.EventStatusRescheduled
+	arg.Date = pgtype.Timestamptz{Time: *req.Date, Valid: true}
+	arg.EndsAt = pgtype.Timestamptz{Time: endsAt, Valid: true}
+	server.changeEventStatus(ctx, arg)
+}
@@ -0,0 +1,423 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/token"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelEventAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.Role = util.StaffRole
+
+	donor, _ := randomUser(t)
+	donor.ID = user.ID + 1
+	donor.Role = util.DonorRole
+
+	event := randomEvent()
+	booking := randomEventBooking(util.RandomInt(1, 1000), event.ID)
+	refund := db.TicketRefund{
+		ID:         util.RandomInt(1, 1000),
+		UserID:     booking.UserID.Int64,
+		EventID:    event.ID,
+		Amount:     2500,
+		RefundedAt: event.CreatedAt,
+	}
+
+	cancelled := event
+	cancelled.Status = db.EventStatusCancelled
+	cancelled.StatusReason = "Venue flooded"
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"reason": "Venue flooded"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				arg := db.ChangeEventStatusTxParams{
+					EventID: event.ID,
+					Status:  db.EventStatusCancelled,
+					Reason:  "Venue flooded",
+				}
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ChangeEventStatusTxResult{
+						Event:    cancelled,
+						Released: []db.EventBooking{booking},
+						Refunds:  []db.TicketRefund{refund},
+					}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response eventStatusResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, cancelled.ID, response.Event.ID)
+				require.Equal(t, db.EventStatusCancelled, response.Event.Status)
+				require.Equal(t, "Venue flooded", response.Event.StatusReason)
+				require.Equal(t, 1, response.ReleasedBookings)
+				require.Len(t, response.Refunds, 1)
+				require.Equal(t, refund.Amount, response.Refunds[0].Amount)
+			},
+		},
+		{
+			name: "MissingReason",
+			body: gin.H{},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyCancelled",
+			body: gin.H{"reason": "Venue flooded"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ChangeEventStatusTxResult{}, db.ErrEventCancelled)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "Started",
+			body: gin.H{"reason": "Venue flooded"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ChangeEventStatusTxResult{}, db.ErrEventStarted)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"reason": "Venue flooded"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ChangeEventStatusTxResult{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"reason": "Venue flooded"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ChangeEventStatusTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{"reason": "Venue flooded"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NotStaff",
+			body: gin.H{"reason": "Venue flooded"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, donor.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/events/%d/cancel", event.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestPostponeEventAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.Role = util.StaffRole
+
+	donor, _ := randomUser(t)
+	donor.ID = user.ID + 1
+	donor.Role = util.DonorRole
+
+	event := randomEvent()
+	newDate := time.Now().AddDate(0, 0, 7).Truncate(time.Second).UTC()
+
+	rescheduled := event
+	rescheduled.Status = db.EventStatusRescheduled
+	rescheduled.Date = newDate
+	rescheduled.EndsAt = newDate.Add(event.EndsAt.Sub(event.Date))
+
+	testCases := []struct {
+		name          string
+		userID        int64
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Postponed",
+			userID: user.ID,
+			body:   gin.H{"reason": "Storm warning"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				arg := db.ChangeEventStatusTxParams{
+					EventID: event.ID,
+					Status:  db.EventStatusPostponed,
+					Reason:  "Storm warning",
+				}
+				postponed := event
+				postponed.Status = db.EventStatusPostponed
+
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ChangeEventStatusTxResult{Event: postponed}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response eventStatusResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, db.EventStatusPostponed, response.Event.Status)
+				require.Zero(t, response.ReleasedBookings)
+				require.Empty(t, response.Refunds)
+			},
+		},
+		{
+			name:   "Rescheduled",
+			userID: user.ID,
+			body:   gin.H{"reason": "Storm warning", "date": newDate, "release_bookings": true},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(event.ID)).
+					Times(1).
+					Return(event, nil)
+
+				// The event keeps its length
+				arg := db.ChangeEventStatusTxParams{
+					EventID:         event.ID,
+					Status:          db.EventStatusRescheduled,
+					Reason:          "Storm warning",
+					Date:            pgtype.Timestamptz{Time: newDate, Valid: true},
+					EndsAt:          pgtype.Timestamptz{Time: rescheduled.EndsAt, Valid: true},
+					ReleaseBookings: true,
+				}
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ChangeEventStatusTxResult{Event: rescheduled}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response eventStatusResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, db.EventStatusRescheduled, response.Event.Status)
+				require.WithinDuration(t, newDate, parseTime(t, response.Event.Date), time.Second)
+			},
+		},
+		{
+			name:   "PastDate",
+			userID: user.ID,
+			body:   gin.H{"reason": "Storm warning", "date": time.Now().Add(-time.Hour)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "EndsBeforeStart",
+			userID: user.ID,
+			body:   gin.H{"reason": "Storm warning", "date": newDate, "ends_at": newDate.Add(-time.Hour)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(event.ID)).
+					Times(1).
+					Return(event, nil)
+
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "EndsAtWithoutDate",
+			userID: user.ID,
+			body:   gin.H{"reason": "Storm warning", "ends_at": newDate},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound",
+			userID: user.ID,
+			body:   gin.H{"reason": "Storm warning", "date": newDate},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEvent(gomock.Any(), gomock.Eq(event.ID)).
+					Times(1).
+					Return(db.Event{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "NotStaff",
+			userID: donor.ID,
+			body:   gin.H{"reason": "Storm warning"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+				store.EXPECT().
+					ChangeEventStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/events/%d/postpone", event.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.userID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
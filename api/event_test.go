@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	mockdb "github.com/kholodihor/charity/db/mock"
 	db "github.com/kholodihor/charity/db/sqlc"
@@ -24,6 +26,14 @@ import (
 func TestCreateEventAPI(t *testing.T) {
 	user, _ := randomUser(t)
 	event := randomEvent()
+	goal := randomGoal()
+	goalEvent := event
+	goalEvent.GoalID = pgtype.Int8{Int64: goal.ID, Valid: true}
+	paidEvent := goalEvent
+	paidEvent.TicketPrice = 2500
+	localEvent := event
+	localEvent.EndsAt = event.Date.Add(90 * time.Minute)
+	localEvent.TimeZone = "Europe/Kyiv"
 
 	testCases := []struct {
 		name          string
@@ -44,9 +54,11 @@ func TestCreateEventAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				arg := db.CreateEventParams{
-					Name:  event.Name,
-					Place: event.Place,
-					Date:  event.Date,
+					Name:     event.Name,
+					Place:    event.Place,
+					Date:     event.Date,
+					EndsAt:   event.EndsAt,
+					TimeZone: event.TimeZone,
 				}
 
 				store.EXPECT().
@@ -59,6 +71,200 @@ func TestCreateEventAPI(t *testing.T) {
 				requireBodyMatchEvent(t, recorder.Body, event)
 			},
 		},
+		{
+			name: "WithGoal",
+			body: gin.H{
+				"name":    event.Name,
+				"place":   event.Place,
+				"date":    event.Date.Format("2006-01-02T15:04:05Z"),
+				"goal_id": goal.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGoal(gomock.Any(), gomock.Eq(goal.ID)).
+					Times(1).
+					Return(goal, nil)
+
+				arg := db.CreateEventParams{
+					Name:     event.Name,
+					Place:    event.Place,
+					Date:     event.Date,
+					GoalID:   pgtype.Int8{Int64: goal.ID, Valid: true},
+					EndsAt:   event.EndsAt,
+					TimeZone: event.TimeZone,
+				}
+
+				store.EXPECT().
+					CreateEvent(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(goalEvent, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchEvent(t, recorder.Body, goalEvent)
+			},
+		},
+		{
+			name: "Paid",
+			body: gin.H{
+				"name":         event.Name,
+				"place":        event.Place,
+				"date":         event.Date.Format("2006-01-02T15:04:05Z"),
+				"goal_id":      goal.ID,
+				"ticket_price": paidEvent.TicketPrice,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGoal(gomock.Any(), gomock.Eq(goal.ID)).
+					Times(1).
+					Return(goal, nil)
+
+				arg := db.CreateEventParams{
+					Name:        event.Name,
+					Place:       event.Place,
+					Date:        event.Date,
+					GoalID:      pgtype.Int8{Int64: goal.ID, Valid: true},
+					TicketPrice: paidEvent.TicketPrice,
+					EndsAt:      event.EndsAt,
+					TimeZone:    event.TimeZone,
+				}
+
+				store.EXPECT().
+					CreateEvent(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(paidEvent, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchEvent(t, recorder.Body, paidEvent)
+			},
+		},
+		{
+			name: "TimeZone",
+			body: gin.H{
+				"name":      event.Name,
+				"place":     event.Place,
+				"date":      "2023-01-02T14:00:00+02:00",
+				"ends_at":   "2023-01-02T15:30:00+02:00",
+				"time_zone": localEvent.TimeZone,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateEventParams) (db.Event, error) {
+						require.True(t, localEvent.Date.Equal(arg.Date))
+						require.True(t, localEvent.EndsAt.Equal(arg.EndsAt))
+						require.Equal(t, localEvent.TimeZone, arg.TimeZone)
+						return localEvent, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response eventResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, "2023-01-02T12:00:00Z", response.Date)
+				require.Equal(t, "2023-01-02T13:30:00Z", response.EndsAt)
+				require.Equal(t, "2023-01-02T14:00:00+02:00", response.LocalDate)
+				require.Equal(t, "2023-01-02T15:30:00+02:00", response.LocalEndsAt)
+				require.Equal(t, "Europe/Kyiv", response.TimeZone)
+				require.Equal(t, int64(90), response.DurationMinutes)
+			},
+		},
+		{
+			name: "EndsBeforeStart",
+			body: gin.H{
+				"name":    event.Name,
+				"place":   event.Place,
+				"date":    event.Date.Format(time.RFC3339),
+				"ends_at": event.Date.Format(time.RFC3339),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidTimeZone",
+			body: gin.H{
+				"name":      event.Name,
+				"place":     event.Place,
+				"date":      event.Date.Format(time.RFC3339),
+				"time_zone": "Europe/Atlantis",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PaidWithoutGoal",
+			body: gin.H{
+				"name":         event.Name,
+				"place":        event.Place,
+				"date":         event.Date.Format("2006-01-02T15:04:05Z"),
+				"ticket_price": paidEvent.TicketPrice,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "GoalNotFound",
+			body: gin.H{
+				"name":    event.Name,
+				"place":   event.Place,
+				"date":    event.Date.Format("2006-01-02T15:04:05Z"),
+				"goal_id": goal.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGoal(gomock.Any(), gomock.Eq(goal.ID)).
+					Times(1).
+					Return(db.Goal{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					CreateEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
 		{
 			name: "NoAuthorization",
 			body: gin.H{
@@ -154,6 +360,7 @@ func TestBookEventAPI(t *testing.T) {
 	testCases := []struct {
 		name          string
 		eventID       int64
+		body          gin.H
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(recorder *httptest.ResponseRecorder)
@@ -166,12 +373,13 @@ func TestBookEventAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				arg := db.BookEventParams{
-					UserID:  user.ID,
-					EventID: event.ID,
+					UserID:    user.ID,
+					EventID:   event.ID,
+					PartySize: 1,
 				}
 
 				store.EXPECT().
-					BookEvent(gomock.Any(), gomock.Eq(arg)).
+					BookEventTx(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
 					Return(booking, nil)
 			},
@@ -179,6 +387,71 @@ func TestBookEventAPI(t *testing.T) {
 				require.Equal(t, http.StatusCreated, recorder.Code)
 			},
 		},
+		{
+			name:    "Party",
+			eventID: event.ID,
+			body:    gin.H{"party_size": 3, "party_names": []string{"Olena", "Taras"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.BookEventParams{
+					UserID:     user.ID,
+					EventID:    event.ID,
+					PartySize:  3,
+					PartyNames: []string{"Olena", "Taras"},
+				}
+				party := booking
+				party.PartySize = arg.PartySize
+				party.PartyNames = arg.PartyNames
+
+				store.EXPECT().
+					BookEventTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(party, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response eventBookingResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, int32(3), response.PartySize)
+				require.Equal(t, []string{"Olena", "Taras"}, response.PartyNames)
+			},
+		},
+		{
+			name:    "TooManyPartyNames",
+			eventID: event.ID,
+			body:    gin.H{"party_size": 2, "party_names": []string{"Olena", "Taras"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BookEventTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "PartyTooLarge",
+			eventID: event.ID,
+			body:    gin.H{"party_size": 12},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BookEventTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrPartyTooLarge)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name:    "NoAuthorization",
 			eventID: event.ID,
@@ -186,13 +459,100 @@ func TestBookEventAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					BookEvent(gomock.Any(), gomock.Any()).
+					BookEventTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name:    "Paid",
+			eventID: event.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				paid := booking
+				paid.DonationID = pgtype.Int8{Int64: util.RandomInt(1, 1000), Valid: true}
+
+				store.EXPECT().
+					BookEventTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(paid, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response eventBookingResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.NotNil(t, response.DonationID)
+			},
+		},
+		{
+			name:    "NotFound",
+			eventID: event.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BookEventTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "AlreadyBooked",
+			eventID: event.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BookEventTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, &pgconn.PgError{Code: db.UniqueViolation})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:    "InsufficientBalance",
+			eventID: event.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BookEventTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrInsufficientBalance)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "InactiveGoal",
+			eventID: event.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BookEventTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrInactiveGoal)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
 		{
 			name:    "InternalError",
 			eventID: event.ID,
@@ -201,7 +561,7 @@ func TestBookEventAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					BookEvent(gomock.Any(), gomock.Any()).
+					BookEventTx(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(db.EventBooking{}, sql.ErrConnDone)
 			},
@@ -217,7 +577,7 @@ func TestBookEventAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					BookEvent(gomock.Any(), gomock.Any()).
+					BookEventTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -239,8 +599,131 @@ func TestBookEventAPI(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
+			var body io.Reader
+			if tc.body != nil {
+				data, err := json.Marshal(tc.body)
+				require.NoError(t, err)
+				body = bytes.NewReader(data)
+			}
+
 			url := fmt.Sprintf("/events/%d/book", tc.eventID)
-			request, err := http.NewRequest(http.MethodPost, url, nil)
+			request, err := http.NewRequest(http.MethodPost, url, body)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCancelEventBookingAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	event := randomEvent()
+	booking := randomEventBooking(user.ID, event.ID)
+	refund := db.TicketRefund{
+		ID:         util.RandomInt(1, 1000),
+		UserID:     user.ID,
+		EventID:    event.ID,
+		GoalID:     util.RandomInt(1, 1000),
+		DonationID: util.RandomInt(1, 1000),
+		Amount:     2500,
+		RefundedAt: event.CreatedAt,
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CancelEventBookingTxParams{
+					UserID:       user.ID,
+					EventID:      event.ID,
+					RefundCutoff: ticketRefundCutoffForTest,
+				}
+
+				store.EXPECT().
+					CancelEventBookingTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CancelEventBookingTxResult{Booking: booking}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name: "Refunded",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CancelEventBookingTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CancelEventBookingTxResult{Booking: booking, Refund: &refund}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response ticketRefundResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, refund.EventID, response.EventID)
+				require.Equal(t, refund.Amount, response.Amount)
+				require.WithinDuration(t, refund.RefundedAt, response.RefundedAt, time.Second)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CancelEventBookingTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CancelEventBookingTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CancelEventBookingTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/events/%d/book", event.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
 			require.NoError(t, err)
 
 			tc.setupAuth(t, request, server.tokenMaker)
@@ -251,16 +734,22 @@ func TestBookEventAPI(t *testing.T) {
 }
 
 func TestListEventBookingsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.Role = util.StaffRole
+	donor, _ := randomUser(t)
+	donor.ID = user.ID + 1
+	donor.Role = util.DonorRole
 	event := randomEvent()
 	n := 5
 	bookings := make([]db.ListEventBookingsRow, n)
 	for i := 0; i < n; i++ {
 		user, _ := randomUser(t)
 		bookings[i] = db.ListEventBookingsRow{
-			ID:       util.RandomInt(1, 1000),
-			UserID:   user.ID,
-			EventID:  event.ID,
-			BookedAt: time.Now(),
+			ID:        util.RandomInt(1, 1000),
+			UserID:    pgtype.Int8{Int64: user.ID, Valid: true},
+			EventID:   event.ID,
+			BookedAt:  time.Now(),
+			PartySize: 1,
 			UserName: pgtype.Text{
 				String: user.Name.String,
 				Valid:  true,
@@ -276,6 +765,7 @@ func TestListEventBookingsAPI(t *testing.T) {
 
 	testCases := []struct {
 		name          string
+		userID        int64
 		eventID       int64
 		query         Query
 		buildStubs    func(store *mockdb.MockStore)
@@ -283,12 +773,14 @@ func TestListEventBookingsAPI(t *testing.T) {
 	}{
 		{
 			name:    "OK",
+			userID:  user.ID,
 			eventID: event.ID,
 			query: Query{
 				pageID:   1,
 				pageSize: n,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
 				arg := db.ListEventBookingsParams{
 					EventID: event.ID,
 					Limit:   int32(n),
@@ -307,12 +799,14 @@ func TestListEventBookingsAPI(t *testing.T) {
 		},
 		{
 			name:    "InternalError",
+			userID:  user.ID,
 			eventID: event.ID,
 			query: Query{
 				pageID:   1,
 				pageSize: n,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
 				store.EXPECT().
 					ListEventBookings(gomock.Any(), gomock.Any()).
 					Times(1).
@@ -324,12 +818,14 @@ func TestListEventBookingsAPI(t *testing.T) {
 		},
 		{
 			name:    "InvalidID",
+			userID:  user.ID,
 			eventID: 0,
 			query: Query{
 				pageID:   1,
 				pageSize: n,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
 				store.EXPECT().
 					ListEventBookings(gomock.Any(), gomock.Any()).
 					Times(0)
@@ -338,6 +834,40 @@ func TestListEventBookingsAPI(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name:    "NotStaff",
+			userID:  donor.ID,
+			eventID: event.ID,
+			query: Query{
+				pageID:   1,
+				pageSize: n,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+				store.EXPECT().
+					ListEventBookings(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:    "NoAuthorization",
+			eventID: event.ID,
+			query: Query{
+				pageID:   1,
+				pageSize: n,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListEventBookings(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
@@ -363,6 +893,129 @@ func TestListEventBookingsAPI(t *testing.T) {
 			q.Add("offset", fmt.Sprintf("%d", (tc.query.pageID-1)*tc.query.pageSize))
 			request.URL.RawQuery = q.Encode()
 
+			if tc.userID != 0 {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.userID, time.Minute)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateBookingRemindersAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	event := randomEvent()
+	booking := randomEventBooking(user.ID, event.ID)
+
+	testCases := []struct {
+		name          string
+		eventID       int64
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			eventID: event.ID,
+			body:    gin.H{"enabled": false},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateEventBookingRemindersParams{
+					UserID:           user.ID,
+					EventID:          event.ID,
+					RemindersEnabled: false,
+				}
+
+				store.EXPECT().
+					UpdateEventBookingReminders(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(booking, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got eventBookingResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, booking.ID, got.ID)
+				require.False(t, got.RemindersEnabled)
+			},
+		},
+		{
+			name:    "NotFound",
+			eventID: event.ID,
+			body:    gin.H{"enabled": true},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateEventBookingReminders(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "MissingEnabled",
+			eventID: event.ID,
+			body:    gin.H{},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateEventBookingReminders(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "NoAuthorization",
+			eventID: event.ID,
+			body:    gin.H{"enabled": false},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateEventBookingReminders(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/events/%d/book/reminders", tc.eventID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
 			tc.checkResponse(recorder)
 		})
@@ -381,7 +1034,22 @@ func requireBodyMatchEvent(t *testing.T, body *bytes.Buffer, event db.Event) {
 	require.Equal(t, event.Name, gotEvent.Name)
 	require.Equal(t, event.Place, gotEvent.Place)
 	require.WithinDuration(t, event.Date, parseTime(t, gotEvent.Date), time.Second)
+	require.WithinDuration(t, event.EndsAt, parseTime(t, gotEvent.EndsAt), time.Second)
+	require.WithinDuration(t, event.Date, parseTime(t, gotEvent.LocalDate), time.Second)
+	require.Equal(t, event.TimeZone, gotEvent.TimeZone)
 	require.WithinDuration(t, event.CreatedAt, parseTime(t, gotEvent.CreatedAt), time.Second)
+	if event.GoalID.Valid {
+		require.Equal(t, event.GoalID.Int64, *gotEvent.GoalID)
+	} else {
+		require.Nil(t, gotEvent.GoalID)
+	}
+	require.Equal(t, event.TicketPrice, gotEvent.TicketPrice)
+	require.Equal(t, event.Status, gotEvent.Status)
+	if event.MaxPartySize.Valid {
+		require.Equal(t, event.MaxPartySize.Int32, *gotEvent.MaxPartySize)
+	} else {
+		require.Nil(t, gotEvent.MaxPartySize)
+	}
 }
 
 func requireBodyMatchEventBookings(t *testing.T, body *bytes.Buffer, bookings []db.ListEventBookingsRow) {
@@ -395,7 +1063,9 @@ func requireBodyMatchEventBookings(t *testing.T, body *bytes.Buffer, bookings []
 	require.Len(t, gotBookings, len(bookings))
 	for i, booking := range bookings {
 		require.Equal(t, booking.ID, gotBookings[i].ID)
-		require.Equal(t, booking.UserID, gotBookings[i].UserID)
+		require.Equal(t, booking.UserID.Int64, *gotBookings[i].UserID)
+		require.False(t, gotBookings[i].Guest)
+		require.Equal(t, booking.PartySize, gotBookings[i].PartySize)
 		require.Equal(t, booking.EventID, gotBookings[i].EventID)
 		require.Equal(t, booking.UserEmail, gotBookings[i].UserEmail)
 		if booking.UserName.Valid {
@@ -403,3 +1073,32 @@ func requireBodyMatchEventBookings(t *testing.T, body *bytes.Buffer, bookings []
 		}
 	}
 }
+
+func TestNewEventResponseUTC(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	// Times read in another zone are still given in UTC
+	start := time.Date(2024, 5, 8, 13, 0, 0, 0, kyiv)
+	event := randomEvent()
+	event.CreatedAt = start.Add(-24 * time.Hour)
+	event.SeriesID = pgtype.Int8{Int64: util.RandomInt(1, 1000), Valid: true}
+	event.RecurrenceID = pgtype.Timestamptz{Time: start, Valid: true}
+
+	response := newEventResponse(event)
+	require.Equal(t, "2024-05-07T10:00:00Z", response.CreatedAt)
+	require.Equal(t, "2024-05-08T10:00:00Z", *response.RecurrenceID)
+}
+
+func TestNewEventBookingResponseUTC(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	// Bookings read in another zone are still given in UTC
+	booking := randomEventBooking(util.RandomInt(1, 1000), util.RandomInt(1, 1000))
+	booking.BookedAt = time.Date(2024, 5, 8, 13, 0, 0, 0, kyiv)
+	require.Equal(t, "2024-05-08T10:00:00Z", newEventBookingResponse(booking).BookedAt)
+
+	row := db.ListEventBookingsRow{ID: booking.ID, EventID: booking.EventID, BookedAt: booking.BookedAt}
+	require.Equal(t, "2024-05-08T10:00:00Z", newEventBookingWithUserResponse(row).BookedAt)
+}
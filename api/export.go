@@ -0,0 +1,195 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/export"
+)
+
+// exportBatchSize is the number of donations fetched per query while streaming an export
+const exportBatchSize = 500
+
+const exportDateFormat = "2006-01-02"
+
+var donationExportHeader = []any{
+	"id",
+	"created_at",
+	"goal_id",
+	"goal_title",
+	"amount_cents",
+	"currency",
+	"is_anonymous",
+	"donor_id",
+	"donor_name",
+	"donor_email",
+	"refunded_at",
+}
+
+type exportDonationsRequest struct {
+	Format    string `form:"format"`
+	GoalID    int64  `form:"goal_id" binding:"omitempty,min=1"`
+	From      string `form:"from"`
+	To        string `form:"to"`
+	Anonymous string `form:"anonymous" binding:"omitempty,oneof=true false"`
+}
+
+// GET /admin/donations/export
+func (server *Server) exportDonations(ctx *gin.Context) {
+	var req exportDonationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = export.FormatCSV
+	}
+	if req.Format != export.FormatCSV && req.Format != export.FormatXLSX {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or xlsx"})
+		return
+	}
+
+	arg := db.ListDonationsForExportParams{
+		RowLimit: exportBatchSize,
+	}
+
+	if req.GoalID > 0 {
+		arg.GoalID = pgtype.Int8{Int64: req.GoalID, Valid: true}
+	}
+
+	if req.From != "" {
+		from, err := time.Parse(exportDateFormat, req.From)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date in YYYY-MM-DD format"})
+			return
+		}
+		arg.FromTime = pgtype.Timestamptz{Time: from, Valid: true}
+	}
+
+	if req.To != "" {
+		to, err := time.Parse(exportDateFormat, req.To)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date in YYYY-MM-DD format"})
+			return
+		}
+		// The end date is inclusive
+		arg.ToTime = pgtype.Timestamptz{Time: to.AddDate(0, 0, 1), Valid: true}
+	}
+
+	if arg.FromTime.Valid && arg.ToTime.Valid && !arg.FromTime.Time.Before(arg.ToTime.Time) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+
+	if req.Anonymous != "" {
+		anonymous, _ := strconv.ParseBool(req.Anonymous)
+		arg.IsAnonymous = pgtype.Bool{Bool: anonymous, Valid: true}
+	}
+
+	// Fetch the first batch before writing anything so errors can still be reported as JSON
+	rows, err := server.store.ListDonationsForExport(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	writer, err := export.NewWriter(req.Format, ctx.Writer, "Donations")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	filename := fmt.Sprintf("donations-%s.%s", time.Now().UTC().Format("20060102"), req.Format)
+	ctx.Header("Content-Type", export.ContentType(req.Format))
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Status(http.StatusOK)
+
+	// Headers are already sent, so errors can only cut the response short.
+	// The writer is closed either way to release its resources.
+	defer func() {
+		if err := writer.Close(); err != nil {
+			_ = ctx.Error(err)
+			ctx.Abort()
+		}
+	}()
+
+	if err := server.writeDonationExport(ctx, writer, arg, rows); err != nil {
+		_ = ctx.Error(err)
+		ctx.Abort()
+	}
+}
+
+// writeDonationExport writes the header and every matching donation, fetching them in batches
+func (server *Server) writeDonationExport(ctx *gin.Context, writer export.Writer, arg db.ListDonationsForExportParams, rows []db.ListDonationsForExportRow) error {
+	if err := writer.Write(donationExportHeader); err != nil {
+		return err
+	}
+
+	for {
+		for _, row := range rows {
+			if err := writer.Write(server.donationExportRow(row)); err != nil {
+				return err
+			}
+		}
+
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		ctx.Writer.Flush()
+
+		if len(rows) < exportBatchSize {
+			return nil
+		}
+
+		arg.AfterID = rows[len(rows)-1].ID
+
+		var err error
+		rows, err = server.store.ListDonationsForExport(ctx, arg)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// donationExportRow converts a donation to an export row, redacting the donor of anonymous donations.
+// Offline donations without an account list the donor recorded at import, and refunded ticket donations when they were refunded.
+func (server *Server) donationExportRow(row db.ListDonationsForExportRow) []any {
+	record := []any{
+		row.ID,
+		row.CreatedAt.UTC().Format(time.RFC3339),
+		row.GoalID,
+		row.GoalTitle,
+		row.Amount,
+		server.config.Currency,
+		row.IsAnonymous,
+		"",
+		"Anonymous",
+		"",
+		"",
+	}
+
+	if row.RefundedAt.Valid {
+		record[10] = row.RefundedAt.Time.UTC().Format(time.RFC3339)
+	}
+
+	if row.IsAnonymous {
+		return record
+	}
+
+	if row.UserID.Valid {
+		record[7] = row.UserID.Int64
+		record[8] = row.UserName.String
+		record[9] = row.UserEmail.String
+	} else {
+		record[8] = row.DonorName.String
+		record[9] = row.DonorEmail.String
+	}
+
+	return record
+}
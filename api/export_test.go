@@ -0,0 +1,231 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func randomExportRow(id int64, anonymous bool) db.ListDonationsForExportRow {
+	return db.ListDonationsForExportRow{
+		ID:          id,
+		GoalID:      util.RandomInt(1, 1000),
+		GoalTitle:   util.RandomString(10),
+		Amount:      util.RandomMoney(),
+		IsAnonymous: anonymous,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+		UserID:      pgtype.Int8{Int64: util.RandomInt(1, 1000), Valid: true},
+		UserName:    pgtype.Text{String: util.RandomOwner(), Valid: true},
+		UserEmail:   pgtype.Text{String: util.RandomEmail(), Valid: true},
+	}
+}
+
+func TestExportDonationsAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	donor, _ := randomUser(t)
+	donor.Role = util.DonorRole
+
+	named := randomExportRow(1, false)
+	anonymous := randomExportRow(2, true)
+	anonymous.RefundedAt = pgtype.Timestamptz{Time: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), Valid: true}
+
+	offline := randomExportRow(3, false)
+	offline.UserID = pgtype.Int8{}
+	offline.UserName = pgtype.Text{}
+	offline.UserEmail = pgtype.Text{}
+	offline.DonorName = pgtype.Text{String: util.RandomOwner(), Valid: true}
+	offline.DonorEmail = pgtype.Text{String: util.RandomEmail(), Valid: true}
+
+	testCases := []struct {
+		name          string
+		userID        int64
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "CSV",
+			userID: admin.ID,
+			query:  "?goal_id=7&from=2024-01-01&to=2024-12-31&anonymous=false",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+
+				arg := db.ListDonationsForExportParams{
+					GoalID:      pgtype.Int8{Int64: 7, Valid: true},
+					FromTime:    pgtype.Timestamptz{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					ToTime:      pgtype.Timestamptz{Time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					IsAnonymous: pgtype.Bool{Bool: false, Valid: true},
+					RowLimit:    exportBatchSize,
+				}
+				store.EXPECT().
+					ListDonationsForExport(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.ListDonationsForExportRow{named, anonymous, offline}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "text/csv")
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), ".csv")
+
+				records, err := csv.NewReader(recorder.Body).ReadAll()
+				require.NoError(t, err)
+				require.Len(t, records, 4)
+				require.Equal(t, "id", records[0][0])
+
+				require.Equal(t, named.UserName.String, records[1][8])
+				require.Equal(t, named.UserEmail.String, records[1][9])
+				require.Equal(t, "", records[1][10])
+
+				// Refunded donations stay in the export
+				require.Equal(t, "2024-03-01T10:00:00Z", records[2][10])
+				require.Equal(t, "true", records[2][6])
+				require.Equal(t, "", records[2][7])
+				require.Equal(t, "Anonymous", records[2][8])
+				require.Equal(t, "", records[2][9])
+
+				require.Equal(t, "", records[3][7])
+				require.Equal(t, offline.DonorName.String, records[3][8])
+				require.Equal(t, offline.DonorEmail.String, records[3][9])
+			},
+		},
+		{
+			name:   "Batches",
+			userID: admin.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+
+				batch := make([]db.ListDonationsForExportRow, exportBatchSize)
+				for i := range batch {
+					batch[i] = randomExportRow(int64(i+1), false)
+				}
+
+				first := db.ListDonationsForExportParams{RowLimit: exportBatchSize}
+				second := first
+				second.AfterID = exportBatchSize
+
+				gomock.InOrder(
+					store.EXPECT().ListDonationsForExport(gomock.Any(), gomock.Eq(first)).Times(1).Return(batch, nil),
+					store.EXPECT().ListDonationsForExport(gomock.Any(), gomock.Eq(second)).Times(1).Return([]db.ListDonationsForExportRow{anonymous}, nil),
+				)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				records, err := csv.NewReader(recorder.Body).ReadAll()
+				require.NoError(t, err)
+				require.Len(t, records, exportBatchSize+2)
+			},
+		},
+		{
+			name:   "XLSX",
+			userID: admin.ID,
+			query:  "?format=xlsx",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+				store.EXPECT().
+					ListDonationsForExport(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListDonationsForExportRow{named, anonymous}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), ".xlsx")
+
+				file, err := excelize.OpenReader(bytes.NewReader(recorder.Body.Bytes()))
+				require.NoError(t, err)
+				defer file.Close()
+
+				rows, err := file.GetRows("Donations")
+				require.NoError(t, err)
+				require.Len(t, rows, 3)
+				require.Equal(t, named.UserEmail.String, rows[1][9])
+				require.Equal(t, "Anonymous", rows[2][8])
+			},
+		},
+		{
+			name:   "InvalidFormat",
+			userID: admin.ID,
+			query:  "?format=pdf",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+				store.EXPECT().ListDonationsForExport(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "InvalidDateRange",
+			userID: admin.ID,
+			query:  "?from=2024-02-01&to=2024-01-01",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+				store.EXPECT().ListDonationsForExport(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "NotAdmin",
+			userID: donor.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+				store.EXPECT().ListDonationsForExport(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "InternalError",
+			userID: admin.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+				store.EXPECT().
+					ListDonationsForExport(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, pgx.ErrTxClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/donations/export"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.userID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/kholodihor/charity/db/sqlc"
+)
+
+// Buckets of the goal donation series
+const (
+	statsIntervalHour = "hour"
+	statsIntervalDay  = "day"
+	statsIntervalWeek = "week"
+)
+
+// statsMaxBuckets caps the length of a donation series
+const statsMaxBuckets = 1000
+
+// statsDefaultBuckets is the length of the series when no start is given
+const statsDefaultBuckets = 30
+
+type goalStatsRequest struct {
+	Interval string `form:"interval" binding:"omitempty,oneof=hour day week"`
+	From     string `form:"from"`
+	To       string `form:"to"`
+	Top      int32  `form:"top" binding:"omitempty,min=1,max=50"`
+}
+
+type goalStatsBucket struct {
+	Start         time.Time `json:"start"`
+	DonationCount int64     `json:"donation_count"`
+	TotalAmount   int64     `json:"total_amount"`
+}
+
+type goalTopDonor struct {
+	UserID        int64  `json:"user_id"`
+	Name          string `json:"name"`
+	TotalAmount   int64  `json:"total_amount"`
+	DonationCount int64  `json:"donation_count"`
+}
+
+type goalStatsResponse struct {
+	GoalID          int64             `json:"goal_id"`
+	TargetAmount    int64             `json:"target_amount"`
+	CollectedAmount int64             `json:"collected_amount"`
+	PercentFunded   *float64          `json:"percent_funded"`
+	DonationCount   int64             `json:"donation_count"`
+	DonorCount      int64             `json:"donor_count"`
+	AverageAmount   int64             `json:"average_amount"`
+	MedianAmount    int64             `json:"median_amount"`
+	LargestAmount   int64             `json:"largest_amount"`
+	Interval        string            `json:"interval"`
+	From            time.Time         `json:"from"`
+	To              time.Time         `json:"to"`
+	Series          []goalStatsBucket `json:"series"`
+	TopDonors       []goalTopDonor    `json:"top_donors"`
+}
+
+// GET /goals/:id/stats
+func (server *Server) getGoalStats(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req goalStatsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Interval == "" {
+		req.Interval = statsIntervalDay
+	}
+	if req.Top == 0 {
+		req.Top = 10
+	}
+
+	to := time.Now().UTC()
+	if req.To != "" {
+		to, err = parseDateOrTime(req.To)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD) or an RFC 3339 timestamp"})
+			return
+		}
+		// An end date includes the whole day
+		if len(req.To) == len(exportDateFormat) {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+
+	// Buckets are aligned so the first one covers the start of the range
+	from := addStatsBuckets(truncateStatsBucket(to, req.Interval), req.Interval, 1-statsDefaultBuckets)
+	if req.From != "" {
+		from, err = parseDateOrTime(req.From)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD) or an RFC 3339 timestamp"})
+			return
+		}
+		from = truncateStatsBucket(from, req.Interval)
+	}
+
+	if !from.Before(to) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+	if !addStatsBuckets(from, req.Interval, statsMaxBuckets).After(to) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "the date range has too many buckets for the interval", "max_buckets": statsMaxBuckets})
+		return
+	}
+
+	goal, err := server.store.GetGoal(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "goal not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	stats, err := server.store.GetGoalDonationStats(ctx, goal.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	series, err := server.store.ListGoalDonationSeries(ctx, db.ListGoalDonationSeriesParams{
+		Bucket:   req.Interval,
+		GoalID:   goal.ID,
+		FromTime: from,
+		ToTime:   to,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	topDonors, err := server.store.ListGoalTopDonors(ctx, db.ListGoalTopDonorsParams{
+		GoalID: goal.ID,
+		Limit:  req.Top,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := goalStatsResponse{
+		GoalID:          goal.ID,
+		CollectedAmount: goal.CollectedAmount,
+		DonationCount:   stats.DonationCount,
+		DonorCount:      stats.DonorCount,
+		AverageAmount:   stats.AverageAmount,
+		MedianAmount:    stats.MedianAmount,
+		LargestAmount:   stats.LargestAmount,
+		Interval:        req.Interval,
+		From:            from,
+		To:              to,
+		Series:          fillStatsSeries(series, from, to, req.Interval),
+		TopDonors:       make([]goalTopDonor, 0, len(topDonors)),
+	}
+
+	if goal.TargetAmount.Valid {
+		response.TargetAmount = goal.TargetAmount.Int64
+	}
+	response.PercentFunded = percentFunded(goal.CollectedAmount, goal.TargetAmount)
+
+	for _, donor := range topDonors {
+		response.TopDonors = append(response.TopDonors, goalTopDonor{
+			UserID:        donor.UserID,
+			Name:          donor.UserName.String,
+			TotalAmount:   donor.TotalAmount,
+			DonationCount: donor.DonationCount,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// truncateStatsBucket returns the start of the UTC bucket containing t, matching date_trunc
+func truncateStatsBucket(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	switch interval {
+	case statsIntervalHour:
+		return t.Truncate(time.Hour)
+	case statsIntervalWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		// Weeks start on Monday
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func addStatsBuckets(t time.Time, interval string, n int) time.Time {
+	switch interval {
+	case statsIntervalHour:
+		return t.Add(time.Duration(n) * time.Hour)
+	case statsIntervalWeek:
+		return t.AddDate(0, 0, 7*n)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+// fillStatsSeries returns one bucket per interval between from and to, with zeros where there were no donations
+func fillStatsSeries(rows []db.ListGoalDonationSeriesRow, from, to time.Time, interval string) []goalStatsBucket {
+	totals := make(map[time.Time]db.ListGoalDonationSeriesRow, len(rows))
+	for _, row := range rows {
+		totals[row.BucketStart.UTC()] = row
+	}
+
+	series := []goalStatsBucket{}
+	for start := truncateStatsBucket(from, interval); start.Before(to); start = addStatsBuckets(start, interval, 1) {
+		row := totals[start]
+		series = append(series, goalStatsBucket{
+			Start:         start,
+			DonationCount: row.DonationCount,
+			TotalAmount:   row.TotalAmount,
+		})
+	}
+
+	return series
+}
+
+// percentFunded returns collected as a percentage of target rounded to two decimals,
+// or nil when the goal has no target
+func percentFunded(collected int64, target pgtype.Int8) *float64 {
+	if !target.Valid || target.Int64 <= 0 {
+		return nil
+	}
+	percent := math.Round(float64(collected)*10000/float64(target.Int64)) / 100
+	return &percent
+}
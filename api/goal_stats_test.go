@@ -0,0 +1,183 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGoalStatsAPI(t *testing.T) {
+	goal := randomGoal()
+	goal.TargetAmount = pgtype.Int8{Int64: 80000, Valid: true}
+	goal.CollectedAmount = 20000
+
+	stats := db.GetGoalDonationStatsRow{
+		DonationCount: 4,
+		DonorCount:    3,
+		TotalAmount:   20000,
+		AverageAmount: 5000,
+		MedianAmount:  4000,
+		LargestAmount: 10000,
+	}
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?from=2024-03-01&to=2024-03-03&top=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(goal, nil)
+				store.EXPECT().GetGoalDonationStats(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(stats, nil)
+
+				arg := db.ListGoalDonationSeriesParams{
+					Bucket:   statsIntervalDay,
+					GoalID:   goal.ID,
+					FromTime: from,
+					ToTime:   to,
+				}
+				store.EXPECT().
+					ListGoalDonationSeries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.ListGoalDonationSeriesRow{
+						{BucketStart: from, DonationCount: 1, TotalAmount: 10000},
+						{BucketStart: from.AddDate(0, 0, 2), DonationCount: 3, TotalAmount: 10000},
+					}, nil)
+
+				store.EXPECT().
+					ListGoalTopDonors(gomock.Any(), gomock.Eq(db.ListGoalTopDonorsParams{GoalID: goal.ID, Limit: 2})).
+					Times(1).
+					Return([]db.ListGoalTopDonorsRow{
+						{UserID: 1, UserName: pgtype.Text{String: "Jane", Valid: true}, TotalAmount: 10000, DonationCount: 1},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response goalStatsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+
+				require.Equal(t, goal.ID, response.GoalID)
+				require.NotNil(t, response.PercentFunded)
+				require.Equal(t, 25.0, *response.PercentFunded)
+				require.Equal(t, stats.DonorCount, response.DonorCount)
+				require.Equal(t, stats.MedianAmount, response.MedianAmount)
+
+				// Days without donations are filled with zeros
+				require.Len(t, response.Series, 3)
+				require.Equal(t, int64(10000), response.Series[0].TotalAmount)
+				require.Zero(t, response.Series[1].TotalAmount)
+				require.True(t, from.AddDate(0, 0, 1).Equal(response.Series[1].Start))
+				require.Equal(t, int64(3), response.Series[2].DonationCount)
+
+				require.Equal(t, []goalTopDonor{{UserID: 1, Name: "Jane", TotalAmount: 10000, DonationCount: 1}}, response.TopDonors)
+			},
+		},
+		{
+			name:  "WeeklyNoTarget",
+			query: "?interval=week&from=2024-03-06&to=2024-03-20",
+			buildStubs: func(store *mockdb.MockStore) {
+				noTarget := goal
+				noTarget.TargetAmount = pgtype.Int8{}
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(noTarget, nil)
+				store.EXPECT().GetGoalDonationStats(gomock.Any(), gomock.Any()).Times(1).Return(db.GetGoalDonationStatsRow{}, nil)
+
+				// Weeks start on Monday
+				arg := db.ListGoalDonationSeriesParams{
+					Bucket:   statsIntervalWeek,
+					GoalID:   goal.ID,
+					FromTime: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+					ToTime:   time.Date(2024, 3, 21, 0, 0, 0, 0, time.UTC),
+				}
+				store.EXPECT().ListGoalDonationSeries(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.ListGoalDonationSeriesRow{}, nil)
+				store.EXPECT().ListGoalTopDonors(gomock.Any(), gomock.Any()).Times(1).Return([]db.ListGoalTopDonorsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response goalStatsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Nil(t, response.PercentFunded)
+				require.Len(t, response.Series, 3)
+				require.Empty(t, response.TopDonors)
+			},
+		},
+		{
+			name:  "InvalidInterval",
+			query: "?interval=month",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "TooManyBuckets",
+			query: "?interval=hour&from=2020-01-01&to=2024-01-01",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidRange",
+			query: "?from=2024-03-05&to=2024-03-01",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(db.Goal{}, db.ErrRecordNotFound)
+				store.EXPECT().GetGoalDonationStats(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/goals/%d/stats%s", goal.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/pubsub"
+)
+
+const (
+	// goalStreamHeartbeat is how often an idle stream sends a comment so proxies keep it open
+	goalStreamHeartbeat = 15 * time.Second
+
+	goalStreamEvent = "progress"
+)
+
+type goalProgressResponse struct {
+	GoalID          int64    `json:"goal_id"`
+	CollectedAmount int64    `json:"collected_amount"`
+	TargetAmount    int64    `json:"target_amount"`
+	PercentFunded   *float64 `json:"percent_funded"`
+	DonationCount   int64    `json:"donation_count"`
+	DonorCount      int64    `json:"donor_count"`
+}
+
+func newGoalProgressResponse(progress db.GetGoalProgressRow) goalProgressResponse {
+	return goalProgressResponse{
+		GoalID:          progress.ID,
+		CollectedAmount: progress.CollectedAmount,
+		TargetAmount:    progress.TargetAmount.Int64,
+		PercentFunded:   percentFunded(progress.CollectedAmount, progress.TargetAmount),
+		DonationCount:   progress.DonationCount,
+		DonorCount:      progress.DonorCount,
+	}
+}
+
+// goalProgressHub fans goal progress out to the streams open on this replica
+type goalProgressHub struct {
+	mutex sync.Mutex
+	// streams holds an update channel per open stream, by goal ID
+	streams map[int64]map[chan goalProgressResponse]struct{}
+}
+
+func newGoalProgressHub() *goalProgressHub {
+	return &goalProgressHub{
+		streams: make(map[int64]map[chan goalProgressResponse]struct{}),
+	}
+}
+
+// subscribe returns a channel receiving the latest progress of a goal and a function closing the subscription
+func (hub *goalProgressHub) subscribe(goalID int64) (<-chan goalProgressResponse, func()) {
+	updates := make(chan goalProgressResponse, 1)
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	if hub.streams[goalID] == nil {
+		hub.streams[goalID] = make(map[chan goalProgressResponse]struct{})
+	}
+	hub.streams[goalID][updates] = struct{}{}
+
+	return updates, func() {
+		hub.mutex.Lock()
+		defer hub.mutex.Unlock()
+
+		delete(hub.streams[goalID], updates)
+		if len(hub.streams[goalID]) == 0 {
+			delete(hub.streams, goalID)
+		}
+	}
+}
+
+// has reports whether a goal has open streams
+func (hub *goalProgressHub) has(goalID int64) bool {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	return len(hub.streams[goalID]) > 0
+}
+
+// goalIDs returns the goals with open streams
+func (hub *goalProgressHub) goalIDs() []int64 {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	ids := make([]int64, 0, len(hub.streams))
+	for id := range hub.streams {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// publish sends progress to every stream of its goal. A stream that hasn't sent the previous
+// update yet gets it replaced, so slow clients skip to the latest progress.
+func (hub *goalProgressHub) publish(progress goalProgressResponse) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for updates := range hub.streams[progress.GoalID] {
+		select {
+		case <-updates:
+		default:
+		}
+		updates <- progress
+	}
+}
+
+// GET /goals/:id/stream
+func (server *Server) streamGoalProgress(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Subscribe first so a donation committed while loading the progress isn't missed
+	updates, unsubscribe := server.goalStreams.subscribe(id)
+	defer unsubscribe()
+
+	progress, err := server.store.GetGoalProgress(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "goal not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// Stop nginx from buffering the stream
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.SSEvent(goalStreamEvent, newGoalProgressResponse(progress))
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(goalStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case update := <-updates:
+			ctx.SSEvent(goalStreamEvent, update)
+		case <-heartbeat.C:
+			if _, err := ctx.Writer.WriteString(": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+// StreamGoalProgress pushes goal progress to the open streams whenever receiver gets the ID of a goal
+// whose collected amount changed, until ctx is done. Every replica runs it on its own listener,
+// so streams are updated no matter which replica took the donation.
+// Every donation and refund changes a collected amount, so the leaderboard cache is invalidated here too.
+func (server *Server) StreamGoalProgress(ctx context.Context, receiver pubsub.Receiver) {
+	receiveIDNotifications(ctx, receiver, "goal progress", func(ctx context.Context, goalID int64) {
+		server.leaderboards.invalidate()
+		server.publishGoalProgress(ctx, goalID)
+	}, func(ctx context.Context) {
+		// Refresh every open stream and leaderboard since updates may have been missed
+		server.leaderboards.invalidate()
+		for _, id := range server.goalStreams.goalIDs() {
+			server.publishGoalProgress(ctx, id)
+		}
+	})
+}
+
+func (server *Server) publishGoalProgress(ctx context.Context, goalID int64) {
+	if !server.goalStreams.has(goalID) {
+		return
+	}
+
+	progress, err := server.store.GetGoalProgress(ctx, goalID)
+	if err != nil {
+		if ctx.Err() == nil && !errors.Is(err, db.ErrRecordNotFound) {
+			log.Printf("load progress of goal %d failed: %v", goalID, err)
+		}
+		return
+	}
+
+	server.goalStreams.publish(newGoalProgressResponse(progress))
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReceiver hands out the payloads sent on its channel
+type fakeReceiver struct {
+	payloads chan string
+}
+
+func (receiver *fakeReceiver) Receive(ctx context.Context) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case payload := <-receiver.payloads:
+		return payload, nil
+	}
+}
+
+// readSSEvent reads the next event from a stream, skipping comments
+func readSSEvent(t *testing.T, reader *bufio.Reader) (string, goalProgressResponse) {
+	var event string
+	var progress goalProgressResponse
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+
+		switch {
+		case line == "" && event != "":
+			return event, progress
+		case strings.HasPrefix(line, "event:"):
+			event = line[len("event:"):]
+		case strings.HasPrefix(line, "data:"):
+			require.NoError(t, json.Unmarshal([]byte(line[len("data:"):]), &progress))
+		}
+	}
+}
+
+func TestStreamGoalProgressAPI(t *testing.T) {
+	goal := randomGoal()
+	initial := db.GetGoalProgressRow{
+		ID:              goal.ID,
+		CollectedAmount: 2500,
+		TargetAmount:    pgtype.Int8{Int64: 10000, Valid: true},
+		DonationCount:   1,
+		DonorCount:      1,
+	}
+	updated := initial
+	updated.CollectedAmount = 5000
+	updated.DonationCount = 2
+	updated.DonorCount = 2
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().GetGoalProgress(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(initial, nil),
+		store.EXPECT().GetGoalProgress(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(updated, nil),
+	)
+
+	server := newTestServer(t, store)
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	receiver := &fakeReceiver{payloads: make(chan string)}
+	go server.StreamGoalProgress(ctx, receiver)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/goals/%d/stream", httpServer.URL, goal.ID), nil)
+	require.NoError(t, err)
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Contains(t, response.Header.Get("Content-Type"), "text/event-stream")
+
+	reader := bufio.NewReader(response.Body)
+
+	event, progress := readSSEvent(t, reader)
+	require.Equal(t, goalStreamEvent, event)
+	require.Equal(t, int64(2500), progress.CollectedAmount)
+	require.Equal(t, 25.0, *progress.PercentFunded)
+
+	// Notifications about goals without open streams are ignored
+	receiver.payloads <- strconv.FormatInt(goal.ID+1, 10)
+	receiver.payloads <- strconv.FormatInt(goal.ID, 10)
+
+	event, progress = readSSEvent(t, reader)
+	require.Equal(t, goalStreamEvent, event)
+	require.Equal(t, int64(5000), progress.CollectedAmount)
+	require.Equal(t, int64(2), progress.DonorCount)
+	require.Equal(t, 50.0, *progress.PercentFunded)
+}
+
+func TestStreamGoalProgressErrorsAPI(t *testing.T) {
+	goal := randomGoal()
+
+	testCases := []struct {
+		name          string
+		goalID        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "NotFound",
+			goalID: strconv.FormatInt(goal.ID, 10),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetGoalProgress(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(db.GetGoalProgressRow{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "InternalError",
+			goalID: strconv.FormatInt(goal.ID, 10),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetGoalProgress(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(db.GetGoalProgressRow{}, pgx.ErrTxClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:   "InvalidID",
+			goalID: "abc",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetGoalProgress(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/goals/"+tc.goalID+"/stream", nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+
+			// Failed streams don't stay subscribed
+			require.Empty(t, server.goalStreams.goalIDs())
+		})
+	}
+}
+
+func TestGoalProgressHub(t *testing.T) {
+	hub := newGoalProgressHub()
+
+	updates, unsubscribe := hub.subscribe(1)
+	other, unsubscribeOther := hub.subscribe(2)
+	defer unsubscribeOther()
+	require.True(t, hub.has(1))
+	require.ElementsMatch(t, []int64{1, 2}, hub.goalIDs())
+
+	// A slow stream only gets the latest progress
+	hub.publish(goalProgressResponse{GoalID: 1, CollectedAmount: 100})
+	hub.publish(goalProgressResponse{GoalID: 1, CollectedAmount: 200})
+	select {
+	case progress := <-updates:
+		require.Equal(t, int64(200), progress.CollectedAmount)
+	case <-time.After(time.Second):
+		t.Fatal("no update received")
+	}
+	require.Empty(t, updates)
+	require.Empty(t, other)
+
+	unsubscribe()
+	require.False(t, hub.has(1))
+	require.Equal(t, []int64{2}, hub.goalIDs())
+}
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/notify"
+	"github.com/kholodihor/charity/ticket"
+)
+
+type createGuestBookingRequest struct {
+	Name  string `json:"name" binding:"required,max=100"`
+	Email string `json:"email" binding:"required,email"`
+	// PartySize is how many people the booking is for, the guest included, 1 by default
+	PartySize  int32    `json:"party_size" binding:"omitempty,min=1"`
+	PartyNames []string `json:"party_names" binding:"omitempty,dive,required,max=100"`
+	// Locale is the language of the confirmation email, the default locale if left out
+	Locale string `json:"locale"`
+}
+
+type guestBookingCodeRequest struct {
+	// Code is the confirmation code from the link emailed to the guest
+	Code string `form:"code" binding:"required"`
+}
+
+type guestBookingResponse struct {
+	ID         int64    `json:"id"`
+	EventID    int64    `json:"event_id"`
+	Name       string   `json:"name"`
+	Email      string   `json:"email"`
+	PartySize  int32    `json:"party_size"`
+	PartyNames []string `json:"party_names"`
+	BookedAt   string   `json:"booked_at"`
+	// ConfirmedAt is null until the guest confirms the booking from the emailed link
+	ConfirmedAt *string `json:"confirmed_at"`
+	// TicketCode is scanned at the door, and shown as a QR code by GET /guest-bookings/ticket
+	TicketCode string `json:"ticket_code"`
+}
+
+// guestBookingAccepted is the response to every guest booking that might have been made, so the
+// response doesn't tell who has booked an event
+var guestBookingAccepted = gin.H{"message": "check your email to confirm the booking"}
+
+// guestBookingConfirmPage is opened from the emailed link. Mail scanners and link previews fetch
+// links, so the page only posts the code back when the guest confirms.
+var guestBookingConfirmPage = template.Must(template.New("confirm").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Confirm your booking</title>
+</head>
+<body>
+<h1>Confirm your booking</h1>
+<form method="post" action="{{.Action}}">
+<input type="hidden" name="code" value="{{.Code}}">
+<button type="submit">Confirm booking</button>
+</form>
+</body>
+</html>
+`))
+
+// GuestBookingURL returns the link that confirms a guest booking, emailed to the guest
+func (server *Server) GuestBookingURL(booking db.EventBooking) string {
+	code := server.tickets.ConfirmationCode(ticket.Ticket{BookingID: booking.ID, EventID: booking.EventID})
+	return server.guestBookingConfirmURL() + "?code=" + url.QueryEscape(code)
+}
+
+func (server *Server) guestBookingConfirmURL() string {
+	return strings.TrimSuffix(server.config.PublicURL, "/") + "/guest-bookings/confirm"
+}
+
+func (server *Server) newGuestBookingResponse(booking db.EventBooking) guestBookingResponse {
+	response := guestBookingResponse{
+		ID:         booking.ID,
+		EventID:    booking.EventID,
+		Name:       booking.GuestName.String,
+		Email:      booking.GuestEmail.String,
+		PartySize:  booking.PartySize,
+		PartyNames: booking.PartyNames,
+		BookedAt:   booking.BookedAt.UTC().Format(time.RFC3339),
+		TicketCode: server.tickets.Code(ticket.Ticket{BookingID: booking.ID, EventID: booking.EventID}),
+	}
+	if booking.ConfirmedAt.Valid {
+		confirmedAt := booking.ConfirmedAt.Time.UTC().Format(time.RFC3339)
+		response.ConfirmedAt = &confirmedAt
+	}
+	return response
+}
+
+// parseGuestBookingCode responds with an error and returns false unless the request has a valid
+// confirmation code
+func (server *Server) parseGuestBookingCode(ctx *gin.Context) (db.GuestBookingTxParams, bool) {
+	var req guestBookingCodeRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.GuestBookingTxParams{}, false
+	}
+
+	booking, err := server.tickets.ParseConfirmation(req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+		return db.GuestBookingTxParams{}, false
+	}
+	return db.GuestBookingTxParams{BookingID: booking.BookingID, EventID: booking.EventID}, true
+}
+
+// POST /events/:id/guest-bookings
+func (server *Server) createGuestBooking(ctx *gin.Context) {
+	eventID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req createGuestBookingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.PartySize == 0 {
+		req.PartySize = 1
+	}
+	if !checkParty(ctx, req.PartySize, req.PartyNames) {
+		return
+	}
+	if req.Locale == "" {
+		req.Locale = notify.DefaultLocale
+	}
+	if !slices.Contains(notify.Locales(), req.Locale) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unsupported locale, use one of: " + strings.Join(notify.Locales(), ", ")})
+		return
+	}
+
+	_, err = server.store.CreateGuestBookingTx(ctx, db.CreateGuestBookingParams{
+		EventID:     eventID,
+		PartySize:   req.PartySize,
+		PartyNames:  req.PartyNames,
+		GuestName:   req.Name,
+		GuestEmail:  req.Email,
+		GuestLocale: req.Locale,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrGuestAlreadyBooked):
+			// The guest already has the booking, and saying so would tell anyone who has booked
+			ctx.JSON(http.StatusAccepted, guestBookingAccepted)
+		case errors.Is(err, db.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case errors.Is(err, db.ErrPartyTooLarge):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		case errors.Is(err, db.ErrEventCancelled), errors.Is(err, db.ErrEventStarted), errors.Is(err, db.ErrGuestBookingPaid):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, guestBookingAccepted)
+}
+
+// GET /guest-bookings/confirm
+func (server *Server) getGuestBookingConfirmation(ctx *gin.Context) {
+	var req guestBookingCodeRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var buf bytes.Buffer
+	err := guestBookingConfirmPage.Execute(&buf, struct{ Action, Code string }{
+		Action: server.guestBookingConfirmURL(),
+		Code:   req.Code,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// The code is in the URL, so it isn't passed on to other sites
+	ctx.Header("Referrer-Policy", "no-referrer")
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// POST /guest-bookings/confirm
+func (server *Server) confirmGuestBooking(ctx *gin.Context) {
+	arg, ok := server.parseGuestBookingCode(ctx)
+	if !ok {
+		return
+	}
+
+	booking, err := server.store.ConfirmGuestBookingTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+		case errors.Is(err, db.ErrEventCancelled), errors.Is(err, db.ErrEventStarted):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.newGuestBookingResponse(booking))
+}
+
+// GET /guest-bookings/ticket
+func (server *Server) getGuestBookingTicket(ctx *gin.Context) {
+	arg, ok := server.parseGuestBookingCode(ctx)
+	if !ok {
+		return
+	}
+
+	format := ctx.DefaultQuery("format", ticketFormatPNG)
+	if format != ticketFormatPNG && format != ticketFormatJSON {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unsupported ticket format, use png or json"})
+		return
+	}
+
+	booking, err := server.store.GetEventBookingByID(ctx, arg.BookingID)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	// Unconfirmed bookings get no ticket
+	if err != nil || booking.EventID != arg.EventID || booking.UserID.Valid || !booking.ConfirmedAt.Valid {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+		return
+	}
+
+	server.respondTicket(ctx, booking, format)
+}
+
+// DELETE /guest-bookings
+func (server *Server) cancelGuestBooking(ctx *gin.Context) {
+	arg, ok := server.parseGuestBookingCode(ctx)
+	if !ok {
+		return
+	}
+
+	_, err := server.store.CancelGuestBookingTx(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
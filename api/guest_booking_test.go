@@ -0,0 +1,486 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/ticket"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomGuestBooking(eventID int64) db.EventBooking {
+	booking := randomEventBooking(0, eventID)
+	booking.UserID = pgtype.Int8{}
+	booking.GuestName = pgtype.Text{String: util.RandomName(), Valid: true}
+	booking.GuestEmail = pgtype.Text{String: util.RandomEmail(), Valid: true}
+	return booking
+}
+
+// confirmationCode returns the code emailed to the guest of booking
+func confirmationCode(server *Server, booking db.EventBooking) string {
+	return server.tickets.ConfirmationCode(ticket.Ticket{BookingID: booking.ID, EventID: booking.EventID})
+}
+
+func TestCreateGuestBookingAPI(t *testing.T) {
+	event := randomEvent()
+	email := util.RandomEmail()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name":        "Olena",
+				"email":       email,
+				"party_size":  3,
+				"party_names": []string{"Taras"},
+				"locale":      "uk",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateGuestBookingParams{
+					EventID:     event.ID,
+					PartySize:   3,
+					PartyNames:  []string{"Taras"},
+					GuestName:   "Olena",
+					GuestEmail:  email,
+					GuestLocale: "uk",
+				}
+				store.EXPECT().
+					CreateGuestBookingTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(randomGuestBooking(event.ID), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "DefaultsToOneInDefaultLocale",
+			body: gin.H{"name": "Olena", "email": email},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateGuestBookingParams{
+					EventID:     event.ID,
+					PartySize:   1,
+					GuestName:   "Olena",
+					GuestEmail:  email,
+					GuestLocale: "en",
+				}
+				store.EXPECT().
+					CreateGuestBookingTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(randomGuestBooking(event.ID), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			// Booking again with a confirmed email looks the same, so nobody learns who has booked
+			name: "AlreadyBooked",
+			body: gin.H{"name": "Olena", "email": email},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateGuestBookingTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrGuestAlreadyBooked)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidEmail",
+			body: gin.H{"name": "Olena", "email": "olena"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateGuestBookingTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnsupportedLocale",
+			body: gin.H{"name": "Olena", "email": email, "locale": "xx"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateGuestBookingTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TooManyPartyNames",
+			body: gin.H{"name": "Olena", "email": email, "party_names": []string{"Taras"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateGuestBookingTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PartyTooLarge",
+			body: gin.H{"name": "Olena", "email": email, "party_size": 12},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateGuestBookingTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrPartyTooLarge)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PaidEvent",
+			body: gin.H{"name": "Olena", "email": email},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateGuestBookingTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrGuestBookingPaid)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"name": "Olena", "email": email},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateGuestBookingTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			// No authorization is needed
+			url := fmt.Sprintf("/events/%d/guest-bookings", event.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestConfirmGuestBookingAPI(t *testing.T) {
+	event := randomEvent()
+	booking := randomGuestBooking(event.ID)
+
+	testCases := []struct {
+		name          string
+		code          func(server *Server) string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			code: func(server *Server) string {
+				return confirmationCode(server, booking)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.GuestBookingTxParams{BookingID: booking.ID, EventID: event.ID}
+				store.EXPECT().
+					ConfirmGuestBookingTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(booking, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response guestBookingResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, booking.ID, response.ID)
+				require.Equal(t, booking.GuestEmail.String, response.Email)
+
+				scanned, err := server.tickets.Parse(response.TicketCode)
+				require.NoError(t, err)
+				require.Equal(t, booking.ID, scanned.BookingID)
+			},
+		},
+		{
+			// Tickets can't stand in for confirmation codes
+			name: "TicketCode",
+			code: func(server *Server) string {
+				return server.tickets.Code(ticket.Ticket{BookingID: booking.ID, EventID: event.ID})
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ConfirmGuestBookingTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "MissingCode",
+			code: func(server *Server) string {
+				return ""
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ConfirmGuestBookingTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Cancelled",
+			code: func(server *Server) string {
+				return confirmationCode(server, booking)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ConfirmGuestBookingTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "EventCancelled",
+			code: func(server *Server) string {
+				return confirmationCode(server, booking)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ConfirmGuestBookingTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrEventCancelled)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			// The page opened from the emailed link posts the code as a form
+			form := url.Values{"code": {tc.code(server)}}
+			request, err := http.NewRequest(http.MethodPost, "/guest-bookings/confirm", strings.NewReader(form.Encode()))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestGuestBookingConfirmationPageAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Opening the link confirms nothing, since mail scanners open links too
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ConfirmGuestBookingTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	server.config.PublicURL = "https://charity.example.com"
+	booking := randomGuestBooking(util.RandomInt(1, 1000))
+
+	link, err := url.Parse(server.GuestBookingURL(booking))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, link.RequestURI(), nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "text/html; charset=utf-8", recorder.Header().Get("Content-Type"))
+	require.Equal(t, "no-referrer", recorder.Header().Get("Referrer-Policy"))
+
+	page := recorder.Body.String()
+	require.Contains(t, page, `<form method="post" action="https://charity.example.com/guest-bookings/confirm">`)
+	require.Contains(t, page, `value="`+template.HTMLEscapeString(link.Query().Get("code"))+`"`)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/guest-bookings/confirm", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestGuestBookingTicketAPI(t *testing.T) {
+	event := randomEvent()
+	booking := randomGuestBooking(event.ID)
+	unconfirmed := booking
+	unconfirmed.ConfirmedAt = pgtype.Timestamptz{}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEventBookingByID(gomock.Any(), gomock.Eq(booking.ID)).Times(1).Return(booking, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "image/png", recorder.Header().Get("Content-Type"))
+			},
+		},
+		{
+			name: "Unconfirmed",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEventBookingByID(gomock.Any(), gomock.Eq(booking.ID)).Times(1).Return(unconfirmed, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEventBookingByID(gomock.Any(), gomock.Any()).Times(1).Return(db.EventBooking{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/guest-bookings/ticket?code="+url.QueryEscape(confirmationCode(server, booking)), nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCancelGuestBookingAPI(t *testing.T) {
+	event := randomEvent()
+	booking := randomGuestBooking(event.ID)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	arg := db.GuestBookingTxParams{BookingID: booking.ID, EventID: event.ID}
+	store.EXPECT().
+		CancelGuestBookingTx(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(booking, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, "/guest-bookings?code="+url.QueryEscape(confirmationCode(server, booking)), nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+}
+
+func TestGuestBookingURL(t *testing.T) {
+	server := newTestServer(t, mockdb.NewMockStore(gomock.NewController(t)))
+	server.config.PublicURL = "https://charity.example.com/"
+	booking := randomGuestBooking(util.RandomInt(1, 1000))
+
+	link, err := url.Parse(server.GuestBookingURL(booking))
+	require.NoError(t, err)
+	require.Equal(t, "charity.example.com", link.Host)
+	require.Equal(t, "/guest-bookings/confirm", link.Path)
+
+	confirmed, err := server.tickets.ParseConfirmation(link.Query().Get("code"))
+	require.NoError(t, err)
+	require.Equal(t, booking.ID, confirmed.BookingID)
+	require.Equal(t, booking.EventID, confirmed.EventID)
+}
+
+func TestNewGuestBookingResponse(t *testing.T) {
+	server := newTestServer(t, mockdb.NewMockStore(gomock.NewController(t)))
+
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	booking := randomGuestBooking(util.RandomInt(1, 1000))
+	booking.BookedAt = time.Date(2024, 5, 8, 13, 0, 0, 0, kyiv)
+	booking.ConfirmedAt = pgtype.Timestamptz{}
+
+	// Unconfirmed bookings have no confirmation time
+	response := server.newGuestBookingResponse(booking)
+	require.Equal(t, "2024-05-08T10:00:00Z", response.BookedAt)
+	require.Nil(t, response.ConfirmedAt)
+
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"confirmed_at":null`)
+
+	booking.ConfirmedAt = pgtype.Timestamptz{Time: booking.BookedAt.Add(time.Hour), Valid: true}
+	response = server.newGuestBookingResponse(booking)
+	require.Equal(t, "2024-05-08T11:00:00Z", *response.ConfirmedAt)
+}
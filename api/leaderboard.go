@@ -0,0 +1,126 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/kholodihor/charity/db/sqlc"
+)
+
+// leaderboardDefaultName is shown for donors who haven't set a name
+const leaderboardDefaultName = "Supporter"
+
+type leaderboardRequest struct {
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type leaderboardEntry struct {
+	Rank          int    `json:"rank"`
+	Name          string `json:"name"`
+	TotalAmount   int64  `json:"total_amount"`
+	DonationCount int64  `json:"donation_count"`
+}
+
+// leaderboardAnonymous sums the donations that aren't attributed to anyone on the leaderboard
+type leaderboardAnonymous struct {
+	TotalAmount   int64 `json:"total_amount"`
+	DonationCount int64 `json:"donation_count"`
+}
+
+type leaderboardResponse struct {
+	GoalID    *int64               `json:"goal_id,omitempty"`
+	Entries   []leaderboardEntry   `json:"entries"`
+	Anonymous leaderboardAnonymous `json:"anonymous"`
+}
+
+// GET /leaderboard
+func (server *Server) getLeaderboard(ctx *gin.Context) {
+	server.respondLeaderboard(ctx, pgtype.Int8{})
+}
+
+// GET /goals/:id/leaderboard
+func (server *Server) getGoalLeaderboard(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	server.respondLeaderboard(ctx, pgtype.Int8{Int64: id, Valid: true})
+}
+
+func (server *Server) respondLeaderboard(ctx *gin.Context, goalID pgtype.Int8) {
+	var req leaderboardRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	key := fmt.Sprintf("all:%d", req.Limit)
+	if goalID.Valid {
+		key = fmt.Sprintf("goal:%d:%d", goalID.Int64, req.Limit)
+	}
+
+	response, generation, ok := server.leaderboards.get(key)
+	if ok {
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+
+	if goalID.Valid {
+		_, err := server.store.GetGoal(ctx, goalID.Int64)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "goal not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		response.GoalID = &goalID.Int64
+	}
+
+	rows, err := server.store.ListLeaderboard(ctx, db.ListLeaderboardParams{
+		GoalID:   goalID,
+		RowLimit: req.Limit,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	anonymous, err := server.store.GetAnonymousDonationTotals(ctx, goalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response.Entries = make([]leaderboardEntry, 0, len(rows))
+	for i, row := range rows {
+		name := row.UserName.String
+		if name == "" {
+			name = leaderboardDefaultName
+		}
+
+		response.Entries = append(response.Entries, leaderboardEntry{
+			Rank:          i + 1,
+			Name:          name,
+			TotalAmount:   row.TotalAmount,
+			DonationCount: row.DonationCount,
+		})
+	}
+	response.Anonymous = leaderboardAnonymous{
+		TotalAmount:   anonymous.TotalAmount,
+		DonationCount: anonymous.DonationCount,
+	}
+
+	server.leaderboards.set(key, generation, response)
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// leaderboardCache keeps leaderboard responses in memory for a short time.
+// Invalidating bumps a generation number, so a response computed before the
+// invalidation can't be stored after it.
+type leaderboardCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	generation uint64
+	entries    map[string]leaderboardCacheEntry
+}
+
+type leaderboardCacheEntry struct {
+	response  leaderboardResponse
+	expiresAt time.Time
+}
+
+func newLeaderboardCache(ttl time.Duration) *leaderboardCache {
+	return &leaderboardCache{
+		ttl:     ttl,
+		entries: make(map[string]leaderboardCacheEntry),
+	}
+}
+
+// get returns the cached response for key and the current generation
+func (cache *leaderboardCache) get(key string) (leaderboardResponse, uint64, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return leaderboardResponse{}, cache.generation, false
+	}
+	return entry.response, cache.generation, true
+}
+
+// set stores a response unless the cache was invalidated since generation was read
+func (cache *leaderboardCache) set(key string, generation uint64, response leaderboardResponse) {
+	if cache.ttl <= 0 {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if generation != cache.generation {
+		return
+	}
+	cache.entries[key] = leaderboardCacheEntry{
+		response:  response,
+		expiresAt: time.Now().Add(cache.ttl),
+	}
+}
+
+// invalidate drops every cached response
+func (cache *leaderboardCache) invalidate() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.generation++
+	cache.entries = make(map[string]leaderboardCacheEntry)
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func getLeaderboardResponse(t *testing.T, server *Server, url string) (int, leaderboardResponse) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+
+	var response leaderboardResponse
+	if recorder.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	}
+	return recorder.Code, response
+}
+
+func TestGetLeaderboardAPI(t *testing.T) {
+	goal := randomGoal()
+
+	rows := []db.ListLeaderboardRow{
+		{UserID: 1, UserName: pgtype.Text{String: "Jane", Valid: true}, TotalAmount: 50000, DonationCount: 2},
+		{UserID: 2, TotalAmount: 10000, DonationCount: 1},
+	}
+	anonymous := db.GetAnonymousDonationTotalsRow{DonationCount: 3, TotalAmount: 7000}
+
+	testCases := []struct {
+		name          string
+		url           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, code int, response leaderboardResponse)
+	}{
+		{
+			name: "Overall",
+			url:  "/leaderboard?limit=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListLeaderboard(gomock.Any(), gomock.Eq(db.ListLeaderboardParams{RowLimit: 5})).
+					Times(1).
+					Return(rows, nil)
+				store.EXPECT().
+					GetAnonymousDonationTotals(gomock.Any(), gomock.Eq(pgtype.Int8{})).
+					Times(1).
+					Return(anonymous, nil)
+			},
+			checkResponse: func(t *testing.T, code int, response leaderboardResponse) {
+				require.Equal(t, http.StatusOK, code)
+				require.Nil(t, response.GoalID)
+				require.Equal(t, []leaderboardEntry{
+					{Rank: 1, Name: "Jane", TotalAmount: 50000, DonationCount: 2},
+					{Rank: 2, Name: leaderboardDefaultName, TotalAmount: 10000, DonationCount: 1},
+				}, response.Entries)
+				require.Equal(t, leaderboardAnonymous{TotalAmount: 7000, DonationCount: 3}, response.Anonymous)
+			},
+		},
+		{
+			name: "Goal",
+			url:  fmt.Sprintf("/goals/%d/leaderboard", goal.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				goalID := pgtype.Int8{Int64: goal.ID, Valid: true}
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(goal, nil)
+				store.EXPECT().
+					ListLeaderboard(gomock.Any(), gomock.Eq(db.ListLeaderboardParams{GoalID: goalID, RowLimit: 10})).
+					Times(1).
+					Return([]db.ListLeaderboardRow{}, nil)
+				store.EXPECT().
+					GetAnonymousDonationTotals(gomock.Any(), gomock.Eq(goalID)).
+					Times(1).
+					Return(db.GetAnonymousDonationTotalsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, code int, response leaderboardResponse) {
+				require.Equal(t, http.StatusOK, code)
+				require.Equal(t, goal.ID, *response.GoalID)
+				require.Empty(t, response.Entries)
+			},
+		},
+		{
+			name: "GoalNotFound",
+			url:  fmt.Sprintf("/goals/%d/leaderboard", goal.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(db.Goal{}, db.ErrRecordNotFound)
+				store.EXPECT().ListLeaderboard(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, code int, response leaderboardResponse) {
+				require.Equal(t, http.StatusNotFound, code)
+			},
+		},
+		{
+			name: "InvalidLimit",
+			url:  "/leaderboard?limit=1000",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListLeaderboard(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, code int, response leaderboardResponse) {
+				require.Equal(t, http.StatusBadRequest, code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			code, response := getLeaderboardResponse(t, server, tc.url)
+			tc.checkResponse(t, code, response)
+		})
+	}
+}
+
+func TestLeaderboardCacheInvalidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+	server.leaderboards = newLeaderboardCache(time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	receiver := &fakeReceiver{payloads: make(chan string)}
+	go server.StreamGoalProgress(ctx, receiver)
+
+	// Two reads share one query, then a goal progress notification forces the next read to query again
+	store.EXPECT().ListLeaderboard(gomock.Any(), gomock.Any()).Times(2).Return([]db.ListLeaderboardRow{}, nil)
+	store.EXPECT().GetAnonymousDonationTotals(gomock.Any(), gomock.Any()).Times(2).Return(db.GetAnonymousDonationTotalsRow{}, nil)
+
+	for i := 0; i < 2; i++ {
+		code, _ := getLeaderboardResponse(t, server, "/leaderboard")
+		require.Equal(t, http.StatusOK, code)
+	}
+
+	// The second send only completes once the first notification was handled
+	receiver.payloads <- "1"
+	receiver.payloads <- "1"
+	code, _ := getLeaderboardResponse(t, server, "/leaderboard")
+	require.Equal(t, http.StatusOK, code)
+}
+
+func TestLeaderboardCacheGeneration(t *testing.T) {
+	cache := newLeaderboardCache(time.Minute)
+
+	_, generation, ok := cache.get("all:10")
+	require.False(t, ok)
+
+	// A response computed before an invalidation is not stored
+	cache.invalidate()
+	cache.set("all:10", generation, leaderboardResponse{})
+	_, generation, ok = cache.get("all:10")
+	require.False(t, ok)
+
+	cache.set("all:10", generation, leaderboardResponse{Entries: []leaderboardEntry{{Rank: 1}}})
+	response, _, ok := cache.get("all:10")
+	require.True(t, ok)
+	require.Len(t, response.Entries, 1)
+}
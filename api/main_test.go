@@ -17,6 +17,9 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// ticketRefundCutoffForTest is the refund cutoff test servers pass to the store
+const ticketRefundCutoffForTest = 48 * time.Hour
+
 func newTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config{
 		TokenSymmetricKey:     util.RandomString(32),
@@ -25,6 +28,7 @@ func newTestServer(t *testing.T, store db.Store) *Server {
 		MaxAnonymousDonation:  1000000,      // $10,000
 		MaxRegisteredDonation: 5000000,      // $50,000
 		RateLimitPerMinute:    1000,         // High limit for testing
+		TicketRefundCutoff:    ticketRefundCutoffForTest,
 	}
 
 	server, err := NewServer(config, store)
@@ -109,7 +113,10 @@ func randomEvent() db.Event {
 		Name:      util.RandomString(10),
 		Place:     util.RandomString(15),
 		Date:      fixedTime.Add(time.Hour * 24),
+		EndsAt:    fixedTime.Add(time.Hour * 26),
+		TimeZone:  "UTC",
 		CreatedAt: fixedTime,
+		Status:    db.EventStatusScheduled,
 	}
 }
 
@@ -133,10 +140,14 @@ func randomEventBooking(userID, eventID int64) db.EventBooking {
 	// Use a fixed time to avoid timezone issues in tests
 	fixedTime, _ := time.Parse(time.RFC3339, "2023-01-01T12:00:00Z")
 	return db.EventBooking{
-		ID:       util.RandomInt(1, 1000),
-		UserID:   userID,
-		EventID:  eventID,
-		BookedAt: fixedTime,
+		ID:          util.RandomInt(1, 1000),
+		UserID:      pgtype.Int8{Int64: userID, Valid: true},
+		EventID:     eventID,
+		BookedAt:    fixedTime,
+		PartySize:   1,
+		PartyNames:  []string{},
+		GuestLocale: "en",
+		ConfirmedAt: pgtype.Timestamptz{Time: fixedTime, Valid: true},
 	}
 }
 
@@ -3,13 +3,19 @@ package api
 import (
 	"errors"
 	"fmt"
+	"log"
+	"math"
+	"net"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/gin-gonic/gin"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/limiter"
 	"github.com/kholodihor/charity/token"
+	"github.com/kholodihor/charity/util"
 )
 
 const (
@@ -55,111 +61,161 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	}
 }
 
-// Visitor represents a client making requests
-type Visitor struct {
-	requests []time.Time
-	mutex    sync.RWMutex
+// adminMiddleware only lets through authenticated users with the admin role.
+// It must run after authMiddleware.
+func adminMiddleware(store db.Store) gin.HandlerFunc {
+	return roleMiddleware(store, util.AdminRole)
 }
 
-// RateLimiter represents a simple in-memory rate limiter
-type RateLimiter struct {
-	visitors map[string]*Visitor
-	mutex    sync.RWMutex
-	rate     int           // requests per minute
-	window   time.Duration // time window
+// staffMiddleware only lets through authenticated event staff and admins.
+// It must run after authMiddleware.
+func staffMiddleware(store db.Store) gin.HandlerFunc {
+	return roleMiddleware(store, util.StaffRole, util.AdminRole)
 }
 
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(requestsPerMinute int) *RateLimiter {
-	rl := &RateLimiter{
-		visitors: make(map[string]*Visitor),
-		rate:     requestsPerMinute,
-		window:   time.Minute,
+// roleMiddleware only lets through authenticated users with one of roles
+func roleMiddleware(store db.Store, roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		user, err := store.GetUser(ctx, authPayload.UserID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if !slices.Contains(roles, user.Role) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": strings.Join(roles, " or ") + " role required"})
+			return
+		}
+
+		ctx.Next()
 	}
-	
-	// Start cleanup goroutine to remove old visitors
-	go rl.cleanupVisitors()
-	
-	return rl
 }
 
-// Allow checks if a request from the given IP is allowed
-func (rl *RateLimiter) Allow(ip string) bool {
-	rl.mutex.Lock()
-	defer rl.mutex.Unlock()
-	
-	visitor, exists := rl.visitors[ip]
-	if !exists {
-		visitor = &Visitor{
-			requests: make([]time.Time, 0),
+// Rate limit policy names, one per route group
+const (
+	rateLimitPolicyDonations = "donations"
+	rateLimitPolicyEvents    = "events"
+	rateLimitPolicyBookings  = "bookings"
+)
+
+// RateLimitMiddleware creates a gin middleware for rate limiting requests with the given policy.
+// Authenticated users are limited by user ID and everyone else by client IP.
+func RateLimitMiddleware(rateLimiter limiter.Limiter, policy limiter.Policy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := policy.Name + ":" + rateLimitKey(ctx)
+
+		result, err := rateLimiter.Allow(ctx, key, policy)
+		if err != nil {
+			// Fail open so that an unavailable limiter backend doesn't block donations
+			log.Printf("rate limiter error: %v", err)
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+
+		if !result.Allowed {
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":   "rate limit exceeded",
+				"message": "too many requests, please try again later",
+			})
+			return
 		}
-		rl.visitors[ip] = visitor
+
+		ctx.Next()
 	}
-	
-	visitor.mutex.Lock()
-	defer visitor.mutex.Unlock()
-	
-	now := time.Now()
-	cutoff := now.Add(-rl.window)
-	
-	// Remove old requests outside the time window
-	validRequests := make([]time.Time, 0)
-	for _, reqTime := range visitor.requests {
-		if reqTime.After(cutoff) {
-			validRequests = append(validRequests, reqTime)
+}
+
+// rateLimitKey identifies the client making the request
+func rateLimitKey(ctx *gin.Context) string {
+	if payload, exists := ctx.Get(authorizationPayloadKey); exists {
+		if authPayload, ok := payload.(*token.Payload); ok {
+			return fmt.Sprintf("user:%d", authPayload.UserID)
 		}
 	}
-	visitor.requests = validRequests
-	
-	// Check if we're under the rate limit
-	if len(visitor.requests) >= rl.rate {
-		return false
+	return "ip:" + ctx.ClientIP()
+}
+
+// forwardedForHeader is an internal header carrying the "for" addresses of an
+// RFC 7239 Forwarded header in X-Forwarded-For format, which gin can resolve.
+const forwardedForHeader = "X-Charity-Forwarded-For"
+
+// remoteIPHeaders maps the configured client IP headers to the headers gin reads,
+// replacing the Forwarded header with its X-Forwarded-For style translation.
+func remoteIPHeaders(headers []string) []string {
+	result := make([]string, 0, len(headers))
+	for _, header := range headers {
+		if strings.EqualFold(header, "Forwarded") {
+			header = forwardedForHeader
+		}
+		result = append(result, header)
 	}
-	
-	// Add current request
-	visitor.requests = append(visitor.requests, now)
-	return true
+	return result
 }
 
-// cleanupVisitors removes visitors that haven't made requests recently
-func (rl *RateLimiter) cleanupVisitors() {
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
-	
-	for range ticker.C {
-		rl.mutex.Lock()
-		now := time.Now()
-		cutoff := now.Add(-10 * time.Minute) // Remove visitors inactive for 10 minutes
-		
-		for ip, visitor := range rl.visitors {
-			visitor.mutex.RLock()
-			lastRequest := time.Time{}
-			if len(visitor.requests) > 0 {
-				lastRequest = visitor.requests[len(visitor.requests)-1]
+// forwardedMiddleware translates the RFC 7239 Forwarded header so that gin can
+// resolve the client IP from it. Gin only reads it when the peer is a trusted proxy.
+func forwardedMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Never accept the internal header from clients
+		ctx.Request.Header.Del(forwardedForHeader)
+
+		forwarded := ctx.Request.Header.Values("Forwarded")
+		if len(forwarded) > 0 {
+			addresses := parseForwardedFor(strings.Join(forwarded, ","))
+			if len(addresses) > 0 {
+				ctx.Request.Header.Set(forwardedForHeader, strings.Join(addresses, ", "))
 			}
-			visitor.mutex.RUnlock()
-			
-			if lastRequest.Before(cutoff) {
-				delete(rl.visitors, ip)
+		}
+
+		ctx.Next()
+	}
+}
+
+// parseForwardedFor returns the node addresses of the "for" parameters in an
+// RFC 7239 Forwarded header, in the order the proxies appended them.
+// Ports and IPv6 brackets are stripped; obfuscated and unknown nodes are kept as is.
+func parseForwardedFor(header string) []string {
+	var addresses []string
+
+	for _, element := range strings.Split(header, ",") {
+		for _, pair := range strings.Split(element, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || !strings.EqualFold(name, "for") {
+				continue
 			}
+
+			addresses = append(addresses, forwardedNodeAddress(strings.Trim(value, `"`)))
 		}
-		rl.mutex.Unlock()
 	}
+
+	return addresses
 }
 
-// RateLimitMiddleware creates a gin middleware for rate limiting
-func RateLimitMiddleware(rateLimiter *RateLimiter) gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		ip := ctx.ClientIP()
-		
-		if !rateLimiter.Allow(ip) {
-			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error": "rate limit exceeded",
-				"message": "too many requests, please try again later",
-			})
-			return
+// forwardedNodeAddress strips the port from a Forwarded node, e.g. "[2001:db8::1]:4711" or "192.0.2.43:47011".
+func forwardedNodeAddress(node string) string {
+	if strings.HasPrefix(node, "[") {
+		if end := strings.Index(node, "]"); end > 0 {
+			return node[1:end]
 		}
-		
-		ctx.Next()
+		return node
+	}
+
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		return host
 	}
+	return node
 }
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kholodihor/charity/limiter"
+	"github.com/kholodihor/charity/token"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitTestRouter(tokenMaker token.Maker, policy limiter.Policy) *gin.Engine {
+	rateLimiter := limiter.NewMemoryLimiter()
+	handler := func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{})
+	}
+
+	router := gin.New()
+	router.GET("/public", RateLimitMiddleware(rateLimiter, policy), handler)
+	router.GET("/private", authMiddleware(tokenMaker), RateLimitMiddleware(rateLimiter, policy), handler)
+	return router
+}
+
+func TestRateLimitMiddlewareHeaders(t *testing.T) {
+	tokenMaker, err := token.NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	policy := limiter.Policy{Name: "test", Limit: 2, Window: time.Minute}
+	router := newRateLimitTestRouter(tokenMaker, policy)
+
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/public", nil)
+		require.NoError(t, err)
+
+		router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, "2", recorder.Header().Get("RateLimit-Limit"))
+		require.Equal(t, []string{"1", "0"}[i], recorder.Header().Get("RateLimit-Remaining"))
+		require.Empty(t, recorder.Header().Get("Retry-After"))
+	}
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/public", nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "2", recorder.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "60", recorder.Header().Get("Retry-After"))
+}
+
+func TestRateLimitMiddlewareKeysByUser(t *testing.T) {
+	tokenMaker, err := token.NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	policy := limiter.Policy{Name: "test", Limit: 1, Window: time.Minute}
+	router := newRateLimitTestRouter(tokenMaker, policy)
+
+	send := func(path string, userID int64) int {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		if userID > 0 {
+			addAuthorization(t, request, tokenMaker, authorizationTypeBearer, userID, time.Minute)
+		}
+
+		router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// Each user gets their own quota even when sharing an IP address
+	require.Equal(t, http.StatusOK, send("/private", 1))
+	require.Equal(t, http.StatusTooManyRequests, send("/private", 1))
+	require.Equal(t, http.StatusOK, send("/private", 2))
+
+	// Anonymous requests from the same IP are limited separately from users
+	require.Equal(t, http.StatusOK, send("/public", 0))
+	require.Equal(t, http.StatusTooManyRequests, send("/public", 0))
+}
+
+func TestClientIPResolution(t *testing.T) {
+	testCases := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		headers        map[string]string
+		expectedIP     string
+	}{
+		{
+			name:       "NoProxyTrusted",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			expectedIP: "10.0.0.1",
+		},
+		{
+			name:           "UntrustedPeer",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "198.51.100.9:1234",
+			headers:        map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			expectedIP:     "198.51.100.9",
+		},
+		{
+			name:           "TrustedProxyXForwardedFor",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:1234",
+			headers:        map[string]string{"X-Forwarded-For": "203.0.113.7, 10.0.0.2"},
+			expectedIP:     "203.0.113.7",
+		},
+		{
+			name:           "SpoofedXForwardedForPrefix",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:1234",
+			headers:        map[string]string{"X-Forwarded-For": "1.2.3.4, 203.0.113.7"},
+			expectedIP:     "203.0.113.7",
+		},
+		{
+			name:           "TrustedProxyForwarded",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:1234",
+			headers: map[string]string{
+				"Forwarded":       `for=203.0.113.7;proto=https, for="10.0.0.2:8080"`,
+				"X-Forwarded-For": "198.51.100.1",
+			},
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:           "ForwardedIPv6",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:1234",
+			headers:        map[string]string{"Forwarded": `For="[2001:db8:cafe::17]:4711"`},
+			expectedIP:     "2001:db8:cafe::17",
+		},
+		{
+			name:           "InternalHeaderIgnored",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:1234",
+			headers:        map[string]string{forwardedForHeader: "1.2.3.4"},
+			expectedIP:     "10.0.0.1",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			config := util.Config{
+				TokenSymmetricKey: util.RandomString(32),
+				TrustedProxies:    tc.trustedProxies,
+				RemoteIPHeaders:   []string{"Forwarded", "X-Forwarded-For", "X-Real-IP"},
+			}
+			server, err := NewServer(config, nil)
+			require.NoError(t, err)
+
+			var clientIP string
+			server.router.GET("/ip", func(ctx *gin.Context) {
+				clientIP = ctx.ClientIP()
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/ip", nil)
+			require.NoError(t, err)
+			request.RemoteAddr = tc.remoteAddr
+			for key, value := range tc.headers {
+				request.Header.Set(key, value)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectedIP, clientIP)
+		})
+	}
+}
+
+func TestInvalidTrustedProxies(t *testing.T) {
+	config := util.Config{
+		TokenSymmetricKey: util.RandomString(32),
+		TrustedProxies:    []string{"not-a-cidr"},
+	}
+
+	_, err := NewServer(config, nil)
+	require.Error(t, err)
+}
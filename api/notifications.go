@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/kholodihor/charity/pubsub"
+)
+
+// notificationReconnectDelay is how long to wait before listening again after a listener failed
+const notificationReconnectDelay = 5 * time.Second
+
+// receiveIDNotifications calls handle with the ID carried by every notification receiver gets, until ctx is done.
+// Notifications sent while the listener reconnects are lost, so resync is called once it is listening again.
+func receiveIDNotifications(
+	ctx context.Context,
+	receiver pubsub.Receiver,
+	kind string,
+	handle func(ctx context.Context, id int64),
+	resync func(ctx context.Context),
+) {
+	for {
+		payload, err := receiver.Receive(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("receive %s notifications failed: %v", kind, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(notificationReconnectDelay):
+			}
+
+			resync(ctx)
+			continue
+		}
+
+		id, err := strconv.ParseInt(payload, 10, 64)
+		if err != nil {
+			log.Printf("invalid %s notification %q", kind, payload)
+			continue
+		}
+		handle(ctx, id)
+	}
+}
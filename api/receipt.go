@@ -0,0 +1,214 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/receipt"
+	"github.com/kholodihor/charity/token"
+)
+
+// Supported receipt formats
+const (
+	receiptFormatPDF  = "pdf"
+	receiptFormatHTML = "html"
+)
+
+// GET /donations/:id/receipt
+func (server *Server) getDonationReceipt(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	format, ok := receiptFormat(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	donation, err := server.store.GetDonation(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "donation not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !donation.UserID.Valid || donation.UserID.Int64 != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "donation doesn't belong to the authenticated user"})
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	lines, err := server.receiptLines(ctx, []db.Donation{donation})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rcpt, err := server.store.IssueDonationReceiptTx(ctx, donation.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrDonationRefunded) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	doc := server.newReceiptDocument("Donation Receipt", rcpt, user, lines)
+	server.renderReceipt(ctx, doc, format)
+}
+
+// GET /users/me/receipts/:year
+func (server *Server) getAnnualReceipt(ctx *gin.Context) {
+	year, err := strconv.Atoi(ctx.Param("year"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if year < 2000 || year >= time.Now().UTC().Year() {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "annual receipts are available once the year has ended"})
+		return
+	}
+
+	format, ok := receiptFormat(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, err := server.store.GetUser(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.IssueAnnualReceiptTx(ctx, db.IssueAnnualReceiptTxParams{
+		UserID: authPayload.UserID,
+		Year:   int32(year),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrNoDonations) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	lines, err := server.receiptLines(ctx, result.Donations)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	doc := server.newReceiptDocument(fmt.Sprintf("Annual Donation Summary %d", year), result.Receipt, user, lines)
+	server.renderReceipt(ctx, doc, format)
+}
+
+// receiptFormat reads the requested receipt format, responding with an error if it isn't supported
+func receiptFormat(ctx *gin.Context) (string, bool) {
+	format := ctx.DefaultQuery("format", receiptFormatPDF)
+	if format != receiptFormatPDF && format != receiptFormatHTML {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unsupported receipt format, use pdf or html"})
+		return "", false
+	}
+	return format, true
+}
+
+// receiptLines describes each donation with the title of its goal
+func (server *Server) receiptLines(ctx *gin.Context, donations []db.Donation) ([]receipt.Line, error) {
+	goalTitles := make(map[int64]string)
+	lines := make([]receipt.Line, len(donations))
+
+	for i, donation := range donations {
+		title, ok := goalTitles[donation.GoalID]
+		if !ok {
+			goal, err := server.store.GetGoal(ctx, donation.GoalID)
+			if err != nil {
+				return nil, err
+			}
+			title = goal.Title
+			goalTitles[donation.GoalID] = title
+		}
+
+		lines[i] = receipt.Line{
+			Date:        donation.CreatedAt,
+			Description: "Donation to " + title,
+			Amount:      donation.Amount,
+		}
+	}
+
+	return lines, nil
+}
+
+func (server *Server) newReceiptDocument(title string, rcpt db.Receipt, user db.User, lines []receipt.Line) receipt.Document {
+	donorName := user.Email
+	if user.Name.Valid {
+		donorName = user.Name.String
+	}
+
+	return receipt.Document{
+		Number:   rcpt.ReceiptNumber,
+		Title:    title,
+		IssuedAt: rcpt.IssuedAt,
+		Organization: receipt.Organization{
+			Name:    server.config.OrganizationName,
+			Address: server.config.OrganizationAddress,
+			TaxID:   server.config.OrganizationTaxID,
+			Email:   server.config.OrganizationEmail,
+		},
+		DonorName:  donorName,
+		DonorEmail: user.Email,
+		Lines:      lines,
+		// The issued amount, which the lines stored with the receipt add up to
+		Total:    rcpt.Amount,
+		Currency: server.config.Currency,
+	}
+}
+
+func (server *Server) renderReceipt(ctx *gin.Context, doc receipt.Document, format string) {
+	var buf bytes.Buffer
+	var err error
+	var contentType string
+
+	switch format {
+	case receiptFormatHTML:
+		contentType = "text/html; charset=utf-8"
+		err = receipt.RenderHTML(&buf, doc)
+	default:
+		contentType = "application/pdf"
+		err = receipt.RenderPDF(&buf, doc)
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if format == receiptFormatPDF {
+		ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="receipt-%s.pdf"`, doc.Number))
+	}
+
+	ctx.Data(http.StatusOK, contentType, buf.Bytes())
+}
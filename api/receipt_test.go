@@ -0,0 +1,326 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/token"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomReceipt(donation db.Donation) db.Receipt {
+	return db.Receipt{
+		ID:            donation.ID,
+		ReceiptNumber: fmt.Sprintf("%d-%06d", donation.CreatedAt.Year(), donation.ID),
+		UserID:        donation.UserID.Int64,
+		DonationID:    pgtype.Int8{Int64: donation.ID, Valid: true},
+		Year:          int32(donation.CreatedAt.Year()),
+		Kind:          db.ReceiptKindDonation,
+		Amount:        donation.Amount,
+		IssuedAt:      donation.CreatedAt,
+	}
+}
+
+func TestGetDonationReceiptAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	otherUser, _ := randomUser(t)
+	goal := randomGoal()
+	donation := randomDonation(user.ID, goal.ID)
+	rcpt := randomReceipt(donation)
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "PDF",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDonation(gomock.Any(), gomock.Eq(donation.ID)).Times(1).Return(donation, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(goal, nil)
+				store.EXPECT().IssueDonationReceiptTx(gomock.Any(), gomock.Eq(donation.ID)).Times(1).Return(rcpt, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/pdf", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), rcpt.ReceiptNumber)
+				require.True(t, bytes.HasPrefix(recorder.Body.Bytes(), []byte("%PDF")))
+			},
+		},
+		{
+			name:  "HTML",
+			query: "?format=html",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDonation(gomock.Any(), gomock.Eq(donation.ID)).Times(1).Return(donation, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(goal, nil)
+				store.EXPECT().IssueDonationReceiptTx(gomock.Any(), gomock.Eq(donation.ID)).Times(1).Return(rcpt, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+				require.Contains(t, recorder.Body.String(), rcpt.ReceiptNumber)
+				require.Contains(t, recorder.Body.String(), goal.Title)
+			},
+		},
+		{
+			name:  "UnsupportedFormat",
+			query: "?format=docx",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDonation(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotOwner",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, otherUser.ID+1000, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDonation(gomock.Any(), gomock.Eq(donation.ID)).Times(1).Return(donation, nil)
+				store.EXPECT().IssueDonationReceiptTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDonation(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDonation(gomock.Any(), gomock.Eq(donation.ID)).Times(1).Return(db.Donation{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Refunded",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDonation(gomock.Any(), gomock.Eq(donation.ID)).Times(1).Return(donation, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(goal, nil)
+				store.EXPECT().IssueDonationReceiptTx(gomock.Any(), gomock.Eq(donation.ID)).Times(1).Return(db.Receipt{}, db.ErrDonationRefunded)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetDonation(gomock.Any(), gomock.Eq(donation.ID)).Times(1).Return(donation, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(goal, nil)
+				store.EXPECT().IssueDonationReceiptTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Receipt{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/donations/%d/receipt%s", donation.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetAnnualReceiptAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	goal := randomGoal()
+	donation1 := randomDonation(user.ID, goal.ID)
+	donation2 := randomDonation(user.ID, goal.ID)
+	year := donation1.CreatedAt.Year()
+
+	annual := db.Receipt{
+		ID:            1,
+		ReceiptNumber: fmt.Sprintf("%d-000001", year),
+		UserID:        user.ID,
+		Year:          int32(year),
+		Kind:          db.ReceiptKindAnnual,
+		Amount:        donation1.Amount + donation2.Amount,
+		IssuedAt:      time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		year          int
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			year: year,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					IssueAnnualReceiptTx(gomock.Any(), gomock.Eq(db.IssueAnnualReceiptTxParams{UserID: user.ID, Year: int32(year)})).
+					Times(1).
+					Return(db.IssueAnnualReceiptTxResult{Receipt: annual, Donations: []db.Donation{donation1, donation2}}, nil)
+				// Goal titles are looked up once per goal
+				store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(goal, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/pdf", recorder.Header().Get("Content-Type"))
+			},
+		},
+		{
+			name: "NoDonations",
+			year: year,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					IssueAnnualReceiptTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IssueAnnualReceiptTxResult{}, db.ErrNoDonations)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "CurrentYear",
+			year: time.Now().UTC().Year(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IssueAnnualReceiptTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/users/me/receipts/%d", tc.year)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetAnnualReceiptStoredDonationsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	goal := randomGoal()
+	donation1 := randomDonation(user.ID, goal.ID)
+	donation2 := randomDonation(user.ID, goal.ID)
+	year := donation1.CreatedAt.Year()
+
+	// The lines are the donations stored with the receipt, so they add up to its total
+	annual := db.Receipt{
+		ID:            1,
+		ReceiptNumber: fmt.Sprintf("%d-000001", year),
+		UserID:        user.ID,
+		Year:          int32(year),
+		Kind:          db.ReceiptKindAnnual,
+		Amount:        donation1.Amount + donation2.Amount,
+		IssuedAt:      time.Now(),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+	store.EXPECT().
+		IssueAnnualReceiptTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.IssueAnnualReceiptTxResult{Receipt: annual, Donations: []db.Donation{donation1, donation2}}, nil)
+	store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(goal, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/users/me/receipts/%d?format=html", year)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	for _, donation := range []db.Donation{donation1, donation2} {
+		amount := util.FormatCents(donation.Amount, server.config.Currency)
+		require.Contains(t, recorder.Body.String(), `<td class="amount">`+amount+`</td>`)
+	}
+	total := util.FormatCents(annual.Amount, server.config.Currency)
+	require.Contains(t, recorder.Body.String(), `<th class="amount">`+total+`</th>`)
+}
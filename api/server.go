@@ -2,11 +2,16 @@ package api
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kholodihor/charity/challenge"
 	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/limiter"
+	"github.com/kholodihor/charity/ticket"
 	"github.com/kholodihor/charity/token"
 	"github.com/kholodihor/charity/util"
+	"github.com/redis/go-redis/v9"
 )
 
 // Server serves HTTP requests for our banking service.
@@ -14,8 +19,18 @@ type Server struct {
 	config      util.Config
 	store       db.Store
 	tokenMaker  token.Maker
-	rateLimiter *RateLimiter
+	rateLimiter limiter.Limiter
+	policies    map[string]limiter.Policy
+	verifier    challenge.Verifier
+	tickets     *ticket.Signer
 	router      *gin.Engine
+
+	// Leaderboard responses, invalidated whenever donations or donor preferences change
+	leaderboards *leaderboardCache
+	// Live goal progress streams open on this replica
+	goalStreams *goalProgressHub
+	// Live donation feed connections open on this replica
+	donationFeed *donationFeedHub
 }
 
 // NewServer creates a new HTTP server and set up routing.
@@ -25,20 +40,136 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	rateLimiter, err := newRateLimiter(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create rate limiter: %w", err)
+	}
+
+	policies, err := limiter.ParsePolicies(config.RateLimitPolicies)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse rate limit policies: %w", err)
+	}
+
+	verifier, err := newChallengeVerifier(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create challenge verifier: %w", err)
+	}
+
+	ticketSecret := config.TicketSecret
+	if ticketSecret == "" {
+		ticketSecret = config.TokenSymmetricKey
+	}
+	tickets, err := ticket.NewSigner(ticketSecret)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create ticket signer: %w", err)
+	}
+
 	server := &Server{
 		config:      config,
 		store:       store,
 		tokenMaker:  tokenMaker,
-		rateLimiter: NewRateLimiter(config.RateLimitPerMinute),
+		rateLimiter: rateLimiter,
+		policies:    policies,
+		verifier:    verifier,
+		tickets:     tickets,
+
+		leaderboards: newLeaderboardCache(config.LeaderboardCacheTTL),
+		goalStreams:  newGoalProgressHub(),
+		donationFeed: newDonationFeedHub(),
+	}
+
+	err = server.setupRouter()
+	if err != nil {
+		return nil, fmt.Errorf("cannot set up router: %w", err)
 	}
 
-	server.setupRouter()
 	return server, nil
 }
 
-func (server *Server) setupRouter() {
+// newRateLimiter creates the rate limiter for the backend selected in config.
+func newRateLimiter(config util.Config) (limiter.Limiter, error) {
+	switch config.RateLimitBackend {
+	case "", util.RateLimitBackendMemory:
+		return limiter.NewMemoryLimiter(), nil
+	case util.RateLimitBackendRedis:
+		client := redis.NewClient(&redis.Options{Addr: config.RedisAddress})
+		return limiter.NewRedisLimiter(client), nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit backend %q", config.RateLimitBackend)
+	}
+}
+
+// newChallengeVerifier creates the anonymous donation challenge verifier for the provider selected in config.
+// It returns nil when no challenge is required.
+func newChallengeVerifier(config util.Config) (challenge.Verifier, error) {
+	switch config.ChallengeProvider {
+	case "", util.ChallengeProviderNone:
+		return nil, nil
+	case util.ChallengeProviderProofOfWork:
+		difficulty := config.ChallengeDifficulty
+		if difficulty == 0 {
+			difficulty = 20
+		}
+		duration := config.ChallengeDuration
+		if duration == 0 {
+			duration = 5 * time.Minute
+		}
+		guard, err := newChallengeReplayGuard(config)
+		if err != nil {
+			return nil, err
+		}
+		return challenge.NewProofOfWorkVerifier(config.ChallengeSecret, difficulty, duration, guard)
+	case util.ChallengeProviderHCaptcha:
+		return challenge.NewTokenVerifier(challenge.HCaptchaVerifyURL, config.ChallengeSecret), nil
+	case util.ChallengeProviderTurnstile:
+		return challenge.NewTokenVerifier(challenge.TurnstileVerifyURL, config.ChallengeSecret), nil
+	default:
+		return nil, fmt.Errorf("unsupported challenge provider %q", config.ChallengeProvider)
+	}
+}
+
+// newChallengeReplayGuard creates the store of solved proof-of-work challenges for the backend selected in config.
+func newChallengeReplayGuard(config util.Config) (challenge.ReplayGuard, error) {
+	switch config.ChallengeBackend {
+	case "", util.ChallengeBackendMemory:
+		return challenge.NewMemoryReplayGuard(), nil
+	case util.ChallengeBackendRedis:
+		client := redis.NewClient(&redis.Options{Addr: config.RedisAddress})
+		return challenge.NewRedisReplayGuard(client), nil
+	default:
+		return nil, fmt.Errorf("unsupported challenge backend %q", config.ChallengeBackend)
+	}
+}
+
+// rateLimit creates a rate limiting middleware for the named policy.
+// Policies missing from config fall back to the default per-minute limit.
+func (server *Server) rateLimit(name string) gin.HandlerFunc {
+	policy, ok := server.policies[name]
+	if !ok {
+		policy = limiter.Policy{
+			Name:   name,
+			Limit:  server.config.RateLimitPerMinute,
+			Burst:  server.config.RateLimitBurst,
+			Window: time.Minute,
+		}
+	}
+
+	return RateLimitMiddleware(server.rateLimiter, policy)
+}
+
+func (server *Server) setupRouter() error {
 	router := gin.Default()
 
+	// Only trust client IP headers set by the configured proxies
+	err := router.SetTrustedProxies(server.config.TrustedProxies)
+	if err != nil {
+		return err
+	}
+	if len(server.config.RemoteIPHeaders) > 0 {
+		router.RemoteIPHeaders = remoteIPHeaders(server.config.RemoteIPHeaders)
+		router.Use(forwardedMiddleware())
+	}
+
 	// Public routes
 	router.POST("/users", server.createUser)
 	router.POST("/users/login", server.loginUser)
@@ -50,22 +181,43 @@ func (server *Server) setupRouter() {
 	// Public goal routes (read-only)
 	router.GET("/goals", server.listGoals)
 	router.GET("/goals/:id", server.getGoal)
+	router.GET("/goals/:id/stats", server.getGoalStats)
+	router.GET("/goals/:id/leaderboard", server.getGoalLeaderboard)
+	router.GET("/goals/:id/stream", server.streamGoalProgress)
+	router.GET("/leaderboard", server.getLeaderboard)
 
 	// Public event routes (read-only)
 	router.GET("/events", server.listEvents)
+	router.GET("/events.ics", server.getEventsCalendar)
 	router.GET("/events/:id", server.getEvent)
+	router.GET("/event-series/:id", server.getEventSeries)
+	router.GET("/venues", server.listVenues)
+	router.GET("/venues/:id", server.getVenue)
+	router.GET("/events/:id/volunteer-roles", server.listEventVolunteerRoles)
 
 	// Public donation routes (read-only)
 	router.GET("/donations", server.listDonations)
+	router.GET("/donations/feed", server.streamDonationFeed)
 	router.GET("/donations/:id", server.getDonation)
 	
 	// Anonymous donation route (public) with rate limiting
-	router.POST("/donations/anonymous", RateLimitMiddleware(server.rateLimiter), server.createAnonymousDonation)
+	router.POST("/donations/anonymous", server.rateLimit(rateLimitPolicyDonations), server.createAnonymousDonation)
+	router.GET("/donations/anonymous/challenge", server.getDonationChallenge)
 
 	// Public user routes (read-only)
 	router.GET("/users", server.listUsers)
 	router.GET("/users/:id", server.getUser)
 
+	// Personal calendar feeds, authenticated by the secret token in the URL
+	router.GET("/calendar/:token", server.getBookingsCalendar)
+
+	// Guest bookings without an account, authenticated by the confirmation code emailed to the guest
+	router.POST("/events/:id/guest-bookings", server.rateLimit(rateLimitPolicyBookings), server.createGuestBooking)
+	router.GET("/guest-bookings/confirm", server.getGuestBookingConfirmation)
+	router.POST("/guest-bookings/confirm", server.confirmGuestBooking)
+	router.GET("/guest-bookings/ticket", server.getGuestBookingTicket)
+	router.DELETE("/guest-bookings", server.cancelGuestBooking)
+
 	// Protected routes (require authentication)
 	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
 
@@ -74,6 +226,10 @@ func (server *Server) setupRouter() {
 	authRoutes.PUT("/users/me", server.updateCurrentUser)
 	authRoutes.GET("/users/me/donations", server.listUserDonations)
 	authRoutes.GET("/users/me/bookings", server.listUserBookings)
+	authRoutes.GET("/users/me/receipts/:year", server.getAnnualReceipt)
+	authRoutes.POST("/users/me/calendar", server.createCalendarFeed)
+	authRoutes.DELETE("/users/me/calendar", server.deleteCalendarFeed)
+	authRoutes.GET("/users/me/volunteering", server.listUserVolunteerShifts)
 	
 	// Auth management (protected)
 	authRoutes.POST("/auth/logout-all", server.logoutAllDevices)
@@ -84,19 +240,61 @@ func (server *Server) setupRouter() {
 	authRoutes.DELETE("/goals/:id", server.deleteGoal)
 
 	// Donation management with rate limiting
-	authRoutes.POST("/donations", RateLimitMiddleware(server.rateLimiter), server.createDonation)
+	authRoutes.POST("/donations", server.rateLimit(rateLimitPolicyDonations), server.createDonation)
+	authRoutes.GET("/donations/:id/receipt", server.getDonationReceipt)
 
 	// Event management (admin/authenticated users) with rate limiting
-	authRoutes.POST("/events", RateLimitMiddleware(server.rateLimiter), server.createEvent)
-	authRoutes.PUT("/events/:id", server.updateEvent)
-	authRoutes.DELETE("/events/:id", server.deleteEvent)
+	authRoutes.POST("/events", server.rateLimit(rateLimitPolicyEvents), server.createEvent)
 
 	// Event booking management with rate limiting
-	authRoutes.POST("/events/:id/book", RateLimitMiddleware(server.rateLimiter), server.bookEvent)
+	authRoutes.POST("/events/:id/book", server.rateLimit(rateLimitPolicyBookings), server.bookEvent)
 	authRoutes.DELETE("/events/:id/book", server.cancelEventBooking)
-	authRoutes.GET("/events/:id/bookings", server.listEventBookings)
+	authRoutes.PUT("/events/:id/book/reminders", server.updateBookingReminders)
+	authRoutes.GET("/events/:id/ticket", server.getEventTicket)
+
+	// Volunteer sign-ups
+	authRoutes.POST("/events/:id/volunteer-roles/:role_id/signup", server.signUpVolunteer)
+	authRoutes.DELETE("/events/:id/volunteer-roles/:role_id/signup", server.withdrawVolunteer)
+
+	// Door check-in (staff and admins)
+	staffRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), staffMiddleware(server.store))
+	staffRoutes.POST("/events/:id/checkin", server.checkInTicket)
+	staffRoutes.GET("/events/:id/attendance", server.getEventAttendance)
+
+	// Volunteer roles and logged hours (staff and admins)
+	staffRoutes.POST("/events/:id/volunteer-roles", server.createVolunteerRole)
+	staffRoutes.PUT("/events/:id/volunteer-roles/:role_id", server.updateVolunteerRole)
+	staffRoutes.DELETE("/events/:id/volunteer-roles/:role_id", server.deleteVolunteerRole)
+	staffRoutes.GET("/events/:id/volunteers", server.listEventVolunteers)
+	staffRoutes.PUT("/events/:id/volunteers/:shift_id/hours", server.logVolunteerHours)
+
+	// Event changes (staff and admins)
+	staffRoutes.PUT("/events/:id", server.updateEvent)
+	staffRoutes.DELETE("/events/:id", server.deleteEvent)
+	staffRoutes.POST("/events/:id/cancel", server.cancelEvent)
+	staffRoutes.POST("/events/:id/postpone", server.postponeEvent)
+	staffRoutes.GET("/events/:id/bookings", server.listEventBookings)
+	staffRoutes.POST("/event-series", server.rateLimit(rateLimitPolicyEvents), server.createEventSeries)
+
+	// Venue management (staff and admins)
+	staffRoutes.POST("/venues", server.createVenue)
+	staffRoutes.PUT("/venues/:id", server.updateVenue)
+	staffRoutes.DELETE("/venues/:id", server.deleteVenue)
+
+	// Admin routes
+	adminRoutes := router.Group("/admin").Use(authMiddleware(server.tokenMaker), adminMiddleware(server.store))
+	adminRoutes.GET("/dashboard", server.getDashboard)
+	adminRoutes.GET("/events/attendance", server.listEventAttendance)
+	adminRoutes.GET("/donations/export", server.exportDonations)
+	adminRoutes.POST("/donations/import", server.importDonations)
+	adminRoutes.POST("/webhooks", server.createWebhook)
+	adminRoutes.GET("/webhooks", server.listWebhooks)
+	adminRoutes.DELETE("/webhooks/:id", server.deleteWebhook)
+	adminRoutes.GET("/webhooks/:id/deliveries", server.listWebhookDeliveries)
+	adminRoutes.POST("/webhooks/deliveries/:id/redeliver", server.redeliverWebhook)
 
 	server.router = router
+	return nil
 }
 
 // Start runs the HTTP server on a specific address.
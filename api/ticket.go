@@ -0,0 +1,273 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/ticket"
+	"github.com/kholodihor/charity/token"
+)
+
+// Supported ticket formats
+const (
+	ticketFormatPNG  = "png"
+	ticketFormatJSON = "json"
+)
+
+// ticketQRCodeSize is the width and height of ticket QR codes in pixels
+const ticketQRCodeSize = 512
+
+type ticketResponse struct {
+	BookingID int64  `json:"booking_id"`
+	EventID   int64  `json:"event_id"`
+	Code      string `json:"code"`
+}
+
+type checkInRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type checkInResponse struct {
+	BookingID int64 `json:"booking_id"`
+	EventID   int64 `json:"event_id"`
+	// UserID is left out for guest bookings, and UserName is then the guest's name
+	UserID   int64  `json:"user_id,omitempty"`
+	UserName string `json:"user_name"`
+	// PartySize is how many people the ticket admits, and PartyNames who they are besides the booker
+	PartySize   int32     `json:"party_size"`
+	PartyNames  []string  `json:"party_names"`
+	CheckedInAt time.Time `json:"checked_in_at"`
+}
+
+type eventAttendanceResponse struct {
+	EventID   int64  `json:"event_id"`
+	Name      string `json:"name,omitempty"`
+	Place     string `json:"place,omitempty"`
+	Date      string `json:"date,omitempty"`
+	Bookings  int64  `json:"bookings"`
+	CheckedIn int64  `json:"checked_in"`
+	NoShows   int64  `json:"no_shows"`
+	// AttendancePercent is null when the event has no bookings
+	AttendancePercent *float64 `json:"attendance_percent"`
+}
+
+func newEventAttendanceResponse(eventID, bookings, checkedIn int64) eventAttendanceResponse {
+	response := eventAttendanceResponse{
+		EventID:   eventID,
+		Bookings:  bookings,
+		CheckedIn: checkedIn,
+		NoShows:   bookings - checkedIn,
+	}
+	if bookings > 0 {
+		percent := float64(checkedIn) * 100 / float64(bookings)
+		response.AttendancePercent = &percent
+	}
+	return response
+}
+
+type listEventAttendanceRequest struct {
+	Limit  int32 `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int32 `form:"offset" binding:"omitempty,min=0"`
+}
+
+// GET /events/:id/ticket
+func (server *Server) getEventTicket(ctx *gin.Context) {
+	eventID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	format := ctx.DefaultQuery("format", ticketFormatPNG)
+	if format != ticketFormatPNG && format != ticketFormatJSON {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unsupported ticket format, use png or json"})
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	booking, err := server.store.GetEventBooking(ctx, db.GetEventBookingParams{
+		UserID:  authPayload.UserID,
+		EventID: eventID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.respondTicket(ctx, booking, format)
+}
+
+// respondTicket responds with the ticket of booking as a PNG QR code or as JSON
+func (server *Server) respondTicket(ctx *gin.Context, booking db.EventBooking, format string) {
+	code := server.tickets.Code(ticket.Ticket{BookingID: booking.ID, EventID: booking.EventID})
+
+	if format == ticketFormatJSON {
+		ctx.JSON(http.StatusOK, ticketResponse{
+			BookingID: booking.ID,
+			EventID:   booking.EventID,
+			Code:      code,
+		})
+		return
+	}
+
+	image, err := ticket.QRCode(code, ticketQRCodeSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`inline; filename="ticket-%d.png"`, booking.ID))
+	ctx.Data(http.StatusOK, "image/png", image)
+}
+
+// POST /events/:id/checkin
+func (server *Server) checkInTicket(ctx *gin.Context) {
+	eventID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req checkInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scanned, err := server.tickets.Parse(req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if scanned.EventID != eventID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ticket is for another event"})
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	booking, err := server.store.CheckInEventBooking(ctx, db.CheckInEventBookingParams{
+		CheckedInBy: authPayload.UserID,
+		ID:          scanned.BookingID,
+		EventID:     eventID,
+	})
+	if errors.Is(err, db.ErrRecordNotFound) {
+		server.rejectCheckIn(ctx, scanned.BookingID)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	name := booking.GuestName.String
+	if booking.UserID.Valid {
+		user, err := server.store.GetUser(ctx, booking.UserID.Int64)
+		if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		name = user.Name.String
+	}
+
+	ctx.JSON(http.StatusOK, checkInResponse{
+		BookingID:   booking.ID,
+		EventID:     booking.EventID,
+		UserID:      booking.UserID.Int64,
+		UserName:    name,
+		PartySize:   booking.PartySize,
+		PartyNames:  booking.PartyNames,
+		CheckedInAt: booking.CheckedInAt.Time,
+	})
+}
+
+// rejectCheckIn explains why a validly signed ticket couldn't be checked in
+func (server *Server) rejectCheckIn(ctx *gin.Context, bookingID int64) {
+	booking, err := server.store.GetEventBookingByID(ctx, bookingID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			// Cancelled bookings are deleted, and booking again issues a new ticket
+			ctx.JSON(http.StatusGone, gin.H{"error": "booking was cancelled"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusConflict, gin.H{
+		"error":         "ticket already checked in",
+		"checked_in_at": booking.CheckedInAt.Time,
+	})
+}
+
+// GET /events/:id/attendance
+func (server *Server) getEventAttendance(ctx *gin.Context) {
+	eventID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	event, err := server.store.GetEvent(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	attendance, err := server.store.GetEventAttendance(ctx, eventID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := newEventAttendanceResponse(event.ID, attendance.Bookings, attendance.CheckedIn)
+	response.Name = event.Name
+	response.Place = event.Place
+	response.Date = event.Date.UTC().Format(time.RFC3339)
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GET /admin/events/attendance
+func (server *Server) listEventAttendance(ctx *gin.Context) {
+	var req listEventAttendanceRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	events, err := server.store.ListEventAttendance(ctx, db.ListEventAttendanceParams{
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]eventAttendanceResponse, len(events))
+	for i, event := range events {
+		response[i] = newEventAttendanceResponse(event.ID, event.Bookings, event.CheckedIn)
+		response[i].Name = event.Name
+		response[i].Place = event.Place
+		response[i].Date = event.Date.UTC().Format(time.RFC3339)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
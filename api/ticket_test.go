@@ -0,0 +1,392 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/ticket"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEventTicketAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	event := randomEvent()
+	booking := randomEventBooking(user.ID, event.ID)
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "PNG",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEventBooking(gomock.Any(), gomock.Eq(db.GetEventBookingParams{UserID: user.ID, EventID: event.ID})).
+					Times(1).
+					Return(booking, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "image/png", recorder.Header().Get("Content-Type"))
+
+				image, err := png.Decode(bytes.NewReader(recorder.Body.Bytes()))
+				require.NoError(t, err)
+				require.Equal(t, ticketQRCodeSize, image.Bounds().Dx())
+			},
+		},
+		{
+			name:  "JSON",
+			query: "?format=json",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEventBooking(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(booking, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response ticketResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, booking.ID, response.BookingID)
+
+				scanned, err := server.tickets.Parse(response.Code)
+				require.NoError(t, err)
+				require.Equal(t, ticket.Ticket{BookingID: booking.ID, EventID: event.ID}, scanned)
+			},
+		},
+		{
+			name: "NotBooked",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEventBooking(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "UnsupportedFormat",
+			query: "?format=pdf",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEventBooking(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/events/%d/ticket%s", event.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestCheckInTicketAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	staff.Role = util.StaffRole
+
+	donor, _ := randomUser(t)
+	donor.ID = staff.ID + 1
+	donor.Role = util.DonorRole
+
+	event := randomEvent()
+	booking := randomEventBooking(donor.ID, event.ID)
+	checkedIn := booking
+	checkedIn.CheckedInAt = pgtype.Timestamptz{Time: time.Now().UTC().Truncate(time.Second), Valid: true}
+	checkedIn.CheckedInBy = pgtype.Int8{Int64: staff.ID, Valid: true}
+
+	validCode := func(server *Server) string {
+		return server.tickets.Code(ticket.Ticket{BookingID: booking.ID, EventID: event.ID})
+	}
+
+	testCases := []struct {
+		name          string
+		userID        int64
+		code          func(server *Server) string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			userID: staff.ID,
+			code:   validCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().
+					CheckInEventBooking(gomock.Any(), gomock.Eq(db.CheckInEventBookingParams{
+						CheckedInBy: staff.ID,
+						ID:          booking.ID,
+						EventID:     event.ID,
+					})).
+					Times(1).
+					Return(checkedIn, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response checkInResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, booking.ID, response.BookingID)
+				require.Equal(t, donor.ID, response.UserID)
+				require.Equal(t, donor.Name.String, response.UserName)
+				require.True(t, checkedIn.CheckedInAt.Time.Equal(response.CheckedInAt))
+			},
+		},
+		{
+			name:   "AlreadyCheckedIn",
+			userID: staff.ID,
+			code:   validCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().
+					CheckInEventBooking(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetEventBookingByID(gomock.Any(), gomock.Eq(booking.ID)).
+					Times(1).
+					Return(checkedIn, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "CancelledBooking",
+			userID: staff.ID,
+			code:   validCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().
+					CheckInEventBooking(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					GetEventBookingByID(gomock.Any(), gomock.Eq(booking.ID)).
+					Times(1).
+					Return(db.EventBooking{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusGone, recorder.Code)
+			},
+		},
+		{
+			name:   "ForgedCode",
+			userID: staff.ID,
+			code: func(server *Server) string {
+				return fmt.Sprintf("T1.%d.%d.forged", booking.ID, event.ID)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().CheckInEventBooking(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "OtherEvent",
+			userID: staff.ID,
+			code: func(server *Server) string {
+				return server.tickets.Code(ticket.Ticket{BookingID: booking.ID, EventID: event.ID + 1})
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().CheckInEventBooking(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "NotStaff",
+			userID: donor.ID,
+			code:   validCode,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+				store.EXPECT().CheckInEventBooking(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"code": tc.code(server)})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/events/%d/checkin", event.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.userID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetEventAttendanceAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	staff.Role = util.StaffRole
+	event := randomEvent()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+	store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+	store.EXPECT().
+		GetEventAttendance(gomock.Any(), gomock.Eq(event.ID)).
+		Times(1).
+		Return(db.GetEventAttendanceRow{Bookings: 8, CheckedIn: 6}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/events/%d/attendance", event.ID), nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, staff.ID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response eventAttendanceResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, event.ID, response.EventID)
+	require.Equal(t, event.Name, response.Name)
+	require.Equal(t, int64(8), response.Bookings)
+	require.Equal(t, int64(6), response.CheckedIn)
+	require.Equal(t, int64(2), response.NoShows)
+	require.InDelta(t, 75.0, *response.AttendancePercent, 0.001)
+}
+
+func TestListEventAttendanceAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	staff, _ := randomUser(t)
+	staff.ID = admin.ID + 1
+	staff.Role = util.StaffRole
+
+	rows := []db.ListEventAttendanceRow{
+		{ID: 2, Name: "Gala", Place: "Kyiv", Date: time.Now().UTC(), Bookings: 4, CheckedIn: 1},
+		{ID: 1, Name: "Run", Place: "Lviv", Date: time.Now().Add(-time.Hour).UTC()},
+	}
+
+	testCases := []struct {
+		name          string
+		userID        int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			userID: admin.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+				store.EXPECT().
+					ListEventAttendance(gomock.Any(), gomock.Eq(db.ListEventAttendanceParams{Limit: 10, Offset: 0})).
+					Times(1).
+					Return(rows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response []eventAttendanceResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response, 2)
+				require.Equal(t, int64(3), response[0].NoShows)
+				require.InDelta(t, 25.0, *response[0].AttendancePercent, 0.001)
+				// Events without bookings have no attendance rate
+				require.Nil(t, response[1].AttendancePercent)
+			},
+		},
+		{
+			name:   "Staff",
+			userID: staff.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().ListEventAttendance(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/events/attendance", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.userID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -2,14 +2,18 @@ package api
 
 import (
 	"database/sql"
+	"encoding/json"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/notify"
 	"github.com/kholodihor/charity/token"
 	"github.com/kholodihor/charity/util"
 )
@@ -26,11 +30,13 @@ type loginUserRequest struct {
 }
 
 type userResponse struct {
-	ID        int64  `json:"id"`
-	Email     string `json:"email"`
-	Name      string `json:"name"`
-	Balance   int64  `json:"balance"`
-	CreatedAt string `json:"created_at"`
+	ID                   int64  `json:"id"`
+	Email                string `json:"email"`
+	Name                 string `json:"name"`
+	Balance              int64  `json:"balance"`
+	HideFromLeaderboards bool   `json:"hide_from_leaderboards"`
+	Locale               string `json:"locale"`
+	CreatedAt            string `json:"created_at"`
 }
 
 type loginUserResponse struct {
@@ -59,11 +65,13 @@ func newUserResponse(user db.User) userResponse {
 	}
 
 	return userResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      name,
-		Balance:   user.Balance,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		ID:                   user.ID,
+		Email:                user.Email,
+		Name:                 name,
+		Balance:              user.Balance,
+		HideFromLeaderboards: user.HideFromLeaderboards,
+		Locale:               user.Locale,
+		CreatedAt:            user.CreatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
@@ -294,12 +302,25 @@ func (server *Server) getCurrentUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, newUserResponse(user))
 }
 
+// nullableString tells a JSON null, which clears a value, apart from a missing field
+type nullableString struct {
+	Set   bool
+	Value *string
+}
+
+func (s *nullableString) UnmarshalJSON(data []byte) error {
+	s.Set = true
+	return json.Unmarshal(data, &s.Value)
+}
+
 // PUT /users/me
 func (server *Server) updateCurrentUser(ctx *gin.Context) {
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
 	type updateUserRequest struct {
-		Name *string `json:"name"`
+		Name                 nullableString `json:"name"`
+		HideFromLeaderboards *bool          `json:"hide_from_leaderboards"`
+		Locale               *string        `json:"locale"`
 	}
 
 	var req updateUserRequest
@@ -308,17 +329,39 @@ func (server *Server) updateCurrentUser(ctx *gin.Context) {
 		return
 	}
 
-	if req.Name == nil {
+	if !req.Name.Set && req.HideFromLeaderboards == nil && req.Locale == nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "no fields to update"})
 		return
 	}
 
+	if req.Locale != nil && !slices.Contains(notify.Locales(), *req.Locale) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unsupported locale, use one of: " + strings.Join(notify.Locales(), ", ")})
+		return
+	}
+
 	arg := db.UpdateUserParams{
 		ID: authPayload.UserID,
-		Name: pgtype.Text{
-			String: *req.Name,
+	}
+	if req.Name.Set {
+		arg.SetName = true
+		if req.Name.Value != nil {
+			arg.Name = pgtype.Text{
+				String: *req.Name.Value,
+				Valid:  true,
+			}
+		}
+	}
+	if req.HideFromLeaderboards != nil {
+		arg.HideFromLeaderboards = pgtype.Bool{
+			Bool:  *req.HideFromLeaderboards,
+			Valid: true,
+		}
+	}
+	if req.Locale != nil {
+		arg.Locale = pgtype.Text{
+			String: *req.Locale,
 			Valid:  true,
-		},
+		}
 	}
 
 	user, err := server.store.UpdateUser(ctx, arg)
@@ -331,6 +374,9 @@ func (server *Server) updateCurrentUser(ctx *gin.Context) {
 		return
 	}
 
+	// Names and visibility both show up on leaderboards
+	server.leaderboards.invalidate()
+
 	ctx.JSON(http.StatusOK, newUserResponse(user))
 }
 
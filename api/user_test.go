@@ -332,6 +332,144 @@ func TestGetUserAPI(t *testing.T) {
 	}
 }
 
+func TestUpdateCurrentUserLocaleAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	updated := user
+	updated.Locale = "uk"
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"locale": "uk"},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateUserParams{
+					ID:     user.ID,
+					Locale: pgtype.Text{String: "uk", Valid: true},
+				}
+
+				store.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(updated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got userResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, "uk", got.Locale)
+			},
+		},
+		{
+			name: "UnsupportedLocale",
+			body: gin.H{"locale": "xx"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/users/me", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateCurrentUserNameAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	name := util.RandomOwner()
+
+	testCases := []struct {
+		name string
+		body gin.H
+		arg  db.UpdateUserParams
+	}{
+		{
+			name: "SetName",
+			body: gin.H{"name": name},
+			arg: db.UpdateUserParams{
+				ID:      user.ID,
+				SetName: true,
+				Name:    pgtype.Text{String: name, Valid: true},
+			},
+		},
+		{
+			name: "ClearName",
+			body: gin.H{"name": nil},
+			arg: db.UpdateUserParams{
+				ID:      user.ID,
+				SetName: true,
+			},
+		},
+		{
+			name: "KeepName",
+			body: gin.H{"hide_from_leaderboards": true},
+			arg: db.UpdateUserParams{
+				ID:                   user.ID,
+				HideFromLeaderboards: pgtype.Bool{Bool: true, Valid: true},
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				UpdateUser(gomock.Any(), gomock.Eq(tc.arg)).
+				Times(1).
+				Return(user, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/users/me", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
 func randomUser(t *testing.T) (user db.User, password string) {
 	password = util.RandomString(6)
 	hashedPassword, err := util.HashPassword(password)
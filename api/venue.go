@@ -0,0 +1,399 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/util"
+)
+
+// defaultNearbyRadiusKm is the radius of nearby searches without radius_km
+const defaultNearbyRadiusKm = 25
+
+// maxNearbyRadiusKm caps nearby searches, so the latitude band still narrows the venues scanned
+const maxNearbyRadiusKm = 500
+
+// kmPerDegreeLatitude is the length of a degree of latitude on the mean Earth radius
+const kmPerDegreeLatitude = 111.195
+
+type createVenueRequest struct {
+	Name        string `json:"name" binding:"required"`
+	AddressLine string `json:"address_line" binding:"required"`
+	City        string `json:"city" binding:"required"`
+	Region      string `json:"region"`
+	PostalCode  string `json:"postal_code"`
+	// Country is an ISO 3166-1 alpha-2 code such as "UA"
+	Country   string   `json:"country" binding:"required,iso3166_1_alpha2"`
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	// TimeZone is the IANA time zone events at the venue default to, UTC by default
+	TimeZone           string `json:"time_zone"`
+	Capacity           *int32 `json:"capacity" binding:"omitempty,min=1"`
+	AccessibilityNotes string `json:"accessibility_notes"`
+}
+
+type updateVenueRequest struct {
+	Name               *string  `json:"name" binding:"omitempty,min=1"`
+	AddressLine        *string  `json:"address_line" binding:"omitempty,min=1"`
+	City               *string  `json:"city" binding:"omitempty,min=1"`
+	Region             *string  `json:"region"`
+	PostalCode         *string  `json:"postal_code"`
+	Country            *string  `json:"country" binding:"omitempty,iso3166_1_alpha2"`
+	Latitude           *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude          *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	TimeZone           *string  `json:"time_zone"`
+	Capacity           *int32   `json:"capacity" binding:"omitempty,min=1"`
+	AccessibilityNotes *string  `json:"accessibility_notes"`
+}
+
+type listVenuesRequest struct {
+	Limit  int32 `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int32 `form:"offset" binding:"omitempty,min=0"`
+}
+
+type venueResponse struct {
+	ID                 int64   `json:"id"`
+	Name               string  `json:"name"`
+	AddressLine        string  `json:"address_line"`
+	City               string  `json:"city"`
+	Region             string  `json:"region"`
+	PostalCode         string  `json:"postal_code"`
+	Country            string  `json:"country"`
+	Latitude           float64 `json:"latitude"`
+	Longitude          float64 `json:"longitude"`
+	TimeZone           string  `json:"time_zone"`
+	Capacity           *int32  `json:"capacity,omitempty"`
+	AccessibilityNotes string  `json:"accessibility_notes"`
+	CreatedAt          string  `json:"created_at"`
+}
+
+func newVenueResponse(venue db.Venue) venueResponse {
+	response := venueResponse{
+		ID:                 venue.ID,
+		Name:               venue.Name,
+		AddressLine:        venue.AddressLine,
+		City:               venue.City,
+		Region:             venue.Region,
+		PostalCode:         venue.PostalCode,
+		Country:            venue.Country,
+		Latitude:           venue.Latitude,
+		Longitude:          venue.Longitude,
+		TimeZone:           venue.TimeZone,
+		AccessibilityNotes: venue.AccessibilityNotes,
+		CreatedAt:          venue.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if venue.Capacity.Valid {
+		capacity := venue.Capacity.Int32
+		response.Capacity = &capacity
+	}
+	return response
+}
+
+// venuePlace is the free-text place of events at a venue that are created without one
+func venuePlace(venue db.Venue) string {
+	return fmt.Sprintf("%s, %s, %s", venue.Name, venue.AddressLine, venue.City)
+}
+
+// getEventVenue responds with an error and returns false if the venue an event links to doesn't exist
+func (server *Server) getEventVenue(ctx *gin.Context, venueID int64) (db.Venue, bool) {
+	venue, err := server.store.GetVenue(ctx, venueID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "venue not found"})
+			return venue, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return venue, false
+	}
+	return venue, true
+}
+
+// parseNear parses the "lat,lng" of a nearby search
+func parseNear(near string) (latitude, longitude float64, err error) {
+	latStr, lngStr, ok := strings.Cut(near, ",")
+	if !ok {
+		return 0, 0, errors.New("near must be latitude,longitude")
+	}
+	latitude, err = strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return 0, 0, errors.New("near latitude must be between -90 and 90")
+	}
+	longitude, err = strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return 0, 0, errors.New("near longitude must be between -180 and 180")
+	}
+	return latitude, longitude, nil
+}
+
+// listEventsNear responds with the events within radius_km of the near query parameter, nearest first
+func (server *Server) listEventsNear(ctx *gin.Context, near string, upcoming bool, limit, offset int32) {
+	latitude, longitude, err := parseNear(near)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	radiusKm, err := strconv.ParseFloat(ctx.DefaultQuery("radius_km", strconv.Itoa(defaultNearbyRadiusKm)), 64)
+	if err != nil || radiusKm <= 0 || radiusKm > maxNearbyRadiusKm {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("radius_km must be more than 0 and at most %d", maxNearbyRadiusKm)})
+		return
+	}
+
+	// Venues further north or south than the radius can't be within it
+	band := radiusKm / kmPerDegreeLatitude
+	events, err := server.store.ListEventsNear(ctx, db.ListEventsNearParams{
+		Latitude:     latitude,
+		Longitude:    longitude,
+		MinLatitude:  math.Max(latitude-band, -90),
+		MaxLatitude:  math.Min(latitude+band, 90),
+		RadiusKm:     radiusKm,
+		UpcomingOnly: upcoming,
+		RowLimit:     limit,
+		RowOffset:    offset,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]eventResponse, len(events))
+	for i, row := range events {
+		response[i] = newEventResponse(row.Event)
+		distanceKm := math.Round(row.DistanceKm*100) / 100
+		response[i].DistanceKm = &distanceKm
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// POST /venues
+func (server *Server) createVenue(ctx *gin.Context) {
+	var req createVenueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.TimeZone == "" {
+		req.TimeZone = "UTC"
+	}
+	if _, err := util.LoadTimeZone(req.TimeZone); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateVenueParams{
+		Name:               req.Name,
+		AddressLine:        req.AddressLine,
+		City:               req.City,
+		Region:             req.Region,
+		PostalCode:         req.PostalCode,
+		Country:            req.Country,
+		Latitude:           *req.Latitude,
+		Longitude:          *req.Longitude,
+		TimeZone:           req.TimeZone,
+		AccessibilityNotes: req.AccessibilityNotes,
+	}
+	if req.Capacity != nil {
+		arg.Capacity = pgtype.Int4{
+			Int32: *req.Capacity,
+			Valid: true,
+		}
+	}
+
+	venue, err := server.store.CreateVenue(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newVenueResponse(venue))
+}
+
+// GET /venues/:id
+func (server *Server) getVenue(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	venue, err := server.store.GetVenue(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "venue not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newVenueResponse(venue))
+}
+
+// GET /venues
+func (server *Server) listVenues(ctx *gin.Context) {
+	var req listVenuesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	venues, err := server.store.ListVenues(ctx, db.ListVenuesParams{
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]venueResponse, len(venues))
+	for i, venue := range venues {
+		response[i] = newVenueResponse(venue)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// PUT /venues/:id
+func (server *Server) updateVenue(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateVenueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.TimeZone != nil {
+		if _, err := util.LoadTimeZone(*req.TimeZone); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	arg := db.UpdateVenueParams{
+		ID: id,
+	}
+	if req.Name != nil {
+		arg.Name = pgtype.Text{
+			String: *req.Name,
+			Valid:  true,
+		}
+	}
+	if req.AddressLine != nil {
+		arg.AddressLine = pgtype.Text{
+			String: *req.AddressLine,
+			Valid:  true,
+		}
+	}
+	if req.City != nil {
+		arg.City = pgtype.Text{
+			String: *req.City,
+			Valid:  true,
+		}
+	}
+	if req.Region != nil {
+		arg.Region = pgtype.Text{
+			String: *req.Region,
+			Valid:  true,
+		}
+	}
+	if req.PostalCode != nil {
+		arg.PostalCode = pgtype.Text{
+			String: *req.PostalCode,
+			Valid:  true,
+		}
+	}
+	if req.Country != nil {
+		arg.Country = pgtype.Text{
+			String: *req.Country,
+			Valid:  true,
+		}
+	}
+	if req.Latitude != nil {
+		arg.Latitude = pgtype.Float8{
+			Float64: *req.Latitude,
+			Valid:   true,
+		}
+	}
+	if req.Longitude != nil {
+		arg.Longitude = pgtype.Float8{
+			Float64: *req.Longitude,
+			Valid:   true,
+		}
+	}
+	if req.TimeZone != nil {
+		arg.TimeZone = pgtype.Text{
+			String: *req.TimeZone,
+			Valid:  true,
+		}
+	}
+	if req.Capacity != nil {
+		arg.Capacity = pgtype.Int4{
+			Int32: *req.Capacity,
+			Valid: true,
+		}
+	}
+	if req.AccessibilityNotes != nil {
+		arg.AccessibilityNotes = pgtype.Text{
+			String: *req.AccessibilityNotes,
+			Valid:  true,
+		}
+	}
+
+	venue, err := server.store.UpdateVenue(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "venue not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newVenueResponse(venue))
+}
+
+// DELETE /venues/:id
+func (server *Server) deleteVenue(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, err = server.store.GetVenue(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "venue not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Events keep their place text and lose the link to the venue
+	err = server.store.DeleteVenue(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
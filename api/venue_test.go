@@ -0,0 +1,566 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/token"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomVenue() db.Venue {
+	// Use a fixed time to avoid timezone issues in tests
+	fixedTime, _ := time.Parse(time.RFC3339, "2023-01-01T12:00:00Z")
+	return db.Venue{
+		ID:                 util.RandomInt(1, 1000),
+		Name:               "Hall " + util.RandomString(6),
+		AddressLine:        util.RandomString(8) + " Street 1",
+		City:               "Kyiv",
+		Country:            "UA",
+		Latitude:           50.4501,
+		Longitude:          30.5234,
+		TimeZone:           "Europe/Kyiv",
+		Capacity:           pgtype.Int4{Int32: 120, Valid: true},
+		AccessibilityNotes: "Step-free entrance",
+		CreatedAt:          fixedTime,
+	}
+}
+
+func TestCreateVenueAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.Role = util.StaffRole
+
+	donor, _ := randomUser(t)
+	donor.ID = user.ID + 1
+	donor.Role = util.DonorRole
+
+	venue := randomVenue()
+
+	validBody := func() gin.H {
+		return gin.H{
+			"name":                venue.Name,
+			"address_line":        venue.AddressLine,
+			"city":                venue.City,
+			"country":             venue.Country,
+			"latitude":            venue.Latitude,
+			"longitude":           venue.Longitude,
+			"time_zone":           venue.TimeZone,
+			"capacity":            venue.Capacity.Int32,
+			"accessibility_notes": venue.AccessibilityNotes,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: validBody(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				arg := db.CreateVenueParams{
+					Name:               venue.Name,
+					AddressLine:        venue.AddressLine,
+					City:               venue.City,
+					Country:            venue.Country,
+					Latitude:           venue.Latitude,
+					Longitude:          venue.Longitude,
+					TimeZone:           venue.TimeZone,
+					Capacity:           venue.Capacity,
+					AccessibilityNotes: venue.AccessibilityNotes,
+				}
+				store.EXPECT().
+					CreateVenue(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(venue, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchVenue(t, recorder.Body, venue)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: validBody(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateVenue(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MissingCoordinates",
+			body: func() gin.H {
+				body := validBody()
+				delete(body, "latitude")
+				return body
+			}(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().CreateVenue(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidLatitude",
+			body: func() gin.H {
+				body := validBody()
+				body["latitude"] = 91.5
+				return body
+			}(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().CreateVenue(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCountry",
+			body: func() gin.H {
+				body := validBody()
+				body["country"] = "Ukraine"
+				return body
+			}(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().CreateVenue(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidTimeZone",
+			body: func() gin.H {
+				body := validBody()
+				body["time_zone"] = "Mars/Olympus"
+				return body
+			}(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().CreateVenue(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotStaff",
+			body: validBody(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, donor.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+				store.EXPECT().CreateVenue(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/venues", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestChangeVenueNotStaffAPI(t *testing.T) {
+	donor, _ := randomUser(t)
+	donor.Role = util.DonorRole
+	venue := randomVenue()
+
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+			store.EXPECT().UpdateVenue(gomock.Any(), gomock.Any()).Times(0)
+			store.EXPECT().DeleteVenue(gomock.Any(), gomock.Any()).Times(0)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/venues/%d", venue.ID)
+			request, err := http.NewRequest(method, url, strings.NewReader(`{"name": "Hall"}`))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, donor.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusForbidden, recorder.Code)
+		})
+	}
+}
+
+func TestGetVenueAPI(t *testing.T) {
+	venue := randomVenue()
+
+	testCases := []struct {
+		name          string
+		venueID       int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			venueID: venue.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVenue(gomock.Any(), gomock.Eq(venue.ID)).
+					Times(1).
+					Return(venue, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchVenue(t, recorder.Body, venue)
+			},
+		},
+		{
+			name:    "NotFound",
+			venueID: venue.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVenue(gomock.Any(), gomock.Eq(venue.ID)).
+					Times(1).
+					Return(db.Venue{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/venues/%d", tc.venueID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCreateEventAtVenueAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	venue := randomVenue()
+	event := randomEvent()
+	event.Place = venuePlace(venue)
+	event.TimeZone = venue.TimeZone
+	event.VenueID = pgtype.Int8{Int64: venue.ID, Valid: true}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "PlaceFromVenue",
+			body: gin.H{
+				"name":     event.Name,
+				"date":     event.Date.Format(time.RFC3339),
+				"venue_id": venue.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVenue(gomock.Any(), gomock.Eq(venue.ID)).
+					Times(1).
+					Return(venue, nil)
+
+				arg := db.CreateEventParams{
+					Name:     event.Name,
+					Place:    event.Place,
+					Date:     event.Date,
+					EndsAt:   event.EndsAt,
+					TimeZone: venue.TimeZone,
+					VenueID:  event.VenueID,
+				}
+				store.EXPECT().
+					CreateEvent(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(event, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchEvent(t, recorder.Body, event)
+			},
+		},
+		{
+			name: "VenueNotFound",
+			body: gin.H{
+				"name":     event.Name,
+				"date":     event.Date.Format(time.RFC3339),
+				"venue_id": venue.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVenue(gomock.Any(), gomock.Eq(venue.ID)).
+					Times(1).
+					Return(db.Venue{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NoPlaceOrVenue",
+			body: gin.H{
+				"name": event.Name,
+				"date": event.Date.Format(time.RFC3339),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetVenue(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/events", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListEventsNearAPI(t *testing.T) {
+	venue := randomVenue()
+	event := randomEvent()
+	event.VenueID = pgtype.Int8{Int64: venue.ID, Valid: true}
+	event.Status = db.EventStatusPostponed
+	event.StatusReason = "Storm warning"
+	event.MaxPartySize = pgtype.Int4{Int32: 4, Valid: true}
+	row := db.ListEventsNearRow{
+		Event:      event,
+		Latitude:   venue.Latitude,
+		Longitude:  venue.Longitude,
+		DistanceKm: 3.14159,
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "near=50.45,30.52&radius_km=10&upcoming=true",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListEventsNear(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.ListEventsNearParams) ([]db.ListEventsNearRow, error) {
+						require.Equal(t, 50.45, arg.Latitude)
+						require.Equal(t, 30.52, arg.Longitude)
+						require.Equal(t, 10.0, arg.RadiusKm)
+						require.True(t, arg.UpcomingOnly)
+						require.Equal(t, int32(10), arg.RowLimit)
+						// A 10 km band is about 0.09 degrees of latitude either way
+						require.InDelta(t, 50.36, arg.MinLatitude, 0.01)
+						require.InDelta(t, 50.54, arg.MaxLatitude, 0.01)
+						return []db.ListEventsNearRow{row}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var events []eventResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &events)
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+				require.Equal(t, event.ID, events[0].ID)
+				require.Equal(t, venue.ID, *events[0].VenueID)
+				require.Equal(t, event.Status, events[0].Status)
+				require.Equal(t, event.StatusReason, events[0].StatusReason)
+				require.Equal(t, event.MaxPartySize.Int32, *events[0].MaxPartySize)
+				require.Equal(t, 3.14, *events[0].DistanceKm)
+			},
+		},
+		{
+			name:  "DefaultRadius",
+			query: "near=50.45,30.52",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListEventsNear(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.ListEventsNearParams) ([]db.ListEventsNearRow, error) {
+						require.Equal(t, float64(defaultNearbyRadiusKm), arg.RadiusKm)
+						require.False(t, arg.UpcomingOnly)
+						return []db.ListEventsNearRow{}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidNear",
+			query: "near=50.45",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListEventsNear(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "LatitudeOutOfRange",
+			query: "near=95,30.52",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListEventsNear(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidRadius",
+			query: fmt.Sprintf("near=50.45,30.52&radius_km=%d", maxNearbyRadiusKm+1),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListEventsNear(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/events?"+tc.query, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func requireBodyMatchVenue(t *testing.T, body *bytes.Buffer, venue db.Venue) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotVenue venueResponse
+	err = json.Unmarshal(data, &gotVenue)
+	require.NoError(t, err)
+
+	require.Equal(t, venue.ID, gotVenue.ID)
+	require.Equal(t, venue.Name, gotVenue.Name)
+	require.Equal(t, venue.AddressLine, gotVenue.AddressLine)
+	require.Equal(t, venue.City, gotVenue.City)
+	require.Equal(t, venue.Country, gotVenue.Country)
+	require.Equal(t, venue.Latitude, gotVenue.Latitude)
+	require.Equal(t, venue.Longitude, gotVenue.Longitude)
+	require.Equal(t, venue.TimeZone, gotVenue.TimeZone)
+	require.Equal(t, venue.Capacity.Int32, *gotVenue.Capacity)
+	require.Equal(t, venue.AccessibilityNotes, gotVenue.AccessibilityNotes)
+}
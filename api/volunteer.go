@@ -0,0 +1,532 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/token"
+	"github.com/kholodihor/charity/util"
+)
+
+type createVolunteerRoleRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+	// Slots is how many volunteers the role needs
+	Slots int32 `json:"slots" binding:"required,min=1,max=1000"`
+}
+
+type updateVolunteerRoleRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description"`
+	Slots       *int32  `json:"slots" binding:"omitempty,min=1,max=1000"`
+}
+
+type logVolunteerHoursRequest struct {
+	// Hours is the time worked, rounded to the minute
+	Hours *float64 `json:"hours" binding:"required,min=0,max=24"`
+}
+
+type listVolunteerHistoryRequest struct {
+	Limit  int32 `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int32 `form:"offset" binding:"omitempty,min=0"`
+}
+
+type volunteerRoleResponse struct {
+	ID          int64  `json:"id"`
+	EventID     int64  `json:"event_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Slots       int32  `json:"slots"`
+	SignedUp    int64  `json:"signed_up"`
+	SlotsLeft   int64  `json:"slots_left"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type volunteerShiftResponse struct {
+	ID         int64  `json:"id"`
+	RoleID     int64  `json:"role_id"`
+	UserID     int64  `json:"user_id"`
+	SignedUpAt string `json:"signed_up_at"`
+	// Hours and LoggedAt are left out until staff log the time worked
+	Hours    *float64 `json:"hours,omitempty"`
+	LoggedAt *string  `json:"logged_at,omitempty"`
+}
+
+type eventVolunteerResponse struct {
+	ID         int64    `json:"id"`
+	RoleID     int64    `json:"role_id"`
+	RoleName   string   `json:"role_name"`
+	UserID     int64    `json:"user_id"`
+	UserName   string   `json:"user_name"`
+	UserEmail  string   `json:"user_email"`
+	SignedUpAt string   `json:"signed_up_at"`
+	Hours      *float64 `json:"hours,omitempty"`
+	LoggedAt   *string  `json:"logged_at,omitempty"`
+}
+
+type userVolunteerShiftResponse struct {
+	ID         int64  `json:"id"`
+	RoleID     int64  `json:"role_id"`
+	RoleName   string `json:"role_name"`
+	EventID    int64  `json:"event_id"`
+	EventName  string `json:"event_name"`
+	EventPlace string `json:"event_place"`
+	EventDate  string `json:"event_date"`
+	// EventLocalDate is the start in the venue's time zone
+	EventLocalDate string   `json:"event_local_date"`
+	EventTimeZone  string   `json:"event_time_zone"`
+	EventStatus    string   `json:"event_status"`
+	SignedUpAt     string   `json:"signed_up_at"`
+	Hours          *float64 `json:"hours,omitempty"`
+	LoggedAt       *string  `json:"logged_at,omitempty"`
+}
+
+type volunteerHistoryResponse struct {
+	// TotalHours is the time logged for all of the user's shifts, not just the listed ones
+	TotalHours float64                      `json:"total_hours"`
+	Shifts     []userVolunteerShiftResponse `json:"shifts"`
+}
+
+func newVolunteerRoleResponse(role db.VolunteerRole, signedUp int64) volunteerRoleResponse {
+	return volunteerRoleResponse{
+		ID:          role.ID,
+		EventID:     role.EventID,
+		Name:        role.Name,
+		Description: role.Description,
+		Slots:       role.Slots,
+		SignedUp:    signedUp,
+		SlotsLeft:   max(int64(role.Slots)-signedUp, 0),
+		CreatedAt:   role.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func newVolunteerShiftResponse(shift db.VolunteerShift) volunteerShiftResponse {
+	response := volunteerShiftResponse{
+		ID:         shift.ID,
+		RoleID:     shift.RoleID,
+		UserID:     shift.UserID,
+		SignedUpAt: shift.SignedUpAt.Format("2006-01-02T15:04:05Z"),
+	}
+	response.Hours, response.LoggedAt = loggedHours(shift.Minutes, shift.LoggedAt)
+	return response
+}
+
+// loggedHours returns the hours logged for a shift and when, or nils until they are logged
+func loggedHours(minutes pgtype.Int4, loggedAt pgtype.Timestamptz) (*float64, *string) {
+	if !minutes.Valid || !loggedAt.Valid {
+		return nil, nil
+	}
+	hours := minutesToHours(int64(minutes.Int32))
+	at := loggedAt.Time.Format("2006-01-02T15:04:05Z")
+	return &hours, &at
+}
+
+// minutesToHours converts logged minutes to hours rounded to two decimals
+func minutesToHours(minutes int64) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
+
+// parseVolunteerRolePath parses the event and role ids of a volunteer role route, responding
+// with an error and returning false if either is invalid
+func parseVolunteerRolePath(ctx *gin.Context) (eventID, roleID int64, ok bool) {
+	eventID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return 0, 0, false
+	}
+	roleID, err = strconv.ParseInt(ctx.Param("role_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return 0, 0, false
+	}
+	return eventID, roleID, true
+}
+
+// getVolunteerRole responds with an error and returns false unless the role exists and belongs to the event
+func (server *Server) getVolunteerRole(ctx *gin.Context, eventID, roleID int64) (db.VolunteerRole, bool) {
+	role, err := server.store.GetVolunteerRole(ctx, roleID)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return role, false
+	}
+	if err != nil || role.EventID != eventID {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "volunteer role not found"})
+		return role, false
+	}
+	return role, true
+}
+
+// getVolunteerEvent responds with an error and returns false if the event doesn't exist
+func (server *Server) getVolunteerEvent(ctx *gin.Context, eventID int64) (db.Event, bool) {
+	event, err := server.store.GetEvent(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+			return event, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return event, false
+	}
+	return event, true
+}
+
+// GET /events/:id/volunteer-roles
+func (server *Server) listEventVolunteerRoles(ctx *gin.Context) {
+	eventID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.getVolunteerEvent(ctx, eventID); !ok {
+		return
+	}
+
+	roles, err := server.store.ListEventVolunteerRoles(ctx, eventID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]volunteerRoleResponse, len(roles))
+	for i, row := range roles {
+		response[i] = newVolunteerRoleResponse(db.VolunteerRole{
+			ID:          row.ID,
+			EventID:     row.EventID,
+			Name:        row.Name,
+			Description: row.Description,
+			Slots:       row.Slots,
+			CreatedAt:   row.CreatedAt,
+		}, row.SignedUp)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// POST /events/:id/volunteer-roles
+func (server *Server) createVolunteerRole(ctx *gin.Context) {
+	eventID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req createVolunteerRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	role, err := server.store.CreateVolunteerRole(ctx, db.CreateVolunteerRoleParams{
+		EventID:     eventID,
+		Name:        req.Name,
+		Description: req.Description,
+		Slots:       req.Slots,
+	})
+	if err != nil {
+		switch db.ErrorCode(err) {
+		case db.ForeignKeyViolation:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		case db.UniqueViolation:
+			ctx.JSON(http.StatusConflict, gin.H{"error": "event already has a volunteer role with this name"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newVolunteerRoleResponse(role, 0))
+}
+
+// PUT /events/:id/volunteer-roles/:role_id
+func (server *Server) updateVolunteerRole(ctx *gin.Context) {
+	eventID, roleID, ok := parseVolunteerRolePath(ctx)
+	if !ok {
+		return
+	}
+
+	var req updateVolunteerRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdateVolunteerRoleTxParams{
+		EventID: eventID,
+		RoleID:  roleID,
+	}
+	if req.Name != nil {
+		arg.Name = pgtype.Text{
+			String: *req.Name,
+			Valid:  true,
+		}
+	}
+	if req.Description != nil {
+		arg.Description = pgtype.Text{
+			String: *req.Description,
+			Valid:  true,
+		}
+	}
+	if req.Slots != nil {
+		arg.Slots = pgtype.Int4{
+			Int32: *req.Slots,
+			Valid: true,
+		}
+	}
+
+	role, err := server.store.UpdateVolunteerRoleTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "volunteer role not found"})
+		case errors.Is(err, db.ErrVolunteerSlotsTaken):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		case db.ErrorCode(err) == db.UniqueViolation:
+			ctx.JSON(http.StatusConflict, gin.H{"error": "event already has a volunteer role with this name"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	signedUp, err := server.store.CountVolunteerShifts(ctx, role.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newVolunteerRoleResponse(role, signedUp))
+}
+
+// DELETE /events/:id/volunteer-roles/:role_id
+func (server *Server) deleteVolunteerRole(ctx *gin.Context) {
+	eventID, roleID, ok := parseVolunteerRolePath(ctx)
+	if !ok {
+		return
+	}
+
+	if _, ok := server.getVolunteerRole(ctx, eventID, roleID); !ok {
+		return
+	}
+
+	// Logged hours are part of volunteers' history, so their role stays
+	_, err := server.store.DeleteVolunteerRole(ctx, roleID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "hours have been logged for volunteers of this role"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// POST /events/:id/volunteer-roles/:role_id/signup
+func (server *Server) signUpVolunteer(ctx *gin.Context) {
+	eventID, roleID, ok := parseVolunteerRolePath(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	shift, err := server.store.SignUpVolunteerTx(ctx, db.SignUpVolunteerTxParams{
+		EventID: eventID,
+		RoleID:  roleID,
+		UserID:  authPayload.UserID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "volunteer role not found"})
+		case db.ErrorCode(err) == db.UniqueViolation:
+			ctx.JSON(http.StatusConflict, gin.H{"error": "already signed up for this role"})
+		case errors.Is(err, db.ErrVolunteerRoleFull), errors.Is(err, db.ErrEventCancelled), errors.Is(err, db.ErrEventStarted):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newVolunteerShiftResponse(shift))
+}
+
+// DELETE /events/:id/volunteer-roles/:role_id/signup
+func (server *Server) withdrawVolunteer(ctx *gin.Context) {
+	eventID, roleID, ok := parseVolunteerRolePath(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	event, ok := server.getVolunteerEvent(ctx, eventID)
+	if !ok {
+		return
+	}
+	// Shifts of events that took place are kept for volunteers' history
+	if event.Status != db.EventStatusPostponed && !event.Date.After(time.Now()) {
+		ctx.JSON(http.StatusConflict, errorResponse(db.ErrEventStarted))
+		return
+	}
+
+	if _, ok := server.getVolunteerRole(ctx, eventID, roleID); !ok {
+		return
+	}
+
+	err := server.store.DeleteVolunteerShift(ctx, db.DeleteVolunteerShiftParams{
+		RoleID: roleID,
+		UserID: authPayload.UserID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// GET /events/:id/volunteers
+func (server *Server) listEventVolunteers(ctx *gin.Context) {
+	eventID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.getVolunteerEvent(ctx, eventID); !ok {
+		return
+	}
+
+	volunteers, err := server.store.ListEventVolunteers(ctx, eventID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]eventVolunteerResponse, len(volunteers))
+	for i, row := range volunteers {
+		response[i] = eventVolunteerResponse{
+			ID:         row.ID,
+			RoleID:     row.RoleID,
+			RoleName:   row.RoleName,
+			UserID:     row.UserID,
+			UserName:   row.UserName.String,
+			UserEmail:  row.UserEmail,
+			SignedUpAt: row.SignedUpAt.Format("2006-01-02T15:04:05Z"),
+		}
+		response[i].Hours, response[i].LoggedAt = loggedHours(row.Minutes, row.LoggedAt)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// PUT /events/:id/volunteers/:shift_id/hours
+func (server *Server) logVolunteerHours(ctx *gin.Context) {
+	eventID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	shiftID, err := strconv.ParseInt(ctx.Param("shift_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req logVolunteerHoursRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	event, ok := server.getVolunteerEvent(ctx, eventID)
+	if !ok {
+		return
+	}
+	if event.Date.After(time.Now()) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "hours can only be logged once the event has started"})
+		return
+	}
+
+	shift, err := server.store.LogVolunteerMinutes(ctx, db.LogVolunteerMinutesParams{
+		Minutes:  int32(math.Round(*req.Hours * 60)),
+		LoggedBy: authPayload.UserID,
+		ID:       shiftID,
+		EventID:  eventID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "volunteer shift not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newVolunteerShiftResponse(shift))
+}
+
+// GET /users/me/volunteering
+func (server *Server) listUserVolunteerShifts(ctx *gin.Context) {
+	var req listVolunteerHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	shifts, err := server.store.ListUserVolunteerShifts(ctx, db.ListUserVolunteerShiftsParams{
+		UserID: authPayload.UserID,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	minutes, err := server.store.GetUserVolunteerMinutes(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := volunteerHistoryResponse{
+		TotalHours: minutesToHours(minutes),
+		Shifts:     make([]userVolunteerShiftResponse, len(shifts)),
+	}
+	for i, row := range shifts {
+		response.Shifts[i] = userVolunteerShiftResponse{
+			ID:             row.ID,
+			RoleID:         row.RoleID,
+			RoleName:       row.RoleName,
+			EventID:        row.EventID,
+			EventName:      row.EventName,
+			EventPlace:     row.EventPlace,
+			EventDate:      row.EventDate.UTC().Format(time.RFC3339),
+			EventLocalDate: util.InTimeZone(row.EventDate, row.EventTimeZone).Format(time.RFC3339),
+			EventTimeZone:  row.EventTimeZone,
+			EventStatus:    row.EventStatus,
+			SignedUpAt:     row.SignedUpAt.Format("2006-01-02T15:04:05Z"),
+		}
+		response.Shifts[i].Hours, response.Shifts[i].LoggedAt = loggedHours(row.Minutes, row.LoggedAt)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
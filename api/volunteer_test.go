@@ -0,0 +1,569 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomVolunteerRole(eventID int64) db.VolunteerRole {
+	return db.VolunteerRole{
+		ID:          util.RandomInt(1, 1000),
+		EventID:     eventID,
+		Name:        util.RandomString(10),
+		Description: util.RandomString(20),
+		Slots:       int32(util.RandomInt(1, 10)),
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func TestCreateVolunteerRoleAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	staff.Role = util.StaffRole
+
+	donor, _ := randomUser(t)
+	donor.ID = staff.ID + 1
+	donor.Role = util.DonorRole
+
+	event := randomEvent()
+	role := randomVolunteerRole(event.ID)
+
+	testCases := []struct {
+		name          string
+		userID        int64
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			userID: staff.ID,
+			body:   gin.H{"name": role.Name, "description": role.Description, "slots": role.Slots},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().
+					CreateVolunteerRole(gomock.Any(), gomock.Eq(db.CreateVolunteerRoleParams{
+						EventID:     event.ID,
+						Name:        role.Name,
+						Description: role.Description,
+						Slots:       role.Slots,
+					})).
+					Times(1).
+					Return(role, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response volunteerRoleResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, role.ID, response.ID)
+				require.Equal(t, role.Slots, response.Slots)
+				require.Equal(t, int64(role.Slots), response.SlotsLeft)
+			},
+		},
+		{
+			name:   "DuplicateName",
+			userID: staff.ID,
+			body:   gin.H{"name": role.Name, "slots": role.Slots},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().
+					CreateVolunteerRole(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VolunteerRole{}, &pgconn.PgError{Code: db.UniqueViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "EventNotFound",
+			userID: staff.ID,
+			body:   gin.H{"name": role.Name, "slots": role.Slots},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().
+					CreateVolunteerRole(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VolunteerRole{}, &pgconn.PgError{Code: db.ForeignKeyViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "NoSlots",
+			userID: staff.ID,
+			body:   gin.H{"name": role.Name, "slots": 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().CreateVolunteerRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "NotStaff",
+			userID: donor.ID,
+			body:   gin.H{"name": role.Name, "slots": role.Slots},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+				store.EXPECT().CreateVolunteerRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/events/%d/volunteer-roles", event.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.userID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSignUpVolunteerAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	event := randomEvent()
+	role := randomVolunteerRole(event.ID)
+	shift := db.VolunteerShift{
+		ID:         util.RandomInt(1, 1000),
+		RoleID:     role.ID,
+		UserID:     user.ID,
+		SignedUpAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, server *Server)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SignUpVolunteerTx(gomock.Any(), gomock.Eq(db.SignUpVolunteerTxParams{
+						EventID: event.ID,
+						RoleID:  role.ID,
+						UserID:  user.ID,
+					})).
+					Times(1).
+					Return(shift, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response volunteerShiftResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, shift.ID, response.ID)
+				require.Nil(t, response.Hours)
+			},
+		},
+		{
+			name: "RoleFull",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SignUpVolunteerTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VolunteerShift{}, db.ErrVolunteerRoleFull)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadySignedUp",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SignUpVolunteerTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VolunteerShift{}, &pgconn.PgError{Code: db.UniqueViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "RoleNotFound",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SignUpVolunteerTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VolunteerShift{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SignUpVolunteerTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/events/%d/volunteer-roles/%d/signup", event.ID, role.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestWithdrawVolunteerAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	upcoming := randomEvent()
+	upcoming.Date = time.Now().Add(24 * time.Hour)
+	started := randomEvent()
+	role := randomVolunteerRole(upcoming.ID)
+
+	testCases := []struct {
+		name          string
+		event         db.Event
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			event: upcoming,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(upcoming.ID)).Times(1).Return(upcoming, nil)
+				store.EXPECT().GetVolunteerRole(gomock.Any(), gomock.Eq(role.ID)).Times(1).Return(role, nil)
+				store.EXPECT().
+					DeleteVolunteerShift(gomock.Any(), gomock.Eq(db.DeleteVolunteerShiftParams{
+						RoleID: role.ID,
+						UserID: user.ID,
+					})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name:  "EventStarted",
+			event: started,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(started.ID)).Times(1).Return(started, nil)
+				store.EXPECT().DeleteVolunteerShift(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:  "RoleOfOtherEvent",
+			event: upcoming,
+			buildStubs: func(store *mockdb.MockStore) {
+				other := role
+				other.EventID = upcoming.ID + 1
+				store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(upcoming.ID)).Times(1).Return(upcoming, nil)
+				store.EXPECT().GetVolunteerRole(gomock.Any(), gomock.Eq(role.ID)).Times(1).Return(other, nil)
+				store.EXPECT().DeleteVolunteerShift(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/events/%d/volunteer-roles/%d/signup", tc.event.ID, role.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestLogVolunteerHoursAPI(t *testing.T) {
+	staff, _ := randomUser(t)
+	staff.Role = util.StaffRole
+
+	started := randomEvent()
+	upcoming := randomEvent()
+	upcoming.Date = time.Now().Add(24 * time.Hour)
+
+	shift := db.VolunteerShift{
+		ID:         util.RandomInt(1, 1000),
+		RoleID:     util.RandomInt(1, 1000),
+		UserID:     staff.ID + 1,
+		SignedUpAt: started.CreatedAt,
+		Minutes:    pgtype.Int4{Int32: 150, Valid: true},
+		LoggedAt:   pgtype.Timestamptz{Time: time.Now().UTC().Truncate(time.Second), Valid: true},
+		LoggedBy:   pgtype.Int8{Int64: staff.ID, Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		event         db.Event
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			event: started,
+			body:  gin.H{"hours": 2.5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(started.ID)).Times(1).Return(started, nil)
+				store.EXPECT().
+					LogVolunteerMinutes(gomock.Any(), gomock.Eq(db.LogVolunteerMinutesParams{
+						Minutes:  150,
+						LoggedBy: staff.ID,
+						ID:       shift.ID,
+						EventID:  started.ID,
+					})).
+					Times(1).
+					Return(shift, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response volunteerShiftResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.NotNil(t, response.Hours)
+				require.Equal(t, 2.5, *response.Hours)
+				require.NotNil(t, response.LoggedAt)
+			},
+		},
+		{
+			name:  "EventNotStarted",
+			event: upcoming,
+			body:  gin.H{"hours": 2},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(upcoming.ID)).Times(1).Return(upcoming, nil)
+				store.EXPECT().LogVolunteerMinutes(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:  "ShiftNotFound",
+			event: started,
+			body:  gin.H{"hours": 2},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(started.ID)).Times(1).Return(started, nil)
+				store.EXPECT().
+					LogVolunteerMinutes(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VolunteerShift{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "TooManyHours",
+			event: started,
+			body:  gin.H{"hours": 25},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().LogVolunteerMinutes(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "MissingHours",
+			event: started,
+			body:  gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(staff.ID)).Times(1).Return(staff, nil)
+				store.EXPECT().LogVolunteerMinutes(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/events/%d/volunteers/%d/hours", tc.event.ID, shift.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, staff.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListUserVolunteerShiftsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	event := randomEvent()
+
+	shifts := []db.ListUserVolunteerShiftsRow{
+		{
+			ID:            util.RandomInt(1, 1000),
+			RoleID:        util.RandomInt(1, 1000),
+			UserID:        user.ID,
+			SignedUpAt:    event.CreatedAt,
+			Minutes:       pgtype.Int4{Int32: 100, Valid: true},
+			LoggedAt:      pgtype.Timestamptz{Time: event.EndsAt, Valid: true},
+			RoleName:      "Setup",
+			EventID:       event.ID,
+			EventName:     event.Name,
+			EventPlace:    event.Place,
+			EventDate:     event.Date,
+			EventTimeZone: "Europe/Kyiv",
+			EventStatus:   event.Status,
+		},
+		{
+			ID:            util.RandomInt(1, 1000),
+			RoleID:        util.RandomInt(1, 1000),
+			UserID:        user.ID,
+			SignedUpAt:    event.CreatedAt,
+			RoleName:      "Cleanup",
+			EventID:       event.ID,
+			EventName:     event.Name,
+			EventPlace:    event.Place,
+			EventDate:     event.Date,
+			EventTimeZone: "UTC",
+			EventStatus:   event.Status,
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListUserVolunteerShifts(gomock.Any(), gomock.Eq(db.ListUserVolunteerShiftsParams{
+			UserID: user.ID,
+			Limit:  5,
+			Offset: 0,
+		})).
+		Times(1).
+		Return(shifts, nil)
+	store.EXPECT().
+		GetUserVolunteerMinutes(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return(int64(340), nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/users/me/volunteering?limit=5", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response volunteerHistoryResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, 5.67, response.TotalHours)
+	require.Len(t, response.Shifts, 2)
+	require.Equal(t, "Setup", response.Shifts[0].RoleName)
+	require.Equal(t, 1.67, *response.Shifts[0].Hours)
+	require.Equal(t, "2023-01-02T14:00:00+02:00", response.Shifts[0].EventLocalDate)
+	require.Nil(t, response.Shifts[1].Hours)
+	require.Nil(t, response.Shifts[1].LoggedAt)
+}
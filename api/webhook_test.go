@@ -0,0 +1,456 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/util"
+	"github.com/kholodihor/charity/webhook"
+	"github.com/stretchr/testify/require"
+)
+
+func randomWebhookEndpoint(createdBy int64) db.WebhookEndpoint {
+	return db.WebhookEndpoint{
+		ID:         util.RandomInt(1, 1000),
+		Url:        "https://crm.example.com/hooks/" + util.RandomString(6),
+		Secret:     util.RandomString(32),
+		EventTypes: []string{db.EventDonationCreated, db.EventGoalFunded},
+		IsActive:   true,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now().Truncate(time.Second),
+	}
+}
+
+func randomWebhookDelivery(endpointID int64) db.WebhookDelivery {
+	return db.WebhookDelivery{
+		ID:             util.RandomInt(1, 1000),
+		EndpointID:     endpointID,
+		EventID:        util.RandomInt(1, 1000),
+		Status:         webhook.StatusFailed,
+		Attempts:       10,
+		NextAttemptAt:  time.Now().Truncate(time.Second),
+		LastStatusCode: pgtype.Int4{Int32: http.StatusBadGateway, Valid: true},
+		LastError:      pgtype.Text{String: "unexpected status code 502", Valid: true},
+		CreatedAt:      time.Now().Truncate(time.Second),
+	}
+}
+
+func TestCreateWebhookAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	donor, _ := randomUser(t)
+	donor.Role = util.DonorRole
+
+	endpoint := randomWebhookEndpoint(admin.ID)
+
+	testCases := []struct {
+		name          string
+		userID        int64
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			userID: admin.ID,
+			body: gin.H{
+				"url":    endpoint.Url,
+				"events": []string{db.EventDonationCreated, db.EventGoalFunded, db.EventDonationCreated},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+				store.EXPECT().
+					CreateWebhookEndpoint(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+						require.Equal(t, endpoint.Url, arg.Url)
+						require.Equal(t, endpoint.EventTypes, arg.EventTypes)
+						require.Equal(t, admin.ID, arg.CreatedBy)
+						require.True(t, strings.HasPrefix(arg.Secret, "whsec_"))
+
+						created := endpoint
+						created.Secret = arg.Secret
+						return created, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response webhookEndpointResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, endpoint.ID, response.ID)
+				require.Equal(t, endpoint.EventTypes, response.Events)
+				require.True(t, strings.HasPrefix(response.Secret, "whsec_"))
+			},
+		},
+		{
+			name:   "UnsupportedEvent",
+			userID: admin.ID,
+			body: gin.H{
+				"url":    endpoint.Url,
+				"events": []string{"user.deleted"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "InvalidScheme",
+			userID: admin.ID,
+			body: gin.H{
+				"url":    "ftp://crm.example.com/hooks",
+				"events": []string{db.EventDonationCreated},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "NoEvents",
+			userID: admin.ID,
+			body: gin.H{
+				"url":    endpoint.Url,
+				"events": []string{},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "NotAdmin",
+			userID: donor.ID,
+			body: gin.H{
+				"url":    endpoint.Url,
+				"events": []string{db.EventDonationCreated},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(donor.ID)).Times(1).Return(donor, nil)
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "InternalError",
+			userID: admin.ID,
+			body: gin.H{
+				"url":    endpoint.Url,
+				"events": []string{db.EventDonationCreated},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+				store.EXPECT().
+					CreateWebhookEndpoint(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.WebhookEndpoint{}, pgx.ErrTxClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/webhooks", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.userID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListWebhooksAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	endpoints := []db.WebhookEndpoint{
+		randomWebhookEndpoint(admin.ID),
+		randomWebhookEndpoint(admin.ID),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+	store.EXPECT().
+		ListWebhookEndpoints(gomock.Any(), gomock.Eq(db.ListWebhookEndpointsParams{Limit: 10, Offset: 0})).
+		Times(1).
+		Return(endpoints, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/admin/webhooks", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.ID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response []webhookEndpointResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response, 2)
+	for i, endpoint := range response {
+		require.Equal(t, endpoints[i].ID, endpoint.ID)
+		require.Equal(t, endpoints[i].Url, endpoint.URL)
+		// Secrets are never listed
+		require.Empty(t, endpoint.Secret)
+	}
+}
+
+func TestDeleteWebhookAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	endpoint := randomWebhookEndpoint(admin.ID)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().DeleteWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(db.WebhookEndpoint{}, db.ErrRecordNotFound)
+				store.EXPECT().DeleteWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/webhooks/%d", endpoint.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListWebhookDeliveriesAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	endpoint := randomWebhookEndpoint(admin.ID)
+	delivery := randomWebhookDelivery(endpoint.ID)
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?limit=5&offset=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				arg := db.ListWebhookDeliveriesParams{
+					EndpointID: endpoint.ID,
+					Limit:      5,
+					Offset:     5,
+				}
+				store.EXPECT().
+					ListWebhookDeliveries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.WebhookDelivery{delivery}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response []webhookDeliveryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response, 1)
+				require.Equal(t, delivery.ID, response[0].ID)
+				require.Equal(t, webhook.StatusFailed, response[0].Status)
+				require.Nil(t, response[0].NextAttemptAt)
+				require.Equal(t, delivery.LastStatusCode.Int32, *response[0].LastStatusCode)
+				require.Equal(t, delivery.LastError.String, *response[0].LastError)
+			},
+		},
+		{
+			name:  "InvalidLimit",
+			query: "?limit=1000",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListWebhookDeliveries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(db.WebhookEndpoint{}, db.ErrRecordNotFound)
+				store.EXPECT().ListWebhookDeliveries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/webhooks/%d/deliveries%s", endpoint.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRedeliverWebhookAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	delivery := randomWebhookDelivery(1)
+	reset := delivery
+	reset.Status = webhook.StatusPending
+	reset.Attempts = 0
+	reset.LastError = pgtype.Text{}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(reset, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var response webhookDeliveryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, webhook.StatusPending, response.Status)
+				require.Zero(t, response.Attempts)
+				require.NotNil(t, response.NextAttemptAt)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(db.WebhookDelivery{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.ID)).Times(1).Return(admin, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/webhooks/deliveries/%d/redeliver", delivery.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
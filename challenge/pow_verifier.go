@@ -0,0 +1,120 @@
+package challenge
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const minSecretKeySize = 32
+
+// ProofOfWorkVerifier issues HMAC-signed challenges and verifies hashcash-style solutions.
+// A solution is a counter such that sha256("<challenge>:<counter>") starts with
+// at least difficulty zero bits. It needs no external service.
+type ProofOfWorkVerifier struct {
+	secretKey  []byte
+	difficulty int
+	ttl        time.Duration
+	now        func() time.Time
+
+	// guard remembers solved challenges until they expire to prevent replays
+	guard ReplayGuard
+}
+
+// NewProofOfWorkVerifier creates a new ProofOfWorkVerifier that records solved challenges with guard
+func NewProofOfWorkVerifier(secretKey string, difficulty int, ttl time.Duration, guard ReplayGuard) (*ProofOfWorkVerifier, error) {
+	if len(secretKey) < minSecretKeySize {
+		return nil, fmt.Errorf("invalid key size: must be at least %d characters", minSecretKeySize)
+	}
+	if difficulty < 1 || difficulty > 64 {
+		return nil, fmt.Errorf("invalid difficulty %d: must be between 1 and 64", difficulty)
+	}
+
+	return &ProofOfWorkVerifier{
+		secretKey:  []byte(secretKey),
+		difficulty: difficulty,
+		ttl:        ttl,
+		now:        time.Now,
+		guard:      guard,
+	}, nil
+}
+
+// NewChallenge creates a new signed challenge in the form "<nonce>.<expires>.<signature>"
+func (verifier *ProofOfWorkVerifier) NewChallenge() (Challenge, error) {
+	nonce := make([]byte, 16)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return Challenge{}, err
+	}
+
+	expiresAt := verifier.now().Add(verifier.ttl).Truncate(time.Second)
+	payload := hex.EncodeToString(nonce) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+
+	return Challenge{
+		Challenge:  payload + "." + verifier.sign(payload),
+		Difficulty: verifier.difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// Verify checks a response in the form "<challenge>:<counter>"
+func (verifier *ProofOfWorkVerifier) Verify(ctx context.Context, response string, remoteIP string) error {
+	if response == "" {
+		return ErrMissingResponse
+	}
+
+	challenge, counter, ok := strings.Cut(response, ":")
+	if !ok || counter == "" {
+		return ErrInvalidResponse
+	}
+
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 3 {
+		return ErrInvalidResponse
+	}
+
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(verifier.sign(payload))) {
+		return ErrInvalidResponse
+	}
+
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return ErrInvalidResponse
+	}
+	expiresAt := time.Unix(expires, 0)
+	if verifier.now().After(expiresAt) {
+		return ErrExpiredResponse
+	}
+
+	if LeadingZeroBits(sha256.Sum256([]byte(response))) < verifier.difficulty {
+		return ErrInvalidResponse
+	}
+
+	return verifier.guard.MarkUsed(ctx, challenge, expiresAt)
+}
+
+func (verifier *ProofOfWorkVerifier) sign(payload string) string {
+	mac := hmac.New(sha256.New, verifier.secretKey)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// LeadingZeroBits counts the leading zero bits of a hash
+func LeadingZeroBits(hash [sha256.Size]byte) int {
+	count := 0
+	for _, b := range hash {
+		if b != 0 {
+			return count + bits.LeadingZeros8(b)
+		}
+		count += 8
+	}
+	return count
+}
@@ -0,0 +1,98 @@
+package challenge
+
+import (
+	"context"
+	"crypto/sha256"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func solve(challenge Challenge) string {
+	for counter := 0; ; counter++ {
+		response := challenge.Challenge + ":" + strconv.Itoa(counter)
+		if LeadingZeroBits(sha256.Sum256([]byte(response))) >= challenge.Difficulty {
+			return response
+		}
+	}
+}
+
+func newTestProofOfWorkVerifier(t *testing.T) *ProofOfWorkVerifier {
+	verifier, err := NewProofOfWorkVerifier(util.RandomString(32), 8, time.Minute, NewMemoryReplayGuard())
+	require.NoError(t, err)
+	return verifier
+}
+
+func TestProofOfWorkVerifier(t *testing.T) {
+	verifier := newTestProofOfWorkVerifier(t)
+
+	challenge, err := verifier.NewChallenge()
+	require.NoError(t, err)
+	require.Equal(t, 8, challenge.Difficulty)
+	require.WithinDuration(t, time.Now().Add(time.Minute), challenge.ExpiresAt, time.Second)
+
+	response := solve(challenge)
+	require.NoError(t, verifier.Verify(context.Background(), response, ""))
+
+	// A solved challenge can't be replayed
+	require.ErrorIs(t, verifier.Verify(context.Background(), response, ""), ErrUsedResponse)
+}
+
+func TestProofOfWorkVerifierInvalid(t *testing.T) {
+	verifier := newTestProofOfWorkVerifier(t)
+
+	challenge, err := verifier.NewChallenge()
+	require.NoError(t, err)
+
+	require.ErrorIs(t, verifier.Verify(context.Background(), "", ""), ErrMissingResponse)
+	require.ErrorIs(t, verifier.Verify(context.Background(), challenge.Challenge, ""), ErrInvalidResponse)
+	require.ErrorIs(t, verifier.Verify(context.Background(), "a.b:1", ""), ErrInvalidResponse)
+
+	// Tampered challenge
+	// Nonces are hex, so this always changes the challenge
+	tampered := Challenge{Challenge: "x" + challenge.Challenge[1:], Difficulty: challenge.Difficulty}
+	require.ErrorIs(t, verifier.Verify(context.Background(), solve(tampered), ""), ErrInvalidResponse)
+
+	// Challenge signed with another key
+	other := newTestProofOfWorkVerifier(t)
+	otherChallenge, err := other.NewChallenge()
+	require.NoError(t, err)
+	require.ErrorIs(t, verifier.Verify(context.Background(), solve(otherChallenge), ""), ErrInvalidResponse)
+}
+
+func TestProofOfWorkVerifierInsufficientWork(t *testing.T) {
+	verifier := newTestProofOfWorkVerifier(t)
+
+	challenge, err := verifier.NewChallenge()
+	require.NoError(t, err)
+
+	for counter := 0; ; counter++ {
+		response := challenge.Challenge + ":" + strconv.Itoa(counter)
+		if LeadingZeroBits(sha256.Sum256([]byte(response))) < challenge.Difficulty {
+			require.ErrorIs(t, verifier.Verify(context.Background(), response, ""), ErrInvalidResponse)
+			return
+		}
+	}
+}
+
+func TestProofOfWorkVerifierExpired(t *testing.T) {
+	verifier := newTestProofOfWorkVerifier(t)
+
+	challenge, err := verifier.NewChallenge()
+	require.NoError(t, err)
+	response := solve(challenge)
+
+	verifier.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	require.ErrorIs(t, verifier.Verify(context.Background(), response, ""), ErrExpiredResponse)
+}
+
+func TestNewProofOfWorkVerifierInvalid(t *testing.T) {
+	_, err := NewProofOfWorkVerifier(util.RandomString(10), 8, time.Minute, NewMemoryReplayGuard())
+	require.Error(t, err)
+
+	_, err = NewProofOfWorkVerifier(util.RandomString(32), 0, time.Minute, NewMemoryReplayGuard())
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package challenge
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "challenge:used:"
+
+// ReplayGuard remembers solved challenges until they expire, so each one is accepted only once
+type ReplayGuard interface {
+	// MarkUsed records the challenge until expiresAt, or returns ErrUsedResponse if it was already recorded
+	MarkUsed(ctx context.Context, challenge string, expiresAt time.Time) error
+}
+
+// MemoryReplayGuard remembers solved challenges in process memory.
+// Replays are only caught by the instance that saw the first use.
+type MemoryReplayGuard struct {
+	used  map[string]time.Time
+	mutex sync.Mutex
+	now   func() time.Time
+}
+
+// NewMemoryReplayGuard creates a new in-memory replay guard
+func NewMemoryReplayGuard() ReplayGuard {
+	return &MemoryReplayGuard{
+		used: make(map[string]time.Time),
+		now:  time.Now,
+	}
+}
+
+// MarkUsed records a solved challenge and drops the ones that already expired
+func (guard *MemoryReplayGuard) MarkUsed(ctx context.Context, challenge string, expiresAt time.Time) error {
+	guard.mutex.Lock()
+	defer guard.mutex.Unlock()
+
+	now := guard.now()
+	for key, expiry := range guard.used {
+		if now.After(expiry) {
+			delete(guard.used, key)
+		}
+	}
+
+	if _, exists := guard.used[challenge]; exists {
+		return ErrUsedResponse
+	}
+
+	guard.used[challenge] = expiresAt
+	return nil
+}
+
+// RedisReplayGuard remembers solved challenges in Redis.
+// The state is shared, so a challenge solved once is rejected by every replica.
+type RedisReplayGuard struct {
+	client redis.Cmdable
+	now    func() time.Time
+}
+
+// NewRedisReplayGuard creates a new Redis-backed replay guard
+func NewRedisReplayGuard(client redis.Cmdable) ReplayGuard {
+	return &RedisReplayGuard{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// MarkUsed records a solved challenge with SET NX, so concurrent uses can't both succeed.
+// The key expires together with the challenge.
+func (guard *RedisReplayGuard) MarkUsed(ctx context.Context, challenge string, expiresAt time.Time) error {
+	ttl := expiresAt.Sub(guard.now())
+	if ttl <= 0 {
+		return ErrExpiredResponse
+	}
+
+	ok, err := guard.client.SetNX(ctx, redisKeyPrefix+challenge, 1, ttl).Result()
+	if err != nil {
+		return fmt.Errorf("cannot record used challenge: %w", err)
+	}
+	if !ok {
+		return ErrUsedResponse
+	}
+	return nil
+}
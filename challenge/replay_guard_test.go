@@ -0,0 +1,35 @@
+package challenge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/kholodihor/charity/util"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisReplayGuard(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	// Two replicas sharing one Redis
+	guard := NewRedisReplayGuard(client)
+	other := NewRedisReplayGuard(client)
+
+	challenge := util.RandomString(16)
+	expiresAt := time.Now().Add(time.Minute)
+
+	require.NoError(t, guard.MarkUsed(context.Background(), challenge, expiresAt))
+	require.ErrorIs(t, other.MarkUsed(context.Background(), challenge, expiresAt), ErrUsedResponse)
+	require.NoError(t, other.MarkUsed(context.Background(), util.RandomString(16), expiresAt))
+
+	// Used challenges are forgotten once they expire
+	mr.FastForward(2 * time.Minute)
+	require.False(t, mr.Exists(redisKeyPrefix+challenge))
+
+	require.ErrorIs(t, guard.MarkUsed(context.Background(), challenge, time.Now().Add(-time.Second)), ErrExpiredResponse)
+}
@@ -0,0 +1,81 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Verification endpoints of the supported hosted CAPTCHA providers
+const (
+	HCaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+	TurnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+// TokenVerifier verifies CAPTCHA tokens with a hosted siteverify endpoint.
+// hCaptcha and Cloudflare Turnstile share the same request and response format.
+type TokenVerifier struct {
+	verifyURL string
+	secret    string
+	client    *http.Client
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// NewTokenVerifier creates a new TokenVerifier for the given siteverify endpoint
+func NewTokenVerifier(verifyURL string, secret string) Verifier {
+	return &TokenVerifier{
+		verifyURL: verifyURL,
+		secret:    secret,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Verify checks the CAPTCHA token with the provider
+func (verifier *TokenVerifier) Verify(ctx context.Context, response string, remoteIP string) error {
+	if response == "" {
+		return ErrMissingResponse
+	}
+
+	form := url.Values{}
+	form.Set("secret", verifier.secret)
+	form.Set("response", response)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, verifier.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rsp, err := verifier.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach challenge provider: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("challenge provider returned status %d", rsp.StatusCode)
+	}
+
+	var result siteVerifyResponse
+	err = json.NewDecoder(rsp.Body).Decode(&result)
+	if err != nil {
+		return fmt.Errorf("cannot decode challenge provider response: %w", err)
+	}
+
+	if !result.Success {
+		return ErrInvalidResponse
+	}
+
+	return nil
+}
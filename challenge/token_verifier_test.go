@@ -0,0 +1,57 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSiteVerifyServer(t *testing.T, secret string, validToken string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+
+		rsp := siteVerifyResponse{
+			Success: r.PostForm.Get("secret") == secret && r.PostForm.Get("response") == validToken,
+		}
+		if !rsp.Success {
+			rsp.ErrorCodes = []string{"invalid-input-response"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rsp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTokenVerifier(t *testing.T) {
+	server := newTestSiteVerifyServer(t, "secret", "valid-token")
+	verifier := NewTokenVerifier(server.URL, "secret")
+
+	require.NoError(t, verifier.Verify(context.Background(), "valid-token", "203.0.113.7"))
+	require.ErrorIs(t, verifier.Verify(context.Background(), "invalid-token", "203.0.113.7"), ErrInvalidResponse)
+	require.ErrorIs(t, verifier.Verify(context.Background(), "", "203.0.113.7"), ErrMissingResponse)
+}
+
+func TestTokenVerifierWrongSecret(t *testing.T) {
+	server := newTestSiteVerifyServer(t, "secret", "valid-token")
+	verifier := NewTokenVerifier(server.URL, "wrong-secret")
+
+	require.ErrorIs(t, verifier.Verify(context.Background(), "valid-token", ""), ErrInvalidResponse)
+}
+
+func TestTokenVerifierProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	verifier := NewTokenVerifier(server.URL, "secret")
+	err := verifier.Verify(context.Background(), "valid-token", "")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrInvalidResponse)
+}
@@ -0,0 +1,34 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Different types of error returned by the Verify function
+var (
+	ErrMissingResponse = errors.New("challenge response is missing")
+	ErrInvalidResponse = errors.New("challenge response is invalid")
+	ErrExpiredResponse = errors.New("challenge has expired")
+	ErrUsedResponse    = errors.New("challenge has already been used")
+)
+
+// Verifier is an interface for verifying that a request was made by a human or paid for with work
+type Verifier interface {
+	// Verify checks the challenge response submitted by the client at remoteIP
+	Verify(ctx context.Context, response string, remoteIP string) error
+}
+
+// Issuer is implemented by verifiers that hand out their own challenges
+type Issuer interface {
+	// NewChallenge creates a new challenge for the client to solve
+	NewChallenge() (Challenge, error)
+}
+
+// Challenge is a puzzle the client has to solve before submitting a request
+type Challenge struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
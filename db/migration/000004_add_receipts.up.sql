@@ -0,0 +1,26 @@
+CREATE TABLE "receipt_counters" (
+  "year" int PRIMARY KEY,
+  "last_number" bigint NOT NULL DEFAULT 0
+);
+
+CREATE TABLE "receipts" (
+  "id" bigserial PRIMARY KEY,
+  "receipt_number" varchar UNIQUE NOT NULL,
+  "user_id" bigint NOT NULL,
+  "donation_id" bigint UNIQUE,
+  "year" int NOT NULL,
+  "kind" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "issued_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "receipts" ("user_id");
+CREATE UNIQUE INDEX ON "receipts" ("user_id", "year") WHERE "kind" = 'annual';
+
+ALTER TABLE "receipts" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "receipts" ADD FOREIGN KEY ("donation_id") REFERENCES "donations" ("id");
+
+COMMENT ON TABLE "receipt_counters" IS 'last issued receipt number per year, incremented in the issuing transaction so numbers are gapless';
+COMMENT ON COLUMN "receipts"."donation_id" IS 'null for annual summary receipts';
+COMMENT ON COLUMN "receipts"."kind" IS 'donation or annual';
+COMMENT ON COLUMN "receipts"."amount" IS 'in smallest currency unit, e.g., cents';
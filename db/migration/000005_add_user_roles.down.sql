@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "donations_created_at_idx";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "role";
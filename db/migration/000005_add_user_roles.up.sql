@@ -0,0 +1,5 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'donor';
+
+COMMENT ON COLUMN "users"."role" IS 'donor or admin';
+
+CREATE INDEX ON "donations" ("created_at");
@@ -0,0 +1,16 @@
+CREATE TABLE "offline_donations" (
+  "donation_id" bigint PRIMARY KEY,
+  "external_ref" varchar UNIQUE NOT NULL,
+  "method" varchar NOT NULL,
+  "donor_name" varchar,
+  "donor_email" varchar,
+  "imported_by" bigint NOT NULL,
+  "imported_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "offline_donations" ADD FOREIGN KEY ("donation_id") REFERENCES "donations" ("id") ON DELETE CASCADE;
+ALTER TABLE "offline_donations" ADD FOREIGN KEY ("imported_by") REFERENCES "users" ("id");
+
+COMMENT ON TABLE "offline_donations" IS 'cash and bank transfer donations imported by admins';
+COMMENT ON COLUMN "offline_donations"."external_ref" IS 'reference from the source system, makes re-running an import idempotent';
+COMMENT ON COLUMN "offline_donations"."method" IS 'cash or bank_transfer';
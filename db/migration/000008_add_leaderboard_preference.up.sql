@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "hide_from_leaderboards" boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN "users"."hide_from_leaderboards" IS 'leave the user out of public donor leaderboards';
@@ -0,0 +1,44 @@
+CREATE TABLE "outbox_events" (
+  "id" bigserial PRIMARY KEY,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "dispatched_at" timestamptz
+);
+
+CREATE TABLE "webhook_endpoints" (
+  "id" bigserial PRIMARY KEY,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL,
+  "is_active" boolean NOT NULL DEFAULT true,
+  "created_by" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "endpoint_id" bigint NOT NULL,
+  "event_id" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_status_code" int,
+  "last_error" text,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "delivered_at" timestamptz,
+  UNIQUE ("endpoint_id", "event_id")
+);
+
+CREATE INDEX ON "outbox_events" ("id") WHERE "dispatched_at" IS NULL;
+CREATE INDEX ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+CREATE INDEX ON "webhook_deliveries" ("endpoint_id", "created_at");
+
+ALTER TABLE "webhook_endpoints" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("endpoint_id") REFERENCES "webhook_endpoints" ("id") ON DELETE CASCADE;
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("event_id") REFERENCES "outbox_events" ("id");
+
+COMMENT ON TABLE "outbox_events" IS 'domain events written in the same transaction as the change that caused them';
+COMMENT ON COLUMN "outbox_events"."dispatched_at" IS 'set once the event has been fanned out to subscribers';
+COMMENT ON COLUMN "webhook_endpoints"."secret" IS 'HMAC-SHA256 key used to sign deliveries';
+COMMENT ON COLUMN "webhook_deliveries"."status" IS 'pending, succeeded or failed';
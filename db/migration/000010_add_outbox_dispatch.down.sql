@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS "outbox_events_notify" ON "outbox_events";
+DROP FUNCTION IF EXISTS "notify_outbox_event"();
+
+DROP INDEX IF EXISTS "outbox_events_next_attempt_at_idx";
+CREATE INDEX ON "outbox_events" ("id") WHERE "dispatched_at" IS NULL;
+
+ALTER TABLE "outbox_events" DROP COLUMN IF EXISTS "last_error";
+ALTER TABLE "outbox_events" DROP COLUMN IF EXISTS "next_attempt_at";
+ALTER TABLE "outbox_events" DROP COLUMN IF EXISTS "attempts";
@@ -0,0 +1,20 @@
+ALTER TABLE "outbox_events" ADD COLUMN "attempts" int NOT NULL DEFAULT 0;
+ALTER TABLE "outbox_events" ADD COLUMN "next_attempt_at" timestamptz NOT NULL DEFAULT (now());
+ALTER TABLE "outbox_events" ADD COLUMN "last_error" text;
+
+DROP INDEX IF EXISTS "outbox_events_id_idx";
+CREATE INDEX ON "outbox_events" ("next_attempt_at") WHERE "dispatched_at" IS NULL;
+
+COMMENT ON COLUMN "outbox_events"."next_attempt_at" IS 'events whose subscribers failed are retried with backoff';
+
+-- Wake up dispatchers as soon as the transaction that wrote an event commits
+CREATE FUNCTION "notify_outbox_event"() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('outbox_events', '');
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "outbox_events_notify"
+AFTER INSERT ON "outbox_events"
+FOR EACH STATEMENT EXECUTE FUNCTION "notify_outbox_event"();
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS "goals_notify_progress" ON "goals";
+DROP FUNCTION IF EXISTS "notify_goal_progress"();
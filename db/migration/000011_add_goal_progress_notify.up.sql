@@ -0,0 +1,13 @@
+-- Let every replica push goal progress to its live streams once a donation commits
+CREATE FUNCTION "notify_goal_progress"() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('goal_progress', NEW.id::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "goals_notify_progress"
+AFTER UPDATE OF "collected_amount" ON "goals"
+FOR EACH ROW
+WHEN (OLD."collected_amount" IS DISTINCT FROM NEW."collected_amount")
+EXECUTE FUNCTION "notify_goal_progress"();
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS "donations_notify_created" ON "donations";
+DROP FUNCTION IF EXISTS "notify_donation_created"();
+
+ALTER TABLE "events" DROP COLUMN IF EXISTS "goal_id";
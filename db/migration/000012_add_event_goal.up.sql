@@ -0,0 +1,19 @@
+ALTER TABLE "events" ADD COLUMN "goal_id" bigint;
+
+ALTER TABLE "events" ADD FOREIGN KEY ("goal_id") REFERENCES "goals" ("id") ON DELETE SET NULL;
+
+CREATE INDEX ON "events" ("goal_id");
+
+COMMENT ON COLUMN "events"."goal_id" IS 'goal the event raises money for';
+
+-- Let every replica push new donations to its live feeds once they commit
+CREATE FUNCTION "notify_donation_created"() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('donations', NEW.id::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "donations_notify_created"
+AFTER INSERT ON "donations"
+FOR EACH ROW EXECUTE FUNCTION "notify_donation_created"();
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "event_reminders";
+ALTER TABLE "event_bookings" DROP COLUMN IF EXISTS "reminders_enabled";
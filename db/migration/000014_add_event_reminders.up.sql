@@ -0,0 +1,15 @@
+ALTER TABLE "event_bookings" ADD COLUMN "reminders_enabled" boolean NOT NULL DEFAULT true;
+
+CREATE TABLE "event_reminders" (
+  "id" bigserial PRIMARY KEY,
+  "booking_id" bigint NOT NULL,
+  "offset_minutes" int NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("booking_id", "offset_minutes")
+);
+
+ALTER TABLE "event_reminders" ADD FOREIGN KEY ("booking_id") REFERENCES "event_bookings" ("id") ON DELETE CASCADE;
+
+COMMENT ON COLUMN "event_bookings"."reminders_enabled" IS 'remind the user before the event';
+COMMENT ON TABLE "event_reminders" IS 'reminders already scheduled, so each is sent once';
+COMMENT ON COLUMN "event_reminders"."offset_minutes" IS 'how long before the event the reminder is due';
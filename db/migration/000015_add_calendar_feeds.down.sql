@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS "event_bookings_record_cancellation" ON "event_bookings";
+DROP FUNCTION IF EXISTS "record_booking_cancellation"();
+DROP TRIGGER IF EXISTS "events_record_cancellation" ON "events";
+DROP FUNCTION IF EXISTS "record_event_cancellation"();
+
+DROP TABLE IF EXISTS "calendar_tokens";
+DROP TABLE IF EXISTS "event_cancellations";
+
+ALTER TABLE "events" DROP COLUMN IF EXISTS "sequence";
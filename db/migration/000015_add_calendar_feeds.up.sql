@@ -0,0 +1,70 @@
+ALTER TABLE "events" ADD COLUMN "sequence" int NOT NULL DEFAULT 0;
+
+CREATE TABLE "event_cancellations" (
+  "id" bigserial PRIMARY KEY,
+  "event_id" bigint NOT NULL,
+  "user_id" bigint,
+  "name" varchar NOT NULL,
+  "place" varchar NOT NULL,
+  "date" timestamptz NOT NULL,
+  "sequence" int NOT NULL,
+  "cancelled_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "calendar_tokens" (
+  "user_id" bigint PRIMARY KEY,
+  "token_hash" varchar UNIQUE NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "event_cancellations" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "calendar_tokens" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "event_cancellations" ("event_id");
+
+CREATE INDEX ON "event_cancellations" ("user_id", "date");
+
+COMMENT ON COLUMN "events"."sequence" IS 'iCalendar revision, bumped by every update';
+COMMENT ON TABLE "event_cancellations" IS 'deleted events and cancelled bookings, kept so calendar feeds can cancel them';
+COMMENT ON COLUMN "event_cancellations"."user_id" IS 'user whose booking was cancelled, or NULL when the event was deleted';
+COMMENT ON COLUMN "event_cancellations"."sequence" IS 'iCalendar revision of the cancellation';
+COMMENT ON TABLE "calendar_tokens" IS 'secret tokens of personal calendar feed URLs';
+COMMENT ON COLUMN "calendar_tokens"."token_hash" IS 'SHA-256 of the token, which is only shown to the user';
+
+-- Record a deleted event for the public feed and for the feed of everyone who booked it.
+-- This runs before the bookings are deleted by the cascade, while they can still be read.
+CREATE FUNCTION "record_event_cancellation"() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO "event_cancellations" ("event_id", "user_id", "name", "place", "date", "sequence")
+  VALUES (OLD.id, NULL, OLD.name, OLD.place, OLD.date, OLD.sequence + 1);
+
+  INSERT INTO "event_cancellations" ("event_id", "user_id", "name", "place", "date", "sequence")
+  SELECT OLD.id, eb.user_id, OLD.name, OLD.place, OLD.date, OLD.sequence + 1
+  FROM "event_bookings" eb
+  WHERE eb.event_id = OLD.id;
+
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "events_record_cancellation"
+BEFORE DELETE ON "events"
+FOR EACH ROW EXECUTE FUNCTION "record_event_cancellation"();
+
+-- Record a cancelled booking for the user's feed. Bookings deleted along with their event
+-- find no event here and were already recorded by record_event_cancellation.
+CREATE FUNCTION "record_booking_cancellation"() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO "event_cancellations" ("event_id", "user_id", "name", "place", "date", "sequence")
+  SELECT e.id, OLD.user_id, e.name, e.place, e.date, e.sequence + 1
+  FROM "events" e
+  WHERE e.id = OLD.event_id;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "event_bookings_record_cancellation"
+AFTER DELETE ON "event_bookings"
+FOR EACH ROW EXECUTE FUNCTION "record_booking_cancellation"();
@@ -0,0 +1,4 @@
+COMMENT ON COLUMN "users"."role" IS 'donor or admin';
+
+ALTER TABLE "event_bookings" DROP COLUMN IF EXISTS "checked_in_by";
+ALTER TABLE "event_bookings" DROP COLUMN IF EXISTS "checked_in_at";
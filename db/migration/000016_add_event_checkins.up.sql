@@ -0,0 +1,8 @@
+ALTER TABLE "event_bookings" ADD COLUMN "checked_in_at" timestamptz;
+ALTER TABLE "event_bookings" ADD COLUMN "checked_in_by" bigint;
+
+ALTER TABLE "event_bookings" ADD FOREIGN KEY ("checked_in_by") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+COMMENT ON COLUMN "event_bookings"."checked_in_at" IS 'when the ticket was scanned at the door';
+COMMENT ON COLUMN "event_bookings"."checked_in_by" IS 'staff member who scanned the ticket';
+COMMENT ON COLUMN "users"."role" IS 'donor, staff or admin';
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "ticket_refunds";
+
+ALTER TABLE "event_bookings" DROP COLUMN IF EXISTS "donation_id";
+
+ALTER TABLE "events" DROP CONSTRAINT IF EXISTS "events_ticket_price_check";
+ALTER TABLE "events" DROP COLUMN IF EXISTS "ticket_price";
@@ -0,0 +1,26 @@
+ALTER TABLE "events" ADD COLUMN "ticket_price" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "events" ADD CONSTRAINT "events_ticket_price_check" CHECK ("ticket_price" >= 0);
+
+ALTER TABLE "event_bookings" ADD COLUMN "donation_id" bigint UNIQUE;
+
+ALTER TABLE "event_bookings" ADD FOREIGN KEY ("donation_id") REFERENCES "donations" ("id") ON DELETE SET NULL;
+
+CREATE TABLE "ticket_refunds" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "event_id" bigint NOT NULL,
+  "goal_id" bigint NOT NULL,
+  "donation_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "refunded_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "ticket_refunds" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "ticket_refunds" ("user_id");
+CREATE INDEX ON "ticket_refunds" ("event_id");
+
+COMMENT ON COLUMN "events"."ticket_price" IS 'price of a ticket in cents, donated to the linked goal; 0 for free events';
+COMMENT ON COLUMN "event_bookings"."donation_id" IS 'donation that paid for the ticket of a paid event';
+COMMENT ON TABLE "ticket_refunds" IS 'paid tickets refunded on cancellation; the refunded donation is deleted';
+COMMENT ON COLUMN "ticket_refunds"."donation_id" IS 'id of the deleted donation that paid for the ticket';
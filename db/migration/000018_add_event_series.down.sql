@@ -0,0 +1,5 @@
+ALTER TABLE "events" DROP COLUMN IF EXISTS "detached";
+ALTER TABLE "events" DROP COLUMN IF EXISTS "recurrence_id";
+ALTER TABLE "events" DROP COLUMN IF EXISTS "series_id";
+
+DROP TABLE IF EXISTS "event_series";
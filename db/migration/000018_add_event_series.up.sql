@@ -0,0 +1,34 @@
+CREATE TABLE "event_series" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "place" varchar NOT NULL,
+  "goal_id" bigint,
+  "ticket_price" bigint NOT NULL DEFAULT 0,
+  "starts_at" timestamptz NOT NULL,
+  "rrule" varchar NOT NULL,
+  "exdates" timestamptz[] NOT NULL DEFAULT '{}',
+  "materialized_until" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "event_series" ADD FOREIGN KEY ("goal_id") REFERENCES "goals" ("id") ON DELETE SET NULL;
+ALTER TABLE "event_series" ADD CONSTRAINT "event_series_ticket_price_check" CHECK ("ticket_price" >= 0);
+
+CREATE INDEX ON "event_series" ("materialized_until");
+
+ALTER TABLE "events" ADD COLUMN "series_id" bigint;
+ALTER TABLE "events" ADD COLUMN "recurrence_id" timestamptz;
+ALTER TABLE "events" ADD COLUMN "detached" boolean NOT NULL DEFAULT false;
+
+ALTER TABLE "events" ADD FOREIGN KEY ("series_id") REFERENCES "event_series" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX ON "events" ("series_id", "recurrence_id");
+
+COMMENT ON TABLE "event_series" IS 'recurring events, materialized as one event per occurrence';
+COMMENT ON COLUMN "event_series"."starts_at" IS 'start of the first occurrence (DTSTART)';
+COMMENT ON COLUMN "event_series"."rrule" IS 'RFC 5545 recurrence rule';
+COMMENT ON COLUMN "event_series"."exdates" IS 'start times of cancelled occurrences';
+COMMENT ON COLUMN "event_series"."materialized_until" IS 'occurrences starting before this time exist as events';
+COMMENT ON COLUMN "events"."series_id" IS 'series the event is an occurrence of';
+COMMENT ON COLUMN "events"."recurrence_id" IS 'start of the occurrence in its series, which stays the same when only this occurrence is moved';
+COMMENT ON COLUMN "events"."detached" IS 'occurrence edited on its own, keeping its changes when the series is edited';
@@ -0,0 +1,7 @@
+ALTER TABLE "event_series" DROP COLUMN IF EXISTS "ends_at";
+ALTER TABLE "event_series" DROP COLUMN IF EXISTS "time_zone";
+
+ALTER TABLE "events" DROP COLUMN IF EXISTS "ends_at";
+ALTER TABLE "events" DROP COLUMN IF EXISTS "time_zone";
+
+COMMENT ON COLUMN "events"."date" IS 'event date and time';
@@ -0,0 +1,22 @@
+ALTER TABLE "events" ADD COLUMN "time_zone" varchar NOT NULL DEFAULT 'UTC';
+ALTER TABLE "events" ADD COLUMN "ends_at" timestamptz;
+
+-- Existing events are given the two hour length calendar feeds assumed for them
+UPDATE "events" SET "ends_at" = "date" + interval '2 hours';
+
+ALTER TABLE "events" ALTER COLUMN "ends_at" SET NOT NULL;
+ALTER TABLE "events" ADD CONSTRAINT "events_ends_at_check" CHECK ("ends_at" > "date");
+
+ALTER TABLE "event_series" ADD COLUMN "time_zone" varchar NOT NULL DEFAULT 'UTC';
+ALTER TABLE "event_series" ADD COLUMN "ends_at" timestamptz;
+
+UPDATE "event_series" SET "ends_at" = "starts_at" + interval '2 hours';
+
+ALTER TABLE "event_series" ALTER COLUMN "ends_at" SET NOT NULL;
+ALTER TABLE "event_series" ADD CONSTRAINT "event_series_ends_at_check" CHECK ("ends_at" > "starts_at");
+
+COMMENT ON COLUMN "events"."date" IS 'start of the event';
+COMMENT ON COLUMN "events"."time_zone" IS 'IANA time zone of the venue';
+COMMENT ON COLUMN "events"."ends_at" IS 'end of the event';
+COMMENT ON COLUMN "event_series"."time_zone" IS 'IANA time zone of the venue, which occurrences keep their local time of day in';
+COMMENT ON COLUMN "event_series"."ends_at" IS 'end of the first occurrence (DTEND), giving every occurrence its length';
@@ -0,0 +1,31 @@
+CREATE TABLE "venues" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "address_line" varchar NOT NULL,
+  "city" varchar NOT NULL,
+  "region" varchar NOT NULL DEFAULT '',
+  "postal_code" varchar NOT NULL DEFAULT '',
+  "country" varchar NOT NULL,
+  "latitude" double precision NOT NULL CHECK ("latitude" BETWEEN -90 AND 90),
+  "longitude" double precision NOT NULL CHECK ("longitude" BETWEEN -180 AND 180),
+  "time_zone" varchar NOT NULL DEFAULT 'UTC',
+  "capacity" integer CHECK ("capacity" > 0),
+  "accessibility_notes" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON "venues" ("latitude");
+
+ALTER TABLE "events" ADD COLUMN "venue_id" bigint REFERENCES "venues" ("id") ON DELETE SET NULL;
+ALTER TABLE "event_series" ADD COLUMN "venue_id" bigint REFERENCES "venues" ("id") ON DELETE SET NULL;
+
+CREATE INDEX ON "events" ("venue_id");
+
+COMMENT ON TABLE "venues" IS 'places events are held at, with coordinates for maps and nearby search';
+COMMENT ON COLUMN "venues"."country" IS 'ISO 3166-1 alpha-2 country code';
+COMMENT ON COLUMN "venues"."latitude" IS 'WGS 84 latitude in degrees';
+COMMENT ON COLUMN "venues"."longitude" IS 'WGS 84 longitude in degrees';
+COMMENT ON COLUMN "venues"."time_zone" IS 'IANA time zone events at the venue default to';
+COMMENT ON COLUMN "venues"."capacity" IS 'how many people the venue holds, NULL when unknown';
+COMMENT ON COLUMN "events"."venue_id" IS 'venue the event is held at; place stays the free-text label';
+COMMENT ON COLUMN "event_series"."venue_id" IS 'venue occurrences are held at';
@@ -0,0 +1,6 @@
+ALTER TABLE "event_bookings" DROP CONSTRAINT "event_bookings_event_id_fkey";
+ALTER TABLE "event_bookings" ADD FOREIGN KEY ("event_id") REFERENCES "events" ("id");
+
+ALTER TABLE "events" DROP COLUMN IF EXISTS "status_changed_at";
+ALTER TABLE "events" DROP COLUMN IF EXISTS "status_reason";
+ALTER TABLE "events" DROP COLUMN IF EXISTS "status";
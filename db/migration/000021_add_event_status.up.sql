@@ -0,0 +1,16 @@
+ALTER TABLE "events" ADD COLUMN "status" varchar NOT NULL DEFAULT 'scheduled';
+ALTER TABLE "events" ADD COLUMN "status_reason" varchar NOT NULL DEFAULT '';
+ALTER TABLE "events" ADD COLUMN "status_changed_at" timestamptz;
+
+ALTER TABLE "events" ADD CONSTRAINT "events_status_check"
+  CHECK ("status" IN ('scheduled', 'postponed', 'rescheduled', 'cancelled'));
+
+-- Bookings go with their event, as record_event_cancellation and deleted series occurrences expect
+ALTER TABLE "event_bookings" DROP CONSTRAINT "event_bookings_event_id_fkey";
+ALTER TABLE "event_bookings" ADD FOREIGN KEY ("event_id") REFERENCES "events" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "events" ("status", "date");
+
+COMMENT ON COLUMN "events"."status" IS 'scheduled, postponed (new date to be announced), rescheduled or cancelled';
+COMMENT ON COLUMN "events"."status_reason" IS 'why the event was postponed, rescheduled or cancelled';
+COMMENT ON COLUMN "events"."status_changed_at" IS 'when the event was last postponed, rescheduled or cancelled';
@@ -0,0 +1,39 @@
+-- Guest bookings can't be kept without a user. They are deleted while the triggers still skip them.
+DELETE FROM "event_bookings" WHERE "user_id" IS NULL;
+
+CREATE OR REPLACE FUNCTION "record_booking_cancellation"() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO "event_cancellations" ("event_id", "user_id", "name", "place", "date", "sequence")
+  SELECT e.id, OLD.user_id, e.name, e.place, e.date, e.sequence + 1
+  FROM "events" e
+  WHERE e.id = OLD.event_id;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION "record_event_cancellation"() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO "event_cancellations" ("event_id", "user_id", "name", "place", "date", "sequence")
+  VALUES (OLD.id, NULL, OLD.name, OLD.place, OLD.date, OLD.sequence + 1);
+
+  INSERT INTO "event_cancellations" ("event_id", "user_id", "name", "place", "date", "sequence")
+  SELECT OLD.id, eb.user_id, OLD.name, OLD.place, OLD.date, OLD.sequence + 1
+  FROM "event_bookings" eb
+  WHERE eb.event_id = OLD.id;
+
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS "event_bookings_guest_email_key";
+
+ALTER TABLE "event_bookings" DROP COLUMN IF EXISTS "confirmed_at";
+ALTER TABLE "event_bookings" DROP COLUMN IF EXISTS "guest_locale";
+ALTER TABLE "event_bookings" DROP COLUMN IF EXISTS "guest_email";
+ALTER TABLE "event_bookings" DROP COLUMN IF EXISTS "guest_name";
+ALTER TABLE "event_bookings" DROP COLUMN IF EXISTS "party_names";
+ALTER TABLE "event_bookings" DROP COLUMN IF EXISTS "party_size";
+ALTER TABLE "event_bookings" ALTER COLUMN "user_id" SET NOT NULL;
+
+ALTER TABLE "events" DROP COLUMN IF EXISTS "max_party_size";
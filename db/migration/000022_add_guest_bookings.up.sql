@@ -0,0 +1,61 @@
+ALTER TABLE "events" ADD COLUMN "max_party_size" integer CHECK ("max_party_size" > 0);
+
+ALTER TABLE "event_bookings" ALTER COLUMN "user_id" DROP NOT NULL;
+ALTER TABLE "event_bookings" ADD COLUMN "party_size" integer NOT NULL DEFAULT 1 CHECK ("party_size" > 0);
+ALTER TABLE "event_bookings" ADD COLUMN "party_names" varchar[] NOT NULL DEFAULT '{}';
+ALTER TABLE "event_bookings" ADD COLUMN "guest_name" varchar;
+ALTER TABLE "event_bookings" ADD COLUMN "guest_email" varchar;
+ALTER TABLE "event_bookings" ADD COLUMN "guest_locale" varchar NOT NULL DEFAULT 'en';
+ALTER TABLE "event_bookings" ADD COLUMN "confirmed_at" timestamptz DEFAULT (now());
+
+UPDATE "event_bookings" SET "confirmed_at" = "booked_at";
+
+-- A booking is either a user's or a guest's, and names at most the other people in the party
+ALTER TABLE "event_bookings" ADD CONSTRAINT "event_bookings_booker_check" CHECK (
+  ("user_id" IS NOT NULL AND "guest_email" IS NULL AND "guest_name" IS NULL)
+  OR ("user_id" IS NULL AND "guest_email" IS NOT NULL AND "guest_name" IS NOT NULL)
+);
+ALTER TABLE "event_bookings" ADD CONSTRAINT "event_bookings_party_names_check"
+  CHECK (cardinality("party_names") < "party_size");
+
+CREATE UNIQUE INDEX "event_bookings_guest_email_key" ON "event_bookings" ("event_id", lower("guest_email"))
+WHERE "user_id" IS NULL;
+
+COMMENT ON COLUMN "events"."max_party_size" IS 'most people one booking may be for, NULL for no limit';
+COMMENT ON COLUMN "event_bookings"."user_id" IS 'user who booked, NULL for guest bookings';
+COMMENT ON COLUMN "event_bookings"."party_size" IS 'how many people the booking is for, the booker included';
+COMMENT ON COLUMN "event_bookings"."party_names" IS 'names of the other people in the party';
+COMMENT ON COLUMN "event_bookings"."guest_name" IS 'name of a guest booking without an account';
+COMMENT ON COLUMN "event_bookings"."guest_email" IS 'email a guest booking is confirmed from';
+COMMENT ON COLUMN "event_bookings"."guest_locale" IS 'language of the emails sent to a guest';
+COMMENT ON COLUMN "event_bookings"."confirmed_at" IS 'when a guest confirmed their email, NULL until then; user bookings are confirmed when made';
+
+-- Guests have no calendar feed, and a cancellation without a user would cancel the event in the public feed
+CREATE OR REPLACE FUNCTION "record_event_cancellation"() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO "event_cancellations" ("event_id", "user_id", "name", "place", "date", "sequence")
+  VALUES (OLD.id, NULL, OLD.name, OLD.place, OLD.date, OLD.sequence + 1);
+
+  INSERT INTO "event_cancellations" ("event_id", "user_id", "name", "place", "date", "sequence")
+  SELECT OLD.id, eb.user_id, OLD.name, OLD.place, OLD.date, OLD.sequence + 1
+  FROM "event_bookings" eb
+  WHERE eb.event_id = OLD.id AND eb.user_id IS NOT NULL;
+
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION "record_booking_cancellation"() RETURNS trigger AS $$
+BEGIN
+  IF OLD.user_id IS NULL THEN
+    RETURN NULL;
+  END IF;
+
+  INSERT INTO "event_cancellations" ("event_id", "user_id", "name", "place", "date", "sequence")
+  SELECT e.id, OLD.user_id, e.name, e.place, e.date, e.sequence + 1
+  FROM "events" e
+  WHERE e.id = OLD.event_id;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
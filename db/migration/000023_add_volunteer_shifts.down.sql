@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "volunteer_shifts";
+DROP TABLE IF EXISTS "volunteer_roles";
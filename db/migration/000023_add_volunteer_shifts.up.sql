@@ -0,0 +1,33 @@
+CREATE TABLE "volunteer_roles" (
+  "id" bigserial PRIMARY KEY,
+  "event_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "description" text NOT NULL DEFAULT '',
+  "slots" integer NOT NULL CHECK ("slots" > 0),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "volunteer_shifts" (
+  "id" bigserial PRIMARY KEY,
+  "role_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "signed_up_at" timestamptz NOT NULL DEFAULT (now()),
+  "minutes" integer CHECK ("minutes" BETWEEN 0 AND 1440),
+  "logged_at" timestamptz,
+  "logged_by" bigint,
+  UNIQUE ("role_id", "user_id")
+);
+
+ALTER TABLE "volunteer_roles" ADD FOREIGN KEY ("event_id") REFERENCES "events" ("id") ON DELETE CASCADE;
+ALTER TABLE "volunteer_shifts" ADD FOREIGN KEY ("role_id") REFERENCES "volunteer_roles" ("id") ON DELETE CASCADE;
+ALTER TABLE "volunteer_shifts" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "volunteer_shifts" ADD FOREIGN KEY ("logged_by") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX ON "volunteer_roles" ("event_id", lower("name"));
+CREATE INDEX ON "volunteer_shifts" ("user_id");
+
+COMMENT ON TABLE "volunteer_roles" IS 'roles volunteers sign up for at an event, such as setup or the registration desk';
+COMMENT ON COLUMN "volunteer_roles"."slots" IS 'how many volunteers the role needs';
+COMMENT ON TABLE "volunteer_shifts" IS 'volunteers signed up for a role, with the hours they worked once logged';
+COMMENT ON COLUMN "volunteer_shifts"."minutes" IS 'time worked, logged by staff after the event; NULL until logged';
+COMMENT ON COLUMN "volunteer_shifts"."logged_by" IS 'staff member who logged the time worked';
@@ -0,0 +1,31 @@
+DROP MATERIALIZED VIEW "daily_stats";
+
+CREATE MATERIALIZED VIEW "daily_stats" AS
+SELECT
+  "day",
+  COALESCE(d.donation_count, 0)::bigint AS "donation_count",
+  COALESCE(d.donation_amount, 0)::bigint AS "donation_amount",
+  COALESCE(u.new_users, 0)::bigint AS "new_users",
+  COALESCE(e.events_held, 0)::bigint AS "events_held",
+  COALESCE(e.attendees, 0)::bigint AS "attendees"
+FROM (
+  SELECT (created_at AT TIME ZONE 'UTC')::date AS "day", COUNT(*) AS donation_count, SUM(amount) AS donation_amount
+  FROM "donations"
+  GROUP BY 1
+) d
+FULL JOIN (
+  SELECT (created_at AT TIME ZONE 'UTC')::date AS "day", COUNT(*) AS new_users
+  FROM "users"
+  GROUP BY 1
+) u USING ("day")
+FULL JOIN (
+  SELECT (ev.date AT TIME ZONE 'UTC')::date AS "day", COUNT(DISTINCT ev.id) AS events_held, COUNT(eb.id) AS attendees
+  FROM "events" ev
+  LEFT JOIN "event_bookings" eb ON eb.event_id = ev.id
+  GROUP BY 1
+) e USING ("day");
+
+-- Required by REFRESH MATERIALIZED VIEW CONCURRENTLY
+CREATE UNIQUE INDEX ON "daily_stats" ("day");
+
+COMMENT ON MATERIALIZED VIEW "daily_stats" IS 'per-day UTC totals for the admin dashboard, refreshed by a background job';
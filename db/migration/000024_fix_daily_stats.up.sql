@@ -0,0 +1,41 @@
+DROP MATERIALIZED VIEW "daily_stats";
+
+-- Cancelled and postponed events weren't held on their date, and only confirmed bookings
+-- count, each for its whole party. Refunded tickets are counted on the day of the refund.
+CREATE MATERIALIZED VIEW "daily_stats" AS
+SELECT
+  "day",
+  COALESCE(d.donation_count, 0)::bigint AS "donation_count",
+  COALESCE(d.donation_amount, 0)::bigint AS "donation_amount",
+  COALESCE(u.new_users, 0)::bigint AS "new_users",
+  COALESCE(e.events_held, 0)::bigint AS "events_held",
+  COALESCE(e.attendees, 0)::bigint AS "attendees",
+  COALESCE(r.refund_count, 0)::bigint AS "refund_count",
+  COALESCE(r.refund_amount, 0)::bigint AS "refund_amount"
+FROM (
+  SELECT (created_at AT TIME ZONE 'UTC')::date AS "day", COUNT(*) AS donation_count, SUM(amount) AS donation_amount
+  FROM "donations"
+  GROUP BY 1
+) d
+FULL JOIN (
+  SELECT (created_at AT TIME ZONE 'UTC')::date AS "day", COUNT(*) AS new_users
+  FROM "users"
+  GROUP BY 1
+) u USING ("day")
+FULL JOIN (
+  SELECT (ev.date AT TIME ZONE 'UTC')::date AS "day", COUNT(DISTINCT ev.id) AS events_held, SUM(eb.party_size) AS attendees
+  FROM "events" ev
+  LEFT JOIN "event_bookings" eb ON eb.event_id = ev.id AND eb.confirmed_at IS NOT NULL
+  WHERE ev.status IN ('scheduled', 'rescheduled')
+  GROUP BY 1
+) e USING ("day")
+FULL JOIN (
+  SELECT (refunded_at AT TIME ZONE 'UTC')::date AS "day", COUNT(*) AS refund_count, SUM(amount) AS refund_amount
+  FROM "ticket_refunds"
+  GROUP BY 1
+) r USING ("day");
+
+-- Required by REFRESH MATERIALIZED VIEW CONCURRENTLY
+CREATE UNIQUE INDEX ON "daily_stats" ("day");
+
+COMMENT ON MATERIALIZED VIEW "daily_stats" IS 'per-day UTC totals for the admin dashboard, refreshed by a background job';
@@ -0,0 +1 @@
+ALTER TABLE "venues" ALTER COLUMN "created_at" SET DEFAULT 'now()';
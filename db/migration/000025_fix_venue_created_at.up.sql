@@ -0,0 +1,2 @@
+-- A quoted 'now()' is evaluated once, when the table is created
+ALTER TABLE "venues" ALTER COLUMN "created_at" SET DEFAULT (now());
@@ -0,0 +1,16 @@
+CREATE OR REPLACE FUNCTION "record_event_cancellation"() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO "event_cancellations" ("event_id", "user_id", "name", "place", "date", "sequence")
+  VALUES (OLD.id, NULL, OLD.name, OLD.place, OLD.date, OLD.sequence + 1);
+
+  INSERT INTO "event_cancellations" ("event_id", "user_id", "name", "place", "date", "sequence")
+  SELECT OLD.id, eb.user_id, OLD.name, OLD.place, OLD.date, OLD.sequence + 1
+  FROM "event_bookings" eb
+  WHERE eb.event_id = OLD.id AND eb.user_id IS NOT NULL;
+
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE "event_bookings" DROP CONSTRAINT "event_bookings_event_id_fkey";
+ALTER TABLE "event_bookings" ADD FOREIGN KEY ("event_id") REFERENCES "events" ("id") ON DELETE CASCADE;
@@ -0,0 +1,14 @@
+-- Booked events must be cancelled rather than deleted, so their bookers are told
+ALTER TABLE "event_bookings" DROP CONSTRAINT "event_bookings_event_id_fkey";
+ALTER TABLE "event_bookings" ADD FOREIGN KEY ("event_id") REFERENCES "events" ("id");
+
+-- Record a deleted event for the public feed. Events with bookings can't be deleted,
+-- so their bookers' feeds already have the cancellation of each booking.
+CREATE OR REPLACE FUNCTION "record_event_cancellation"() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO "event_cancellations" ("event_id", "user_id", "name", "place", "date", "sequence")
+  VALUES (OLD.id, NULL, OLD.name, OLD.place, OLD.date, OLD.sequence + 1);
+
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
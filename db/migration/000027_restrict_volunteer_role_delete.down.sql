@@ -0,0 +1,2 @@
+ALTER TABLE "volunteer_roles" DROP CONSTRAINT "volunteer_roles_event_id_fkey";
+ALTER TABLE "volunteer_roles" ADD FOREIGN KEY ("event_id") REFERENCES "events" ("id") ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+-- Roles with logged hours keep their event, so volunteers don't lose their history
+ALTER TABLE "volunteer_roles" DROP CONSTRAINT "volunteer_roles_event_id_fkey";
+ALTER TABLE "volunteer_roles" ADD FOREIGN KEY ("event_id") REFERENCES "events" ("id");
@@ -0,0 +1,22 @@
+CREATE TABLE "receipt_donations" (
+  "receipt_id" bigint NOT NULL,
+  "donation_id" bigint NOT NULL,
+  PRIMARY KEY ("receipt_id", "donation_id")
+);
+
+CREATE INDEX ON "receipt_donations" ("donation_id");
+
+ALTER TABLE "receipt_donations" ADD FOREIGN KEY ("receipt_id") REFERENCES "receipts" ("id");
+ALTER TABLE "receipt_donations" ADD FOREIGN KEY ("donation_id") REFERENCES "donations" ("id");
+
+COMMENT ON TABLE "receipt_donations" IS 'donations itemised on an annual receipt, fixed when the receipt is issued';
+
+-- Annual receipts issued so far itemise the donations of their year recorded before they were issued
+INSERT INTO "receipt_donations" ("receipt_id", "donation_id")
+SELECT r.id, d.id
+FROM "receipts" r
+JOIN "donations" d ON d.user_id = r.user_id
+WHERE r.kind = 'annual'
+  AND d.created_at >= make_timestamptz(r.year, 1, 1, 0, 0, 0, 'UTC')
+  AND d.created_at < make_timestamptz(r.year + 1, 1, 1, 0, 0, 0, 'UTC')
+  AND d.created_at <= r.issued_at;
@@ -0,0 +1,10 @@
+CREATE TABLE "outbox_deliveries" (
+  "event_id" bigint NOT NULL,
+  "subscriber" varchar NOT NULL,
+  "delivered_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("event_id", "subscriber")
+);
+
+ALTER TABLE "outbox_deliveries" ADD FOREIGN KEY ("event_id") REFERENCES "outbox_events" ("id") ON DELETE CASCADE;
+
+COMMENT ON TABLE "outbox_deliveries" IS 'subscribers that handled an outbox event, so retries of the event skip them';
@@ -0,0 +1 @@
+ALTER TABLE "offline_donations" DROP COLUMN IF EXISTS "donor_name_public";
@@ -0,0 +1,3 @@
+ALTER TABLE "offline_donations" ADD COLUMN "donor_name_public" boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN "offline_donations"."donor_name_public" IS 'the donor agreed to have their name shown in the public donation feed';
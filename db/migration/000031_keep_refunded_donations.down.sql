@@ -0,0 +1,5 @@
+ALTER TABLE "ticket_refunds" DROP CONSTRAINT IF EXISTS "ticket_refunds_donation_id_fkey";
+ALTER TABLE "ticket_refunds" DROP CONSTRAINT IF EXISTS "ticket_refunds_donation_id_key";
+
+COMMENT ON TABLE "ticket_refunds" IS 'paid tickets refunded on cancellation; the refunded donation is deleted';
+COMMENT ON COLUMN "ticket_refunds"."donation_id" IS 'id of the deleted donation that paid for the ticket';
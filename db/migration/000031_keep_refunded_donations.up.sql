@@ -0,0 +1,7 @@
+-- Refunded tickets keep their donation, which the refund reverses, so each donation is refunded at most once.
+-- Refunds recorded before this migration point at donations that were deleted, so they aren't checked.
+ALTER TABLE "ticket_refunds" ADD CONSTRAINT "ticket_refunds_donation_id_key" UNIQUE ("donation_id");
+ALTER TABLE "ticket_refunds" ADD CONSTRAINT "ticket_refunds_donation_id_fkey" FOREIGN KEY ("donation_id") REFERENCES "donations" ("id") NOT VALID;
+
+COMMENT ON TABLE "ticket_refunds" IS 'paid tickets refunded on cancellation; the refunded donation is kept and reversed by the refund';
+COMMENT ON COLUMN "ticket_refunds"."donation_id" IS 'donation that paid for the ticket';
@@ -7,9 +7,11 @@ package mockdb
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
+	pgtype "github.com/jackc/pgx/v5/pgtype"
 	db "github.com/kholodihor/charity/db/sqlc"
 )
 
@@ -36,6 +38,20 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AddReceiptDonation mocks base method.
+func (m *MockStore) AddReceiptDonation(arg0 context.Context, arg1 db.AddReceiptDonationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReceiptDonation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReceiptDonation indicates an expected call of AddReceiptDonation.
+func (mr *MockStoreMockRecorder) AddReceiptDonation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReceiptDonation", reflect.TypeOf((*MockStore)(nil).AddReceiptDonation), arg0, arg1)
+}
+
 // BookEvent mocks base method.
 func (m *MockStore) BookEvent(arg0 context.Context, arg1 db.BookEventParams) (db.EventBooking, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +67,21 @@ func (mr *MockStoreMockRecorder) BookEvent(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookEvent", reflect.TypeOf((*MockStore)(nil).BookEvent), arg0, arg1)
 }
 
+// BookEventTx mocks base method.
+func (m *MockStore) BookEventTx(arg0 context.Context, arg1 db.BookEventParams) (db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookEventTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BookEventTx indicates an expected call of BookEventTx.
+func (mr *MockStoreMockRecorder) BookEventTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookEventTx", reflect.TypeOf((*MockStore)(nil).BookEventTx), arg0, arg1)
+}
+
 // CancelEventBooking mocks base method.
 func (m *MockStore) CancelEventBooking(arg0 context.Context, arg1 db.CancelEventBookingParams) error {
 	m.ctrl.T.Helper()
@@ -65,6 +96,110 @@ func (mr *MockStoreMockRecorder) CancelEventBooking(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelEventBooking", reflect.TypeOf((*MockStore)(nil).CancelEventBooking), arg0, arg1)
 }
 
+// CancelEventBookingTx mocks base method.
+func (m *MockStore) CancelEventBookingTx(arg0 context.Context, arg1 db.CancelEventBookingTxParams) (db.CancelEventBookingTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelEventBookingTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CancelEventBookingTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelEventBookingTx indicates an expected call of CancelEventBookingTx.
+func (mr *MockStoreMockRecorder) CancelEventBookingTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelEventBookingTx", reflect.TypeOf((*MockStore)(nil).CancelEventBookingTx), arg0, arg1)
+}
+
+// CancelEventSeriesTx mocks base method.
+func (m *MockStore) CancelEventSeriesTx(arg0 context.Context, arg1 db.CancelEventSeriesTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelEventSeriesTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelEventSeriesTx indicates an expected call of CancelEventSeriesTx.
+func (mr *MockStoreMockRecorder) CancelEventSeriesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelEventSeriesTx", reflect.TypeOf((*MockStore)(nil).CancelEventSeriesTx), arg0, arg1)
+}
+
+// CancelGuestBookingTx mocks base method.
+func (m *MockStore) CancelGuestBookingTx(arg0 context.Context, arg1 db.GuestBookingTxParams) (db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelGuestBookingTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelGuestBookingTx indicates an expected call of CancelGuestBookingTx.
+func (mr *MockStoreMockRecorder) CancelGuestBookingTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelGuestBookingTx", reflect.TypeOf((*MockStore)(nil).CancelGuestBookingTx), arg0, arg1)
+}
+
+// ChangeEventStatusTx mocks base method.
+func (m *MockStore) ChangeEventStatusTx(arg0 context.Context, arg1 db.ChangeEventStatusTxParams) (db.ChangeEventStatusTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeEventStatusTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ChangeEventStatusTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeEventStatusTx indicates an expected call of ChangeEventStatusTx.
+func (mr *MockStoreMockRecorder) ChangeEventStatusTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeEventStatusTx", reflect.TypeOf((*MockStore)(nil).ChangeEventStatusTx), arg0, arg1)
+}
+
+// CheckInEventBooking mocks base method.
+func (m *MockStore) CheckInEventBooking(arg0 context.Context, arg1 db.CheckInEventBookingParams) (db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckInEventBooking", arg0, arg1)
+	ret0, _ := ret[0].(db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckInEventBooking indicates an expected call of CheckInEventBooking.
+func (mr *MockStoreMockRecorder) CheckInEventBooking(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInEventBooking", reflect.TypeOf((*MockStore)(nil).CheckInEventBooking), arg0, arg1)
+}
+
+// ClaimDueOutboxEvents mocks base method.
+func (m *MockStore) ClaimDueOutboxEvents(arg0 context.Context, arg1 db.ClaimDueOutboxEventsParams) ([]db.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueOutboxEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueOutboxEvents indicates an expected call of ClaimDueOutboxEvents.
+func (mr *MockStoreMockRecorder) ClaimDueOutboxEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueOutboxEvents", reflect.TypeOf((*MockStore)(nil).ClaimDueOutboxEvents), arg0, arg1)
+}
+
+// ClaimDueWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimDueWebhookDeliveries(arg0 context.Context, arg1 db.ClaimDueWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueWebhookDeliveries indicates an expected call of ClaimDueWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimDueWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimDueWebhookDeliveries), arg0, arg1)
+}
+
 // CleanupExpiredRefreshTokens mocks base method.
 func (m *MockStore) CleanupExpiredRefreshTokens(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -79,6 +214,66 @@ func (mr *MockStoreMockRecorder) CleanupExpiredRefreshTokens(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanupExpiredRefreshTokens", reflect.TypeOf((*MockStore)(nil).CleanupExpiredRefreshTokens), arg0)
 }
 
+// ConfirmGuestBooking mocks base method.
+func (m *MockStore) ConfirmGuestBooking(arg0 context.Context, arg1 int64) (db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmGuestBooking", arg0, arg1)
+	ret0, _ := ret[0].(db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmGuestBooking indicates an expected call of ConfirmGuestBooking.
+func (mr *MockStoreMockRecorder) ConfirmGuestBooking(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmGuestBooking", reflect.TypeOf((*MockStore)(nil).ConfirmGuestBooking), arg0, arg1)
+}
+
+// ConfirmGuestBookingTx mocks base method.
+func (m *MockStore) ConfirmGuestBookingTx(arg0 context.Context, arg1 db.GuestBookingTxParams) (db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmGuestBookingTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmGuestBookingTx indicates an expected call of ConfirmGuestBookingTx.
+func (mr *MockStoreMockRecorder) ConfirmGuestBookingTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmGuestBookingTx", reflect.TypeOf((*MockStore)(nil).ConfirmGuestBookingTx), arg0, arg1)
+}
+
+// CountActiveGoals mocks base method.
+func (m *MockStore) CountActiveGoals(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveGoals", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveGoals indicates an expected call of CountActiveGoals.
+func (mr *MockStoreMockRecorder) CountActiveGoals(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveGoals", reflect.TypeOf((*MockStore)(nil).CountActiveGoals), arg0)
+}
+
+// CountVolunteerShifts mocks base method.
+func (m *MockStore) CountVolunteerShifts(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountVolunteerShifts", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountVolunteerShifts indicates an expected call of CountVolunteerShifts.
+func (mr *MockStoreMockRecorder) CountVolunteerShifts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountVolunteerShifts", reflect.TypeOf((*MockStore)(nil).CountVolunteerShifts), arg0, arg1)
+}
+
 // CreateDonation mocks base method.
 func (m *MockStore) CreateDonation(arg0 context.Context, arg1 db.CreateDonationParams) (db.Donation, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +289,36 @@ func (mr *MockStoreMockRecorder) CreateDonation(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDonation", reflect.TypeOf((*MockStore)(nil).CreateDonation), arg0, arg1)
 }
 
+// CreateDonationAt mocks base method.
+func (m *MockStore) CreateDonationAt(arg0 context.Context, arg1 db.CreateDonationAtParams) (db.Donation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDonationAt", arg0, arg1)
+	ret0, _ := ret[0].(db.Donation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDonationAt indicates an expected call of CreateDonationAt.
+func (mr *MockStoreMockRecorder) CreateDonationAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDonationAt", reflect.TypeOf((*MockStore)(nil).CreateDonationAt), arg0, arg1)
+}
+
+// CreateDueEventReminders mocks base method.
+func (m *MockStore) CreateDueEventReminders(arg0 context.Context, arg1 int32) ([]db.CreateDueEventRemindersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDueEventReminders", arg0, arg1)
+	ret0, _ := ret[0].([]db.CreateDueEventRemindersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDueEventReminders indicates an expected call of CreateDueEventReminders.
+func (mr *MockStoreMockRecorder) CreateDueEventReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDueEventReminders", reflect.TypeOf((*MockStore)(nil).CreateDueEventReminders), arg0, arg1)
+}
+
 // CreateEvent mocks base method.
 func (m *MockStore) CreateEvent(arg0 context.Context, arg1 db.CreateEventParams) (db.Event, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +334,51 @@ func (mr *MockStoreMockRecorder) CreateEvent(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockStore)(nil).CreateEvent), arg0, arg1)
 }
 
+// CreateEventOccurrence mocks base method.
+func (m *MockStore) CreateEventOccurrence(arg0 context.Context, arg1 db.CreateEventOccurrenceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEventOccurrence", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEventOccurrence indicates an expected call of CreateEventOccurrence.
+func (mr *MockStoreMockRecorder) CreateEventOccurrence(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEventOccurrence", reflect.TypeOf((*MockStore)(nil).CreateEventOccurrence), arg0, arg1)
+}
+
+// CreateEventSeries mocks base method.
+func (m *MockStore) CreateEventSeries(arg0 context.Context, arg1 db.CreateEventSeriesParams) (db.EventSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEventSeries", arg0, arg1)
+	ret0, _ := ret[0].(db.EventSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEventSeries indicates an expected call of CreateEventSeries.
+func (mr *MockStoreMockRecorder) CreateEventSeries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEventSeries", reflect.TypeOf((*MockStore)(nil).CreateEventSeries), arg0, arg1)
+}
+
+// CreateEventSeriesTx mocks base method.
+func (m *MockStore) CreateEventSeriesTx(arg0 context.Context, arg1 db.CreateEventSeriesTxParams) (db.EventSeriesTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEventSeriesTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EventSeriesTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEventSeriesTx indicates an expected call of CreateEventSeriesTx.
+func (mr *MockStoreMockRecorder) CreateEventSeriesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEventSeriesTx", reflect.TypeOf((*MockStore)(nil).CreateEventSeriesTx), arg0, arg1)
+}
+
 // CreateGoal mocks base method.
 func (m *MockStore) CreateGoal(arg0 context.Context, arg1 db.CreateGoalParams) (db.Goal, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +394,81 @@ func (mr *MockStoreMockRecorder) CreateGoal(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGoal", reflect.TypeOf((*MockStore)(nil).CreateGoal), arg0, arg1)
 }
 
+// CreateGuestBooking mocks base method.
+func (m *MockStore) CreateGuestBooking(arg0 context.Context, arg1 db.CreateGuestBookingParams) (db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGuestBooking", arg0, arg1)
+	ret0, _ := ret[0].(db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGuestBooking indicates an expected call of CreateGuestBooking.
+func (mr *MockStoreMockRecorder) CreateGuestBooking(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGuestBooking", reflect.TypeOf((*MockStore)(nil).CreateGuestBooking), arg0, arg1)
+}
+
+// CreateGuestBookingTx mocks base method.
+func (m *MockStore) CreateGuestBookingTx(arg0 context.Context, arg1 db.CreateGuestBookingParams) (db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGuestBookingTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGuestBookingTx indicates an expected call of CreateGuestBookingTx.
+func (mr *MockStoreMockRecorder) CreateGuestBookingTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGuestBookingTx", reflect.TypeOf((*MockStore)(nil).CreateGuestBookingTx), arg0, arg1)
+}
+
+// CreateOfflineDonation mocks base method.
+func (m *MockStore) CreateOfflineDonation(arg0 context.Context, arg1 db.CreateOfflineDonationParams) (db.OfflineDonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOfflineDonation", arg0, arg1)
+	ret0, _ := ret[0].(db.OfflineDonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOfflineDonation indicates an expected call of CreateOfflineDonation.
+func (mr *MockStoreMockRecorder) CreateOfflineDonation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOfflineDonation", reflect.TypeOf((*MockStore)(nil).CreateOfflineDonation), arg0, arg1)
+}
+
+// CreateOutboxEvent mocks base method.
+func (m *MockStore) CreateOutboxEvent(arg0 context.Context, arg1 db.CreateOutboxEventParams) (db.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxEvent indicates an expected call of CreateOutboxEvent.
+func (mr *MockStoreMockRecorder) CreateOutboxEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxEvent", reflect.TypeOf((*MockStore)(nil).CreateOutboxEvent), arg0, arg1)
+}
+
+// CreateReceipt mocks base method.
+func (m *MockStore) CreateReceipt(arg0 context.Context, arg1 db.CreateReceiptParams) (db.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReceipt", arg0, arg1)
+	ret0, _ := ret[0].(db.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReceipt indicates an expected call of CreateReceipt.
+func (mr *MockStoreMockRecorder) CreateReceipt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReceipt", reflect.TypeOf((*MockStore)(nil).CreateReceipt), arg0, arg1)
+}
+
 // CreateRefreshToken mocks base method.
 func (m *MockStore) CreateRefreshToken(arg0 context.Context, arg1 db.CreateRefreshTokenParams) (db.RefreshToken, error) {
 	m.ctrl.T.Helper()
@@ -139,6 +484,21 @@ func (mr *MockStoreMockRecorder) CreateRefreshToken(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockStore)(nil).CreateRefreshToken), arg0, arg1)
 }
 
+// CreateTicketRefund mocks base method.
+func (m *MockStore) CreateTicketRefund(arg0 context.Context, arg1 db.CreateTicketRefundParams) (db.TicketRefund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTicketRefund", arg0, arg1)
+	ret0, _ := ret[0].(db.TicketRefund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTicketRefund indicates an expected call of CreateTicketRefund.
+func (mr *MockStoreMockRecorder) CreateTicketRefund(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTicketRefund", reflect.TypeOf((*MockStore)(nil).CreateTicketRefund), arg0, arg1)
+}
+
 // CreateUser mocks base method.
 func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -154,331 +514,1473 @@ func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
 }
 
-// DeleteEvent mocks base method.
-func (m *MockStore) DeleteEvent(arg0 context.Context, arg1 int64) error {
+// CreateVenue mocks base method.
+func (m *MockStore) CreateVenue(arg0 context.Context, arg1 db.CreateVenueParams) (db.Venue, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEvent", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateVenue", arg0, arg1)
+	ret0, _ := ret[0].(db.Venue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteEvent indicates an expected call of DeleteEvent.
-func (mr *MockStoreMockRecorder) DeleteEvent(arg0, arg1 interface{}) *gomock.Call {
+// CreateVenue indicates an expected call of CreateVenue.
+func (mr *MockStoreMockRecorder) CreateVenue(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockStore)(nil).DeleteEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVenue", reflect.TypeOf((*MockStore)(nil).CreateVenue), arg0, arg1)
 }
 
-// DeleteGoal mocks base method.
-func (m *MockStore) DeleteGoal(arg0 context.Context, arg1 int64) error {
+// CreateVolunteerRole mocks base method.
+func (m *MockStore) CreateVolunteerRole(arg0 context.Context, arg1 db.CreateVolunteerRoleParams) (db.VolunteerRole, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteGoal", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateVolunteerRole", arg0, arg1)
+	ret0, _ := ret[0].(db.VolunteerRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteGoal indicates an expected call of DeleteGoal.
-func (mr *MockStoreMockRecorder) DeleteGoal(arg0, arg1 interface{}) *gomock.Call {
+// CreateVolunteerRole indicates an expected call of CreateVolunteerRole.
+func (mr *MockStoreMockRecorder) CreateVolunteerRole(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGoal", reflect.TypeOf((*MockStore)(nil).DeleteGoal), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolunteerRole", reflect.TypeOf((*MockStore)(nil).CreateVolunteerRole), arg0, arg1)
 }
 
-// DeleteUser mocks base method.
-func (m *MockStore) DeleteUser(arg0 context.Context, arg1 int64) error {
+// CreateVolunteerShift mocks base method.
+func (m *MockStore) CreateVolunteerShift(arg0 context.Context, arg1 db.CreateVolunteerShiftParams) (db.VolunteerShift, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateVolunteerShift", arg0, arg1)
+	ret0, _ := ret[0].(db.VolunteerShift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVolunteerShift indicates an expected call of CreateVolunteerShift.
+func (mr *MockStoreMockRecorder) CreateVolunteerShift(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolunteerShift", reflect.TypeOf((*MockStore)(nil).CreateVolunteerShift), arg0, arg1)
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockStore) CreateWebhookDelivery(arg0 context.Context, arg1 db.CreateWebhookDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockStoreMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockStoreMockRecorder) CreateWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStore)(nil).DeleteUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookDelivery), arg0, arg1)
 }
 
-// DonateToGoalTx mocks base method.
-func (m *MockStore) DonateToGoalTx(arg0 context.Context, arg1 db.DonateToGoalTxParams) (db.DonateToGoalTxResult, error) {
+// CreateWebhookEndpoint mocks base method.
+func (m *MockStore) CreateWebhookEndpoint(arg0 context.Context, arg1 db.CreateWebhookEndpointParams) (db.WebhookEndpoint, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DonateToGoalTx", arg0, arg1)
-	ret0, _ := ret[0].(db.DonateToGoalTxResult)
+	ret := m.ctrl.Call(m, "CreateWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DonateToGoalTx indicates an expected call of DonateToGoalTx.
-func (mr *MockStoreMockRecorder) DonateToGoalTx(arg0, arg1 interface{}) *gomock.Call {
+// CreateWebhookEndpoint indicates an expected call of CreateWebhookEndpoint.
+func (mr *MockStoreMockRecorder) CreateWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DonateToGoalTx", reflect.TypeOf((*MockStore)(nil).DonateToGoalTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).CreateWebhookEndpoint), arg0, arg1)
 }
 
-// GetDonation mocks base method.
-func (m *MockStore) GetDonation(arg0 context.Context, arg1 int64) (db.Donation, error) {
+// DeleteCalendarToken mocks base method.
+func (m *MockStore) DeleteCalendarToken(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCalendarToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCalendarToken indicates an expected call of DeleteCalendarToken.
+func (mr *MockStoreMockRecorder) DeleteCalendarToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCalendarToken", reflect.TypeOf((*MockStore)(nil).DeleteCalendarToken), arg0, arg1)
+}
+
+// DeleteEvent mocks base method.
+func (m *MockStore) DeleteEvent(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEvent indicates an expected call of DeleteEvent.
+func (mr *MockStoreMockRecorder) DeleteEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockStore)(nil).DeleteEvent), arg0, arg1)
+}
+
+// DeleteEventBooking mocks base method.
+func (m *MockStore) DeleteEventBooking(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventBooking", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEventBooking indicates an expected call of DeleteEventBooking.
+func (mr *MockStoreMockRecorder) DeleteEventBooking(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventBooking", reflect.TypeOf((*MockStore)(nil).DeleteEventBooking), arg0, arg1)
+}
+
+// DeleteEventSeries mocks base method.
+func (m *MockStore) DeleteEventSeries(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventSeries", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEventSeries indicates an expected call of DeleteEventSeries.
+func (mr *MockStoreMockRecorder) DeleteEventSeries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventSeries", reflect.TypeOf((*MockStore)(nil).DeleteEventSeries), arg0, arg1)
+}
+
+// DeleteEventTx mocks base method.
+func (m *MockStore) DeleteEventTx(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEventTx indicates an expected call of DeleteEventTx.
+func (mr *MockStoreMockRecorder) DeleteEventTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventTx", reflect.TypeOf((*MockStore)(nil).DeleteEventTx), arg0, arg1)
+}
+
+// DeleteEventVolunteerRoles mocks base method.
+func (m *MockStore) DeleteEventVolunteerRoles(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventVolunteerRoles", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEventVolunteerRoles indicates an expected call of DeleteEventVolunteerRoles.
+func (mr *MockStoreMockRecorder) DeleteEventVolunteerRoles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventVolunteerRoles", reflect.TypeOf((*MockStore)(nil).DeleteEventVolunteerRoles), arg0, arg1)
+}
+
+// DeleteGoal mocks base method.
+func (m *MockStore) DeleteGoal(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGoal", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGoal indicates an expected call of DeleteGoal.
+func (mr *MockStoreMockRecorder) DeleteGoal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGoal", reflect.TypeOf((*MockStore)(nil).DeleteGoal), arg0, arg1)
+}
+
+// DeleteUser mocks base method.
+func (m *MockStore) DeleteUser(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockStoreMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStore)(nil).DeleteUser), arg0, arg1)
+}
+
+// DeleteVenue mocks base method.
+func (m *MockStore) DeleteVenue(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVenue", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVenue indicates an expected call of DeleteVenue.
+func (mr *MockStoreMockRecorder) DeleteVenue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVenue", reflect.TypeOf((*MockStore)(nil).DeleteVenue), arg0, arg1)
+}
+
+// DeleteVolunteerRole mocks base method.
+func (m *MockStore) DeleteVolunteerRole(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVolunteerRole", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVolunteerRole indicates an expected call of DeleteVolunteerRole.
+func (mr *MockStoreMockRecorder) DeleteVolunteerRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolunteerRole", reflect.TypeOf((*MockStore)(nil).DeleteVolunteerRole), arg0, arg1)
+}
+
+// DeleteVolunteerShift mocks base method.
+func (m *MockStore) DeleteVolunteerShift(arg0 context.Context, arg1 db.DeleteVolunteerShiftParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVolunteerShift", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVolunteerShift indicates an expected call of DeleteVolunteerShift.
+func (mr *MockStoreMockRecorder) DeleteVolunteerShift(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolunteerShift", reflect.TypeOf((*MockStore)(nil).DeleteVolunteerShift), arg0, arg1)
+}
+
+// DeleteWebhookEndpoint mocks base method.
+func (m *MockStore) DeleteWebhookEndpoint(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookEndpoint indicates an expected call of DeleteWebhookEndpoint.
+func (mr *MockStoreMockRecorder) DeleteWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).DeleteWebhookEndpoint), arg0, arg1)
+}
+
+// DonateToGoalTx mocks base method.
+func (m *MockStore) DonateToGoalTx(arg0 context.Context, arg1 db.DonateToGoalTxParams) (db.DonateToGoalTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DonateToGoalTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DonateToGoalTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DonateToGoalTx indicates an expected call of DonateToGoalTx.
+func (mr *MockStoreMockRecorder) DonateToGoalTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DonateToGoalTx", reflect.TypeOf((*MockStore)(nil).DonateToGoalTx), arg0, arg1)
+}
+
+// GetAnnualReceipt mocks base method.
+func (m *MockStore) GetAnnualReceipt(arg0 context.Context, arg1 db.GetAnnualReceiptParams) (db.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnnualReceipt", arg0, arg1)
+	ret0, _ := ret[0].(db.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnnualReceipt indicates an expected call of GetAnnualReceipt.
+func (mr *MockStoreMockRecorder) GetAnnualReceipt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnnualReceipt", reflect.TypeOf((*MockStore)(nil).GetAnnualReceipt), arg0, arg1)
+}
+
+// GetAnonymousDonationTotals mocks base method.
+func (m *MockStore) GetAnonymousDonationTotals(arg0 context.Context, arg1 pgtype.Int8) (db.GetAnonymousDonationTotalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnonymousDonationTotals", arg0, arg1)
+	ret0, _ := ret[0].(db.GetAnonymousDonationTotalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnonymousDonationTotals indicates an expected call of GetAnonymousDonationTotals.
+func (mr *MockStoreMockRecorder) GetAnonymousDonationTotals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnonymousDonationTotals", reflect.TypeOf((*MockStore)(nil).GetAnonymousDonationTotals), arg0, arg1)
+}
+
+// GetCalendarTokenByHash mocks base method.
+func (m *MockStore) GetCalendarTokenByHash(arg0 context.Context, arg1 string) (db.CalendarToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarTokenByHash", arg0, arg1)
+	ret0, _ := ret[0].(db.CalendarToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarTokenByHash indicates an expected call of GetCalendarTokenByHash.
+func (mr *MockStoreMockRecorder) GetCalendarTokenByHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarTokenByHash", reflect.TypeOf((*MockStore)(nil).GetCalendarTokenByHash), arg0, arg1)
+}
+
+// GetDashboardTotals mocks base method.
+func (m *MockStore) GetDashboardTotals(arg0 context.Context, arg1 db.GetDashboardTotalsParams) (db.GetDashboardTotalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDashboardTotals", arg0, arg1)
+	ret0, _ := ret[0].(db.GetDashboardTotalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDashboardTotals indicates an expected call of GetDashboardTotals.
+func (mr *MockStoreMockRecorder) GetDashboardTotals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDashboardTotals", reflect.TypeOf((*MockStore)(nil).GetDashboardTotals), arg0, arg1)
+}
+
+// GetDonation mocks base method.
+func (m *MockStore) GetDonation(arg0 context.Context, arg1 int64) (db.Donation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDonation", arg0, arg1)
+	ret0, _ := ret[0].(db.Donation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDonation indicates an expected call of GetDonation.
+func (mr *MockStoreMockRecorder) GetDonation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDonation", reflect.TypeOf((*MockStore)(nil).GetDonation), arg0, arg1)
+}
+
+// GetDonationFeedItem mocks base method.
+func (m *MockStore) GetDonationFeedItem(arg0 context.Context, arg1 int64) (db.GetDonationFeedItemRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDonationFeedItem", arg0, arg1)
+	ret0, _ := ret[0].(db.GetDonationFeedItemRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDonationFeedItem indicates an expected call of GetDonationFeedItem.
+func (mr *MockStoreMockRecorder) GetDonationFeedItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDonationFeedItem", reflect.TypeOf((*MockStore)(nil).GetDonationFeedItem), arg0, arg1)
+}
+
+// GetDonationForUpdate mocks base method.
+func (m *MockStore) GetDonationForUpdate(arg0 context.Context, arg1 int64) (db.Donation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDonationForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Donation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDonationForUpdate indicates an expected call of GetDonationForUpdate.
+func (mr *MockStoreMockRecorder) GetDonationForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDonationForUpdate", reflect.TypeOf((*MockStore)(nil).GetDonationForUpdate), arg0, arg1)
+}
+
+// GetEvent mocks base method.
+func (m *MockStore) GetEvent(arg0 context.Context, arg1 int64) (db.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvent indicates an expected call of GetEvent.
+func (mr *MockStoreMockRecorder) GetEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockStore)(nil).GetEvent), arg0, arg1)
+}
+
+// GetEventAttendance mocks base method.
+func (m *MockStore) GetEventAttendance(arg0 context.Context, arg1 int64) (db.GetEventAttendanceRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventAttendance", arg0, arg1)
+	ret0, _ := ret[0].(db.GetEventAttendanceRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventAttendance indicates an expected call of GetEventAttendance.
+func (mr *MockStoreMockRecorder) GetEventAttendance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventAttendance", reflect.TypeOf((*MockStore)(nil).GetEventAttendance), arg0, arg1)
+}
+
+// GetEventBooking mocks base method.
+func (m *MockStore) GetEventBooking(arg0 context.Context, arg1 db.GetEventBookingParams) (db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventBooking", arg0, arg1)
+	ret0, _ := ret[0].(db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventBooking indicates an expected call of GetEventBooking.
+func (mr *MockStoreMockRecorder) GetEventBooking(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventBooking", reflect.TypeOf((*MockStore)(nil).GetEventBooking), arg0, arg1)
+}
+
+// GetEventBookingByID mocks base method.
+func (m *MockStore) GetEventBookingByID(arg0 context.Context, arg1 int64) (db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventBookingByID", arg0, arg1)
+	ret0, _ := ret[0].(db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventBookingByID indicates an expected call of GetEventBookingByID.
+func (mr *MockStoreMockRecorder) GetEventBookingByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventBookingByID", reflect.TypeOf((*MockStore)(nil).GetEventBookingByID), arg0, arg1)
+}
+
+// GetEventCancellation mocks base method.
+func (m *MockStore) GetEventCancellation(arg0 context.Context, arg1 int64) (db.EventCancellation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventCancellation", arg0, arg1)
+	ret0, _ := ret[0].(db.EventCancellation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventCancellation indicates an expected call of GetEventCancellation.
+func (mr *MockStoreMockRecorder) GetEventCancellation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventCancellation", reflect.TypeOf((*MockStore)(nil).GetEventCancellation), arg0, arg1)
+}
+
+// GetEventForShare mocks base method.
+func (m *MockStore) GetEventForShare(arg0 context.Context, arg1 int64) (db.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventForShare", arg0, arg1)
+	ret0, _ := ret[0].(db.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventForShare indicates an expected call of GetEventForShare.
+func (mr *MockStoreMockRecorder) GetEventForShare(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventForShare", reflect.TypeOf((*MockStore)(nil).GetEventForShare), arg0, arg1)
+}
+
+// GetEventForUpdate mocks base method.
+func (m *MockStore) GetEventForUpdate(arg0 context.Context, arg1 int64) (db.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventForUpdate indicates an expected call of GetEventForUpdate.
+func (mr *MockStoreMockRecorder) GetEventForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventForUpdate", reflect.TypeOf((*MockStore)(nil).GetEventForUpdate), arg0, arg1)
+}
+
+// GetEventSeries mocks base method.
+func (m *MockStore) GetEventSeries(arg0 context.Context, arg1 int64) (db.EventSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventSeries", arg0, arg1)
+	ret0, _ := ret[0].(db.EventSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventSeries indicates an expected call of GetEventSeries.
+func (mr *MockStoreMockRecorder) GetEventSeries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventSeries", reflect.TypeOf((*MockStore)(nil).GetEventSeries), arg0, arg1)
+}
+
+// GetEventSeriesForUpdate mocks base method.
+func (m *MockStore) GetEventSeriesForUpdate(arg0 context.Context, arg1 int64) (db.EventSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventSeriesForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.EventSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventSeriesForUpdate indicates an expected call of GetEventSeriesForUpdate.
+func (mr *MockStoreMockRecorder) GetEventSeriesForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventSeriesForUpdate", reflect.TypeOf((*MockStore)(nil).GetEventSeriesForUpdate), arg0, arg1)
+}
+
+// GetGoal mocks base method.
+func (m *MockStore) GetGoal(arg0 context.Context, arg1 int64) (db.Goal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGoal", arg0, arg1)
+	ret0, _ := ret[0].(db.Goal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGoal indicates an expected call of GetGoal.
+func (mr *MockStoreMockRecorder) GetGoal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGoal", reflect.TypeOf((*MockStore)(nil).GetGoal), arg0, arg1)
+}
+
+// GetGoalDonationStats mocks base method.
+func (m *MockStore) GetGoalDonationStats(arg0 context.Context, arg1 int64) (db.GetGoalDonationStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGoalDonationStats", arg0, arg1)
+	ret0, _ := ret[0].(db.GetGoalDonationStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGoalDonationStats indicates an expected call of GetGoalDonationStats.
+func (mr *MockStoreMockRecorder) GetGoalDonationStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGoalDonationStats", reflect.TypeOf((*MockStore)(nil).GetGoalDonationStats), arg0, arg1)
+}
+
+// GetGoalForUpdate mocks base method.
+func (m *MockStore) GetGoalForUpdate(arg0 context.Context, arg1 int64) (db.Goal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGoalForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Goal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGoalForUpdate indicates an expected call of GetGoalForUpdate.
+func (mr *MockStoreMockRecorder) GetGoalForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGoalForUpdate", reflect.TypeOf((*MockStore)(nil).GetGoalForUpdate), arg0, arg1)
+}
+
+// GetGoalProgress mocks base method.
+func (m *MockStore) GetGoalProgress(arg0 context.Context, arg1 int64) (db.GetGoalProgressRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGoalProgress", arg0, arg1)
+	ret0, _ := ret[0].(db.GetGoalProgressRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGoalProgress indicates an expected call of GetGoalProgress.
+func (mr *MockStoreMockRecorder) GetGoalProgress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGoalProgress", reflect.TypeOf((*MockStore)(nil).GetGoalProgress), arg0, arg1)
+}
+
+// GetGuestBookingByEmail mocks base method.
+func (m *MockStore) GetGuestBookingByEmail(arg0 context.Context, arg1 db.GetGuestBookingByEmailParams) (db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuestBookingByEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuestBookingByEmail indicates an expected call of GetGuestBookingByEmail.
+func (mr *MockStoreMockRecorder) GetGuestBookingByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuestBookingByEmail", reflect.TypeOf((*MockStore)(nil).GetGuestBookingByEmail), arg0, arg1)
+}
+
+// GetOfflineDonationByRef mocks base method.
+func (m *MockStore) GetOfflineDonationByRef(arg0 context.Context, arg1 string) (db.OfflineDonation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOfflineDonationByRef", arg0, arg1)
+	ret0, _ := ret[0].(db.OfflineDonation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOfflineDonationByRef indicates an expected call of GetOfflineDonationByRef.
+func (mr *MockStoreMockRecorder) GetOfflineDonationByRef(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOfflineDonationByRef", reflect.TypeOf((*MockStore)(nil).GetOfflineDonationByRef), arg0, arg1)
+}
+
+// GetOutboxEvent mocks base method.
+func (m *MockStore) GetOutboxEvent(arg0 context.Context, arg1 int64) (db.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxEvent indicates an expected call of GetOutboxEvent.
+func (mr *MockStoreMockRecorder) GetOutboxEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxEvent", reflect.TypeOf((*MockStore)(nil).GetOutboxEvent), arg0, arg1)
+}
+
+// GetReceiptByDonation mocks base method.
+func (m *MockStore) GetReceiptByDonation(arg0 context.Context, arg1 pgtype.Int8) (db.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReceiptByDonation", arg0, arg1)
+	ret0, _ := ret[0].(db.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceiptByDonation indicates an expected call of GetReceiptByDonation.
+func (mr *MockStoreMockRecorder) GetReceiptByDonation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceiptByDonation", reflect.TypeOf((*MockStore)(nil).GetReceiptByDonation), arg0, arg1)
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockStore) GetRefreshToken(arg0 context.Context, arg1 uuid.UUID) (db.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", arg0, arg1)
+	ret0, _ := ret[0].(db.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockStoreMockRecorder) GetRefreshToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockStore)(nil).GetRefreshToken), arg0, arg1)
+}
+
+// GetTicketRefundByDonation mocks base method.
+func (m *MockStore) GetTicketRefundByDonation(arg0 context.Context, arg1 int64) (db.TicketRefund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicketRefundByDonation", arg0, arg1)
+	ret0, _ := ret[0].(db.TicketRefund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicketRefundByDonation indicates an expected call of GetTicketRefundByDonation.
+func (mr *MockStoreMockRecorder) GetTicketRefundByDonation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketRefundByDonation", reflect.TypeOf((*MockStore)(nil).GetTicketRefundByDonation), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 int64) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockStore) GetUserByEmail(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockStoreMockRecorder) GetUserByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), arg0, arg1)
+}
+
+// GetUserForUpdate mocks base method.
+func (m *MockStore) GetUserForUpdate(arg0 context.Context, arg1 int64) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserForUpdate indicates an expected call of GetUserForUpdate.
+func (mr *MockStoreMockRecorder) GetUserForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserForUpdate", reflect.TypeOf((*MockStore)(nil).GetUserForUpdate), arg0, arg1)
+}
+
+// GetUserVolunteerMinutes mocks base method.
+func (m *MockStore) GetUserVolunteerMinutes(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserVolunteerMinutes", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserVolunteerMinutes indicates an expected call of GetUserVolunteerMinutes.
+func (mr *MockStoreMockRecorder) GetUserVolunteerMinutes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserVolunteerMinutes", reflect.TypeOf((*MockStore)(nil).GetUserVolunteerMinutes), arg0, arg1)
+}
+
+// GetVenue mocks base method.
+func (m *MockStore) GetVenue(arg0 context.Context, arg1 int64) (db.Venue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVenue", arg0, arg1)
+	ret0, _ := ret[0].(db.Venue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVenue indicates an expected call of GetVenue.
+func (mr *MockStoreMockRecorder) GetVenue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVenue", reflect.TypeOf((*MockStore)(nil).GetVenue), arg0, arg1)
+}
+
+// GetVolunteerRole mocks base method.
+func (m *MockStore) GetVolunteerRole(arg0 context.Context, arg1 int64) (db.VolunteerRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolunteerRole", arg0, arg1)
+	ret0, _ := ret[0].(db.VolunteerRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolunteerRole indicates an expected call of GetVolunteerRole.
+func (mr *MockStoreMockRecorder) GetVolunteerRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolunteerRole", reflect.TypeOf((*MockStore)(nil).GetVolunteerRole), arg0, arg1)
+}
+
+// GetVolunteerRoleForUpdate mocks base method.
+func (m *MockStore) GetVolunteerRoleForUpdate(arg0 context.Context, arg1 int64) (db.VolunteerRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolunteerRoleForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.VolunteerRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolunteerRoleForUpdate indicates an expected call of GetVolunteerRoleForUpdate.
+func (mr *MockStoreMockRecorder) GetVolunteerRoleForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolunteerRoleForUpdate", reflect.TypeOf((*MockStore)(nil).GetVolunteerRoleForUpdate), arg0, arg1)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStore) GetWebhookDelivery(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), arg0, arg1)
+}
+
+// GetWebhookEndpoint mocks base method.
+func (m *MockStore) GetWebhookEndpoint(arg0 context.Context, arg1 int64) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpoint indicates an expected call of GetWebhookEndpoint.
+func (mr *MockStoreMockRecorder) GetWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).GetWebhookEndpoint), arg0, arg1)
+}
+
+// ImportDonationsTx mocks base method.
+func (m *MockStore) ImportDonationsTx(arg0 context.Context, arg1 db.ImportDonationsTxParams) (db.ImportDonationsTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportDonationsTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportDonationsTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportDonationsTx indicates an expected call of ImportDonationsTx.
+func (mr *MockStoreMockRecorder) ImportDonationsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportDonationsTx", reflect.TypeOf((*MockStore)(nil).ImportDonationsTx), arg0, arg1)
+}
+
+// IsEventBooked mocks base method.
+func (m *MockStore) IsEventBooked(arg0 context.Context, arg1 db.IsEventBookedParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEventBooked", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEventBooked indicates an expected call of IsEventBooked.
+func (mr *MockStoreMockRecorder) IsEventBooked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEventBooked", reflect.TypeOf((*MockStore)(nil).IsEventBooked), arg0, arg1)
+}
+
+// IssueAnnualReceiptTx mocks base method.
+func (m *MockStore) IssueAnnualReceiptTx(arg0 context.Context, arg1 db.IssueAnnualReceiptTxParams) (db.IssueAnnualReceiptTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueAnnualReceiptTx", arg0, arg1)
+	ret0, _ := ret[0].(db.IssueAnnualReceiptTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueAnnualReceiptTx indicates an expected call of IssueAnnualReceiptTx.
+func (mr *MockStoreMockRecorder) IssueAnnualReceiptTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueAnnualReceiptTx", reflect.TypeOf((*MockStore)(nil).IssueAnnualReceiptTx), arg0, arg1)
+}
+
+// IssueDonationReceiptTx mocks base method.
+func (m *MockStore) IssueDonationReceiptTx(arg0 context.Context, arg1 int64) (db.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueDonationReceiptTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueDonationReceiptTx indicates an expected call of IssueDonationReceiptTx.
+func (mr *MockStoreMockRecorder) IssueDonationReceiptTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueDonationReceiptTx", reflect.TypeOf((*MockStore)(nil).IssueDonationReceiptTx), arg0, arg1)
+}
+
+// ListAllEventBookings mocks base method.
+func (m *MockStore) ListAllEventBookings(arg0 context.Context, arg1 int64) ([]db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllEventBookings", arg0, arg1)
+	ret0, _ := ret[0].([]db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllEventBookings indicates an expected call of ListAllEventBookings.
+func (mr *MockStoreMockRecorder) ListAllEventBookings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllEventBookings", reflect.TypeOf((*MockStore)(nil).ListAllEventBookings), arg0, arg1)
+}
+
+// ListCalendarEvents mocks base method.
+func (m *MockStore) ListCalendarEvents(arg0 context.Context, arg1 db.ListCalendarEventsParams) ([]db.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCalendarEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCalendarEvents indicates an expected call of ListCalendarEvents.
+func (mr *MockStoreMockRecorder) ListCalendarEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCalendarEvents", reflect.TypeOf((*MockStore)(nil).ListCalendarEvents), arg0, arg1)
+}
+
+// ListDonationFeed mocks base method.
+func (m *MockStore) ListDonationFeed(arg0 context.Context, arg1 db.ListDonationFeedParams) ([]db.ListDonationFeedRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDonationFeed", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListDonationFeedRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDonationFeed indicates an expected call of ListDonationFeed.
+func (mr *MockStoreMockRecorder) ListDonationFeed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDonationFeed", reflect.TypeOf((*MockStore)(nil).ListDonationFeed), arg0, arg1)
+}
+
+// ListDonations mocks base method.
+func (m *MockStore) ListDonations(arg0 context.Context, arg1 db.ListDonationsParams) ([]db.Donation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDonations", arg0, arg1)
+	ret0, _ := ret[0].([]db.Donation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDonations indicates an expected call of ListDonations.
+func (mr *MockStoreMockRecorder) ListDonations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDonations", reflect.TypeOf((*MockStore)(nil).ListDonations), arg0, arg1)
+}
+
+// ListDonationsByGoal mocks base method.
+func (m *MockStore) ListDonationsByGoal(arg0 context.Context, arg1 db.ListDonationsByGoalParams) ([]db.Donation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDonationsByGoal", arg0, arg1)
+	ret0, _ := ret[0].([]db.Donation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDonationsByGoal indicates an expected call of ListDonationsByGoal.
+func (mr *MockStoreMockRecorder) ListDonationsByGoal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDonationsByGoal", reflect.TypeOf((*MockStore)(nil).ListDonationsByGoal), arg0, arg1)
+}
+
+// ListDonationsByUser mocks base method.
+func (m *MockStore) ListDonationsByUser(arg0 context.Context, arg1 db.ListDonationsByUserParams) ([]db.Donation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDonationsByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Donation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDonationsByUser indicates an expected call of ListDonationsByUser.
+func (mr *MockStoreMockRecorder) ListDonationsByUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDonationsByUser", reflect.TypeOf((*MockStore)(nil).ListDonationsByUser), arg0, arg1)
+}
+
+// ListDonationsForExport mocks base method.
+func (m *MockStore) ListDonationsForExport(arg0 context.Context, arg1 db.ListDonationsForExportParams) ([]db.ListDonationsForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDonationsForExport", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListDonationsForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDonationsForExport indicates an expected call of ListDonationsForExport.
+func (mr *MockStoreMockRecorder) ListDonationsForExport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDonationsForExport", reflect.TypeOf((*MockStore)(nil).ListDonationsForExport), arg0, arg1)
+}
+
+// ListDueEventSeries mocks base method.
+func (m *MockStore) ListDueEventSeries(arg0 context.Context, arg1 db.ListDueEventSeriesParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueEventSeries", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueEventSeries indicates an expected call of ListDueEventSeries.
+func (mr *MockStoreMockRecorder) ListDueEventSeries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueEventSeries", reflect.TypeOf((*MockStore)(nil).ListDueEventSeries), arg0, arg1)
+}
+
+// ListEventAttendance mocks base method.
+func (m *MockStore) ListEventAttendance(arg0 context.Context, arg1 db.ListEventAttendanceParams) ([]db.ListEventAttendanceRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEventAttendance", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListEventAttendanceRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEventAttendance indicates an expected call of ListEventAttendance.
+func (mr *MockStoreMockRecorder) ListEventAttendance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventAttendance", reflect.TypeOf((*MockStore)(nil).ListEventAttendance), arg0, arg1)
+}
+
+// ListEventBookings mocks base method.
+func (m *MockStore) ListEventBookings(arg0 context.Context, arg1 db.ListEventBookingsParams) ([]db.ListEventBookingsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEventBookings", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListEventBookingsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEventBookings indicates an expected call of ListEventBookings.
+func (mr *MockStoreMockRecorder) ListEventBookings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventBookings", reflect.TypeOf((*MockStore)(nil).ListEventBookings), arg0, arg1)
+}
+
+// ListEventCancellations mocks base method.
+func (m *MockStore) ListEventCancellations(arg0 context.Context, arg1 db.ListEventCancellationsParams) ([]db.EventCancellation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEventCancellations", arg0, arg1)
+	ret0, _ := ret[0].([]db.EventCancellation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEventCancellations indicates an expected call of ListEventCancellations.
+func (mr *MockStoreMockRecorder) ListEventCancellations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventCancellations", reflect.TypeOf((*MockStore)(nil).ListEventCancellations), arg0, arg1)
+}
+
+// ListEventSeriesOccurrences mocks base method.
+func (m *MockStore) ListEventSeriesOccurrences(arg0 context.Context, arg1 db.ListEventSeriesOccurrencesParams) ([]db.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEventSeriesOccurrences", arg0, arg1)
+	ret0, _ := ret[0].([]db.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEventSeriesOccurrences indicates an expected call of ListEventSeriesOccurrences.
+func (mr *MockStoreMockRecorder) ListEventSeriesOccurrences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventSeriesOccurrences", reflect.TypeOf((*MockStore)(nil).ListEventSeriesOccurrences), arg0, arg1)
+}
+
+// ListEventVolunteerRoles mocks base method.
+func (m *MockStore) ListEventVolunteerRoles(arg0 context.Context, arg1 int64) ([]db.ListEventVolunteerRolesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEventVolunteerRoles", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListEventVolunteerRolesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEventVolunteerRoles indicates an expected call of ListEventVolunteerRoles.
+func (mr *MockStoreMockRecorder) ListEventVolunteerRoles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventVolunteerRoles", reflect.TypeOf((*MockStore)(nil).ListEventVolunteerRoles), arg0, arg1)
+}
+
+// ListEventVolunteers mocks base method.
+func (m *MockStore) ListEventVolunteers(arg0 context.Context, arg1 int64) ([]db.ListEventVolunteersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEventVolunteers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListEventVolunteersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEventVolunteers indicates an expected call of ListEventVolunteers.
+func (mr *MockStoreMockRecorder) ListEventVolunteers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventVolunteers", reflect.TypeOf((*MockStore)(nil).ListEventVolunteers), arg0, arg1)
+}
+
+// ListEvents mocks base method.
+func (m *MockStore) ListEvents(arg0 context.Context, arg1 db.ListEventsParams) ([]db.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockStoreMockRecorder) ListEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockStore)(nil).ListEvents), arg0, arg1)
+}
+
+// ListEventsNear mocks base method.
+func (m *MockStore) ListEventsNear(arg0 context.Context, arg1 db.ListEventsNearParams) ([]db.ListEventsNearRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEventsNear", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListEventsNearRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEventsNear indicates an expected call of ListEventsNear.
+func (mr *MockStoreMockRecorder) ListEventsNear(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventsNear", reflect.TypeOf((*MockStore)(nil).ListEventsNear), arg0, arg1)
+}
+
+// ListGoalDonationSeries mocks base method.
+func (m *MockStore) ListGoalDonationSeries(arg0 context.Context, arg1 db.ListGoalDonationSeriesParams) ([]db.ListGoalDonationSeriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGoalDonationSeries", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListGoalDonationSeriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGoalDonationSeries indicates an expected call of ListGoalDonationSeries.
+func (mr *MockStoreMockRecorder) ListGoalDonationSeries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGoalDonationSeries", reflect.TypeOf((*MockStore)(nil).ListGoalDonationSeries), arg0, arg1)
+}
+
+// ListGoalDonorContacts mocks base method.
+func (m *MockStore) ListGoalDonorContacts(arg0 context.Context, arg1 int64) ([]db.ListGoalDonorContactsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGoalDonorContacts", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListGoalDonorContactsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGoalDonorContacts indicates an expected call of ListGoalDonorContacts.
+func (mr *MockStoreMockRecorder) ListGoalDonorContacts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGoalDonorContacts", reflect.TypeOf((*MockStore)(nil).ListGoalDonorContacts), arg0, arg1)
+}
+
+// ListGoalTopDonors mocks base method.
+func (m *MockStore) ListGoalTopDonors(arg0 context.Context, arg1 db.ListGoalTopDonorsParams) ([]db.ListGoalTopDonorsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGoalTopDonors", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListGoalTopDonorsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGoalTopDonors indicates an expected call of ListGoalTopDonors.
+func (mr *MockStoreMockRecorder) ListGoalTopDonors(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGoalTopDonors", reflect.TypeOf((*MockStore)(nil).ListGoalTopDonors), arg0, arg1)
+}
+
+// ListGoals mocks base method.
+func (m *MockStore) ListGoals(arg0 context.Context, arg1 db.ListGoalsParams) ([]db.Goal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGoals", arg0, arg1)
+	ret0, _ := ret[0].([]db.Goal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGoals indicates an expected call of ListGoals.
+func (mr *MockStoreMockRecorder) ListGoals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGoals", reflect.TypeOf((*MockStore)(nil).ListGoals), arg0, arg1)
+}
+
+// ListLeaderboard mocks base method.
+func (m *MockStore) ListLeaderboard(arg0 context.Context, arg1 db.ListLeaderboardParams) ([]db.ListLeaderboardRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLeaderboard", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListLeaderboardRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLeaderboard indicates an expected call of ListLeaderboard.
+func (mr *MockStoreMockRecorder) ListLeaderboard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLeaderboard", reflect.TypeOf((*MockStore)(nil).ListLeaderboard), arg0, arg1)
+}
+
+// ListOutboxDeliveries mocks base method.
+func (m *MockStore) ListOutboxDeliveries(arg0 context.Context, arg1 int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutboxDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutboxDeliveries indicates an expected call of ListOutboxDeliveries.
+func (mr *MockStoreMockRecorder) ListOutboxDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutboxDeliveries", reflect.TypeOf((*MockStore)(nil).ListOutboxDeliveries), arg0, arg1)
+}
+
+// ListReceiptDonations mocks base method.
+func (m *MockStore) ListReceiptDonations(arg0 context.Context, arg1 int64) ([]db.Donation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReceiptDonations", arg0, arg1)
+	ret0, _ := ret[0].([]db.Donation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReceiptDonations indicates an expected call of ListReceiptDonations.
+func (mr *MockStoreMockRecorder) ListReceiptDonations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReceiptDonations", reflect.TypeOf((*MockStore)(nil).ListReceiptDonations), arg0, arg1)
+}
+
+// ListUpcomingEvents mocks base method.
+func (m *MockStore) ListUpcomingEvents(arg0 context.Context, arg1 db.ListUpcomingEventsParams) ([]db.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUpcomingEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUpcomingEvents indicates an expected call of ListUpcomingEvents.
+func (mr *MockStoreMockRecorder) ListUpcomingEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpcomingEvents", reflect.TypeOf((*MockStore)(nil).ListUpcomingEvents), arg0, arg1)
+}
+
+// ListUserBookings mocks base method.
+func (m *MockStore) ListUserBookings(arg0 context.Context, arg1 db.ListUserBookingsParams) ([]db.ListUserBookingsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDonation", arg0, arg1)
-	ret0, _ := ret[0].(db.Donation)
+	ret := m.ctrl.Call(m, "ListUserBookings", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListUserBookingsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetDonation indicates an expected call of GetDonation.
-func (mr *MockStoreMockRecorder) GetDonation(arg0, arg1 interface{}) *gomock.Call {
+// ListUserBookings indicates an expected call of ListUserBookings.
+func (mr *MockStoreMockRecorder) ListUserBookings(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDonation", reflect.TypeOf((*MockStore)(nil).GetDonation), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserBookings", reflect.TypeOf((*MockStore)(nil).ListUserBookings), arg0, arg1)
 }
 
-// GetEvent mocks base method.
-func (m *MockStore) GetEvent(arg0 context.Context, arg1 int64) (db.Event, error) {
+// ListUserDonationsBetween mocks base method.
+func (m *MockStore) ListUserDonationsBetween(arg0 context.Context, arg1 db.ListUserDonationsBetweenParams) ([]db.Donation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEvent", arg0, arg1)
-	ret0, _ := ret[0].(db.Event)
+	ret := m.ctrl.Call(m, "ListUserDonationsBetween", arg0, arg1)
+	ret0, _ := ret[0].([]db.Donation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEvent indicates an expected call of GetEvent.
-func (mr *MockStoreMockRecorder) GetEvent(arg0, arg1 interface{}) *gomock.Call {
+// ListUserDonationsBetween indicates an expected call of ListUserDonationsBetween.
+func (mr *MockStoreMockRecorder) ListUserDonationsBetween(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvent", reflect.TypeOf((*MockStore)(nil).GetEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserDonationsBetween", reflect.TypeOf((*MockStore)(nil).ListUserDonationsBetween), arg0, arg1)
 }
 
-// GetEventBooking mocks base method.
-func (m *MockStore) GetEventBooking(arg0 context.Context, arg1 db.GetEventBookingParams) (db.EventBooking, error) {
+// ListUserEventCancellations mocks base method.
+func (m *MockStore) ListUserEventCancellations(arg0 context.Context, arg1 db.ListUserEventCancellationsParams) ([]db.EventCancellation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventBooking", arg0, arg1)
-	ret0, _ := ret[0].(db.EventBooking)
+	ret := m.ctrl.Call(m, "ListUserEventCancellations", arg0, arg1)
+	ret0, _ := ret[0].([]db.EventCancellation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEventBooking indicates an expected call of GetEventBooking.
-func (mr *MockStoreMockRecorder) GetEventBooking(arg0, arg1 interface{}) *gomock.Call {
+// ListUserEventCancellations indicates an expected call of ListUserEventCancellations.
+func (mr *MockStoreMockRecorder) ListUserEventCancellations(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventBooking", reflect.TypeOf((*MockStore)(nil).GetEventBooking), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserEventCancellations", reflect.TypeOf((*MockStore)(nil).ListUserEventCancellations), arg0, arg1)
 }
 
-// GetGoal mocks base method.
-func (m *MockStore) GetGoal(arg0 context.Context, arg1 int64) (db.Goal, error) {
+// ListUserVolunteerShifts mocks base method.
+func (m *MockStore) ListUserVolunteerShifts(arg0 context.Context, arg1 db.ListUserVolunteerShiftsParams) ([]db.ListUserVolunteerShiftsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGoal", arg0, arg1)
-	ret0, _ := ret[0].(db.Goal)
+	ret := m.ctrl.Call(m, "ListUserVolunteerShifts", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListUserVolunteerShiftsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetGoal indicates an expected call of GetGoal.
-func (mr *MockStoreMockRecorder) GetGoal(arg0, arg1 interface{}) *gomock.Call {
+// ListUserVolunteerShifts indicates an expected call of ListUserVolunteerShifts.
+func (mr *MockStoreMockRecorder) ListUserVolunteerShifts(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGoal", reflect.TypeOf((*MockStore)(nil).GetGoal), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserVolunteerShifts", reflect.TypeOf((*MockStore)(nil).ListUserVolunteerShifts), arg0, arg1)
 }
 
-// GetGoalForUpdate mocks base method.
-func (m *MockStore) GetGoalForUpdate(arg0 context.Context, arg1 int64) (db.Goal, error) {
+// ListUsers mocks base method.
+func (m *MockStore) ListUsers(arg0 context.Context, arg1 db.ListUsersParams) ([]db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGoalForUpdate", arg0, arg1)
-	ret0, _ := ret[0].(db.Goal)
+	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetGoalForUpdate indicates an expected call of GetGoalForUpdate.
-func (mr *MockStoreMockRecorder) GetGoalForUpdate(arg0, arg1 interface{}) *gomock.Call {
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockStoreMockRecorder) ListUsers(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGoalForUpdate", reflect.TypeOf((*MockStore)(nil).GetGoalForUpdate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockStore)(nil).ListUsers), arg0, arg1)
 }
 
-// GetRefreshToken mocks base method.
-func (m *MockStore) GetRefreshToken(arg0 context.Context, arg1 uuid.UUID) (db.RefreshToken, error) {
+// ListVenues mocks base method.
+func (m *MockStore) ListVenues(arg0 context.Context, arg1 db.ListVenuesParams) ([]db.Venue, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRefreshToken", arg0, arg1)
-	ret0, _ := ret[0].(db.RefreshToken)
+	ret := m.ctrl.Call(m, "ListVenues", arg0, arg1)
+	ret0, _ := ret[0].([]db.Venue)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRefreshToken indicates an expected call of GetRefreshToken.
-func (mr *MockStoreMockRecorder) GetRefreshToken(arg0, arg1 interface{}) *gomock.Call {
+// ListVenues indicates an expected call of ListVenues.
+func (mr *MockStoreMockRecorder) ListVenues(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockStore)(nil).GetRefreshToken), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVenues", reflect.TypeOf((*MockStore)(nil).ListVenues), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 int64) (db.User, error) {
+// ListWebhookDeliveries mocks base method.
+func (m *MockStore) ListWebhookDeliveries(arg0 context.Context, arg1 db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveries), arg0, arg1)
 }
 
-// GetUserByEmail mocks base method.
-func (m *MockStore) GetUserByEmail(arg0 context.Context, arg1 string) (db.User, error) {
+// ListWebhookEndpoints mocks base method.
+func (m *MockStore) ListWebhookEndpoints(arg0 context.Context, arg1 db.ListWebhookEndpointsParams) ([]db.WebhookEndpoint, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "ListWebhookEndpoints", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookEndpoint)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByEmail indicates an expected call of GetUserByEmail.
-func (mr *MockStoreMockRecorder) GetUserByEmail(arg0, arg1 interface{}) *gomock.Call {
+// ListWebhookEndpoints indicates an expected call of ListWebhookEndpoints.
+func (mr *MockStoreMockRecorder) ListWebhookEndpoints(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookEndpoints", reflect.TypeOf((*MockStore)(nil).ListWebhookEndpoints), arg0, arg1)
 }
 
-// IsEventBooked mocks base method.
-func (m *MockStore) IsEventBooked(arg0 context.Context, arg1 db.IsEventBookedParams) (bool, error) {
+// ListWebhookEndpointsForEvent mocks base method.
+func (m *MockStore) ListWebhookEndpointsForEvent(arg0 context.Context, arg1 string) ([]db.WebhookEndpoint, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsEventBooked", arg0, arg1)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "ListWebhookEndpointsForEvent", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookEndpoint)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// IsEventBooked indicates an expected call of IsEventBooked.
-func (mr *MockStoreMockRecorder) IsEventBooked(arg0, arg1 interface{}) *gomock.Call {
+// ListWebhookEndpointsForEvent indicates an expected call of ListWebhookEndpointsForEvent.
+func (mr *MockStoreMockRecorder) ListWebhookEndpointsForEvent(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEventBooked", reflect.TypeOf((*MockStore)(nil).IsEventBooked), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookEndpointsForEvent", reflect.TypeOf((*MockStore)(nil).ListWebhookEndpointsForEvent), arg0, arg1)
 }
 
-// ListDonations mocks base method.
-func (m *MockStore) ListDonations(arg0 context.Context, arg1 db.ListDonationsParams) ([]db.Donation, error) {
+// LogVolunteerMinutes mocks base method.
+func (m *MockStore) LogVolunteerMinutes(arg0 context.Context, arg1 db.LogVolunteerMinutesParams) (db.VolunteerShift, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListDonations", arg0, arg1)
-	ret0, _ := ret[0].([]db.Donation)
+	ret := m.ctrl.Call(m, "LogVolunteerMinutes", arg0, arg1)
+	ret0, _ := ret[0].(db.VolunteerShift)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListDonations indicates an expected call of ListDonations.
-func (mr *MockStoreMockRecorder) ListDonations(arg0, arg1 interface{}) *gomock.Call {
+// LogVolunteerMinutes indicates an expected call of LogVolunteerMinutes.
+func (mr *MockStoreMockRecorder) LogVolunteerMinutes(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDonations", reflect.TypeOf((*MockStore)(nil).ListDonations), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogVolunteerMinutes", reflect.TypeOf((*MockStore)(nil).LogVolunteerMinutes), arg0, arg1)
 }
 
-// ListDonationsByGoal mocks base method.
-func (m *MockStore) ListDonationsByGoal(arg0 context.Context, arg1 db.ListDonationsByGoalParams) ([]db.Donation, error) {
+// MarkOutboxEventDispatched mocks base method.
+func (m *MockStore) MarkOutboxEventDispatched(arg0 context.Context, arg1 int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListDonationsByGoal", arg0, arg1)
-	ret0, _ := ret[0].([]db.Donation)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "MarkOutboxEventDispatched", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListDonationsByGoal indicates an expected call of ListDonationsByGoal.
-func (mr *MockStoreMockRecorder) ListDonationsByGoal(arg0, arg1 interface{}) *gomock.Call {
+// MarkOutboxEventDispatched indicates an expected call of MarkOutboxEventDispatched.
+func (mr *MockStoreMockRecorder) MarkOutboxEventDispatched(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDonationsByGoal", reflect.TypeOf((*MockStore)(nil).ListDonationsByGoal), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventDispatched", reflect.TypeOf((*MockStore)(nil).MarkOutboxEventDispatched), arg0, arg1)
 }
 
-// ListDonationsByUser mocks base method.
-func (m *MockStore) ListDonationsByUser(arg0 context.Context, arg1 db.ListDonationsByUserParams) ([]db.Donation, error) {
+// MaterializeEventSeriesTx mocks base method.
+func (m *MockStore) MaterializeEventSeriesTx(arg0 context.Context, arg1 time.Time) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListDonationsByUser", arg0, arg1)
-	ret0, _ := ret[0].([]db.Donation)
+	ret := m.ctrl.Call(m, "MaterializeEventSeriesTx", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListDonationsByUser indicates an expected call of ListDonationsByUser.
-func (mr *MockStoreMockRecorder) ListDonationsByUser(arg0, arg1 interface{}) *gomock.Call {
+// MaterializeEventSeriesTx indicates an expected call of MaterializeEventSeriesTx.
+func (mr *MockStoreMockRecorder) MaterializeEventSeriesTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDonationsByUser", reflect.TypeOf((*MockStore)(nil).ListDonationsByUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaterializeEventSeriesTx", reflect.TypeOf((*MockStore)(nil).MaterializeEventSeriesTx), arg0, arg1)
 }
 
-// ListEventBookings mocks base method.
-func (m *MockStore) ListEventBookings(arg0 context.Context, arg1 db.ListEventBookingsParams) ([]db.ListEventBookingsRow, error) {
+// NextReceiptNumber mocks base method.
+func (m *MockStore) NextReceiptNumber(arg0 context.Context, arg1 int32) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEventBookings", arg0, arg1)
-	ret0, _ := ret[0].([]db.ListEventBookingsRow)
+	ret := m.ctrl.Call(m, "NextReceiptNumber", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListEventBookings indicates an expected call of ListEventBookings.
-func (mr *MockStoreMockRecorder) ListEventBookings(arg0, arg1 interface{}) *gomock.Call {
+// NextReceiptNumber indicates an expected call of NextReceiptNumber.
+func (mr *MockStoreMockRecorder) NextReceiptNumber(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventBookings", reflect.TypeOf((*MockStore)(nil).ListEventBookings), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextReceiptNumber", reflect.TypeOf((*MockStore)(nil).NextReceiptNumber), arg0, arg1)
 }
 
-// ListEvents mocks base method.
-func (m *MockStore) ListEvents(arg0 context.Context, arg1 db.ListEventsParams) ([]db.Event, error) {
+// RecordOutboxDelivery mocks base method.
+func (m *MockStore) RecordOutboxDelivery(arg0 context.Context, arg1 db.RecordOutboxDeliveryParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEvents", arg0, arg1)
-	ret0, _ := ret[0].([]db.Event)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "RecordOutboxDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListEvents indicates an expected call of ListEvents.
-func (mr *MockStoreMockRecorder) ListEvents(arg0, arg1 interface{}) *gomock.Call {
+// RecordOutboxDelivery indicates an expected call of RecordOutboxDelivery.
+func (mr *MockStoreMockRecorder) RecordOutboxDelivery(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockStore)(nil).ListEvents), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOutboxDelivery", reflect.TypeOf((*MockStore)(nil).RecordOutboxDelivery), arg0, arg1)
 }
 
-// ListGoals mocks base method.
-func (m *MockStore) ListGoals(arg0 context.Context, arg1 db.ListGoalsParams) ([]db.Goal, error) {
+// RecordOutboxEventFailure mocks base method.
+func (m *MockStore) RecordOutboxEventFailure(arg0 context.Context, arg1 db.RecordOutboxEventFailureParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListGoals", arg0, arg1)
-	ret0, _ := ret[0].([]db.Goal)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "RecordOutboxEventFailure", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListGoals indicates an expected call of ListGoals.
-func (mr *MockStoreMockRecorder) ListGoals(arg0, arg1 interface{}) *gomock.Call {
+// RecordOutboxEventFailure indicates an expected call of RecordOutboxEventFailure.
+func (mr *MockStoreMockRecorder) RecordOutboxEventFailure(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGoals", reflect.TypeOf((*MockStore)(nil).ListGoals), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOutboxEventFailure", reflect.TypeOf((*MockStore)(nil).RecordOutboxEventFailure), arg0, arg1)
 }
 
-// ListUpcomingEvents mocks base method.
-func (m *MockStore) ListUpcomingEvents(arg0 context.Context, arg1 db.ListUpcomingEventsParams) ([]db.Event, error) {
+// RecordWebhookDeliveryAttempt mocks base method.
+func (m *MockStore) RecordWebhookDeliveryAttempt(arg0 context.Context, arg1 db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListUpcomingEvents", arg0, arg1)
-	ret0, _ := ret[0].([]db.Event)
+	ret := m.ctrl.Call(m, "RecordWebhookDeliveryAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListUpcomingEvents indicates an expected call of ListUpcomingEvents.
-func (mr *MockStoreMockRecorder) ListUpcomingEvents(arg0, arg1 interface{}) *gomock.Call {
+// RecordWebhookDeliveryAttempt indicates an expected call of RecordWebhookDeliveryAttempt.
+func (mr *MockStoreMockRecorder) RecordWebhookDeliveryAttempt(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpcomingEvents", reflect.TypeOf((*MockStore)(nil).ListUpcomingEvents), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookDeliveryAttempt", reflect.TypeOf((*MockStore)(nil).RecordWebhookDeliveryAttempt), arg0, arg1)
 }
 
-// ListUserBookings mocks base method.
-func (m *MockStore) ListUserBookings(arg0 context.Context, arg1 db.ListUserBookingsParams) ([]db.ListUserBookingsRow, error) {
+// RefreshDailyStats mocks base method.
+func (m *MockStore) RefreshDailyStats(arg0 context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListUserBookings", arg0, arg1)
-	ret0, _ := ret[0].([]db.ListUserBookingsRow)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "RefreshDailyStats", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListUserBookings indicates an expected call of ListUserBookings.
-func (mr *MockStoreMockRecorder) ListUserBookings(arg0, arg1 interface{}) *gomock.Call {
+// RefreshDailyStats indicates an expected call of RefreshDailyStats.
+func (mr *MockStoreMockRecorder) RefreshDailyStats(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserBookings", reflect.TypeOf((*MockStore)(nil).ListUserBookings), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshDailyStats", reflect.TypeOf((*MockStore)(nil).RefreshDailyStats), arg0)
 }
 
-// ListUsers mocks base method.
-func (m *MockStore) ListUsers(arg0 context.Context, arg1 db.ListUsersParams) ([]db.User, error) {
+// ResetWebhookDelivery mocks base method.
+func (m *MockStore) ResetWebhookDelivery(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1)
-	ret0, _ := ret[0].([]db.User)
+	ret := m.ctrl.Call(m, "ResetWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListUsers indicates an expected call of ListUsers.
-func (mr *MockStoreMockRecorder) ListUsers(arg0, arg1 interface{}) *gomock.Call {
+// ResetWebhookDelivery indicates an expected call of ResetWebhookDelivery.
+func (mr *MockStoreMockRecorder) ResetWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockStore)(nil).ListUsers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).ResetWebhookDelivery), arg0, arg1)
 }
 
 // RevokeAllUserRefreshTokens mocks base method.
@@ -509,6 +2011,51 @@ func (mr *MockStoreMockRecorder) RevokeRefreshToken(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshToken", reflect.TypeOf((*MockStore)(nil).RevokeRefreshToken), arg0, arg1)
 }
 
+// ScheduleEventRemindersTx mocks base method.
+func (m *MockStore) ScheduleEventRemindersTx(arg0 context.Context, arg1 []time.Duration) ([]db.CreateDueEventRemindersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleEventRemindersTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.CreateDueEventRemindersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleEventRemindersTx indicates an expected call of ScheduleEventRemindersTx.
+func (mr *MockStoreMockRecorder) ScheduleEventRemindersTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleEventRemindersTx", reflect.TypeOf((*MockStore)(nil).ScheduleEventRemindersTx), arg0, arg1)
+}
+
+// SetEventBookingDonation mocks base method.
+func (m *MockStore) SetEventBookingDonation(arg0 context.Context, arg1 db.SetEventBookingDonationParams) (db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEventBookingDonation", arg0, arg1)
+	ret0, _ := ret[0].(db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetEventBookingDonation indicates an expected call of SetEventBookingDonation.
+func (mr *MockStoreMockRecorder) SetEventBookingDonation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEventBookingDonation", reflect.TypeOf((*MockStore)(nil).SetEventBookingDonation), arg0, arg1)
+}
+
+// SignUpVolunteerTx mocks base method.
+func (m *MockStore) SignUpVolunteerTx(arg0 context.Context, arg1 db.SignUpVolunteerTxParams) (db.VolunteerShift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignUpVolunteerTx", arg0, arg1)
+	ret0, _ := ret[0].(db.VolunteerShift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignUpVolunteerTx indicates an expected call of SignUpVolunteerTx.
+func (mr *MockStoreMockRecorder) SignUpVolunteerTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignUpVolunteerTx", reflect.TypeOf((*MockStore)(nil).SignUpVolunteerTx), arg0, arg1)
+}
+
 // UpdateEvent mocks base method.
 func (m *MockStore) UpdateEvent(arg0 context.Context, arg1 db.UpdateEventParams) (db.Event, error) {
 	m.ctrl.T.Helper()
@@ -524,6 +2071,81 @@ func (mr *MockStoreMockRecorder) UpdateEvent(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockStore)(nil).UpdateEvent), arg0, arg1)
 }
 
+// UpdateEventBookingReminders mocks base method.
+func (m *MockStore) UpdateEventBookingReminders(arg0 context.Context, arg1 db.UpdateEventBookingRemindersParams) (db.EventBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEventBookingReminders", arg0, arg1)
+	ret0, _ := ret[0].(db.EventBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEventBookingReminders indicates an expected call of UpdateEventBookingReminders.
+func (mr *MockStoreMockRecorder) UpdateEventBookingReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventBookingReminders", reflect.TypeOf((*MockStore)(nil).UpdateEventBookingReminders), arg0, arg1)
+}
+
+// UpdateEventOccurrence mocks base method.
+func (m *MockStore) UpdateEventOccurrence(arg0 context.Context, arg1 db.UpdateEventOccurrenceParams) (db.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEventOccurrence", arg0, arg1)
+	ret0, _ := ret[0].(db.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEventOccurrence indicates an expected call of UpdateEventOccurrence.
+func (mr *MockStoreMockRecorder) UpdateEventOccurrence(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventOccurrence", reflect.TypeOf((*MockStore)(nil).UpdateEventOccurrence), arg0, arg1)
+}
+
+// UpdateEventSeries mocks base method.
+func (m *MockStore) UpdateEventSeries(arg0 context.Context, arg1 db.UpdateEventSeriesParams) (db.EventSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEventSeries", arg0, arg1)
+	ret0, _ := ret[0].(db.EventSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEventSeries indicates an expected call of UpdateEventSeries.
+func (mr *MockStoreMockRecorder) UpdateEventSeries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventSeries", reflect.TypeOf((*MockStore)(nil).UpdateEventSeries), arg0, arg1)
+}
+
+// UpdateEventSeriesTx mocks base method.
+func (m *MockStore) UpdateEventSeriesTx(arg0 context.Context, arg1 db.UpdateEventSeriesTxParams) (db.EventSeriesTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEventSeriesTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EventSeriesTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEventSeriesTx indicates an expected call of UpdateEventSeriesTx.
+func (mr *MockStoreMockRecorder) UpdateEventSeriesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventSeriesTx", reflect.TypeOf((*MockStore)(nil).UpdateEventSeriesTx), arg0, arg1)
+}
+
+// UpdateEventStatus mocks base method.
+func (m *MockStore) UpdateEventStatus(arg0 context.Context, arg1 db.UpdateEventStatusParams) (db.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEventStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEventStatus indicates an expected call of UpdateEventStatus.
+func (mr *MockStoreMockRecorder) UpdateEventStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventStatus", reflect.TypeOf((*MockStore)(nil).UpdateEventStatus), arg0, arg1)
+}
+
 // UpdateGoal mocks base method.
 func (m *MockStore) UpdateGoal(arg0 context.Context, arg1 db.UpdateGoalParams) (db.Goal, error) {
 	m.ctrl.T.Helper()
@@ -582,3 +2204,63 @@ func (mr *MockStoreMockRecorder) UpdateUserBalance(arg0, arg1 interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserBalance", reflect.TypeOf((*MockStore)(nil).UpdateUserBalance), arg0, arg1)
 }
+
+// UpdateVenue mocks base method.
+func (m *MockStore) UpdateVenue(arg0 context.Context, arg1 db.UpdateVenueParams) (db.Venue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVenue", arg0, arg1)
+	ret0, _ := ret[0].(db.Venue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVenue indicates an expected call of UpdateVenue.
+func (mr *MockStoreMockRecorder) UpdateVenue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVenue", reflect.TypeOf((*MockStore)(nil).UpdateVenue), arg0, arg1)
+}
+
+// UpdateVolunteerRole mocks base method.
+func (m *MockStore) UpdateVolunteerRole(arg0 context.Context, arg1 db.UpdateVolunteerRoleParams) (db.VolunteerRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVolunteerRole", arg0, arg1)
+	ret0, _ := ret[0].(db.VolunteerRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVolunteerRole indicates an expected call of UpdateVolunteerRole.
+func (mr *MockStoreMockRecorder) UpdateVolunteerRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVolunteerRole", reflect.TypeOf((*MockStore)(nil).UpdateVolunteerRole), arg0, arg1)
+}
+
+// UpdateVolunteerRoleTx mocks base method.
+func (m *MockStore) UpdateVolunteerRoleTx(arg0 context.Context, arg1 db.UpdateVolunteerRoleTxParams) (db.VolunteerRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVolunteerRoleTx", arg0, arg1)
+	ret0, _ := ret[0].(db.VolunteerRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVolunteerRoleTx indicates an expected call of UpdateVolunteerRoleTx.
+func (mr *MockStoreMockRecorder) UpdateVolunteerRoleTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVolunteerRoleTx", reflect.TypeOf((*MockStore)(nil).UpdateVolunteerRoleTx), arg0, arg1)
+}
+
+// UpsertCalendarToken mocks base method.
+func (m *MockStore) UpsertCalendarToken(arg0 context.Context, arg1 db.UpsertCalendarTokenParams) (db.CalendarToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCalendarToken", arg0, arg1)
+	ret0, _ := ret[0].(db.CalendarToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCalendarToken indicates an expected call of UpsertCalendarToken.
+func (mr *MockStoreMockRecorder) UpsertCalendarToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCalendarToken", reflect.TypeOf((*MockStore)(nil).UpsertCalendarToken), arg0, arg1)
+}
@@ -0,0 +1,54 @@
+-- name: ListCalendarEvents :many
+-- Events taking place since a time, for the public calendar feed
+SELECT * FROM events
+WHERE date >= sqlc.arg(since)
+ORDER BY date ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetEventCancellation :one
+-- The cancellation recorded when an event was deleted
+SELECT * FROM event_cancellations
+WHERE event_id = $1 AND user_id IS NULL
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListEventCancellations :many
+-- Deleted events that were taking place since a time
+SELECT * FROM event_cancellations
+WHERE user_id IS NULL AND date >= sqlc.arg(since)
+ORDER BY date ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListUserEventCancellations :many
+-- The latest cancellation of each event a user booked
+-- and no longer has a booking for, taking place since a time
+SELECT DISTINCT ON (ec.event_id) ec.* FROM event_cancellations ec
+WHERE ec.user_id = sqlc.arg(user_id)::bigint
+  AND ec.date >= sqlc.arg(since)
+  AND NOT EXISTS (
+    SELECT 1 FROM event_bookings eb
+    WHERE eb.user_id = ec.user_id AND eb.event_id = ec.event_id
+  )
+ORDER BY ec.event_id, ec.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpsertCalendarToken :one
+-- Replaces the previous calendar token of the user, so old feed URLs stop working
+INSERT INTO calendar_tokens (
+  user_id,
+  token_hash
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET token_hash = EXCLUDED.token_hash,
+    created_at = now()
+RETURNING *;
+
+-- name: GetCalendarTokenByHash :one
+SELECT * FROM calendar_tokens
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: DeleteCalendarToken :exec
+DELETE FROM calendar_tokens
+WHERE user_id = $1;
@@ -0,0 +1,19 @@
+-- name: CountActiveGoals :one
+SELECT COUNT(*) FROM goals
+WHERE is_active = true;
+
+-- name: GetDashboardTotals :one
+SELECT
+  COALESCE(SUM(donation_count), 0)::bigint AS donation_count,
+  COALESCE(SUM(donation_amount), 0)::bigint AS donation_amount,
+  COALESCE(SUM(new_users), 0)::bigint AS new_users,
+  COALESCE(SUM(events_held), 0)::bigint AS events_held,
+  COALESCE(SUM(attendees), 0)::bigint AS attendees,
+  COALESCE(SUM(refund_count), 0)::bigint AS refund_count,
+  COALESCE(SUM(refund_amount), 0)::bigint AS refund_amount
+FROM daily_stats
+WHERE day >= sqlc.arg(from_day)
+  AND day < sqlc.arg(to_day);
+
+-- name: RefreshDailyStats :exec
+REFRESH MATERIALIZED VIEW CONCURRENTLY daily_stats;
@@ -36,3 +36,54 @@ OFFSET $3;
 UPDATE goals
 SET collected_amount = collected_amount + $2
 WHERE id = $1;
+
+-- name: GetDonationForUpdate :one
+SELECT * FROM donations
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListUserDonationsBetween :many
+SELECT * FROM donations
+WHERE user_id = sqlc.arg(user_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+  AND NOT EXISTS (SELECT 1 FROM ticket_refunds tr WHERE tr.donation_id = donations.id)
+ORDER BY created_at ASC;
+
+-- name: ListDonationsForExport :many
+SELECT
+  d.id,
+  d.goal_id,
+  g.title AS goal_title,
+  d.amount,
+  d.is_anonymous,
+  d.created_at,
+  d.user_id,
+  u.name AS user_name,
+  u.email AS user_email,
+  od.donor_name,
+  od.donor_email,
+  tr.refunded_at
+FROM donations d
+JOIN goals g ON d.goal_id = g.id
+LEFT JOIN users u ON d.user_id = u.id
+LEFT JOIN offline_donations od ON od.donation_id = d.id
+LEFT JOIN ticket_refunds tr ON tr.donation_id = d.id
+WHERE d.id > sqlc.arg(after_id)
+  AND (sqlc.narg(goal_id)::bigint IS NULL OR d.goal_id = sqlc.narg(goal_id))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR d.created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR d.created_at < sqlc.narg(to_time))
+  AND (sqlc.narg(is_anonymous)::boolean IS NULL OR d.is_anonymous = sqlc.narg(is_anonymous))
+ORDER BY d.id
+LIMIT sqlc.arg(row_limit);
+
+-- name: CreateDonationAt :one
+INSERT INTO donations (
+  goal_id,
+  user_id,
+  amount,
+  is_anonymous,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
@@ -0,0 +1,35 @@
+-- name: GetDonationFeedItem :one
+SELECT
+  d.id,
+  d.goal_id,
+  g.title AS goal_title,
+  d.amount,
+  d.is_anonymous,
+  d.created_at,
+  u.name AS user_name,
+  COALESCE(u.hide_from_leaderboards, false)::boolean AS hide_donor,
+  od.donor_name AS offline_donor_name
+FROM donations d
+JOIN goals g ON g.id = d.goal_id
+LEFT JOIN users u ON u.id = d.user_id
+LEFT JOIN offline_donations od ON od.donation_id = d.id AND od.donor_name_public
+WHERE d.id = $1;
+
+-- name: ListDonationFeed :many
+SELECT
+  d.id,
+  d.goal_id,
+  g.title AS goal_title,
+  d.amount,
+  d.is_anonymous,
+  d.created_at,
+  u.name AS user_name,
+  COALESCE(u.hide_from_leaderboards, false)::boolean AS hide_donor,
+  od.donor_name AS offline_donor_name
+FROM donations d
+JOIN goals g ON g.id = d.goal_id
+LEFT JOIN users u ON u.id = d.user_id
+LEFT JOIN offline_donations od ON od.donation_id = d.id AND od.donor_name_public
+WHERE sqlc.narg(goal_id)::bigint IS NULL OR d.goal_id = sqlc.narg(goal_id)
+ORDER BY d.id DESC
+LIMIT sqlc.arg(row_limit);
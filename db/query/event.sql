@@ -2,15 +2,33 @@
 INSERT INTO events (
   name,
   place,
-  date
+  date,
+  goal_id,
+  ticket_price,
+  ends_at,
+  time_zone,
+  venue_id,
+  max_party_size
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 ) RETURNING *;
 
 -- name: GetEvent :one
 SELECT * FROM events
 WHERE id = $1 LIMIT 1;
 
+-- name: GetEventForShare :one
+-- Locks the event against status changes while it is booked
+SELECT * FROM events
+WHERE id = $1 LIMIT 1
+FOR SHARE;
+
+-- name: GetEventForUpdate :one
+-- Locks the event, and keeps new bookings waiting, while its status changes
+SELECT * FROM events
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
 -- name: ListEvents :many
 SELECT * FROM events
 ORDER BY date ASC
@@ -19,7 +37,7 @@ OFFSET $2;
 
 -- name: ListUpcomingEvents :many
 SELECT * FROM events
-WHERE date > NOW()
+WHERE date > NOW() AND status <> 'cancelled'
 ORDER BY date ASC
 LIMIT $1
 OFFSET $2;
@@ -29,7 +47,29 @@ UPDATE events
 SET 
   name = COALESCE(sqlc.narg(name), name),
   place = COALESCE(sqlc.narg(place), place),
-  date = COALESCE(sqlc.narg(date), date)
+  date = COALESCE(sqlc.narg(date), date),
+  goal_id = COALESCE(sqlc.narg(goal_id), goal_id),
+  ticket_price = COALESCE(sqlc.narg(ticket_price), ticket_price),
+  ends_at = COALESCE(sqlc.narg(ends_at), ends_at),
+  time_zone = COALESCE(sqlc.narg(time_zone), time_zone),
+  venue_id = COALESCE(sqlc.narg(venue_id), venue_id),
+  max_party_size = COALESCE(sqlc.narg(max_party_size), max_party_size),
+  sequence = sequence + 1
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateEventStatus :one
+-- Postpones, reschedules or cancels an event. Occurrences of a series are detached,
+-- so later changes to the series leave them as they are.
+UPDATE events
+SET
+  status = sqlc.arg(status),
+  status_reason = sqlc.arg(status_reason),
+  status_changed_at = now(),
+  date = COALESCE(sqlc.narg(date), date),
+  ends_at = COALESCE(sqlc.narg(ends_at), ends_at),
+  detached = detached OR series_id IS NOT NULL,
+  sequence = sequence + 1
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
@@ -40,18 +80,24 @@ WHERE id = $1;
 -- name: BookEvent :one
 INSERT INTO event_bookings (
   user_id,
-  event_id
+  event_id,
+  party_size,
+  party_names
 ) VALUES (
-  $1, $2
+  $1::bigint, $2, $3, COALESCE($4::varchar[], '{}')
 ) RETURNING *;
 
 -- name: CancelEventBooking :exec
 DELETE FROM event_bookings
-WHERE user_id = $1 AND event_id = $2;
+WHERE user_id = $1::bigint AND event_id = $2;
+
+-- name: DeleteEventBooking :exec
+DELETE FROM event_bookings
+WHERE id = $1;
 
 -- name: GetEventBooking :one
 SELECT * FROM event_bookings
-WHERE user_id = $1 AND event_id = $2 LIMIT 1;
+WHERE user_id = $1::bigint AND event_id = $2 LIMIT 1;
 
 -- name: ListUserBookings :many
 SELECT 
@@ -59,27 +105,42 @@ SELECT
   eb.user_id,
   eb.event_id,
   eb.booked_at,
+  eb.reminders_enabled,
+  eb.party_size,
   e.name as event_name,
   e.place as event_place,
-  e.date as event_date
+  e.date as event_date,
+  e.sequence as event_sequence,
+  e.ends_at as event_ends_at,
+  e.time_zone as event_time_zone,
+  e.status as event_status
 FROM event_bookings eb
 JOIN events e ON eb.event_id = e.id
-WHERE eb.user_id = $1
+WHERE eb.user_id = $1::bigint
 ORDER BY eb.booked_at DESC
 LIMIT $2
 OFFSET $3;
 
+-- name: ListAllEventBookings :many
+-- Every booking of an event, for releasing them when it is cancelled or rescheduled
+SELECT * FROM event_bookings
+WHERE event_id = $1
+ORDER BY id;
+
 -- name: ListEventBookings :many
+-- Confirmed bookings of an event, with the name and email of the user or guest who made them
 SELECT 
   eb.id,
   eb.user_id,
   eb.event_id,
   eb.booked_at,
-  u.name as user_name,
-  u.email as user_email
+  COALESCE(u.name, eb.guest_name) as user_name,
+  COALESCE(u.email, eb.guest_email)::varchar as user_email,
+  eb.party_size,
+  eb.party_names
 FROM event_bookings eb
-JOIN users u ON eb.user_id = u.id
-WHERE eb.event_id = $1
+LEFT JOIN users u ON eb.user_id = u.id
+WHERE eb.event_id = $1 AND eb.confirmed_at IS NOT NULL
 ORDER BY eb.booked_at DESC
 LIMIT $2
 OFFSET $3;
@@ -87,5 +148,11 @@ OFFSET $3;
 -- name: IsEventBooked :one
 SELECT EXISTS(
   SELECT 1 FROM event_bookings
-  WHERE user_id = $1 AND event_id = $2
+  WHERE user_id = $1::bigint AND event_id = $2
 ) as is_booked;
+
+-- name: UpdateEventBookingReminders :one
+UPDATE event_bookings
+SET reminders_enabled = $3
+WHERE user_id = $1::bigint AND event_id = $2
+RETURNING *;
@@ -0,0 +1,37 @@
+-- name: CheckInEventBooking :one
+-- Checks a booking in once. Returns no row when the booking doesn't exist, is for another event
+-- or is already checked in, so concurrent scans of one ticket can't both succeed.
+UPDATE event_bookings
+SET checked_in_at = now(),
+    checked_in_by = sqlc.arg(checked_in_by)::bigint
+WHERE id = sqlc.arg(id)
+  AND event_id = sqlc.arg(event_id)
+  AND checked_in_at IS NULL
+RETURNING *;
+
+-- name: GetEventBookingByID :one
+SELECT * FROM event_bookings
+WHERE id = $1 LIMIT 1;
+
+-- name: GetEventAttendance :one
+SELECT
+  COUNT(*) AS bookings,
+  COUNT(checked_in_at) AS checked_in
+FROM event_bookings
+WHERE event_id = $1 AND confirmed_at IS NOT NULL;
+
+-- name: ListEventAttendance :many
+-- Confirmed bookings and check-ins of every event, latest first
+SELECT
+  e.id,
+  e.name,
+  e.place,
+  e.date,
+  COUNT(eb.id) AS bookings,
+  COUNT(eb.checked_in_at) AS checked_in
+FROM events e
+LEFT JOIN event_bookings eb ON eb.event_id = e.id AND eb.confirmed_at IS NOT NULL
+GROUP BY e.id
+ORDER BY e.date DESC, e.id DESC
+LIMIT $1
+OFFSET $2;
@@ -0,0 +1,24 @@
+-- name: CreateDueEventReminders :many
+-- Records the reminders due offset_minutes before their event and returns the ones this call recorded,
+-- so each reminder is sent once however many schedulers run. Bookings made after a reminder was due
+-- don't get it, and neither do bookings of postponed or cancelled events or guest bookings whose
+-- email isn't confirmed. Guest bookings have no user, so their user_id is 0.
+WITH due AS (
+  INSERT INTO event_reminders (booking_id, offset_minutes)
+  SELECT eb.id, sqlc.arg(offset_minutes)::int
+  FROM event_bookings eb
+  JOIN events e ON e.id = eb.event_id
+  WHERE eb.reminders_enabled
+    AND eb.confirmed_at IS NOT NULL
+    AND e.status IN ('scheduled', 'rescheduled')
+    AND e.date > now()
+    AND e.date <= now() + make_interval(mins => sqlc.arg(offset_minutes)::int)
+    AND eb.booked_at < e.date - make_interval(mins => sqlc.arg(offset_minutes)::int)
+  ON CONFLICT (booking_id, offset_minutes) DO NOTHING
+  RETURNING booking_id, offset_minutes
+)
+SELECT eb.id AS booking_id, eb.event_id, COALESCE(eb.user_id, 0)::bigint AS user_id, e.date AS event_date, due.offset_minutes
+FROM due
+JOIN event_bookings eb ON eb.id = due.booking_id
+JOIN events e ON e.id = eb.event_id
+ORDER BY eb.id;
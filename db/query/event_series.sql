@@ -0,0 +1,105 @@
+-- name: CreateEventSeries :one
+INSERT INTO event_series (
+  name,
+  place,
+  goal_id,
+  ticket_price,
+  starts_at,
+  rrule,
+  exdates,
+  materialized_until,
+  ends_at,
+  time_zone,
+  venue_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING *;
+
+-- name: GetEventSeries :one
+SELECT * FROM event_series
+WHERE id = $1 LIMIT 1;
+
+-- name: GetEventSeriesForUpdate :one
+SELECT * FROM event_series
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: UpdateEventSeries :one
+UPDATE event_series
+SET
+  name = $2,
+  place = $3,
+  goal_id = $4,
+  ticket_price = $5,
+  starts_at = $6,
+  rrule = $7,
+  exdates = $8,
+  materialized_until = $9,
+  ends_at = $10,
+  time_zone = $11,
+  venue_id = $12
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteEventSeries :exec
+DELETE FROM event_series
+WHERE id = $1;
+
+-- name: ListDueEventSeries :many
+-- Series whose occurrences haven't been materialized up to the horizon yet
+SELECT id FROM event_series
+WHERE materialized_until < sqlc.arg(horizon)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: CreateEventOccurrence :execrows
+INSERT INTO events (
+  name,
+  place,
+  date,
+  goal_id,
+  ticket_price,
+  series_id,
+  recurrence_id,
+  ends_at,
+  time_zone,
+  venue_id
+) VALUES (
+  sqlc.arg(name),
+  sqlc.arg(place),
+  sqlc.arg(date),
+  sqlc.arg(goal_id),
+  sqlc.arg(ticket_price),
+  sqlc.arg(series_id)::bigint,
+  sqlc.arg(recurrence_id)::timestamptz,
+  sqlc.arg(ends_at),
+  sqlc.arg(time_zone),
+  sqlc.arg(venue_id)
+)
+ON CONFLICT (series_id, recurrence_id) DO NOTHING;
+
+-- name: ListEventSeriesOccurrences :many
+-- Occurrences of a series from a start in the series onwards
+SELECT * FROM events
+WHERE series_id = sqlc.arg(series_id)::bigint
+  AND recurrence_id >= sqlc.arg(since)::timestamptz
+ORDER BY recurrence_id
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateEventOccurrence :one
+UPDATE events
+SET
+  name = $2,
+  place = $3,
+  date = $4,
+  goal_id = $5,
+  ticket_price = $6,
+  series_id = $7,
+  recurrence_id = $8,
+  detached = $9,
+  ends_at = $10,
+  time_zone = $11,
+  venue_id = $12,
+  sequence = sequence + 1
+WHERE id = $1
+RETURNING *;
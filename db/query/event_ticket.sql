@@ -0,0 +1,20 @@
+-- name: CreateTicketRefund :one
+INSERT INTO ticket_refunds (
+  user_id,
+  event_id,
+  goal_id,
+  donation_id,
+  amount
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetTicketRefundByDonation :one
+SELECT * FROM ticket_refunds
+WHERE donation_id = $1 LIMIT 1;
+
+-- name: SetEventBookingDonation :one
+UPDATE event_bookings
+SET donation_id = $2
+WHERE id = $1
+RETURNING *;
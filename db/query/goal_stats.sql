@@ -0,0 +1,53 @@
+-- name: GetGoalDonationStats :one
+SELECT
+  COUNT(*)::bigint AS donation_count,
+  (COUNT(DISTINCT user_id) + COUNT(*) FILTER (WHERE user_id IS NULL))::bigint AS donor_count,
+  COALESCE(SUM(amount), 0)::bigint AS total_amount,
+  COALESCE(ROUND(AVG(amount)), 0)::bigint AS average_amount,
+  COALESCE(ROUND(percentile_cont(0.5) WITHIN GROUP (ORDER BY amount)), 0)::bigint AS median_amount,
+  COALESCE(MAX(amount), 0)::bigint AS largest_amount
+FROM donations d
+WHERE goal_id = $1
+  AND NOT EXISTS (SELECT 1 FROM ticket_refunds tr WHERE tr.donation_id = d.id);
+
+-- name: GetGoalProgress :one
+SELECT
+  g.id,
+  g.collected_amount,
+  g.target_amount,
+  COUNT(d.id)::bigint AS donation_count,
+  (COUNT(DISTINCT d.user_id) + COUNT(d.id) FILTER (WHERE d.user_id IS NULL))::bigint AS donor_count
+FROM goals g
+LEFT JOIN donations d ON d.goal_id = g.id
+  AND NOT EXISTS (SELECT 1 FROM ticket_refunds tr WHERE tr.donation_id = d.id)
+WHERE g.id = $1
+GROUP BY g.id;
+
+-- name: ListGoalDonationSeries :many
+SELECT
+  date_trunc(sqlc.arg(bucket)::text, created_at, 'UTC')::timestamptz AS bucket_start,
+  COUNT(*)::bigint AS donation_count,
+  SUM(amount)::bigint AS total_amount
+FROM donations d
+WHERE goal_id = sqlc.arg(goal_id)
+  AND NOT EXISTS (SELECT 1 FROM ticket_refunds tr WHERE tr.donation_id = d.id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+GROUP BY bucket_start
+ORDER BY bucket_start;
+
+-- name: ListGoalTopDonors :many
+SELECT
+  u.id AS user_id,
+  u.name AS user_name,
+  SUM(d.amount)::bigint AS total_amount,
+  COUNT(*)::bigint AS donation_count
+FROM donations d
+JOIN users u ON d.user_id = u.id
+WHERE d.goal_id = $1
+  AND d.is_anonymous = false
+  AND u.hide_from_leaderboards = false
+  AND NOT EXISTS (SELECT 1 FROM ticket_refunds tr WHERE tr.donation_id = d.id)
+GROUP BY u.id
+ORDER BY total_amount DESC, u.id
+LIMIT $2;
@@ -0,0 +1,30 @@
+-- name: CreateGuestBooking :one
+-- Books an event for a guest, unconfirmed until the guest follows the link emailed to them.
+-- Returns no row when the email has already booked the event, leaving that booking as it is.
+INSERT INTO event_bookings (
+  event_id,
+  party_size,
+  party_names,
+  guest_name,
+  guest_email,
+  guest_locale,
+  confirmed_at
+) VALUES (
+  $1, $2, COALESCE($3::varchar[], '{}'), $4::varchar, $5::varchar, $6, NULL
+)
+ON CONFLICT (event_id, lower(guest_email)) WHERE user_id IS NULL
+DO NOTHING
+RETURNING *;
+
+-- name: ConfirmGuestBooking :one
+UPDATE event_bookings
+SET confirmed_at = now()
+WHERE id = $1 AND user_id IS NULL
+RETURNING *;
+
+-- name: GetGuestBookingByEmail :one
+SELECT * FROM event_bookings
+WHERE event_id = $1
+  AND lower(guest_email) = lower(sqlc.arg(guest_email)::varchar)
+  AND user_id IS NULL
+LIMIT 1;
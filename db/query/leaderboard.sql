@@ -0,0 +1,25 @@
+-- name: GetAnonymousDonationTotals :one
+SELECT
+  COUNT(*)::bigint AS donation_count,
+  COALESCE(SUM(d.amount), 0)::bigint AS total_amount
+FROM donations d
+LEFT JOIN users u ON d.user_id = u.id
+WHERE (d.is_anonymous = true OR u.id IS NULL OR u.hide_from_leaderboards = true)
+  AND (sqlc.narg(goal_id)::bigint IS NULL OR d.goal_id = sqlc.narg(goal_id))
+  AND NOT EXISTS (SELECT 1 FROM ticket_refunds tr WHERE tr.donation_id = d.id);
+
+-- name: ListLeaderboard :many
+SELECT
+  u.id AS user_id,
+  u.name AS user_name,
+  SUM(d.amount)::bigint AS total_amount,
+  COUNT(*)::bigint AS donation_count
+FROM donations d
+JOIN users u ON d.user_id = u.id
+WHERE d.is_anonymous = false
+  AND u.hide_from_leaderboards = false
+  AND (sqlc.narg(goal_id)::bigint IS NULL OR d.goal_id = sqlc.narg(goal_id))
+  AND NOT EXISTS (SELECT 1 FROM ticket_refunds tr WHERE tr.donation_id = d.id)
+GROUP BY u.id
+ORDER BY total_amount DESC, u.id
+LIMIT sqlc.arg(row_limit);
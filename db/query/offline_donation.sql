@@ -0,0 +1,16 @@
+-- name: CreateOfflineDonation :one
+INSERT INTO offline_donations (
+  donation_id,
+  external_ref,
+  method,
+  donor_name,
+  donor_email,
+  donor_name_public,
+  imported_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetOfflineDonationByRef :one
+SELECT * FROM offline_donations
+WHERE external_ref = $1 LIMIT 1;
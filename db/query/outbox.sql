@@ -0,0 +1,53 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox_events (
+  event_type,
+  payload
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: GetOutboxEvent :one
+SELECT * FROM outbox_events
+WHERE id = $1 LIMIT 1;
+
+-- name: ClaimDueOutboxEvents :many
+-- Holds the due events back from other dispatchers until locked_until, so they can be published
+-- without keeping a transaction open. Events not marked dispatched by then are due again.
+UPDATE outbox_events
+SET next_attempt_at = sqlc.arg(locked_until)
+WHERE id IN (
+  SELECT id FROM outbox_events
+  WHERE dispatched_at IS NULL
+    AND next_attempt_at <= now()
+  ORDER BY id
+  LIMIT sqlc.arg(row_limit)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkOutboxEventDispatched :exec
+UPDATE outbox_events
+SET dispatched_at = now()
+WHERE id = $1;
+
+-- name: RecordOutboxEventFailure :exec
+UPDATE outbox_events
+SET
+  attempts = attempts + 1,
+  next_attempt_at = $2,
+  last_error = $3
+WHERE id = $1;
+
+-- name: ListOutboxDeliveries :many
+-- Subscribers that already handled an event
+SELECT subscriber FROM outbox_deliveries
+WHERE event_id = $1;
+
+-- name: RecordOutboxDelivery :exec
+INSERT INTO outbox_deliveries (
+  event_id,
+  subscriber
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (event_id, subscriber) DO NOTHING;
@@ -0,0 +1,46 @@
+-- name: NextReceiptNumber :one
+INSERT INTO receipt_counters (
+  year,
+  last_number
+) VALUES (
+  $1, 1
+)
+ON CONFLICT (year) DO UPDATE
+SET last_number = receipt_counters.last_number + 1
+RETURNING last_number;
+
+-- name: CreateReceipt :one
+INSERT INTO receipts (
+  receipt_number,
+  user_id,
+  donation_id,
+  year,
+  kind,
+  amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetReceiptByDonation :one
+SELECT * FROM receipts
+WHERE donation_id = $1 LIMIT 1;
+
+-- name: GetAnnualReceipt :one
+SELECT * FROM receipts
+WHERE user_id = $1 AND year = $2 AND kind = 'annual'
+LIMIT 1;
+
+-- name: AddReceiptDonation :exec
+INSERT INTO receipt_donations (
+  receipt_id,
+  donation_id
+) VALUES (
+  $1, $2
+);
+
+-- name: ListReceiptDonations :many
+-- Lists the donations itemised on a receipt when it was issued
+SELECT d.* FROM donations d
+JOIN receipt_donations rd ON rd.donation_id = d.id
+WHERE rd.receipt_id = $1
+ORDER BY d.created_at ASC, d.id ASC;
@@ -21,10 +21,23 @@ ORDER BY id
 LIMIT $1
 OFFSET $2;
 
+-- name: ListGoalDonorContacts :many
+-- Registered donors of a goal, including anonymous ones since the email isn't shown to anyone
+SELECT DISTINCT u.id, u.email, u.name, u.locale
+FROM users u
+JOIN donations d ON d.user_id = u.id
+WHERE d.goal_id = $1
+  AND NOT EXISTS (SELECT 1 FROM ticket_refunds tr WHERE tr.donation_id = d.id)
+ORDER BY u.id;
+
 -- name: UpdateUser :one
+-- name is only changed when set_name is true, so it can also be cleared to NULL
 UPDATE users
-SET name = $2
-WHERE id = $1
+SET
+  name = CASE WHEN sqlc.arg(set_name)::boolean THEN sqlc.narg(name) ELSE name END,
+  hide_from_leaderboards = COALESCE(sqlc.narg(hide_from_leaderboards), hide_from_leaderboards),
+  locale = COALESCE(sqlc.narg(locale), locale)
+WHERE id = sqlc.arg(id)
 RETURNING *;
 
 -- name: DeleteUser :exec
@@ -36,3 +49,8 @@ UPDATE users
 SET balance = balance + $2
 WHERE id = $1
 RETURNING *;
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
@@ -0,0 +1,67 @@
+-- name: CreateVenue :one
+INSERT INTO venues (
+  name,
+  address_line,
+  city,
+  region,
+  postal_code,
+  country,
+  latitude,
+  longitude,
+  time_zone,
+  capacity,
+  accessibility_notes
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING *;
+
+-- name: GetVenue :one
+SELECT * FROM venues
+WHERE id = $1 LIMIT 1;
+
+-- name: ListVenues :many
+SELECT * FROM venues
+ORDER BY name, id
+LIMIT $1
+OFFSET $2;
+
+-- name: UpdateVenue :one
+UPDATE venues
+SET
+  name = COALESCE(sqlc.narg(name), name),
+  address_line = COALESCE(sqlc.narg(address_line), address_line),
+  city = COALESCE(sqlc.narg(city), city),
+  region = COALESCE(sqlc.narg(region), region),
+  postal_code = COALESCE(sqlc.narg(postal_code), postal_code),
+  country = COALESCE(sqlc.narg(country), country),
+  latitude = COALESCE(sqlc.narg(latitude), latitude),
+  longitude = COALESCE(sqlc.narg(longitude), longitude),
+  time_zone = COALESCE(sqlc.narg(time_zone), time_zone),
+  capacity = COALESCE(sqlc.narg(capacity), capacity),
+  accessibility_notes = COALESCE(sqlc.narg(accessibility_notes), accessibility_notes)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteVenue :exec
+DELETE FROM venues
+WHERE id = $1;
+
+-- name: ListEventsNear :many
+-- Events at venues within radius_km of a point, nearest first. The haversine distance is
+-- computed in SQL and the latitude band lets the venues index skip far away venues.
+SELECT sqlc.embed(e), v.latitude, v.longitude, nearby.distance_km
+FROM events e
+JOIN venues v ON v.id = e.venue_id
+CROSS JOIN LATERAL (
+  SELECT (6371 * 2 * asin(least(1, sqrt(
+    power(sin(radians(v.latitude - sqlc.arg(latitude)::float8) / 2), 2) +
+    cos(radians(sqlc.arg(latitude)::float8)) * cos(radians(v.latitude)) *
+    power(sin(radians(v.longitude - sqlc.arg(longitude)::float8) / 2), 2)
+  ))))::float8 AS distance_km
+) nearby
+WHERE v.latitude BETWEEN sqlc.arg(min_latitude)::float8 AND sqlc.arg(max_latitude)::float8
+  AND nearby.distance_km <= sqlc.arg(radius_km)::float8
+  AND (NOT sqlc.arg(upcoming_only)::boolean OR (e.date > NOW() AND e.status <> 'cancelled'))
+ORDER BY nearby.distance_km, e.date
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
@@ -0,0 +1,109 @@
+-- name: CreateVolunteerRole :one
+INSERT INTO volunteer_roles (
+  event_id,
+  name,
+  description,
+  slots
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetVolunteerRole :one
+SELECT * FROM volunteer_roles
+WHERE id = $1 LIMIT 1;
+
+-- name: GetVolunteerRoleForUpdate :one
+-- Locks the role, so sign-ups and changes to its slots are counted one at a time
+SELECT * FROM volunteer_roles
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListEventVolunteerRoles :many
+-- Volunteer roles of an event with how many of their slots are taken
+SELECT vr.*, COUNT(vs.id) AS signed_up
+FROM volunteer_roles vr
+LEFT JOIN volunteer_shifts vs ON vs.role_id = vr.id
+WHERE vr.event_id = $1
+GROUP BY vr.id
+ORDER BY vr.name, vr.id;
+
+-- name: UpdateVolunteerRole :one
+UPDATE volunteer_roles
+SET
+  name = COALESCE(sqlc.narg(name), name),
+  description = COALESCE(sqlc.narg(description), description),
+  slots = COALESCE(sqlc.narg(slots), slots)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteVolunteerRole :one
+-- Deletes a role and its sign-ups unless time was logged for any of them
+DELETE FROM volunteer_roles
+WHERE id = $1 AND NOT EXISTS (
+  SELECT 1 FROM volunteer_shifts
+  WHERE role_id = $1 AND logged_at IS NOT NULL
+)
+RETURNING id;
+
+-- name: DeleteEventVolunteerRoles :exec
+-- Deletes the roles of an event and their sign-ups, except roles with logged time
+DELETE FROM volunteer_roles vr
+WHERE vr.event_id = $1 AND NOT EXISTS (
+  SELECT 1 FROM volunteer_shifts vs
+  WHERE vs.role_id = vr.id AND vs.logged_at IS NOT NULL
+);
+
+-- name: CountVolunteerShifts :one
+SELECT COUNT(*) FROM volunteer_shifts
+WHERE role_id = $1;
+
+-- name: CreateVolunteerShift :one
+INSERT INTO volunteer_shifts (
+  role_id,
+  user_id
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: DeleteVolunteerShift :exec
+DELETE FROM volunteer_shifts
+WHERE role_id = $1 AND user_id = $2;
+
+-- name: ListEventVolunteers :many
+-- Volunteers signed up for the roles of an event, with their names and the time logged for them
+SELECT vs.*, vr.name AS role_name, u.name AS user_name, u.email AS user_email
+FROM volunteer_shifts vs
+JOIN volunteer_roles vr ON vr.id = vs.role_id
+JOIN users u ON u.id = vs.user_id
+WHERE vr.event_id = $1
+ORDER BY vr.name, vr.id, vs.signed_up_at, vs.id;
+
+-- name: LogVolunteerMinutes :one
+-- Logs the time a volunteer worked at an event, replacing any time logged before
+UPDATE volunteer_shifts
+SET
+  minutes = sqlc.arg(minutes)::integer,
+  logged_at = now(),
+  logged_by = sqlc.arg(logged_by)::bigint
+WHERE id = sqlc.arg(id) AND role_id IN (
+  SELECT id FROM volunteer_roles
+  WHERE event_id = sqlc.arg(event_id)
+)
+RETURNING *;
+
+-- name: ListUserVolunteerShifts :many
+-- A user's volunteer history, with the role and event of every shift, latest event first
+SELECT vs.*, vr.name AS role_name, vr.event_id, e.name AS event_name, e.place AS event_place,
+  e.date AS event_date, e.time_zone AS event_time_zone, e.status AS event_status
+FROM volunteer_shifts vs
+JOIN volunteer_roles vr ON vr.id = vs.role_id
+JOIN events e ON e.id = vr.event_id
+WHERE vs.user_id = $1
+ORDER BY e.date DESC, vs.id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: GetUserVolunteerMinutes :one
+-- Total time logged for a user's shifts
+SELECT COALESCE(SUM(minutes), 0)::bigint AS minutes FROM volunteer_shifts
+WHERE user_id = $1;
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteEventRecordsCancellations(t *testing.T) {
+	event := createRandomEvent(t, testStore)
+	booking := bookEventAt(t, event, time.Now())
+
+	// Bookings aren't deleted along with their event
+	err := testStore.DeleteEvent(context.Background(), event.ID)
+	require.Error(t, err)
+
+	_, err = testStore.CancelEventBookingTx(context.Background(), CancelEventBookingTxParams{
+		UserID:  booking.UserID.Int64,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+
+	err = testStore.DeleteEvent(context.Background(), event.ID)
+	require.NoError(t, err)
+
+	cancellation, err := testStore.GetEventCancellation(context.Background(), event.ID)
+	require.NoError(t, err)
+	require.False(t, cancellation.UserID.Valid)
+	require.Equal(t, event.Name, cancellation.Name)
+	require.Equal(t, event.Place, cancellation.Place)
+	require.WithinDuration(t, event.Date, cancellation.Date, time.Second)
+	require.Equal(t, event.Sequence+1, cancellation.Sequence)
+
+	cancellations, err := testStore.ListUserEventCancellations(context.Background(), ListUserEventCancellationsParams{
+		UserID:   booking.UserID.Int64,
+		Since:    event.Date.Add(-time.Minute),
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	// The cancelled booking is recorded once
+	require.Len(t, cancellations, 1)
+	require.Equal(t, event.ID, cancellations[0].EventID)
+	require.Equal(t, event.Sequence+1, cancellations[0].Sequence)
+}
+
+func TestCancelEventBookingRecordsCancellation(t *testing.T) {
+	event := createRandomEvent(t, testStore)
+	booking := bookEventAt(t, event, time.Now())
+
+	arg := ListUserEventCancellationsParams{
+		UserID:   booking.UserID.Int64,
+		Since:    event.Date.Add(-time.Minute),
+		RowLimit: 10,
+	}
+
+	_, err := testStore.CancelEventBookingTx(context.Background(), CancelEventBookingTxParams{
+		UserID:  booking.UserID.Int64,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+
+	cancellations, err := testStore.ListUserEventCancellations(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, cancellations, 1)
+	require.Equal(t, event.ID, cancellations[0].EventID)
+
+	_, err = testStore.GetEventCancellation(context.Background(), event.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// Booking again hides the cancellation
+	_, err = testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  booking.UserID.Int64,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+
+	cancellations, err = testStore.ListUserEventCancellations(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, cancellations)
+}
+
+func TestUpsertCalendarToken(t *testing.T) {
+	user := createRandomUser(t, testStore)
+
+	token1, err := testStore.UpsertCalendarToken(context.Background(), UpsertCalendarTokenParams{
+		UserID:    user.ID,
+		TokenHash: util.RandomString(64),
+	})
+	require.NoError(t, err)
+
+	token2, err := testStore.UpsertCalendarToken(context.Background(), UpsertCalendarTokenParams{
+		UserID:    user.ID,
+		TokenHash: util.RandomString(64),
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.GetCalendarTokenByHash(context.Background(), token1.TokenHash)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	found, err := testStore.GetCalendarTokenByHash(context.Background(), token2.TokenHash)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, found.UserID)
+
+	err = testStore.DeleteCalendarToken(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	_, err = testStore.GetCalendarTokenByHash(context.Background(), token2.TokenHash)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
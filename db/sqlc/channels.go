@@ -0,0 +1,11 @@
+package db
+
+// Postgres notification channels signalled by triggers
+const (
+	// OutboxChannel is signalled when outbox events are committed
+	OutboxChannel = "outbox_events"
+	// GoalProgressChannel carries the ID of a goal whose collected amount changed
+	GoalProgressChannel = "goal_progress"
+	// DonationChannel carries the ID of every new donation
+	DonationChannel = "donations"
+)
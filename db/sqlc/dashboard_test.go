@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshDailyStats(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	arg := GetDashboardTotalsParams{
+		FromDay: pgtype.Date{Time: today, Valid: true},
+		ToDay:   pgtype.Date{Time: today.AddDate(0, 0, 1), Valid: true},
+	}
+
+	require.NoError(t, testStore.RefreshDailyStats(context.Background()))
+	before, err := testStore.GetDashboardTotals(context.Background(), arg)
+	require.NoError(t, err)
+
+	createRandomUser(t, testStore)
+	goal := createGoalWithStatus(t, testStore, true)
+	donation, err := testStore.CreateDonation(context.Background(), CreateDonationParams{
+		GoalID: goal.ID,
+		Amount: 1500,
+	})
+	require.NoError(t, err)
+
+	// The summary only changes once it is refreshed
+	stale, err := testStore.GetDashboardTotals(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, before, stale)
+
+	require.NoError(t, testStore.RefreshDailyStats(context.Background()))
+	after, err := testStore.GetDashboardTotals(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.GreaterOrEqual(t, after.DonationCount, before.DonationCount+1)
+	require.GreaterOrEqual(t, after.DonationAmount, before.DonationAmount+donation.Amount)
+	require.GreaterOrEqual(t, after.NewUsers, before.NewUsers+1)
+}
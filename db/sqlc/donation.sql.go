@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -48,6 +49,46 @@ func (q *Queries) CreateDonation(ctx context.Context, arg CreateDonationParams)
 	return i, err
 }
 
+const createDonationAt = `-- name: CreateDonationAt :one
+INSERT INTO donations (
+  goal_id,
+  user_id,
+  amount,
+  is_anonymous,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING id, user_id, goal_id, amount, is_anonymous, created_at
+`
+
+type CreateDonationAtParams struct {
+	GoalID      int64       `json:"goal_id"`
+	UserID      pgtype.Int8 `json:"user_id"`
+	Amount      int64       `json:"amount"`
+	IsAnonymous bool        `json:"is_anonymous"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+func (q *Queries) CreateDonationAt(ctx context.Context, arg CreateDonationAtParams) (Donation, error) {
+	row := q.db.QueryRow(ctx, createDonationAt,
+		arg.GoalID,
+		arg.UserID,
+		arg.Amount,
+		arg.IsAnonymous,
+		arg.CreatedAt,
+	)
+	var i Donation
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.GoalID,
+		&i.Amount,
+		&i.IsAnonymous,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getDonation = `-- name: GetDonation :one
 SELECT id, user_id, goal_id, amount, is_anonymous, created_at FROM donations
 WHERE id = $1 LIMIT 1
@@ -67,6 +108,26 @@ func (q *Queries) GetDonation(ctx context.Context, id int64) (Donation, error) {
 	return i, err
 }
 
+const getDonationForUpdate = `-- name: GetDonationForUpdate :one
+SELECT id, user_id, goal_id, amount, is_anonymous, created_at FROM donations
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetDonationForUpdate(ctx context.Context, id int64) (Donation, error) {
+	row := q.db.QueryRow(ctx, getDonationForUpdate, id)
+	var i Donation
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.GoalID,
+		&i.Amount,
+		&i.IsAnonymous,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const listDonations = `-- name: ListDonations :many
 SELECT id, user_id, goal_id, amount, is_anonymous, created_at FROM donations
 ORDER BY created_at DESC
@@ -188,6 +249,140 @@ func (q *Queries) ListDonationsByUser(ctx context.Context, arg ListDonationsByUs
 	return items, nil
 }
 
+const listDonationsForExport = `-- name: ListDonationsForExport :many
+SELECT
+  d.id,
+  d.goal_id,
+  g.title AS goal_title,
+  d.amount,
+  d.is_anonymous,
+  d.created_at,
+  d.user_id,
+  u.name AS user_name,
+  u.email AS user_email,
+  od.donor_name,
+  od.donor_email,
+  tr.refunded_at
+FROM donations d
+JOIN goals g ON d.goal_id = g.id
+LEFT JOIN users u ON d.user_id = u.id
+LEFT JOIN offline_donations od ON od.donation_id = d.id
+LEFT JOIN ticket_refunds tr ON tr.donation_id = d.id
+WHERE d.id > $1
+  AND ($2::bigint IS NULL OR d.goal_id = $2)
+  AND ($3::timestamptz IS NULL OR d.created_at >= $3)
+  AND ($4::timestamptz IS NULL OR d.created_at < $4)
+  AND ($5::boolean IS NULL OR d.is_anonymous = $5)
+ORDER BY d.id
+LIMIT $6
+`
+
+type ListDonationsForExportParams struct {
+	AfterID     int64              `json:"after_id"`
+	GoalID      pgtype.Int8        `json:"goal_id"`
+	FromTime    pgtype.Timestamptz `json:"from_time"`
+	ToTime      pgtype.Timestamptz `json:"to_time"`
+	IsAnonymous pgtype.Bool        `json:"is_anonymous"`
+	RowLimit    int32              `json:"row_limit"`
+}
+
+type ListDonationsForExportRow struct {
+	ID          int64              `json:"id"`
+	GoalID      int64              `json:"goal_id"`
+	GoalTitle   string             `json:"goal_title"`
+	Amount      int64              `json:"amount"`
+	IsAnonymous bool               `json:"is_anonymous"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UserID      pgtype.Int8        `json:"user_id"`
+	UserName    pgtype.Text        `json:"user_name"`
+	UserEmail   pgtype.Text        `json:"user_email"`
+	DonorName   pgtype.Text        `json:"donor_name"`
+	DonorEmail  pgtype.Text        `json:"donor_email"`
+	RefundedAt  pgtype.Timestamptz `json:"refunded_at"`
+}
+
+func (q *Queries) ListDonationsForExport(ctx context.Context, arg ListDonationsForExportParams) ([]ListDonationsForExportRow, error) {
+	rows, err := q.db.Query(ctx, listDonationsForExport,
+		arg.AfterID,
+		arg.GoalID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.IsAnonymous,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDonationsForExportRow{}
+	for rows.Next() {
+		var i ListDonationsForExportRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.GoalID,
+			&i.GoalTitle,
+			&i.Amount,
+			&i.IsAnonymous,
+			&i.CreatedAt,
+			&i.UserID,
+			&i.UserName,
+			&i.UserEmail,
+			&i.DonorName,
+			&i.DonorEmail,
+			&i.RefundedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserDonationsBetween = `-- name: ListUserDonationsBetween :many
+SELECT id, user_id, goal_id, amount, is_anonymous, created_at FROM donations
+WHERE user_id = $1
+  AND created_at >= $2
+  AND created_at < $3
+  AND NOT EXISTS (SELECT 1 FROM ticket_refunds tr WHERE tr.donation_id = donations.id)
+ORDER BY created_at ASC
+`
+
+type ListUserDonationsBetweenParams struct {
+	UserID   pgtype.Int8 `json:"user_id"`
+	FromTime time.Time   `json:"from_time"`
+	ToTime   time.Time   `json:"to_time"`
+}
+
+func (q *Queries) ListUserDonationsBetween(ctx context.Context, arg ListUserDonationsBetweenParams) ([]Donation, error) {
+	rows, err := q.db.Query(ctx, listUserDonationsBetween, arg.UserID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Donation{}
+	for rows.Next() {
+		var i Donation
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.GoalID,
+			&i.Amount,
+			&i.IsAnonymous,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateGoalCollectedAmount = `-- name: UpdateGoalCollectedAmount :exec
 UPDATE goals
 SET collected_amount = collected_amount + $2
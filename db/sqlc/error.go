@@ -0,0 +1,51 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres error codes checked by the store and handlers
+const (
+	ForeignKeyViolation = "23503"
+	UniqueViolation     = "23505"
+)
+
+// ErrRecordNotFound is returned by queries that expect one row but find none
+var ErrRecordNotFound = pgx.ErrNoRows
+
+// Errors returned by transactions that move money
+var (
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrInactiveGoal        = errors.New("cannot donate to inactive goal")
+	ErrEventHasNoGoal      = errors.New("paid event has no goal to donate ticket sales to")
+	ErrTicketReceiptIssued = errors.New("a receipt was issued for this ticket")
+	ErrDonationRefunded    = errors.New("donation was refunded")
+)
+
+// Errors returned by transactions that book events or change their status
+var (
+	ErrEventCancelled     = errors.New("event is cancelled")
+	ErrEventStarted       = errors.New("event has already started")
+	ErrPartyTooLarge      = errors.New("party is larger than the event allows")
+	ErrGuestBookingPaid   = errors.New("paid events can't be booked as a guest")
+	ErrGuestAlreadyBooked = errors.New("guest has already booked this event")
+)
+
+// Errors returned by transactions that sign up volunteers, change their roles or delete their events
+var (
+	ErrVolunteerRoleFull    = errors.New("every slot of the volunteer role is taken")
+	ErrVolunteerSlotsTaken  = errors.New("more volunteers have signed up than the role would have slots")
+	ErrVolunteerHoursLogged = errors.New("hours have been logged for volunteers of this event")
+)
+
+// ErrorCode returns the Postgres error code of err, or an empty string
+func ErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
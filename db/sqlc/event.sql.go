@@ -15,32 +15,51 @@ import (
 const bookEvent = `-- name: BookEvent :one
 INSERT INTO event_bookings (
   user_id,
-  event_id
+  event_id,
+  party_size,
+  party_names
 ) VALUES (
-  $1, $2
-) RETURNING id, user_id, event_id, booked_at
+  $1::bigint, $2, $3, COALESCE($4::varchar[], '{}')
+) RETURNING id, user_id, event_id, booked_at, reminders_enabled, checked_in_at, checked_in_by, donation_id, party_size, party_names, guest_name, guest_email, guest_locale, confirmed_at
 `
 
 type BookEventParams struct {
-	UserID  int64 `json:"user_id"`
-	EventID int64 `json:"event_id"`
+	UserID     int64    `json:"user_id"`
+	EventID    int64    `json:"event_id"`
+	PartySize  int32    `json:"party_size"`
+	PartyNames []string `json:"party_names"`
 }
 
 func (q *Queries) BookEvent(ctx context.Context, arg BookEventParams) (EventBooking, error) {
-	row := q.db.QueryRow(ctx, bookEvent, arg.UserID, arg.EventID)
+	row := q.db.QueryRow(ctx, bookEvent,
+		arg.UserID,
+		arg.EventID,
+		arg.PartySize,
+		arg.PartyNames,
+	)
 	var i EventBooking
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
 		&i.EventID,
 		&i.BookedAt,
+		&i.RemindersEnabled,
+		&i.CheckedInAt,
+		&i.CheckedInBy,
+		&i.DonationID,
+		&i.PartySize,
+		&i.PartyNames,
+		&i.GuestName,
+		&i.GuestEmail,
+		&i.GuestLocale,
+		&i.ConfirmedAt,
 	)
 	return i, err
 }
 
 const cancelEventBooking = `-- name: CancelEventBooking :exec
 DELETE FROM event_bookings
-WHERE user_id = $1 AND event_id = $2
+WHERE user_id = $1::bigint AND event_id = $2
 `
 
 type CancelEventBookingParams struct {
@@ -57,20 +76,42 @@ const createEvent = `-- name: CreateEvent :one
 INSERT INTO events (
   name,
   place,
-  date
+  date,
+  goal_id,
+  ticket_price,
+  ends_at,
+  time_zone,
+  venue_id,
+  max_party_size
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, name, place, date, created_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING id, name, place, date, created_at, goal_id, sequence, ticket_price, series_id, recurrence_id, detached, time_zone, ends_at, venue_id, status, status_reason, status_changed_at, max_party_size
 `
 
 type CreateEventParams struct {
-	Name  string    `json:"name"`
-	Place string    `json:"place"`
-	Date  time.Time `json:"date"`
+	Name         string      `json:"name"`
+	Place        string      `json:"place"`
+	Date         time.Time   `json:"date"`
+	GoalID       pgtype.Int8 `json:"goal_id"`
+	TicketPrice  int64       `json:"ticket_price"`
+	EndsAt       time.Time   `json:"ends_at"`
+	TimeZone     string      `json:"time_zone"`
+	VenueID      pgtype.Int8 `json:"venue_id"`
+	MaxPartySize pgtype.Int4 `json:"max_party_size"`
 }
 
 func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error) {
-	row := q.db.QueryRow(ctx, createEvent, arg.Name, arg.Place, arg.Date)
+	row := q.db.QueryRow(ctx, createEvent,
+		arg.Name,
+		arg.Place,
+		arg.Date,
+		arg.GoalID,
+		arg.TicketPrice,
+		arg.EndsAt,
+		arg.TimeZone,
+		arg.VenueID,
+		arg.MaxPartySize,
+	)
 	var i Event
 	err := row.Scan(
 		&i.ID,
@@ -78,6 +119,19 @@ func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (Event
 		&i.Place,
 		&i.Date,
 		&i.CreatedAt,
+		&i.GoalID,
+		&i.Sequence,
+		&i.TicketPrice,
+		&i.SeriesID,
+		&i.RecurrenceID,
+		&i.Detached,
+		&i.TimeZone,
+		&i.EndsAt,
+		&i.VenueID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.MaxPartySize,
 	)
 	return i, err
 }
@@ -92,8 +146,18 @@ func (q *Queries) DeleteEvent(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteEventBooking = `-- name: DeleteEventBooking :exec
+DELETE FROM event_bookings
+WHERE id = $1
+`
+
+func (q *Queries) DeleteEventBooking(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, deleteEventBooking, id)
+	return err
+}
+
 const getEvent = `-- name: GetEvent :one
-SELECT id, name, place, date, created_at FROM events
+SELECT id, name, place, date, created_at, goal_id, sequence, ticket_price, series_id, recurrence_id, detached, time_zone, ends_at, venue_id, status, status_reason, status_changed_at, max_party_size FROM events
 WHERE id = $1 LIMIT 1
 `
 
@@ -106,13 +170,26 @@ func (q *Queries) GetEvent(ctx context.Context, id int64) (Event, error) {
 		&i.Place,
 		&i.Date,
 		&i.CreatedAt,
+		&i.GoalID,
+		&i.Sequence,
+		&i.TicketPrice,
+		&i.SeriesID,
+		&i.RecurrenceID,
+		&i.Detached,
+		&i.TimeZone,
+		&i.EndsAt,
+		&i.VenueID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.MaxPartySize,
 	)
 	return i, err
 }
 
 const getEventBooking = `-- name: GetEventBooking :one
-SELECT id, user_id, event_id, booked_at FROM event_bookings
-WHERE user_id = $1 AND event_id = $2 LIMIT 1
+SELECT id, user_id, event_id, booked_at, reminders_enabled, checked_in_at, checked_in_by, donation_id, party_size, party_names, guest_name, guest_email, guest_locale, confirmed_at FROM event_bookings
+WHERE user_id = $1::bigint AND event_id = $2 LIMIT 1
 `
 
 type GetEventBookingParams struct {
@@ -128,6 +205,82 @@ func (q *Queries) GetEventBooking(ctx context.Context, arg GetEventBookingParams
 		&i.UserID,
 		&i.EventID,
 		&i.BookedAt,
+		&i.RemindersEnabled,
+		&i.CheckedInAt,
+		&i.CheckedInBy,
+		&i.DonationID,
+		&i.PartySize,
+		&i.PartyNames,
+		&i.GuestName,
+		&i.GuestEmail,
+		&i.GuestLocale,
+		&i.ConfirmedAt,
+	)
+	return i, err
+}
+
+const getEventForShare = `-- name: GetEventForShare :one
+SELECT id, name, place, date, created_at, goal_id, sequence, ticket_price, series_id, recurrence_id, detached, time_zone, ends_at, venue_id, status, status_reason, status_changed_at, max_party_size FROM events
+WHERE id = $1 LIMIT 1
+FOR SHARE
+`
+
+// Locks the event against status changes while it is booked
+func (q *Queries) GetEventForShare(ctx context.Context, id int64) (Event, error) {
+	row := q.db.QueryRow(ctx, getEventForShare, id)
+	var i Event
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Place,
+		&i.Date,
+		&i.CreatedAt,
+		&i.GoalID,
+		&i.Sequence,
+		&i.TicketPrice,
+		&i.SeriesID,
+		&i.RecurrenceID,
+		&i.Detached,
+		&i.TimeZone,
+		&i.EndsAt,
+		&i.VenueID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.MaxPartySize,
+	)
+	return i, err
+}
+
+const getEventForUpdate = `-- name: GetEventForUpdate :one
+SELECT id, name, place, date, created_at, goal_id, sequence, ticket_price, series_id, recurrence_id, detached, time_zone, ends_at, venue_id, status, status_reason, status_changed_at, max_party_size FROM events
+WHERE id = $1 LIMIT 1
+FOR UPDATE
+`
+
+// Locks the event, and keeps new bookings waiting, while its status changes
+func (q *Queries) GetEventForUpdate(ctx context.Context, id int64) (Event, error) {
+	row := q.db.QueryRow(ctx, getEventForUpdate, id)
+	var i Event
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Place,
+		&i.Date,
+		&i.CreatedAt,
+		&i.GoalID,
+		&i.Sequence,
+		&i.TicketPrice,
+		&i.SeriesID,
+		&i.RecurrenceID,
+		&i.Detached,
+		&i.TimeZone,
+		&i.EndsAt,
+		&i.VenueID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.MaxPartySize,
 	)
 	return i, err
 }
@@ -135,7 +288,7 @@ func (q *Queries) GetEventBooking(ctx context.Context, arg GetEventBookingParams
 const isEventBooked = `-- name: IsEventBooked :one
 SELECT EXISTS(
   SELECT 1 FROM event_bookings
-  WHERE user_id = $1 AND event_id = $2
+  WHERE user_id = $1::bigint AND event_id = $2
 ) as is_booked
 `
 
@@ -151,17 +304,61 @@ func (q *Queries) IsEventBooked(ctx context.Context, arg IsEventBookedParams) (b
 	return is_booked, err
 }
 
+const listAllEventBookings = `-- name: ListAllEventBookings :many
+SELECT id, user_id, event_id, booked_at, reminders_enabled, checked_in_at, checked_in_by, donation_id, party_size, party_names, guest_name, guest_email, guest_locale, confirmed_at FROM event_bookings
+WHERE event_id = $1
+ORDER BY id
+`
+
+// Every booking of an event, for releasing them when it is cancelled or rescheduled
+func (q *Queries) ListAllEventBookings(ctx context.Context, eventID int64) ([]EventBooking, error) {
+	rows, err := q.db.Query(ctx, listAllEventBookings, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []EventBooking{}
+	for rows.Next() {
+		var i EventBooking
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.EventID,
+			&i.BookedAt,
+			&i.RemindersEnabled,
+			&i.CheckedInAt,
+			&i.CheckedInBy,
+			&i.DonationID,
+			&i.PartySize,
+			&i.PartyNames,
+			&i.GuestName,
+			&i.GuestEmail,
+			&i.GuestLocale,
+			&i.ConfirmedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEventBookings = `-- name: ListEventBookings :many
 SELECT 
   eb.id,
   eb.user_id,
   eb.event_id,
   eb.booked_at,
-  u.name as user_name,
-  u.email as user_email
+  COALESCE(u.name, eb.guest_name) as user_name,
+  COALESCE(u.email, eb.guest_email)::varchar as user_email,
+  eb.party_size,
+  eb.party_names
 FROM event_bookings eb
-JOIN users u ON eb.user_id = u.id
-WHERE eb.event_id = $1
+LEFT JOIN users u ON eb.user_id = u.id
+WHERE eb.event_id = $1 AND eb.confirmed_at IS NOT NULL
 ORDER BY eb.booked_at DESC
 LIMIT $2
 OFFSET $3
@@ -174,14 +371,18 @@ type ListEventBookingsParams struct {
 }
 
 type ListEventBookingsRow struct {
-	ID        int64       `json:"id"`
-	UserID    int64       `json:"user_id"`
-	EventID   int64       `json:"event_id"`
-	BookedAt  time.Time   `json:"booked_at"`
-	UserName  pgtype.Text `json:"user_name"`
-	UserEmail string      `json:"user_email"`
+	ID         int64       `json:"id"`
+	UserID     pgtype.Int8 `json:"user_id"`
+	EventID    int64       `json:"event_id"`
+	BookedAt   time.Time   `json:"booked_at"`
+	UserName   pgtype.Text `json:"user_name"`
+	UserEmail  string      `json:"user_email"`
+	PartySize  int32       `json:"party_size"`
+	PartyNames []string    `json:"party_names"`
 }
 
+// Confirmed bookings of an event, with the name and email of the user or guest who made them
+
 func (q *Queries) ListEventBookings(ctx context.Context, arg ListEventBookingsParams) ([]ListEventBookingsRow, error) {
 	rows, err := q.db.Query(ctx, listEventBookings, arg.EventID, arg.Limit, arg.Offset)
 	if err != nil {
@@ -198,6 +399,8 @@ func (q *Queries) ListEventBookings(ctx context.Context, arg ListEventBookingsPa
 			&i.BookedAt,
 			&i.UserName,
 			&i.UserEmail,
+			&i.PartySize,
+			&i.PartyNames,
 		); err != nil {
 			return nil, err
 		}
@@ -210,7 +413,7 @@ func (q *Queries) ListEventBookings(ctx context.Context, arg ListEventBookingsPa
 }
 
 const listEvents = `-- name: ListEvents :many
-SELECT id, name, place, date, created_at FROM events
+SELECT id, name, place, date, created_at, goal_id, sequence, ticket_price, series_id, recurrence_id, detached, time_zone, ends_at, venue_id, status, status_reason, status_changed_at, max_party_size FROM events
 ORDER BY date ASC
 LIMIT $1
 OFFSET $2
@@ -236,6 +439,19 @@ func (q *Queries) ListEvents(ctx context.Context, arg ListEventsParams) ([]Event
 			&i.Place,
 			&i.Date,
 			&i.CreatedAt,
+			&i.GoalID,
+			&i.Sequence,
+			&i.TicketPrice,
+			&i.SeriesID,
+			&i.RecurrenceID,
+			&i.Detached,
+			&i.TimeZone,
+			&i.EndsAt,
+			&i.VenueID,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.MaxPartySize,
 		); err != nil {
 			return nil, err
 		}
@@ -248,8 +464,8 @@ func (q *Queries) ListEvents(ctx context.Context, arg ListEventsParams) ([]Event
 }
 
 const listUpcomingEvents = `-- name: ListUpcomingEvents :many
-SELECT id, name, place, date, created_at FROM events
-WHERE date > NOW()
+SELECT id, name, place, date, created_at, goal_id, sequence, ticket_price, series_id, recurrence_id, detached, time_zone, ends_at, venue_id, status, status_reason, status_changed_at, max_party_size FROM events
+WHERE date > NOW() AND status <> 'cancelled'
 ORDER BY date ASC
 LIMIT $1
 OFFSET $2
@@ -275,6 +491,19 @@ func (q *Queries) ListUpcomingEvents(ctx context.Context, arg ListUpcomingEvents
 			&i.Place,
 			&i.Date,
 			&i.CreatedAt,
+			&i.GoalID,
+			&i.Sequence,
+			&i.TicketPrice,
+			&i.SeriesID,
+			&i.RecurrenceID,
+			&i.Detached,
+			&i.TimeZone,
+			&i.EndsAt,
+			&i.VenueID,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.MaxPartySize,
 		); err != nil {
 			return nil, err
 		}
@@ -292,12 +521,18 @@ SELECT
   eb.user_id,
   eb.event_id,
   eb.booked_at,
+  eb.reminders_enabled,
+  eb.party_size,
   e.name as event_name,
   e.place as event_place,
-  e.date as event_date
+  e.date as event_date,
+  e.sequence as event_sequence,
+  e.ends_at as event_ends_at,
+  e.time_zone as event_time_zone,
+  e.status as event_status
 FROM event_bookings eb
 JOIN events e ON eb.event_id = e.id
-WHERE eb.user_id = $1
+WHERE eb.user_id = $1::bigint
 ORDER BY eb.booked_at DESC
 LIMIT $2
 OFFSET $3
@@ -310,13 +545,19 @@ type ListUserBookingsParams struct {
 }
 
 type ListUserBookingsRow struct {
-	ID         int64     `json:"id"`
-	UserID     int64     `json:"user_id"`
-	EventID    int64     `json:"event_id"`
-	BookedAt   time.Time `json:"booked_at"`
-	EventName  string    `json:"event_name"`
-	EventPlace string    `json:"event_place"`
-	EventDate  time.Time `json:"event_date"`
+	ID               int64       `json:"id"`
+	UserID           pgtype.Int8 `json:"user_id"`
+	EventID          int64       `json:"event_id"`
+	BookedAt         time.Time   `json:"booked_at"`
+	RemindersEnabled bool        `json:"reminders_enabled"`
+	PartySize        int32       `json:"party_size"`
+	EventName        string      `json:"event_name"`
+	EventPlace       string      `json:"event_place"`
+	EventDate        time.Time   `json:"event_date"`
+	EventSequence    int32       `json:"event_sequence"`
+	EventEndsAt      time.Time   `json:"event_ends_at"`
+	EventTimeZone    string      `json:"event_time_zone"`
+	EventStatus      string      `json:"event_status"`
 }
 
 func (q *Queries) ListUserBookings(ctx context.Context, arg ListUserBookingsParams) ([]ListUserBookingsRow, error) {
@@ -333,9 +574,15 @@ func (q *Queries) ListUserBookings(ctx context.Context, arg ListUserBookingsPara
 			&i.UserID,
 			&i.EventID,
 			&i.BookedAt,
+			&i.RemindersEnabled,
+			&i.PartySize,
 			&i.EventName,
 			&i.EventPlace,
 			&i.EventDate,
+			&i.EventSequence,
+			&i.EventEndsAt,
+			&i.EventTimeZone,
+			&i.EventStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -352,16 +599,29 @@ UPDATE events
 SET 
   name = COALESCE($1, name),
   place = COALESCE($2, place),
-  date = COALESCE($3, date)
-WHERE id = $4
-RETURNING id, name, place, date, created_at
+  date = COALESCE($3, date),
+  goal_id = COALESCE($4, goal_id),
+  ticket_price = COALESCE($5, ticket_price),
+  ends_at = COALESCE($6, ends_at),
+  time_zone = COALESCE($7, time_zone),
+  venue_id = COALESCE($8, venue_id),
+  max_party_size = COALESCE($9, max_party_size),
+  sequence = sequence + 1
+WHERE id = $10
+RETURNING id, name, place, date, created_at, goal_id, sequence, ticket_price, series_id, recurrence_id, detached, time_zone, ends_at, venue_id, status, status_reason, status_changed_at, max_party_size
 `
 
 type UpdateEventParams struct {
-	Name  pgtype.Text        `json:"name"`
-	Place pgtype.Text        `json:"place"`
-	Date  pgtype.Timestamptz `json:"date"`
-	ID    int64              `json:"id"`
+	Name         pgtype.Text        `json:"name"`
+	Place        pgtype.Text        `json:"place"`
+	Date         pgtype.Timestamptz `json:"date"`
+	GoalID       pgtype.Int8        `json:"goal_id"`
+	TicketPrice  pgtype.Int8        `json:"ticket_price"`
+	EndsAt       pgtype.Timestamptz `json:"ends_at"`
+	TimeZone     pgtype.Text        `json:"time_zone"`
+	VenueID      pgtype.Int8        `json:"venue_id"`
+	MaxPartySize pgtype.Int4        `json:"max_party_size"`
+	ID           int64              `json:"id"`
 }
 
 func (q *Queries) UpdateEvent(ctx context.Context, arg UpdateEventParams) (Event, error) {
@@ -369,6 +629,103 @@ func (q *Queries) UpdateEvent(ctx context.Context, arg UpdateEventParams) (Event
 		arg.Name,
 		arg.Place,
 		arg.Date,
+		arg.GoalID,
+		arg.TicketPrice,
+		arg.EndsAt,
+		arg.TimeZone,
+		arg.VenueID,
+		arg.MaxPartySize,
+		arg.ID,
+	)
+	var i Event
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Place,
+		&i.Date,
+		&i.CreatedAt,
+		&i.GoalID,
+		&i.Sequence,
+		&i.TicketPrice,
+		&i.SeriesID,
+		&i.RecurrenceID,
+		&i.Detached,
+		&i.TimeZone,
+		&i.EndsAt,
+		&i.VenueID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.MaxPartySize,
+	)
+	return i, err
+}
+
+const updateEventBookingReminders = `-- name: UpdateEventBookingReminders :one
+UPDATE event_bookings
+SET reminders_enabled = $3
+WHERE user_id = $1::bigint AND event_id = $2
+RETURNING id, user_id, event_id, booked_at, reminders_enabled, checked_in_at, checked_in_by, donation_id, party_size, party_names, guest_name, guest_email, guest_locale, confirmed_at
+`
+
+type UpdateEventBookingRemindersParams struct {
+	UserID           int64 `json:"user_id"`
+	EventID          int64 `json:"event_id"`
+	RemindersEnabled bool  `json:"reminders_enabled"`
+}
+
+func (q *Queries) UpdateEventBookingReminders(ctx context.Context, arg UpdateEventBookingRemindersParams) (EventBooking, error) {
+	row := q.db.QueryRow(ctx, updateEventBookingReminders, arg.UserID, arg.EventID, arg.RemindersEnabled)
+	var i EventBooking
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.EventID,
+		&i.BookedAt,
+		&i.RemindersEnabled,
+		&i.CheckedInAt,
+		&i.CheckedInBy,
+		&i.DonationID,
+		&i.PartySize,
+		&i.PartyNames,
+		&i.GuestName,
+		&i.GuestEmail,
+		&i.GuestLocale,
+		&i.ConfirmedAt,
+	)
+	return i, err
+}
+
+const updateEventStatus = `-- name: UpdateEventStatus :one
+UPDATE events
+SET
+  status = $1,
+  status_reason = $2,
+  status_changed_at = now(),
+  date = COALESCE($3, date),
+  ends_at = COALESCE($4, ends_at),
+  detached = detached OR series_id IS NOT NULL,
+  sequence = sequence + 1
+WHERE id = $5
+RETURNING id, name, place, date, created_at, goal_id, sequence, ticket_price, series_id, recurrence_id, detached, time_zone, ends_at, venue_id, status, status_reason, status_changed_at, max_party_size
+`
+
+type UpdateEventStatusParams struct {
+	Status       string             `json:"status"`
+	StatusReason string             `json:"status_reason"`
+	Date         pgtype.Timestamptz `json:"date"`
+	EndsAt       pgtype.Timestamptz `json:"ends_at"`
+	ID           int64              `json:"id"`
+}
+
+// Postpones, reschedules or cancels an event. Occurrences of a series are detached,
+// so later changes to the series leave them as they are.
+func (q *Queries) UpdateEventStatus(ctx context.Context, arg UpdateEventStatusParams) (Event, error) {
+	row := q.db.QueryRow(ctx, updateEventStatus,
+		arg.Status,
+		arg.StatusReason,
+		arg.Date,
+		arg.EndsAt,
 		arg.ID,
 	)
 	var i Event
@@ -378,6 +735,19 @@ func (q *Queries) UpdateEvent(ctx context.Context, arg UpdateEventParams) (Event
 		&i.Place,
 		&i.Date,
 		&i.CreatedAt,
+		&i.GoalID,
+		&i.Sequence,
+		&i.TicketPrice,
+		&i.SeriesID,
+		&i.RecurrenceID,
+		&i.Detached,
+		&i.TimeZone,
+		&i.EndsAt,
+		&i.VenueID,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.MaxPartySize,
 	)
 	return i, err
 }
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckInEventBooking(t *testing.T) {
+	event := createRandomEvent(t, testStore)
+	staff := createRandomUser(t, testStore)
+	attending := bookEventAt(t, event, time.Now())
+	bookEventAt(t, event, time.Now())
+
+	arg := CheckInEventBookingParams{
+		CheckedInBy: staff.ID,
+		ID:          attending.ID,
+		EventID:     event.ID,
+	}
+
+	booking, err := testStore.CheckInEventBooking(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, booking.CheckedInAt.Valid)
+	require.WithinDuration(t, time.Now(), booking.CheckedInAt.Time, time.Minute)
+	require.Equal(t, staff.ID, booking.CheckedInBy.Int64)
+
+	// A ticket checks in once
+	_, err = testStore.CheckInEventBooking(context.Background(), arg)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// and only at its own event
+	other := createRandomEvent(t, testStore)
+	_, err = testStore.CheckInEventBooking(context.Background(), CheckInEventBookingParams{
+		CheckedInBy: staff.ID,
+		ID:          attending.ID,
+		EventID:     other.ID,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	attendance, err := testStore.GetEventAttendance(context.Background(), event.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), attendance.Bookings)
+	require.Equal(t, int64(1), attendance.CheckedIn)
+
+	stored, err := testStore.GetEventBookingByID(context.Background(), attending.ID)
+	require.NoError(t, err)
+	require.Equal(t, booking.CheckedInAt.Time, stored.CheckedInAt.Time)
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+// bookEventAt books an event for a new user as if it had been booked at bookedAt
+func bookEventAt(t *testing.T, event Event, bookedAt time.Time) EventBooking {
+	user := createRandomUser(t, testStore)
+
+	booking, err := testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  user.ID,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+	require.True(t, booking.RemindersEnabled)
+
+	_, err = testStore.(*SQLStore).connPool.Exec(context.Background(),
+		"UPDATE event_bookings SET booked_at = $1 WHERE id = $2", bookedAt, booking.ID)
+	require.NoError(t, err)
+	return booking
+}
+
+// bookGuestAt books event as a guest at bookedAt, confirming the booking if confirmed is set
+func bookGuestAt(t *testing.T, event Event, bookedAt time.Time, confirmed bool) EventBooking {
+	booking, err := testStore.CreateGuestBookingTx(context.Background(), CreateGuestBookingParams{
+		EventID:     event.ID,
+		PartySize:   1,
+		GuestName:   util.RandomName(),
+		GuestEmail:  util.RandomEmail(),
+		GuestLocale: "en",
+	})
+	require.NoError(t, err)
+
+	if confirmed {
+		booking, err = testStore.ConfirmGuestBookingTx(context.Background(), GuestBookingTxParams{
+			BookingID: booking.ID,
+			EventID:   event.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = testStore.(*SQLStore).connPool.Exec(context.Background(),
+		"UPDATE event_bookings SET booked_at = $1 WHERE id = $2", bookedAt, booking.ID)
+	require.NoError(t, err)
+	return booking
+}
+
+func TestScheduleEventRemindersTx(t *testing.T) {
+	event := createRandomEvent(t, testStore)
+	event, err := testStore.UpdateEvent(context.Background(), UpdateEventParams{
+		ID:   event.ID,
+		Date: pgtype.Timestamptz{Time: time.Now().Add(2 * time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+
+	reminded := bookEventAt(t, event, time.Now().Add(-48*time.Hour))
+	optedOut := bookEventAt(t, event, time.Now().Add(-48*time.Hour))
+	late := bookEventAt(t, event, time.Now())
+
+	// Guests are reminded once they have confirmed their email
+	guest := bookGuestAt(t, event, time.Now().Add(-48*time.Hour), true)
+	unconfirmed := bookGuestAt(t, event, time.Now().Add(-48*time.Hour), false)
+
+	_, err = testStore.UpdateEventBookingReminders(context.Background(), UpdateEventBookingRemindersParams{
+		UserID:           optedOut.UserID.Int64,
+		EventID:          event.ID,
+		RemindersEnabled: false,
+	})
+	require.NoError(t, err)
+
+	offsets := []time.Duration{24 * time.Hour, time.Hour}
+	reminders, err := testStore.ScheduleEventRemindersTx(context.Background(), offsets)
+	require.NoError(t, err)
+
+	var bookingIDs []int64
+	for _, reminder := range reminders {
+		if reminder.EventID == event.ID {
+			bookingIDs = append(bookingIDs, reminder.BookingID)
+			require.Equal(t, int32(24*60), reminder.OffsetMinutes)
+			if reminder.BookingID == reminded.ID {
+				require.Equal(t, reminded.UserID.Int64, reminder.UserID)
+			} else {
+				require.Zero(t, reminder.UserID)
+			}
+		}
+	}
+	// The hour reminder isn't due yet, opted out users get none and
+	// bookings made after the reminder was due don't get it
+	require.Equal(t, []int64{reminded.ID, guest.ID}, bookingIDs)
+	require.NotContains(t, bookingIDs, late.ID)
+	require.NotContains(t, bookingIDs, unconfirmed.ID)
+
+	// Reminders are only scheduled once
+	reminders, err = testStore.ScheduleEventRemindersTx(context.Background(), offsets)
+	require.NoError(t, err)
+	for _, reminder := range reminders {
+		require.NotEqual(t, event.ID, reminder.EventID)
+	}
+}
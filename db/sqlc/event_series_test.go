@@ -0,0 +1,277 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+// createWeeklySeries creates a series of count weekly occurrences starting a week from now
+func createWeeklySeries(t *testing.T, count int) EventSeriesTxResult {
+	name, place, _ := util.RandomEventParams()
+	startsAt := time.Now().Add(7 * 24 * time.Hour).UTC().Truncate(time.Second)
+
+	result, err := testStore.CreateEventSeriesTx(context.Background(), CreateEventSeriesTxParams{
+		Name:     name,
+		Place:    place,
+		StartsAt: startsAt,
+		EndsAt:   startsAt.Add(2 * time.Hour),
+		TimeZone: "UTC",
+		Rrule:    fmt.Sprintf("FREQ=WEEKLY;COUNT=%d", count),
+		Horizon:  startsAt.AddDate(1, 0, 0),
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Events, count)
+
+	for i, event := range result.Events {
+		start := startsAt.AddDate(0, 0, 7*i)
+		require.Equal(t, name, event.Name)
+		require.WithinDuration(t, start, event.Date, time.Second)
+		require.WithinDuration(t, start, event.RecurrenceID.Time, time.Second)
+		require.WithinDuration(t, start.Add(2*time.Hour), event.EndsAt, time.Second)
+		require.Equal(t, result.Series.ID, event.SeriesID.Int64)
+		require.False(t, event.Detached)
+	}
+
+	return result
+}
+
+func TestCreateEventSeriesTx(t *testing.T) {
+	createWeeklySeries(t, 5)
+}
+
+func TestCreateEventSeriesTxKeepsLocalTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Weekly at 10:00 in Berlin across the start of daylight saving time on March 31
+	startsAt := time.Date(2030, time.March, 24, 10, 0, 0, 0, berlin)
+	name, place, _ := util.RandomEventParams()
+
+	result, err := testStore.CreateEventSeriesTx(context.Background(), CreateEventSeriesTxParams{
+		Name:     name,
+		Place:    place,
+		StartsAt: startsAt,
+		EndsAt:   startsAt.Add(90 * time.Minute),
+		TimeZone: "Europe/Berlin",
+		Rrule:    "FREQ=WEEKLY;COUNT=3",
+		Horizon:  startsAt.AddDate(0, 1, 0),
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Events, 3)
+
+	for _, event := range result.Events {
+		require.Equal(t, "Europe/Berlin", event.TimeZone)
+		require.Equal(t, 10, event.Date.In(berlin).Hour())
+		require.Equal(t, 90*time.Minute, event.EndsAt.Sub(event.Date))
+	}
+	require.Equal(t, 9, result.Events[0].Date.UTC().Hour())
+	require.Equal(t, 8, result.Events[1].Date.UTC().Hour())
+}
+
+func TestMaterializeEventSeriesTx(t *testing.T) {
+	name, place, _ := util.RandomEventParams()
+	startsAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	result, err := testStore.CreateEventSeriesTx(context.Background(), CreateEventSeriesTxParams{
+		Name:     name,
+		Place:    place,
+		StartsAt: startsAt,
+		EndsAt:   startsAt.Add(time.Hour),
+		TimeZone: "UTC",
+		Rrule:    "FREQ=DAILY",
+		Horizon:  startsAt.Add(3 * 24 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Events, 3)
+
+	_, err = testStore.MaterializeEventSeriesTx(context.Background(), startsAt.Add(5*24*time.Hour))
+	require.NoError(t, err)
+
+	events, err := testStore.ListEventSeriesOccurrences(context.Background(), ListEventSeriesOccurrencesParams{
+		SeriesID: result.Series.ID,
+		Since:    startsAt,
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 5)
+}
+
+func TestUpdateEventSeriesTxThis(t *testing.T) {
+	result := createWeeklySeries(t, 3)
+	event := result.Events[1]
+
+	changed, err := testStore.UpdateEventSeriesTx(context.Background(), UpdateEventSeriesTxParams{
+		EventID: event.ID,
+		Scope:   SeriesScopeThis,
+		Name:    pgtype.Text{String: "Moved", Valid: true},
+		Horizon: event.Date.AddDate(1, 0, 0),
+	})
+	require.NoError(t, err)
+	require.Len(t, changed.Events, 1)
+	require.Equal(t, "Moved", changed.Events[0].Name)
+	require.True(t, changed.Events[0].Detached)
+
+	// Changing the whole series keeps the changes to the single occurrence
+	changed, err = testStore.UpdateEventSeriesTx(context.Background(), UpdateEventSeriesTxParams{
+		EventID: result.Events[0].ID,
+		Scope:   SeriesScopeAll,
+		Place:   pgtype.Text{String: "Elsewhere", Valid: true},
+		Horizon: event.Date.AddDate(1, 0, 0),
+	})
+	require.NoError(t, err)
+	require.Len(t, changed.Events, 3)
+	require.Equal(t, "Elsewhere", changed.Events[0].Place)
+	require.Equal(t, "Moved", changed.Events[1].Name)
+	require.Equal(t, event.Place, changed.Events[1].Place)
+}
+
+func TestUpdateEventSeriesTxFollowing(t *testing.T) {
+	result := createWeeklySeries(t, 4)
+	event := result.Events[2]
+
+	changed, err := testStore.UpdateEventSeriesTx(context.Background(), UpdateEventSeriesTxParams{
+		EventID: event.ID,
+		Scope:   SeriesScopeFollowing,
+		Name:    pgtype.Text{String: "Later", Valid: true},
+		Horizon: event.Date.AddDate(1, 0, 0),
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, result.Series.ID, changed.Series.ID)
+	require.Len(t, changed.Events, 2)
+	for _, occurrence := range changed.Events {
+		require.Equal(t, "Later", occurrence.Name)
+		require.Equal(t, changed.Series.ID, occurrence.SeriesID.Int64)
+	}
+
+	earlier, err := testStore.ListEventSeriesOccurrences(context.Background(), ListEventSeriesOccurrencesParams{
+		SeriesID: result.Series.ID,
+		Since:    result.Series.StartsAt,
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, earlier, 2)
+	require.Equal(t, result.Events[0].Name, earlier[1].Name)
+}
+
+func TestCancelEventSeriesTx(t *testing.T) {
+	result := createWeeklySeries(t, 4)
+
+	err := testStore.CancelEventSeriesTx(context.Background(), CancelEventSeriesTxParams{
+		EventID: result.Events[1].ID,
+		Scope:   SeriesScopeThis,
+	})
+	require.NoError(t, err)
+
+	series, err := testStore.GetEventSeries(context.Background(), result.Series.ID)
+	require.NoError(t, err)
+	require.Len(t, series.Exdates, 1)
+
+	err = testStore.CancelEventSeriesTx(context.Background(), CancelEventSeriesTxParams{
+		EventID: result.Events[2].ID,
+		Scope:   SeriesScopeFollowing,
+	})
+	require.NoError(t, err)
+
+	events, err := testStore.ListEventSeriesOccurrences(context.Background(), ListEventSeriesOccurrencesParams{
+		SeriesID: result.Series.ID,
+		Since:    result.Series.StartsAt,
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	err = testStore.CancelEventSeriesTx(context.Background(), CancelEventSeriesTxParams{
+		EventID: result.Events[0].ID,
+		Scope:   SeriesScopeAll,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.GetEventSeries(context.Background(), result.Series.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestCancelEventSeriesTxReleasesBookings(t *testing.T) {
+	result := createWeeklySeries(t, 3)
+	booking := bookEventAt(t, result.Events[1], time.Now())
+
+	err := testStore.CancelEventSeriesTx(context.Background(), CancelEventSeriesTxParams{
+		EventID: result.Events[1].ID,
+		Scope:   SeriesScopeFollowing,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.GetEventBookingByID(context.Background(), booking.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// The booker is told the occurrence is cancelled
+	var bookers []EventBooker
+	for _, outboxEvent := range dispatchAll(t, testStore) {
+		if outboxEvent.EventType != EventStatusChanged {
+			continue
+		}
+		var payload EventStatusChangedPayload
+		require.NoError(t, json.Unmarshal(outboxEvent.Payload, &payload))
+		if payload.EventID == result.Events[1].ID {
+			require.Equal(t, EventStatusCancelled, payload.Status)
+			require.Equal(t, result.Events[1].Name, payload.Name)
+			require.True(t, payload.BookingsReleased)
+			bookers = payload.Bookers
+		}
+	}
+	require.Len(t, bookers, 1)
+	require.Equal(t, booking.UserID.Int64, bookers[0].UserID)
+}
+
+func TestCancelEventSeriesTxAllKeepsStarted(t *testing.T) {
+	name, place, _ := util.RandomEventParams()
+	startsAt := time.Now().Add(-8 * 24 * time.Hour).UTC().Truncate(time.Second)
+
+	result, err := testStore.CreateEventSeriesTx(context.Background(), CreateEventSeriesTxParams{
+		Name:     name,
+		Place:    place,
+		StartsAt: startsAt,
+		EndsAt:   startsAt.Add(2 * time.Hour),
+		TimeZone: "UTC",
+		Rrule:    "FREQ=WEEKLY;COUNT=4",
+		Horizon:  startsAt.AddDate(1, 0, 0),
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Events, 4)
+
+	err = testStore.CancelEventSeriesTx(context.Background(), CancelEventSeriesTxParams{
+		EventID: result.Events[3].ID,
+		Scope:   SeriesScopeAll,
+	})
+	require.NoError(t, err)
+
+	// Occurrences that already took place are kept along with their series
+	series, err := testStore.GetEventSeries(context.Background(), result.Series.ID)
+	require.NoError(t, err)
+
+	events, err := testStore.ListEventSeriesOccurrences(context.Background(), ListEventSeriesOccurrencesParams{
+		SeriesID: series.ID,
+		Since:    series.StartsAt,
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, result.Events[0].ID, events[0].ID)
+	require.Equal(t, result.Events[1].ID, events[1].ID)
+}
+
+func TestCancelEventSeriesTxNotInSeries(t *testing.T) {
+	event := createRandomEvent(t, testStore)
+
+	err := testStore.CancelEventSeriesTx(context.Background(), CancelEventSeriesTxParams{
+		EventID: event.ID,
+		Scope:   SeriesScopeAll,
+	})
+	require.ErrorIs(t, err, ErrNotInSeries)
+}
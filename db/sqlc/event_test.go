@@ -13,9 +13,11 @@ import (
 func createRandomEvent(t *testing.T, store Store) Event {
 	name, place, date := util.RandomEventParams()
 	arg := CreateEventParams{
-		Name:  name,
-		Place: place,
-		Date:  date,
+		Name:     name,
+		Place:    place,
+		Date:     date,
+		EndsAt:   date.Add(2 * time.Hour),
+		TimeZone: "Europe/Kyiv",
 	}
 
 	event, err := store.CreateEvent(context.Background(), arg)
@@ -25,6 +27,8 @@ func createRandomEvent(t *testing.T, store Store) Event {
 	require.Equal(t, arg.Name, event.Name)
 	require.Equal(t, arg.Place, event.Place)
 	require.WithinDuration(t, arg.Date, event.Date, time.Second)
+	require.WithinDuration(t, arg.EndsAt, event.EndsAt, time.Second)
+	require.Equal(t, arg.TimeZone, event.TimeZone)
 	require.NotZero(t, event.ID)
 	require.NotZero(t, event.CreatedAt)
 
@@ -57,10 +61,11 @@ func TestUpdateEvent(t *testing.T) {
 	newDate := time.Now().Add(48 * time.Hour)
 
 	arg := UpdateEventParams{
-		ID:    event1.ID,
-		Name:  pgtype.Text{String: newName, Valid: true},
-		Place: pgtype.Text{String: newPlace, Valid: true},
-		Date:  pgtype.Timestamptz{Time: newDate, Valid: true},
+		ID:     event1.ID,
+		Name:   pgtype.Text{String: newName, Valid: true},
+		Place:  pgtype.Text{String: newPlace, Valid: true},
+		Date:   pgtype.Timestamptz{Time: newDate, Valid: true},
+		EndsAt: pgtype.Timestamptz{Time: newDate.Add(time.Hour), Valid: true},
 	}
 
 	event2, err := testStore.UpdateEvent(context.Background(), arg)
@@ -71,7 +76,10 @@ func TestUpdateEvent(t *testing.T) {
 	require.Equal(t, newName, event2.Name)
 	require.Equal(t, newPlace, event2.Place)
 	require.WithinDuration(t, newDate, event2.Date, time.Second)
+	require.WithinDuration(t, newDate.Add(time.Hour), event2.EndsAt, time.Second)
+	require.Equal(t, event1.TimeZone, event2.TimeZone)
 	require.WithinDuration(t, event1.CreatedAt, event2.CreatedAt, time.Second)
+	require.Equal(t, event1.Sequence+1, event2.Sequence)
 }
 
 func TestDeleteEvent(t *testing.T) {
@@ -131,15 +139,16 @@ func TestBookEvent(t *testing.T) {
 	event := createRandomEvent(t, testStore)
 
 	arg := BookEventParams{
-		UserID:  user.ID,
-		EventID: event.ID,
+		UserID:    user.ID,
+		EventID:   event.ID,
+		PartySize: 1,
 	}
 
 	booking, err := testStore.BookEvent(context.Background(), arg)
 	require.NoError(t, err)
 	require.NotEmpty(t, booking)
 
-	require.Equal(t, user.ID, booking.UserID)
+	require.Equal(t, user.ID, booking.UserID.Int64)
 	require.Equal(t, event.ID, booking.EventID)
 	require.NotZero(t, booking.ID)
 	require.NotZero(t, booking.BookedAt)
@@ -150,8 +159,9 @@ func TestBookEventDuplicate(t *testing.T) {
 	event := createRandomEvent(t, testStore)
 
 	arg := BookEventParams{
-		UserID:  user.ID,
-		EventID: event.ID,
+		UserID:    user.ID,
+		EventID:   event.ID,
+		PartySize: 1,
 	}
 
 	// First booking should succeed
@@ -172,8 +182,9 @@ func TestCancelEventBooking(t *testing.T) {
 
 	// First book the event
 	bookArg := BookEventParams{
-		UserID:  user.ID,
-		EventID: event.ID,
+		UserID:    user.ID,
+		EventID:   event.ID,
+		PartySize: 1,
 	}
 
 	booking, err := testStore.BookEvent(context.Background(), bookArg)
@@ -217,8 +228,9 @@ func TestIsEventBooked(t *testing.T) {
 
 	// Book the event
 	bookArg := BookEventParams{
-		UserID:  user.ID,
-		EventID: event.ID,
+		UserID:    user.ID,
+		EventID:   event.ID,
+		PartySize: 1,
 	}
 
 	_, err = testStore.BookEvent(context.Background(), bookArg)
@@ -238,8 +250,9 @@ func TestListUserBookings(t *testing.T) {
 		event := createRandomEvent(t, testStore)
 
 		bookArg := BookEventParams{
-			UserID:  user.ID,
-			EventID: event.ID,
+			UserID:    user.ID,
+			EventID:   event.ID,
+			PartySize: 1,
 		}
 
 		_, err := testStore.BookEvent(context.Background(), bookArg)
@@ -258,7 +271,7 @@ func TestListUserBookings(t *testing.T) {
 
 	// Verify each booking has event details
 	for _, booking := range bookings {
-		require.Equal(t, user.ID, booking.UserID)
+		require.Equal(t, user.ID, booking.UserID.Int64)
 		require.NotEmpty(t, booking.EventName)
 		require.NotEmpty(t, booking.EventPlace)
 		require.NotZero(t, booking.EventDate)
@@ -273,8 +286,9 @@ func TestListEventBookings(t *testing.T) {
 		user := createRandomUser(t, testStore)
 
 		bookArg := BookEventParams{
-			UserID:  user.ID,
-			EventID: event.ID,
+			UserID:    user.ID,
+			EventID:   event.ID,
+			PartySize: 1,
 		}
 
 		_, err := testStore.BookEvent(context.Background(), bookArg)
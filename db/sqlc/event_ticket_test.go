@@ -0,0 +1,210 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+const ticketRefundCutoff = 48 * time.Hour
+
+// createPaidEvent creates an event at date whose tickets are donated to a new active goal
+func createPaidEvent(t *testing.T, price int64, date time.Time) (Event, Goal) {
+	title, description, targetAmount, _ := util.RandomGoalParams()
+	goal, err := testStore.CreateGoal(context.Background(), CreateGoalParams{
+		Title:        title,
+		Description:  description,
+		TargetAmount: targetAmount,
+		IsActive:     true,
+	})
+	require.NoError(t, err)
+
+	name, place, _ := util.RandomEventParams()
+	event, err := testStore.CreateEvent(context.Background(), CreateEventParams{
+		Name:        name,
+		Place:       place,
+		Date:        date,
+		GoalID:      pgtype.Int8{Int64: goal.ID, Valid: true},
+		TicketPrice: price,
+		EndsAt:      date.Add(2 * time.Hour),
+		TimeZone:    "UTC",
+	})
+	require.NoError(t, err)
+	require.Equal(t, price, event.TicketPrice)
+
+	return event, goal
+}
+
+func TestBookPaidEvent(t *testing.T) {
+	user := createRandomUser(t, testStore)
+	event, goal := createPaidEvent(t, 2500, time.Now().Add(30*24*time.Hour))
+
+	booking, err := testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  user.ID,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+	require.True(t, booking.DonationID.Valid)
+
+	donation, err := testStore.GetDonation(context.Background(), booking.DonationID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, goal.ID, donation.GoalID)
+	require.Equal(t, user.ID, donation.UserID.Int64)
+	require.Equal(t, event.TicketPrice, donation.Amount)
+
+	paid, err := testStore.GetUser(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.Balance-event.TicketPrice, paid.Balance)
+
+	funded, err := testStore.GetGoal(context.Background(), goal.ID)
+	require.NoError(t, err)
+	require.Equal(t, event.TicketPrice, funded.CollectedAmount)
+
+	// Booking twice fails as a duplicate and charges nothing
+	_, err = testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  user.ID,
+		EventID: event.ID,
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	user, err = testStore.GetUser(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, paid.Balance, user.Balance)
+}
+
+func TestBookPaidEventInsufficientBalance(t *testing.T) {
+	user := createRandomUser(t, testStore)
+	event, _ := createPaidEvent(t, user.Balance+1, time.Now().Add(30*24*time.Hour))
+
+	_, err := testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  user.ID,
+		EventID: event.ID,
+	})
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+
+	booked, err := testStore.IsEventBooked(context.Background(), IsEventBookedParams{
+		UserID:  user.ID,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+	require.False(t, booked)
+}
+
+func TestCancelPaidEventBookingRefund(t *testing.T) {
+	user := createRandomUser(t, testStore)
+	event, goal := createPaidEvent(t, 2500, time.Now().Add(30*24*time.Hour))
+
+	booking, err := testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  user.ID,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+
+	result, err := testStore.CancelEventBookingTx(context.Background(), CancelEventBookingTxParams{
+		UserID:       user.ID,
+		EventID:      event.ID,
+		RefundCutoff: ticketRefundCutoff,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Refund)
+	require.Equal(t, event.TicketPrice, result.Refund.Amount)
+	require.Equal(t, booking.DonationID.Int64, result.Refund.DonationID)
+	require.Equal(t, goal.ID, result.Refund.GoalID)
+
+	// The donation is kept and reversed by the refund
+	_, err = testStore.GetDonation(context.Background(), booking.DonationID.Int64)
+	require.NoError(t, err)
+
+	recorded, err := testStore.GetTicketRefundByDonation(context.Background(), booking.DonationID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, result.Refund.ID, recorded.ID)
+
+	stats, err := testStore.GetGoalDonationStats(context.Background(), goal.ID)
+	require.NoError(t, err)
+	require.Zero(t, stats.DonationCount)
+
+	// Refunded donations get no receipt
+	_, err = testStore.IssueDonationReceiptTx(context.Background(), booking.DonationID.Int64)
+	require.ErrorIs(t, err, ErrDonationRefunded)
+
+	refunded, err := testStore.GetUser(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.Balance, refunded.Balance)
+
+	goal, err = testStore.GetGoal(context.Background(), goal.ID)
+	require.NoError(t, err)
+	require.Zero(t, goal.CollectedAmount)
+}
+
+func TestCancelPaidEventBookingAfterCutoff(t *testing.T) {
+	user := createRandomUser(t, testStore)
+	event, goal := createPaidEvent(t, 2500, time.Now().Add(ticketRefundCutoff/2))
+
+	booking, err := testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  user.ID,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+
+	result, err := testStore.CancelEventBookingTx(context.Background(), CancelEventBookingTxParams{
+		UserID:       user.ID,
+		EventID:      event.ID,
+		RefundCutoff: ticketRefundCutoff,
+	})
+	require.NoError(t, err)
+	require.Equal(t, booking.ID, result.Booking.ID)
+	require.Nil(t, result.Refund)
+
+	// The ticket stays donated to the goal
+	_, err = testStore.GetDonation(context.Background(), booking.DonationID.Int64)
+	require.NoError(t, err)
+
+	goal, err = testStore.GetGoal(context.Background(), goal.ID)
+	require.NoError(t, err)
+	require.Equal(t, event.TicketPrice, goal.CollectedAmount)
+}
+
+func TestCancelPaidEventBookingAnnualReceipt(t *testing.T) {
+	user := createRandomUser(t, testStore)
+	event, _ := createPaidEvent(t, 2500, time.Now().Add(30*24*time.Hour))
+
+	booking, err := testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  user.ID,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.IssueAnnualReceiptTx(context.Background(), IssueAnnualReceiptTxParams{
+		UserID: user.ID,
+		Year:   int32(time.Now().UTC().Year()),
+	})
+	require.NoError(t, err)
+
+	// The annual receipt counts the ticket, so the booking is cancelled without a refund
+	result, err := testStore.CancelEventBookingTx(context.Background(), CancelEventBookingTxParams{
+		UserID:       user.ID,
+		EventID:      event.ID,
+		RefundCutoff: ticketRefundCutoff,
+	})
+	require.NoError(t, err)
+	require.Equal(t, booking.ID, result.Booking.ID)
+	require.Nil(t, result.Refund)
+
+	_, err = testStore.GetTicketRefundByDonation(context.Background(), booking.DonationID.Int64)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	booked, err := testStore.IsEventBooked(context.Background(), IsEventBookedParams{
+		UserID:  user.ID,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+	require.False(t, booked)
+
+	charged, err := testStore.GetUser(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.Balance-event.TicketPrice, charged.Balance)
+}
@@ -11,6 +11,26 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// secret tokens of personal calendar feed URLs
+type CalendarToken struct {
+	UserID int64 `json:"user_id"`
+	// SHA-256 of the token, which is only shown to the user
+	TokenHash string    `json:"token_hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// per-day UTC totals for the admin dashboard, refreshed by a background job
+type DailyStat struct {
+	Day            pgtype.Date `json:"day"`
+	DonationCount  int64       `json:"donation_count"`
+	DonationAmount int64       `json:"donation_amount"`
+	NewUsers       int64       `json:"new_users"`
+	EventsHeld     int64       `json:"events_held"`
+	Attendees      int64       `json:"attendees"`
+	RefundCount    int64       `json:"refund_count"`
+	RefundAmount   int64       `json:"refund_amount"`
+}
+
 type Donation struct {
 	ID     int64       `json:"id"`
 	UserID pgtype.Int8 `json:"user_id"`
@@ -25,17 +45,111 @@ type Event struct {
 	ID    int64  `json:"id"`
 	Name  string `json:"name"`
 	Place string `json:"place"`
-	// event date and time
+	// start of the event
 	Date      time.Time `json:"date"`
 	CreatedAt time.Time `json:"created_at"`
+	// goal the event raises money for
+	GoalID pgtype.Int8 `json:"goal_id"`
+	// iCalendar revision, bumped by every update
+	Sequence int32 `json:"sequence"`
+	// price of a ticket in cents, donated to the linked goal; 0 for free events
+	TicketPrice int64 `json:"ticket_price"`
+	// series the event is an occurrence of
+	SeriesID pgtype.Int8 `json:"series_id"`
+	// start of the occurrence in its series, which stays the same when only this occurrence is moved
+	RecurrenceID pgtype.Timestamptz `json:"recurrence_id"`
+	// occurrence edited on its own, keeping its changes when the series is edited
+	Detached bool `json:"detached"`
+	// IANA time zone of the venue
+	TimeZone string `json:"time_zone"`
+	// end of the event
+	EndsAt time.Time `json:"ends_at"`
+	// venue the event is held at; place stays the free-text label
+	VenueID pgtype.Int8 `json:"venue_id"`
+	// scheduled, postponed (new date to be announced), rescheduled or cancelled
+	Status string `json:"status"`
+	// why the event was postponed, rescheduled or cancelled
+	StatusReason string `json:"status_reason"`
+	// when the event was last postponed, rescheduled or cancelled
+	StatusChangedAt pgtype.Timestamptz `json:"status_changed_at"`
+	// most people one booking may be for, NULL for no limit
+	MaxPartySize pgtype.Int4 `json:"max_party_size"`
 }
 
 // tracks which users have booked which events
 type EventBooking struct {
-	ID       int64     `json:"id"`
-	UserID   int64     `json:"user_id"`
-	EventID  int64     `json:"event_id"`
-	BookedAt time.Time `json:"booked_at"`
+	ID int64 `json:"id"`
+	// user who booked, NULL for guest bookings
+	UserID   pgtype.Int8 `json:"user_id"`
+	EventID  int64       `json:"event_id"`
+	BookedAt time.Time   `json:"booked_at"`
+	// remind the user before the event
+	RemindersEnabled bool `json:"reminders_enabled"`
+	// when the ticket was scanned at the door
+	CheckedInAt pgtype.Timestamptz `json:"checked_in_at"`
+	// staff member who scanned the ticket
+	CheckedInBy pgtype.Int8 `json:"checked_in_by"`
+	// donation that paid for the ticket of a paid event
+	DonationID pgtype.Int8 `json:"donation_id"`
+	// how many people the booking is for, the booker included
+	PartySize int32 `json:"party_size"`
+	// names of the other people in the party
+	PartyNames []string `json:"party_names"`
+	// name of a guest booking without an account
+	GuestName pgtype.Text `json:"guest_name"`
+	// email a guest booking is confirmed from
+	GuestEmail pgtype.Text `json:"guest_email"`
+	// language of the emails sent to a guest
+	GuestLocale string `json:"guest_locale"`
+	// when a guest confirmed their email, NULL until then; user bookings are confirmed when made
+	ConfirmedAt pgtype.Timestamptz `json:"confirmed_at"`
+}
+
+// deleted events and cancelled bookings, kept so calendar feeds can cancel them
+type EventCancellation struct {
+	ID      int64 `json:"id"`
+	EventID int64 `json:"event_id"`
+	// user whose booking was cancelled, or NULL when the event was deleted
+	UserID pgtype.Int8 `json:"user_id"`
+	Name   string      `json:"name"`
+	Place  string      `json:"place"`
+	Date   time.Time   `json:"date"`
+	// iCalendar revision of the cancellation
+	Sequence    int32     `json:"sequence"`
+	CancelledAt time.Time `json:"cancelled_at"`
+}
+
+// reminders already scheduled, so each is sent once
+type EventReminder struct {
+	ID        int64 `json:"id"`
+	BookingID int64 `json:"booking_id"`
+	// how long before the event the reminder is due
+	OffsetMinutes int32     `json:"offset_minutes"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// recurring events, materialized as one event per occurrence
+type EventSeries struct {
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Place       string      `json:"place"`
+	GoalID      pgtype.Int8 `json:"goal_id"`
+	TicketPrice int64       `json:"ticket_price"`
+	// start of the first occurrence (DTSTART)
+	StartsAt time.Time `json:"starts_at"`
+	// RFC 5545 recurrence rule
+	Rrule string `json:"rrule"`
+	// start times of cancelled occurrences
+	Exdates []time.Time `json:"exdates"`
+	// occurrences starting before this time exist as events
+	MaterializedUntil time.Time `json:"materialized_until"`
+	CreatedAt         time.Time `json:"created_at"`
+	// IANA time zone of the venue, which occurrences keep their local time of day in
+	TimeZone string `json:"time_zone"`
+	// end of the first occurrence (DTEND), giving every occurrence its length
+	EndsAt time.Time `json:"ends_at"`
+	// venue occurrences are held at
+	VenueID pgtype.Int8 `json:"venue_id"`
 }
 
 type Goal struct {
@@ -49,6 +163,68 @@ type Goal struct {
 	CreatedAt       time.Time   `json:"created_at"`
 }
 
+// cash and bank transfer donations imported by admins
+type OfflineDonation struct {
+	DonationID int64 `json:"donation_id"`
+	// reference from the source system, makes re-running an import idempotent
+	ExternalRef string `json:"external_ref"`
+	// cash or bank_transfer
+	Method     string      `json:"method"`
+	DonorName  pgtype.Text `json:"donor_name"`
+	DonorEmail pgtype.Text `json:"donor_email"`
+	ImportedBy int64       `json:"imported_by"`
+	ImportedAt time.Time   `json:"imported_at"`
+	// the donor agreed to have their name shown in the public donation feed
+	DonorNamePublic bool `json:"donor_name_public"`
+}
+
+// subscribers that handled an outbox event, so retries of the event skip them
+type OutboxDelivery struct {
+	EventID     int64     `json:"event_id"`
+	Subscriber  string    `json:"subscriber"`
+	DeliveredAt time.Time `json:"delivered_at"`
+}
+
+// domain events written in the same transaction as the change that caused them
+type OutboxEvent struct {
+	ID        int64     `json:"id"`
+	EventType string    `json:"event_type"`
+	Payload   []byte    `json:"payload"`
+	CreatedAt time.Time `json:"created_at"`
+	// set once the event has been fanned out to subscribers
+	DispatchedAt pgtype.Timestamptz `json:"dispatched_at"`
+	Attempts     int32              `json:"attempts"`
+	// events whose subscribers failed are retried with backoff
+	NextAttemptAt time.Time   `json:"next_attempt_at"`
+	LastError     pgtype.Text `json:"last_error"`
+}
+
+type Receipt struct {
+	ID            int64  `json:"id"`
+	ReceiptNumber string `json:"receipt_number"`
+	UserID        int64  `json:"user_id"`
+	// null for annual summary receipts
+	DonationID pgtype.Int8 `json:"donation_id"`
+	Year       int32       `json:"year"`
+	// donation or annual
+	Kind string `json:"kind"`
+	// in smallest currency unit, e.g., cents
+	Amount   int64     `json:"amount"`
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// last issued receipt number per year, incremented in the issuing transaction so numbers are gapless
+type ReceiptCounter struct {
+	Year       int32 `json:"year"`
+	LastNumber int64 `json:"last_number"`
+}
+
+// donations itemised on an annual receipt, fixed when the receipt is issued
+type ReceiptDonation struct {
+	ReceiptID  int64 `json:"receipt_id"`
+	DonationID int64 `json:"donation_id"`
+}
+
 type RefreshToken struct {
 	ID        uuid.UUID          `json:"id"`
 	UserID    int64              `json:"user_id"`
@@ -58,6 +234,18 @@ type RefreshToken struct {
 	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
 }
 
+// paid tickets refunded on cancellation; the refunded donation is kept and reversed by the refund
+type TicketRefund struct {
+	ID      int64 `json:"id"`
+	UserID  int64 `json:"user_id"`
+	EventID int64 `json:"event_id"`
+	GoalID  int64 `json:"goal_id"`
+	// donation that paid for the ticket
+	DonationID int64     `json:"donation_id"`
+	Amount     int64     `json:"amount"`
+	RefundedAt time.Time `json:"refunded_at"`
+}
+
 type User struct {
 	ID             int64       `json:"id"`
 	Email          string      `json:"email"`
@@ -65,4 +253,81 @@ type User struct {
 	Balance        int64       `json:"balance"`
 	HashedPassword string      `json:"hashed_password"`
 	CreatedAt      time.Time   `json:"created_at"`
+	// donor, staff or admin
+	Role string `json:"role"`
+	// leave the user out of public donor leaderboards
+	HideFromLeaderboards bool `json:"hide_from_leaderboards"`
+	// language emails are sent in
+	Locale string `json:"locale"`
+}
+
+// places events are held at, with coordinates for maps and nearby search
+type Venue struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	AddressLine string `json:"address_line"`
+	City        string `json:"city"`
+	Region      string `json:"region"`
+	PostalCode  string `json:"postal_code"`
+	// ISO 3166-1 alpha-2 country code
+	Country string `json:"country"`
+	// WGS 84 latitude in degrees
+	Latitude float64 `json:"latitude"`
+	// WGS 84 longitude in degrees
+	Longitude float64 `json:"longitude"`
+	// IANA time zone events at the venue default to
+	TimeZone string `json:"time_zone"`
+	// how many people the venue holds, NULL when unknown
+	Capacity           pgtype.Int4 `json:"capacity"`
+	AccessibilityNotes string      `json:"accessibility_notes"`
+	CreatedAt          time.Time   `json:"created_at"`
+}
+
+// roles volunteers sign up for at an event, such as setup or the registration desk
+type VolunteerRole struct {
+	ID          int64  `json:"id"`
+	EventID     int64  `json:"event_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// how many volunteers the role needs
+	Slots     int32     `json:"slots"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// volunteers signed up for a role, with the hours they worked once logged
+type VolunteerShift struct {
+	ID         int64     `json:"id"`
+	RoleID     int64     `json:"role_id"`
+	UserID     int64     `json:"user_id"`
+	SignedUpAt time.Time `json:"signed_up_at"`
+	// time worked, logged by staff after the event; NULL until logged
+	Minutes  pgtype.Int4        `json:"minutes"`
+	LoggedAt pgtype.Timestamptz `json:"logged_at"`
+	// staff member who logged the time worked
+	LoggedBy pgtype.Int8 `json:"logged_by"`
+}
+
+type WebhookDelivery struct {
+	ID         int64 `json:"id"`
+	EndpointID int64 `json:"endpoint_id"`
+	EventID    int64 `json:"event_id"`
+	// pending, succeeded or failed
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	NextAttemptAt  time.Time          `json:"next_attempt_at"`
+	LastStatusCode pgtype.Int4        `json:"last_status_code"`
+	LastError      pgtype.Text        `json:"last_error"`
+	CreatedAt      time.Time          `json:"created_at"`
+	DeliveredAt    pgtype.Timestamptz `json:"delivered_at"`
+}
+
+type WebhookEndpoint struct {
+	ID  int64  `json:"id"`
+	Url string `json:"url"`
+	// HMAC-SHA256 key used to sign deliveries
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"event_types"`
+	IsActive   bool      `json:"is_active"`
+	CreatedBy  int64     `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
 }
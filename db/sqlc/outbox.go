@@ -0,0 +1,199 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Domain event types written to the outbox
+const (
+	EventDonationCreated  = "donation.created"
+	EventGoalFunded       = "goal.funded"
+	EventBookingCreated   = "booking.created"
+	EventBookingCancelled = "booking.cancelled"
+	EventBookingReminder  = "booking.reminder"
+	EventTicketRefunded   = "ticket.refunded"
+	EventStatusChanged    = "event.status_changed"
+	EventGuestBooked      = "guest_booking.requested"
+)
+
+// EventTypes lists every domain event type in a stable order
+var EventTypes = []string{
+	EventDonationCreated,
+	EventGoalFunded,
+	EventBookingCreated,
+	EventBookingCancelled,
+	EventBookingReminder,
+	EventTicketRefunded,
+	EventStatusChanged,
+	EventGuestBooked,
+}
+
+// IsEventType reports whether eventType is a known domain event type
+func IsEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Sources of a donation.created event
+const (
+	DonationSourceOnline  = "online"
+	DonationSourceOffline = "offline"
+)
+
+// DonationCreatedPayload is the payload of a donation.created event.
+// The donor is left out for anonymous donations.
+type DonationCreatedPayload struct {
+	DonationID  int64     `json:"donation_id"`
+	GoalID      int64     `json:"goal_id"`
+	UserID      *int64    `json:"user_id,omitempty"`
+	Amount      int64     `json:"amount"`
+	IsAnonymous bool      `json:"is_anonymous"`
+	Source      string    `json:"source"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GoalFundedPayload is the payload of a goal.funded event
+type GoalFundedPayload struct {
+	GoalID          int64  `json:"goal_id"`
+	Title           string `json:"title"`
+	TargetAmount    int64  `json:"target_amount"`
+	CollectedAmount int64  `json:"collected_amount"`
+}
+
+// BookingPayload is the payload of booking.created and booking.cancelled events.
+// The user is left out for guest bookings.
+type BookingPayload struct {
+	BookingID int64     `json:"booking_id"`
+	EventID   int64     `json:"event_id"`
+	UserID    int64     `json:"user_id,omitempty"`
+	PartySize int32     `json:"party_size"`
+	BookedAt  time.Time `json:"booked_at"`
+}
+
+// GuestBookedPayload is the payload of a guest_booking.requested event, written when a guest
+// books an event and has to confirm their email before the booking counts
+type GuestBookedPayload struct {
+	BookingID int64     `json:"booking_id"`
+	EventID   int64     `json:"event_id"`
+	BookedAt  time.Time `json:"booked_at"`
+}
+
+// BookingReminderPayload is the payload of a booking.reminder event.
+// The user is left out for guest bookings.
+type BookingReminderPayload struct {
+	BookingID     int64     `json:"booking_id"`
+	EventID       int64     `json:"event_id"`
+	UserID        int64     `json:"user_id,omitempty"`
+	EventDate     time.Time `json:"event_date"`
+	OffsetMinutes int32     `json:"offset_minutes"`
+}
+
+// TicketRefundedPayload is the payload of a ticket.refunded event. The donation
+// that paid for the ticket has been deleted and its amount returned to the user.
+type TicketRefundedPayload struct {
+	RefundID   int64     `json:"refund_id"`
+	BookingID  int64     `json:"booking_id"`
+	EventID    int64     `json:"event_id"`
+	UserID     int64     `json:"user_id"`
+	GoalID     int64     `json:"goal_id"`
+	DonationID int64     `json:"donation_id"`
+	Amount     int64     `json:"amount"`
+	RefundedAt time.Time `json:"refunded_at"`
+}
+
+// EventStatusChangedPayload is the payload of an event.status_changed event, written when an
+// event is postponed, rescheduled or cancelled, and when a booked occurrence is removed from its
+// series. PreviousDate is when the event was due to
+// start before the change.
+type EventStatusChangedPayload struct {
+	EventID int64 `json:"event_id"`
+	// Name, Place and TimeZone describe the event to its bookers, even once it has been deleted
+	Name         string    `json:"name"`
+	Place        string    `json:"place"`
+	TimeZone     string    `json:"time_zone"`
+	Status       string    `json:"status"`
+	Reason       string    `json:"reason"`
+	PreviousDate time.Time `json:"previous_date"`
+	Date         time.Time `json:"date"`
+	// BookingsReleased is set when the bookings were cancelled rather than kept
+	BookingsReleased bool          `json:"bookings_released"`
+	Bookers          []EventBooker `json:"bookers"`
+}
+
+// EventBooker is a booking of an event whose status changed. Guests are named by their
+// email, name and locale instead of a user.
+type EventBooker struct {
+	BookingID   int64  `json:"booking_id"`
+	UserID      int64  `json:"user_id,omitempty"`
+	GuestName   string `json:"guest_name,omitempty"`
+	GuestEmail  string `json:"guest_email,omitempty"`
+	GuestLocale string `json:"guest_locale,omitempty"`
+	// RefundedAmount is the ticket price given back for a released paid booking
+	RefundedAmount int64 `json:"refunded_amount,omitempty"`
+}
+
+func writeOutboxEvent(ctx context.Context, q *Queries, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		EventType: eventType,
+		Payload:   data,
+	})
+	return err
+}
+
+func createDonationEvents(ctx context.Context, q *Queries, donation Donation, source string, goal Goal) error {
+	payload := DonationCreatedPayload{
+		DonationID:  donation.ID,
+		GoalID:      donation.GoalID,
+		Amount:      donation.Amount,
+		IsAnonymous: donation.IsAnonymous,
+		Source:      source,
+		CreatedAt:   donation.CreatedAt,
+	}
+	if donation.UserID.Valid && !donation.IsAnonymous {
+		userID := donation.UserID.Int64
+		payload.UserID = &userID
+	}
+
+	err := writeOutboxEvent(ctx, q, EventDonationCreated, payload)
+	if err != nil {
+		return err
+	}
+
+	// goal is read after the donation was added, so it was funded by this
+	// donation only if the amount collected before it was short of the target
+	if !goal.TargetAmount.Valid {
+		return nil
+	}
+	target := goal.TargetAmount.Int64
+	if goal.CollectedAmount < target || goal.CollectedAmount-donation.Amount >= target {
+		return nil
+	}
+
+	return writeOutboxEvent(ctx, q, EventGoalFunded, GoalFundedPayload{
+		GoalID:          goal.ID,
+		Title:           goal.Title,
+		TargetAmount:    target,
+		CollectedAmount: goal.CollectedAmount,
+	})
+}
+
+func newBookingPayload(booking EventBooking) BookingPayload {
+	return BookingPayload{
+		BookingID: booking.ID,
+		EventID:   booking.EventID,
+		UserID:    booking.UserID.Int64,
+		PartySize: booking.PartySize,
+		BookedAt:  booking.BookedAt,
+	}
+}
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// dispatchAll claims every due outbox event, marks it dispatched and returns them in order
+func dispatchAll(t *testing.T, store Store) []OutboxEvent {
+	var events []OutboxEvent
+	for {
+		claimed, err := store.ClaimDueOutboxEvents(context.Background(), ClaimDueOutboxEventsParams{
+			LockedUntil: time.Now().Add(time.Minute),
+			RowLimit:    100,
+		})
+		require.NoError(t, err)
+		if len(claimed) == 0 {
+			slices.SortFunc(events, func(a, b OutboxEvent) int {
+				return cmp.Compare(a.ID, b.ID)
+			})
+			return events
+		}
+
+		for _, event := range claimed {
+			require.NoError(t, store.MarkOutboxEventDispatched(context.Background(), event.ID))
+		}
+		events = append(events, claimed...)
+	}
+}
+
+func TestBookingOutboxEvents(t *testing.T) {
+	user := createRandomUser(t, testStore)
+	event := createRandomEvent(t, testStore)
+
+	booking, err := testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  user.ID,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+
+	cancelArg := CancelEventBookingTxParams{UserID: user.ID, EventID: event.ID}
+	result, err := testStore.CancelEventBookingTx(context.Background(), cancelArg)
+	require.NoError(t, err)
+	require.Equal(t, booking.ID, result.Booking.ID)
+	require.Nil(t, result.Refund)
+	// Cancelling again is a no-op and emits nothing
+	_, err = testStore.CancelEventBookingTx(context.Background(), cancelArg)
+	require.NoError(t, err)
+
+	var types []string
+	for _, outboxEvent := range dispatchAll(t, testStore) {
+		if outboxEvent.EventType != EventBookingCreated && outboxEvent.EventType != EventBookingCancelled {
+			continue
+		}
+		var payload BookingPayload
+		require.NoError(t, json.Unmarshal(outboxEvent.Payload, &payload))
+		if payload.BookingID == booking.ID {
+			types = append(types, outboxEvent.EventType)
+		}
+	}
+	require.Equal(t, []string{EventBookingCreated, EventBookingCancelled}, types)
+}
+
+func TestGoalFundedOutboxEvent(t *testing.T) {
+	goal := createGoalWithStatus(t, testStore, true)
+
+	goal, err := testStore.UpdateGoal(context.Background(), UpdateGoalParams{
+		ID:           goal.ID,
+		TargetAmount: pgtype.Int8{Int64: 1000, Valid: true},
+		IsActive:     true,
+	})
+	require.NoError(t, err)
+
+	// The second donation reaches the target, the third one goes past it
+	for _, amount := range []int64{600, 400, 100} {
+		_, err = testStore.DonateToGoalTx(context.Background(), DonateToGoalTxParams{
+			GoalID:      goal.ID,
+			Amount:      amount,
+			IsAnonymous: true,
+		})
+		require.NoError(t, err)
+	}
+
+	var funded []GoalFundedPayload
+	for _, outboxEvent := range dispatchAll(t, testStore) {
+		if outboxEvent.EventType != EventGoalFunded {
+			continue
+		}
+		var payload GoalFundedPayload
+		require.NoError(t, json.Unmarshal(outboxEvent.Payload, &payload))
+		if payload.GoalID == goal.ID {
+			funded = append(funded, payload)
+		}
+	}
+	require.Len(t, funded, 1)
+	require.Equal(t, int64(1000), funded[0].TargetAmount)
+	require.Equal(t, int64(1000), funded[0].CollectedAmount)
+}
+
+func TestClaimDueOutboxEvents(t *testing.T) {
+	dispatchAll(t, testStore)
+
+	user := createRandomUser(t, testStore)
+	event := createRandomEvent(t, testStore)
+	_, err := testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  user.ID,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+
+	claimed, err := testStore.ClaimDueOutboxEvents(context.Background(), ClaimDueOutboxEventsParams{
+		LockedUntil: time.Now().Add(time.Hour),
+		RowLimit:    100,
+	})
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	failed := claimed[0]
+	require.WithinDuration(t, time.Now().Add(time.Hour), failed.NextAttemptAt, time.Minute)
+
+	// Claimed events aren't handed to other dispatchers
+	again, err := testStore.ClaimDueOutboxEvents(context.Background(), ClaimDueOutboxEventsParams{
+		LockedUntil: time.Now().Add(time.Hour),
+		RowLimit:    100,
+	})
+	require.NoError(t, err)
+	require.Empty(t, again)
+
+	err = testStore.RecordOutboxEventFailure(context.Background(), RecordOutboxEventFailureParams{
+		ID:            failed.ID,
+		NextAttemptAt: time.Now().Add(time.Hour),
+		LastError:     pgtype.Text{String: "subscriber failed", Valid: true},
+	})
+	require.NoError(t, err)
+
+	retried, err := testStore.GetOutboxEvent(context.Background(), failed.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), retried.Attempts)
+	require.Equal(t, "subscriber failed", retried.LastError.String)
+	require.False(t, retried.DispatchedAt.Valid)
+	require.WithinDuration(t, time.Now().Add(time.Hour), retried.NextAttemptAt, time.Minute)
+
+	// The event isn't due again until the retry delay has passed
+	for _, event := range dispatchAll(t, testStore) {
+		require.NotEqual(t, failed.ID, event.ID)
+	}
+}
+
+func TestRecordOutboxDelivery(t *testing.T) {
+	event, err := testStore.CreateOutboxEvent(context.Background(), CreateOutboxEventParams{
+		EventType: EventDonationCreated,
+		Payload:   []byte(`{}`),
+	})
+	require.NoError(t, err)
+
+	// Recording a delivery again is a no-op
+	for i := 0; i < 2; i++ {
+		err = testStore.RecordOutboxDelivery(context.Background(), RecordOutboxDeliveryParams{
+			EventID:    event.ID,
+			Subscriber: "emails",
+		})
+		require.NoError(t, err)
+	}
+
+	delivered, err := testStore.ListOutboxDeliveries(context.Background(), event.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"emails"}, delivered)
+}
@@ -8,45 +8,176 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	AddReceiptDonation(ctx context.Context, arg AddReceiptDonationParams) error
 	BookEvent(ctx context.Context, arg BookEventParams) (EventBooking, error)
 	CancelEventBooking(ctx context.Context, arg CancelEventBookingParams) error
+	// Checks a booking in once. Returns no row when the booking doesn't exist, is for another event
+	// or is already checked in, so concurrent scans of one ticket can't both succeed.
+	CheckInEventBooking(ctx context.Context, arg CheckInEventBookingParams) (EventBooking, error)
+	// Holds the due events back from other dispatchers until locked_until, so they can be published
+	// without keeping a transaction open. Events not marked dispatched by then are due again.
+	ClaimDueOutboxEvents(ctx context.Context, arg ClaimDueOutboxEventsParams) ([]OutboxEvent, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]WebhookDelivery, error)
 	CleanupExpiredRefreshTokens(ctx context.Context) error
+	ConfirmGuestBooking(ctx context.Context, id int64) (EventBooking, error)
+	CountActiveGoals(ctx context.Context) (int64, error)
+	CountVolunteerShifts(ctx context.Context, roleID int64) (int64, error)
 	CreateDonation(ctx context.Context, arg CreateDonationParams) (Donation, error)
+	CreateDonationAt(ctx context.Context, arg CreateDonationAtParams) (Donation, error)
+	// Records the reminders due offset_minutes before their event and returns the ones this call recorded,
+	// so each reminder is sent once however many schedulers run. Bookings made after a reminder was due
+	// don't get it, and neither do bookings of postponed or cancelled events.
+	CreateDueEventReminders(ctx context.Context, offsetMinutes int32) ([]CreateDueEventRemindersRow, error)
 	CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error)
+	CreateEventOccurrence(ctx context.Context, arg CreateEventOccurrenceParams) (int64, error)
+	CreateEventSeries(ctx context.Context, arg CreateEventSeriesParams) (EventSeries, error)
 	CreateGoal(ctx context.Context, arg CreateGoalParams) (Goal, error)
+	CreateGuestBooking(ctx context.Context, arg CreateGuestBookingParams) (EventBooking, error)
+	CreateOfflineDonation(ctx context.Context, arg CreateOfflineDonationParams) (OfflineDonation, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (OutboxEvent, error)
+	CreateReceipt(ctx context.Context, arg CreateReceiptParams) (Receipt, error)
 	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateTicketRefund(ctx context.Context, arg CreateTicketRefundParams) (TicketRefund, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateVenue(ctx context.Context, arg CreateVenueParams) (Venue, error)
+	CreateVolunteerRole(ctx context.Context, arg CreateVolunteerRoleParams) (VolunteerRole, error)
+	CreateVolunteerShift(ctx context.Context, arg CreateVolunteerShiftParams) (VolunteerShift, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error)
+	DeleteCalendarToken(ctx context.Context, userID int64) error
 	DeleteEvent(ctx context.Context, id int64) error
+	DeleteEventBooking(ctx context.Context, id int64) error
+	DeleteEventSeries(ctx context.Context, id int64) error
+	// Deletes the roles of an event and their sign-ups, except roles with logged time
+	DeleteEventVolunteerRoles(ctx context.Context, eventID int64) error
 	DeleteGoal(ctx context.Context, id int64) error
 	DeleteUser(ctx context.Context, id int64) error
+	DeleteVenue(ctx context.Context, id int64) error
+	// Deletes a role and its sign-ups unless time was logged for any of them
+	DeleteVolunteerRole(ctx context.Context, id int64) (int64, error)
+	DeleteVolunteerShift(ctx context.Context, arg DeleteVolunteerShiftParams) error
+	DeleteWebhookEndpoint(ctx context.Context, id int64) error
+	GetAnnualReceipt(ctx context.Context, arg GetAnnualReceiptParams) (Receipt, error)
+	GetAnonymousDonationTotals(ctx context.Context, goalID pgtype.Int8) (GetAnonymousDonationTotalsRow, error)
+	GetCalendarTokenByHash(ctx context.Context, tokenHash string) (CalendarToken, error)
+	GetDashboardTotals(ctx context.Context, arg GetDashboardTotalsParams) (GetDashboardTotalsRow, error)
 	GetDonation(ctx context.Context, id int64) (Donation, error)
+	GetDonationFeedItem(ctx context.Context, id int64) (GetDonationFeedItemRow, error)
+	GetDonationForUpdate(ctx context.Context, id int64) (Donation, error)
 	GetEvent(ctx context.Context, id int64) (Event, error)
+	GetEventAttendance(ctx context.Context, eventID int64) (GetEventAttendanceRow, error)
 	GetEventBooking(ctx context.Context, arg GetEventBookingParams) (EventBooking, error)
+	GetEventBookingByID(ctx context.Context, id int64) (EventBooking, error)
+	// The cancellation recorded when an event was deleted
+	GetEventCancellation(ctx context.Context, eventID int64) (EventCancellation, error)
+	// Locks the event against status changes while it is booked
+	GetEventForShare(ctx context.Context, id int64) (Event, error)
+	// Locks the event, and keeps new bookings waiting, while its status changes
+	GetEventForUpdate(ctx context.Context, id int64) (Event, error)
+	GetEventSeries(ctx context.Context, id int64) (EventSeries, error)
+	GetEventSeriesForUpdate(ctx context.Context, id int64) (EventSeries, error)
 	GetGoal(ctx context.Context, id int64) (Goal, error)
+	GetGoalDonationStats(ctx context.Context, goalID int64) (GetGoalDonationStatsRow, error)
 	GetGoalForUpdate(ctx context.Context, id int64) (Goal, error)
+	GetGoalProgress(ctx context.Context, id int64) (GetGoalProgressRow, error)
+	GetGuestBookingByEmail(ctx context.Context, arg GetGuestBookingByEmailParams) (EventBooking, error)
+	GetOfflineDonationByRef(ctx context.Context, externalRef string) (OfflineDonation, error)
+	GetOutboxEvent(ctx context.Context, id int64) (OutboxEvent, error)
+	GetReceiptByDonation(ctx context.Context, donationID pgtype.Int8) (Receipt, error)
 	GetRefreshToken(ctx context.Context, tokenID uuid.UUID) (RefreshToken, error)
+	GetTicketRefundByDonation(ctx context.Context, donationID int64) (TicketRefund, error)
 	GetUser(ctx context.Context, id int64) (User, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserForUpdate(ctx context.Context, id int64) (User, error)
+	// Total time logged for a user's shifts
+	GetUserVolunteerMinutes(ctx context.Context, userID int64) (int64, error)
+	GetVenue(ctx context.Context, id int64) (Venue, error)
+	GetVolunteerRole(ctx context.Context, id int64) (VolunteerRole, error)
+	// Locks the role, so sign-ups and changes to its slots are counted one at a time
+	GetVolunteerRoleForUpdate(ctx context.Context, id int64) (VolunteerRole, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookEndpoint(ctx context.Context, id int64) (WebhookEndpoint, error)
 	IsEventBooked(ctx context.Context, arg IsEventBookedParams) (bool, error)
+	// Every booking of an event, for releasing them when it is cancelled or rescheduled
+	ListAllEventBookings(ctx context.Context, eventID int64) ([]EventBooking, error)
+	// Events taking place since a time, for the public calendar feed
+	ListCalendarEvents(ctx context.Context, arg ListCalendarEventsParams) ([]Event, error)
+	ListDonationFeed(ctx context.Context, arg ListDonationFeedParams) ([]ListDonationFeedRow, error)
 	ListDonations(ctx context.Context, arg ListDonationsParams) ([]Donation, error)
 	ListDonationsByGoal(ctx context.Context, arg ListDonationsByGoalParams) ([]Donation, error)
 	ListDonationsByUser(ctx context.Context, arg ListDonationsByUserParams) ([]Donation, error)
+	ListDonationsForExport(ctx context.Context, arg ListDonationsForExportParams) ([]ListDonationsForExportRow, error)
+	// Series whose occurrences haven't been materialized up to the horizon yet
+	ListDueEventSeries(ctx context.Context, arg ListDueEventSeriesParams) ([]int64, error)
+	// Bookings and check-ins of every event, latest first
+	ListEventAttendance(ctx context.Context, arg ListEventAttendanceParams) ([]ListEventAttendanceRow, error)
 	ListEventBookings(ctx context.Context, arg ListEventBookingsParams) ([]ListEventBookingsRow, error)
+	// Deleted events that were taking place since a time
+	ListEventCancellations(ctx context.Context, arg ListEventCancellationsParams) ([]EventCancellation, error)
+	// Occurrences of a series from a start in the series onwards
+	ListEventSeriesOccurrences(ctx context.Context, arg ListEventSeriesOccurrencesParams) ([]Event, error)
+	// Volunteer roles of an event with how many of their slots are taken
+	ListEventVolunteerRoles(ctx context.Context, eventID int64) ([]ListEventVolunteerRolesRow, error)
+	// Volunteers signed up for the roles of an event, with their names and the time logged for them
+	ListEventVolunteers(ctx context.Context, eventID int64) ([]ListEventVolunteersRow, error)
 	ListEvents(ctx context.Context, arg ListEventsParams) ([]Event, error)
+	// Events at venues within radius_km of a point, nearest first. The haversine distance is
+	// computed in SQL and the latitude band lets the venues index skip far away venues.
+	ListEventsNear(ctx context.Context, arg ListEventsNearParams) ([]ListEventsNearRow, error)
+	ListGoalDonationSeries(ctx context.Context, arg ListGoalDonationSeriesParams) ([]ListGoalDonationSeriesRow, error)
+	// Registered donors of a goal, including anonymous ones since the email isn't shown to anyone
+	ListGoalDonorContacts(ctx context.Context, goalID int64) ([]ListGoalDonorContactsRow, error)
+	ListGoalTopDonors(ctx context.Context, arg ListGoalTopDonorsParams) ([]ListGoalTopDonorsRow, error)
 	ListGoals(ctx context.Context, arg ListGoalsParams) ([]Goal, error)
+	ListLeaderboard(ctx context.Context, arg ListLeaderboardParams) ([]ListLeaderboardRow, error)
+	// Subscribers that already handled an event
+	ListOutboxDeliveries(ctx context.Context, eventID int64) ([]string, error)
+	// Lists the donations itemised on a receipt when it was issued
+	ListReceiptDonations(ctx context.Context, receiptID int64) ([]Donation, error)
 	ListUpcomingEvents(ctx context.Context, arg ListUpcomingEventsParams) ([]Event, error)
 	ListUserBookings(ctx context.Context, arg ListUserBookingsParams) ([]ListUserBookingsRow, error)
+	ListUserDonationsBetween(ctx context.Context, arg ListUserDonationsBetweenParams) ([]Donation, error)
+	// The latest cancellation of each event a user booked
+	// and no longer has a booking for, taking place since a time
+	ListUserEventCancellations(ctx context.Context, arg ListUserEventCancellationsParams) ([]EventCancellation, error)
+	// A user's volunteer history, with the role and event of every shift, latest event first
+	ListUserVolunteerShifts(ctx context.Context, arg ListUserVolunteerShiftsParams) ([]ListUserVolunteerShiftsRow, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	ListVenues(ctx context.Context, arg ListVenuesParams) ([]Venue, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookEndpoints(ctx context.Context, arg ListWebhookEndpointsParams) ([]WebhookEndpoint, error)
+	ListWebhookEndpointsForEvent(ctx context.Context, eventType string) ([]WebhookEndpoint, error)
+	// Logs the time a volunteer worked at an event, replacing any time logged before
+	LogVolunteerMinutes(ctx context.Context, arg LogVolunteerMinutesParams) (VolunteerShift, error)
+	MarkOutboxEventDispatched(ctx context.Context, id int64) error
+	NextReceiptNumber(ctx context.Context, year int32) (int64, error)
+	RecordOutboxDelivery(ctx context.Context, arg RecordOutboxDeliveryParams) error
+	RecordOutboxEventFailure(ctx context.Context, arg RecordOutboxEventFailureParams) error
+	RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) (WebhookDelivery, error)
+	RefreshDailyStats(ctx context.Context) error
+	ResetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
 	RevokeAllUserRefreshTokens(ctx context.Context, userID int64) error
 	RevokeRefreshToken(ctx context.Context, tokenID uuid.UUID) error
+	SetEventBookingDonation(ctx context.Context, arg SetEventBookingDonationParams) (EventBooking, error)
 	UpdateEvent(ctx context.Context, arg UpdateEventParams) (Event, error)
+	UpdateEventBookingReminders(ctx context.Context, arg UpdateEventBookingRemindersParams) (EventBooking, error)
+	UpdateEventOccurrence(ctx context.Context, arg UpdateEventOccurrenceParams) (Event, error)
+	UpdateEventSeries(ctx context.Context, arg UpdateEventSeriesParams) (EventSeries, error)
+	// Postpones, reschedules or cancels an event. Occurrences of a series are detached,
+	// so later changes to the series leave them as they are.
+	UpdateEventStatus(ctx context.Context, arg UpdateEventStatusParams) (Event, error)
 	UpdateGoal(ctx context.Context, arg UpdateGoalParams) (Goal, error)
 	UpdateGoalCollectedAmount(ctx context.Context, arg UpdateGoalCollectedAmountParams) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateUserBalance(ctx context.Context, arg UpdateUserBalanceParams) (User, error)
+	UpdateVenue(ctx context.Context, arg UpdateVenueParams) (Venue, error)
+	UpdateVolunteerRole(ctx context.Context, arg UpdateVolunteerRoleParams) (VolunteerRole, error)
+	// Replaces the previous calendar token of the user, so old feed URLs stop working
+	UpsertCalendarToken(ctx context.Context, arg UpsertCalendarTokenParams) (CalendarToken, error)
 }
 
 var _ Querier = (*Queries)(nil)
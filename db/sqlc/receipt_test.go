@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueAnnualReceiptTxStoresDonations(t *testing.T) {
+	user := createRandomUser(t, testStore)
+	goal := createGoalWithStatus(t, testStore, true)
+	year := int32(time.Now().UTC().Year())
+
+	donate := func(amount int64) Donation {
+		donation, err := testStore.CreateDonation(context.Background(), CreateDonationParams{
+			GoalID: goal.ID,
+			UserID: pgtype.Int8{Int64: user.ID, Valid: true},
+			Amount: amount,
+		})
+		require.NoError(t, err)
+		return donation
+	}
+
+	first := donate(1500)
+	second := donate(2500)
+
+	issued, err := testStore.IssueAnnualReceiptTx(context.Background(), IssueAnnualReceiptTxParams{
+		UserID: user.ID,
+		Year:   year,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(4000), issued.Receipt.Amount)
+	require.Len(t, issued.Donations, 2)
+
+	// Donations recorded for the year later aren't itemised on the issued receipt
+	donate(1000)
+
+	again, err := testStore.IssueAnnualReceiptTx(context.Background(), IssueAnnualReceiptTxParams{
+		UserID: user.ID,
+		Year:   year,
+	})
+	require.NoError(t, err)
+	require.Equal(t, issued.Receipt, again.Receipt)
+	require.Len(t, again.Donations, 2)
+	require.Equal(t, first.ID, again.Donations[0].ID)
+	require.Equal(t, second.ID, again.Donations[1].ID)
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -11,6 +12,23 @@ import (
 type Store interface {
 	Querier
 	DonateToGoalTx(ctx context.Context, arg DonateToGoalTxParams) (DonateToGoalTxResult, error)
+	IssueDonationReceiptTx(ctx context.Context, donationID int64) (Receipt, error)
+	IssueAnnualReceiptTx(ctx context.Context, arg IssueAnnualReceiptTxParams) (IssueAnnualReceiptTxResult, error)
+	ImportDonationsTx(ctx context.Context, arg ImportDonationsTxParams) (ImportDonationsTxResult, error)
+	BookEventTx(ctx context.Context, arg BookEventParams) (EventBooking, error)
+	CancelEventBookingTx(ctx context.Context, arg CancelEventBookingTxParams) (CancelEventBookingTxResult, error)
+	ScheduleEventRemindersTx(ctx context.Context, offsets []time.Duration) ([]CreateDueEventRemindersRow, error)
+	CreateEventSeriesTx(ctx context.Context, arg CreateEventSeriesTxParams) (EventSeriesTxResult, error)
+	MaterializeEventSeriesTx(ctx context.Context, horizon time.Time) (int64, error)
+	UpdateEventSeriesTx(ctx context.Context, arg UpdateEventSeriesTxParams) (EventSeriesTxResult, error)
+	CancelEventSeriesTx(ctx context.Context, arg CancelEventSeriesTxParams) error
+	ChangeEventStatusTx(ctx context.Context, arg ChangeEventStatusTxParams) (ChangeEventStatusTxResult, error)
+	CreateGuestBookingTx(ctx context.Context, arg CreateGuestBookingParams) (EventBooking, error)
+	ConfirmGuestBookingTx(ctx context.Context, arg GuestBookingTxParams) (EventBooking, error)
+	CancelGuestBookingTx(ctx context.Context, arg GuestBookingTxParams) (EventBooking, error)
+	SignUpVolunteerTx(ctx context.Context, arg SignUpVolunteerTxParams) (VolunteerShift, error)
+	UpdateVolunteerRoleTx(ctx context.Context, arg UpdateVolunteerRoleTxParams) (VolunteerRole, error)
+	DeleteEventTx(ctx context.Context, eventID int64) error
 }
 
 // SQLStore provides all functions to execute SQL queries and transactions
@@ -0,0 +1,222 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// BookEventTx books an event for a user and writes a booking.created event to the outbox
+// within a database transaction. The tickets of a paid event, one for each person in the party,
+// are paid from the user's balance with a donation to the event's goal in the same transaction.
+// A booking without a party size is for the user alone.
+func (store *SQLStore) BookEventTx(ctx context.Context, arg BookEventParams) (EventBooking, error) {
+	var booking EventBooking
+
+	if arg.PartySize == 0 {
+		arg.PartySize = 1
+	}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// The event stays locked until the booking commits, so a cancellation waits for it
+		event, err := q.GetEventForShare(ctx, arg.EventID)
+		if err != nil {
+			return err
+		}
+		if event.Status == EventStatusCancelled {
+			return ErrEventCancelled
+		}
+		if !fitsParty(event, arg.PartySize) {
+			return ErrPartyTooLarge
+		}
+
+		// Book before paying, so booking twice fails as a duplicate rather than a payment error
+		booking, err = q.BookEvent(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if event.TicketPrice > 0 {
+			if !event.GoalID.Valid {
+				return ErrEventHasNoGoal
+			}
+
+			payment, err := donateToGoal(ctx, q, DonateToGoalTxParams{
+				UserID: pgtype.Int8{Int64: arg.UserID, Valid: true},
+				GoalID: event.GoalID.Int64,
+				Amount: event.TicketPrice * int64(arg.PartySize),
+			})
+			if err != nil {
+				return err
+			}
+
+			booking, err = q.SetEventBookingDonation(ctx, SetEventBookingDonationParams{
+				ID:         booking.ID,
+				DonationID: pgtype.Int8{Int64: payment.Donation.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return writeOutboxEvent(ctx, q, EventBookingCreated, newBookingPayload(booking))
+	})
+
+	return booking, err
+}
+
+// fitsParty reports whether a party of size people may book event
+func fitsParty(event Event, size int32) bool {
+	return !event.MaxPartySize.Valid || size <= event.MaxPartySize.Int32
+}
+
+// CancelEventBookingTxParams contains the input parameters of the booking cancellation transaction
+type CancelEventBookingTxParams struct {
+	UserID  int64 `json:"user_id"`
+	EventID int64 `json:"event_id"`
+	// RefundCutoff is how long before the event paid tickets stop being refunded
+	RefundCutoff time.Duration `json:"refund_cutoff"`
+}
+
+// CancelEventBookingTxResult is the result of the booking cancellation transaction
+type CancelEventBookingTxResult struct {
+	Booking EventBooking `json:"booking"`
+	// Refund is nil unless a paid ticket was refunded
+	Refund *TicketRefund `json:"refund"`
+}
+
+// CancelEventBookingTx cancels a user's booking and writes a booking.cancelled event to the outbox
+// within a database transaction. Cancelling a booking that doesn't exist is a no-op and emits no event.
+// A paid ticket cancelled before the refund cutoff is refunded: the amount is returned to the user's balance
+// and taken off the goal, and a ticket.refunded event written. Tickets cancelled later, or counted on a receipt,
+// stay donated to the goal.
+func (store *SQLStore) CancelEventBookingTx(ctx context.Context, arg CancelEventBookingTxParams) (CancelEventBookingTxResult, error) {
+	var result CancelEventBookingTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		booking, err := q.GetEventBooking(ctx, GetEventBookingParams{
+			UserID:  arg.UserID,
+			EventID: arg.EventID,
+		})
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		result.Booking = booking
+
+		err = q.CancelEventBooking(ctx, CancelEventBookingParams{
+			UserID:  arg.UserID,
+			EventID: arg.EventID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = writeOutboxEvent(ctx, q, EventBookingCancelled, newBookingPayload(booking))
+		if err != nil {
+			return err
+		}
+
+		if !booking.DonationID.Valid {
+			return nil
+		}
+
+		event, err := q.GetEvent(ctx, booking.EventID)
+		if err != nil {
+			return err
+		}
+		if time.Now().Add(arg.RefundCutoff).After(event.Date) {
+			return nil
+		}
+
+		result.Refund, err = refundTicket(ctx, q, booking)
+		if errors.Is(err, ErrTicketReceiptIssued) {
+			return nil
+		}
+		return err
+	})
+
+	return result, err
+}
+
+// refundTicket gives back the amount of the donation that paid for a booking's ticket.
+// The donation is kept, and the refund recorded against it reverses it.
+func refundTicket(ctx context.Context, q *Queries, booking EventBooking) (*TicketRefund, error) {
+	donation, err := q.GetDonationForUpdate(ctx, booking.DonationID.Int64)
+	if err != nil {
+		return nil, err
+	}
+
+	// A receipt can't be taken back, so its donation must stay
+	_, err = q.GetReceiptByDonation(ctx, pgtype.Int8{Int64: donation.ID, Valid: true})
+	if err == nil {
+		return nil, ErrTicketReceiptIssued
+	}
+	if !errors.Is(err, ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Nor can the annual receipt counting it. The user is locked like IssueAnnualReceiptTx does,
+	// so that receipt can't be issued before the refund commits.
+	_, err = q.GetUserForUpdate(ctx, booking.UserID.Int64)
+	if err != nil {
+		return nil, err
+	}
+	_, err = q.GetAnnualReceipt(ctx, GetAnnualReceiptParams{
+		UserID: booking.UserID.Int64,
+		Year:   int32(donation.CreatedAt.UTC().Year()),
+	})
+	if err == nil {
+		return nil, ErrTicketReceiptIssued
+	}
+	if !errors.Is(err, ErrRecordNotFound) {
+		return nil, err
+	}
+
+	err = q.UpdateGoalCollectedAmount(ctx, UpdateGoalCollectedAmountParams{
+		ID:              donation.GoalID,
+		CollectedAmount: -donation.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = q.UpdateUserBalance(ctx, UpdateUserBalanceParams{
+		ID:      booking.UserID.Int64,
+		Balance: donation.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refund, err := q.CreateTicketRefund(ctx, CreateTicketRefundParams{
+		UserID:     booking.UserID.Int64,
+		EventID:    booking.EventID,
+		GoalID:     donation.GoalID,
+		DonationID: donation.ID,
+		Amount:     donation.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeOutboxEvent(ctx, q, EventTicketRefunded, TicketRefundedPayload{
+		RefundID:   refund.ID,
+		BookingID:  booking.ID,
+		EventID:    refund.EventID,
+		UserID:     refund.UserID,
+		GoalID:     refund.GoalID,
+		DonationID: refund.DonationID,
+		Amount:     refund.Amount,
+		RefundedAt: refund.RefundedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &refund, nil
+}
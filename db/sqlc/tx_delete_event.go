@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+)
+
+// DeleteEventTx deletes an event and its volunteer roles within a database transaction.
+// Events with logged volunteer hours are kept and reported as ErrVolunteerHoursLogged.
+func (store *SQLStore) DeleteEventTx(ctx context.Context, eventID int64) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		// Lock the event like SignUpVolunteerTx, so sign-ups made meanwhile wait and then fail
+		if _, err := q.GetEventForUpdate(ctx, eventID); err != nil {
+			return err
+		}
+		return deleteEventAndRoles(ctx, q, eventID)
+	})
+}
+
+// deleteEventAndRoles deletes an event with the volunteer roles nobody logged time for.
+// Roles with logged hours keep the event, so volunteers don't lose their history.
+func deleteEventAndRoles(ctx context.Context, q *Queries, eventID int64) error {
+	err := q.DeleteEventVolunteerRoles(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	roles, err := q.ListEventVolunteerRoles(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if len(roles) > 0 {
+		return ErrVolunteerHoursLogged
+	}
+
+	return q.DeleteEvent(ctx, eventID)
+}
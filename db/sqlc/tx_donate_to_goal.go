@@ -25,80 +25,88 @@ type DonateToGoalTxResult struct {
 }
 
 // DonateToGoalTx performs a donation from a user to a goal.
-// It creates the donation, updates the user's balance, and updates the goal's collected amount within a database transaction
+// It creates the donation, updates the user's balance, and updates the goal's collected amount within a database transaction.
+// The donation.created and goal.funded events are written to the outbox in the same transaction
 func (store *SQLStore) DonateToGoalTx(ctx context.Context, arg DonateToGoalTxParams) (DonateToGoalTxResult, error) {
 	var result DonateToGoalTxResult
 
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
+		result, err = donateToGoal(ctx, q, arg)
+		return err
+	})
 
-		// Validate donation amount
-		if arg.Amount <= 0 {
-			return errors.New("donation amount must be positive")
-		}
+	return result, err
+}
 
-		// Get goal and check if it's active
-		goal, err := q.GetGoal(ctx, arg.GoalID)
-		if err != nil {
-			return err
-		}
-		if !goal.IsActive {
-			return errors.New("cannot donate to inactive goal")
-		}
+// donateToGoal performs a donation within the caller's transaction, so it can be part of a larger one
+func donateToGoal(ctx context.Context, q *Queries, arg DonateToGoalTxParams) (DonateToGoalTxResult, error) {
+	var result DonateToGoalTxResult
+	var err error
 
-		// Check user balance if not anonymous
-		if arg.UserID.Valid {
-			user, err := q.GetUser(ctx, arg.UserID.Int64)
-			if err != nil {
-				return err
-			}
-			if user.Balance < arg.Amount {
-				return errors.New("insufficient balance")
-			}
-
-			// Calculate new balance
-			newBalance := user.Balance - arg.Amount
-			
-			// Update user balance
-			result.User, err = q.UpdateUserBalance(ctx, UpdateUserBalanceParams{
-				ID:      arg.UserID.Int64,
-				Balance: newBalance,
-			})
-			if err != nil {
-				return err
-			}
-			result.FromBalance = user.Balance
-			result.ToBalance = result.User.Balance
-		}
+	// Validate donation amount
+	if arg.Amount <= 0 {
+		return result, errors.New("donation amount must be positive")
+	}
 
-		// Create donation
-		result.Donation, err = q.CreateDonation(ctx, CreateDonationParams{
-			UserID:      arg.UserID,
-			GoalID:      arg.GoalID,
-			Amount:      arg.Amount,
-			IsAnonymous: arg.IsAnonymous,
-		})
+	// Get goal and check if it's active
+	goal, err := q.GetGoal(ctx, arg.GoalID)
+	if err != nil {
+		return result, err
+	}
+	if !goal.IsActive {
+		return result, ErrInactiveGoal
+	}
+
+	// Check user balance if not anonymous
+	if arg.UserID.Valid {
+		// Lock the user so concurrent donations can't spend the same balance
+		user, err := q.GetUserForUpdate(ctx, arg.UserID.Int64)
 		if err != nil {
-			return err
+			return result, err
+		}
+		if user.Balance < arg.Amount {
+			return result, ErrInsufficientBalance
 		}
 
-		// Update goal collected amount (SQL query adds to current amount)
-		err = q.UpdateGoalCollectedAmount(ctx, UpdateGoalCollectedAmountParams{
-			ID:              arg.GoalID,
-			CollectedAmount: arg.Amount, // SQL will add this to current collected_amount
+		// Update user balance (SQL query adds to current balance)
+		result.User, err = q.UpdateUserBalance(ctx, UpdateUserBalanceParams{
+			ID:      arg.UserID.Int64,
+			Balance: -arg.Amount,
 		})
 		if err != nil {
-			return err
-		}
-		
-		// Get updated goal
-		result.Goal, err = q.GetGoal(ctx, arg.GoalID)
-		if err != nil {
-			return err
+			return result, err
 		}
+		result.FromBalance = user.Balance
+		result.ToBalance = result.User.Balance
+	}
 
-		return nil
+	// Create donation
+	result.Donation, err = q.CreateDonation(ctx, CreateDonationParams{
+		UserID:      arg.UserID,
+		GoalID:      arg.GoalID,
+		Amount:      arg.Amount,
+		IsAnonymous: arg.IsAnonymous,
 	})
+	if err != nil {
+		return result, err
+	}
 
-	return result, err
+	// Update goal collected amount (SQL query adds to current amount)
+	err = q.UpdateGoalCollectedAmount(ctx, UpdateGoalCollectedAmountParams{
+		ID:              arg.GoalID,
+		CollectedAmount: arg.Amount, // SQL will add this to current collected_amount
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// Get updated goal
+	result.Goal, err = q.GetGoal(ctx, arg.GoalID)
+	if err != nil {
+		return result, err
+	}
+
+	// Record the donation events in the outbox
+	return result, createDonationEvents(ctx, q, result.Donation, DonationSourceOnline, result.Goal)
 }
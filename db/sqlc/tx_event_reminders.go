@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// ScheduleEventRemindersTx records the reminders due at each offset before their event and writes
+// a booking.reminder event to the outbox for each one, within a database transaction.
+// Reminders already recorded are skipped, so it is safe to run on every replica.
+func (store *SQLStore) ScheduleEventRemindersTx(ctx context.Context, offsets []time.Duration) ([]CreateDueEventRemindersRow, error) {
+	var reminders []CreateDueEventRemindersRow
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		reminders = nil
+
+		for _, offset := range offsets {
+			due, err := q.CreateDueEventReminders(ctx, int32(offset/time.Minute))
+			if err != nil {
+				return err
+			}
+
+			for _, reminder := range due {
+				err = writeOutboxEvent(ctx, q, EventBookingReminder, BookingReminderPayload{
+					BookingID:     reminder.BookingID,
+					EventID:       reminder.EventID,
+					UserID:        reminder.UserID,
+					EventDate:     reminder.EventDate,
+					OffsetMinutes: reminder.OffsetMinutes,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			reminders = append(reminders, due...)
+		}
+		return nil
+	})
+
+	return reminders, err
+}
@@ -0,0 +1,605 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kholodihor/charity/recurrence"
+)
+
+// Scopes of a change to an occurrence of an event series
+const (
+	SeriesScopeThis      = "this"
+	SeriesScopeFollowing = "following"
+	SeriesScopeAll       = "all"
+)
+
+// maxSeriesOccurrences caps the occurrences materialized or listed for a series at once
+const maxSeriesOccurrences = 1000
+
+// ErrNotInSeries is returned for series changes to events that aren't occurrences of a series
+var ErrNotInSeries = errors.New("event is not part of a series")
+
+// CreateEventSeriesTxParams contains the input parameters of the series creation transaction
+type CreateEventSeriesTxParams struct {
+	Name        string      `json:"name"`
+	Place       string      `json:"place"`
+	GoalID      pgtype.Int8 `json:"goal_id"`
+	TicketPrice int64       `json:"ticket_price"`
+	StartsAt    time.Time   `json:"starts_at"`
+	// EndsAt is the end of the first occurrence, giving every occurrence its length
+	EndsAt time.Time `json:"ends_at"`
+	// TimeZone is the IANA time zone occurrences keep their local time of day in
+	TimeZone string      `json:"time_zone"`
+	VenueID  pgtype.Int8 `json:"venue_id"`
+	Rrule    string      `json:"rrule"`
+	// Horizon is how far ahead occurrences are materialized
+	Horizon time.Time `json:"horizon"`
+}
+
+// EventSeriesTxResult is the result of the series transactions
+type EventSeriesTxResult struct {
+	Series EventSeries `json:"series"`
+	// Events are the occurrences the change affected
+	Events []Event `json:"events"`
+}
+
+// UpdateEventSeriesTxParams contains the input parameters of the series update transaction.
+// Fields that aren't valid are left as they are.
+type UpdateEventSeriesTxParams struct {
+	EventID int64       `json:"event_id"`
+	Scope   string      `json:"scope"`
+	Name    pgtype.Text `json:"name"`
+	Place   pgtype.Text `json:"place"`
+	// Date moves the event, and with a series scope every later occurrence by as much
+	Date        pgtype.Timestamptz `json:"date"`
+	GoalID      pgtype.Int8        `json:"goal_id"`
+	TicketPrice pgtype.Int8        `json:"ticket_price"`
+	// EndsAt is the new end of the event, and with a series scope sets the length of every later occurrence
+	EndsAt   pgtype.Timestamptz `json:"ends_at"`
+	TimeZone pgtype.Text        `json:"time_zone"`
+	VenueID  pgtype.Int8        `json:"venue_id"`
+	// Rrule replaces the recurrence rule, and can't be changed for a single occurrence
+	Rrule   pgtype.Text `json:"rrule"`
+	Horizon time.Time   `json:"horizon"`
+}
+
+// CancelEventSeriesTxParams contains the input parameters of the series cancellation transaction
+type CancelEventSeriesTxParams struct {
+	EventID int64  `json:"event_id"`
+	Scope   string `json:"scope"`
+}
+
+// CreateEventSeriesTx creates a recurring event series and its occurrences up to the horizon
+// within a database transaction
+func (store *SQLStore) CreateEventSeriesTx(ctx context.Context, arg CreateEventSeriesTxParams) (EventSeriesTxResult, error) {
+	var result EventSeriesTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		rule, err := recurrence.Parse(arg.Rrule)
+		if err != nil {
+			return err
+		}
+
+		series, err := q.CreateEventSeries(ctx, CreateEventSeriesParams{
+			Name:              arg.Name,
+			Place:             arg.Place,
+			GoalID:            arg.GoalID,
+			TicketPrice:       arg.TicketPrice,
+			StartsAt:          arg.StartsAt,
+			Rrule:             rule.String(),
+			Exdates:           []time.Time{},
+			MaterializedUntil: arg.StartsAt,
+			EndsAt:            arg.EndsAt,
+			TimeZone:          arg.TimeZone,
+			VenueID:           arg.VenueID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Series, _, err = materializeEventSeries(ctx, q, series, arg.Horizon)
+		if err != nil {
+			return err
+		}
+
+		result.Events, err = q.ListEventSeriesOccurrences(ctx, ListEventSeriesOccurrencesParams{
+			SeriesID: series.ID,
+			Since:    series.StartsAt,
+			RowLimit: maxSeriesOccurrences,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// MaterializeEventSeriesTx creates the occurrences of every series up to the horizon, each series
+// in its own transaction. It returns how many occurrences were created.
+func (store *SQLStore) MaterializeEventSeriesTx(ctx context.Context, horizon time.Time) (int64, error) {
+	seriesIDs, err := store.ListDueEventSeries(ctx, ListDueEventSeriesParams{
+		Horizon:  horizon,
+		RowLimit: maxSeriesOccurrences,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var created int64
+	for _, seriesID := range seriesIDs {
+		err := store.execTx(ctx, func(q *Queries) error {
+			series, err := q.GetEventSeriesForUpdate(ctx, seriesID)
+			if err != nil {
+				if errors.Is(err, ErrRecordNotFound) {
+					return nil
+				}
+				return err
+			}
+
+			_, rows, err := materializeEventSeries(ctx, q, series, horizon)
+			created += rows
+			return err
+		})
+		if err != nil {
+			return created, err
+		}
+	}
+
+	return created, nil
+}
+
+// materializeEventSeries creates the occurrences of a locked series from where it was
+// materialized until up to the horizon. It returns how many occurrences were created.
+func materializeEventSeries(ctx context.Context, q *Queries, series EventSeries, horizon time.Time) (EventSeries, int64, error) {
+	rule, start, err := seriesRule(series)
+	if err != nil {
+		return series, 0, err
+	}
+
+	until := horizon
+	occurrences := rule.Between(start, series.MaterializedUntil, horizon)
+	if len(occurrences) > maxSeriesOccurrences {
+		// The rest are materialized on the next run
+		until = occurrences[maxSeriesOccurrences]
+		occurrences = occurrences[:maxSeriesOccurrences]
+	}
+
+	var created int64
+	for _, start := range occurrences {
+		if isExdate(series, start) {
+			continue
+		}
+		rows, err := q.CreateEventOccurrence(ctx, newEventOccurrenceParams(series, start))
+		if err != nil {
+			return series, created, err
+		}
+		created += rows
+	}
+
+	if !until.After(series.MaterializedUntil) {
+		return series, created, nil
+	}
+	series.MaterializedUntil = until
+	series, err = q.UpdateEventSeries(ctx, newUpdateEventSeriesParams(series))
+	return series, created, err
+}
+
+// UpdateEventSeriesTx changes an occurrence of a series within a database transaction.
+// SeriesScopeThis changes only the event, which then keeps its changes when the series is changed.
+// SeriesScopeFollowing ends the series before the event and continues it, changed, as a new series.
+// SeriesScopeAll changes the whole series, leaving occurrences that already started as they are.
+// Occurrences the changed rule no longer produces are cancelled with cancelEventOccurrence.
+func (store *SQLStore) UpdateEventSeriesTx(ctx context.Context, arg UpdateEventSeriesTxParams) (EventSeriesTxResult, error) {
+	var result EventSeriesTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		event, series, err := getEventOccurrence(ctx, q, arg.EventID)
+		if err != nil {
+			return err
+		}
+
+		if arg.Scope == SeriesScopeThis {
+			changed := newUpdateEventOccurrenceParams(event)
+			changed.Name = textOr(arg.Name, changed.Name)
+			changed.Place = textOr(arg.Place, changed.Place)
+			if arg.Date.Valid {
+				changed.Date = arg.Date.Time
+			}
+			if arg.GoalID.Valid {
+				changed.GoalID = arg.GoalID
+			}
+			if arg.TicketPrice.Valid {
+				changed.TicketPrice = arg.TicketPrice.Int64
+			}
+			if arg.EndsAt.Valid {
+				changed.EndsAt = arg.EndsAt.Time
+			}
+			changed.TimeZone = textOr(arg.TimeZone, changed.TimeZone)
+			if arg.VenueID.Valid {
+				changed.VenueID = arg.VenueID
+			}
+			changed.Detached = true
+
+			event, err = q.UpdateEventOccurrence(ctx, changed)
+			result = EventSeriesTxResult{Series: series, Events: []Event{event}}
+			return err
+		}
+
+		original, start, err := seriesRule(series)
+		if err != nil {
+			return err
+		}
+		rule := original
+		recurrenceID := event.RecurrenceID.Time
+
+		var shift time.Duration
+		if arg.Date.Valid {
+			shift = arg.Date.Time.Sub(event.Date)
+		}
+
+		duration := series.EndsAt.Sub(series.StartsAt)
+		if arg.EndsAt.Valid {
+			duration = arg.EndsAt.Time.Sub(event.Date.Add(shift))
+		}
+
+		// A change from the first occurrence on is a change to the whole series
+		split := arg.Scope == SeriesScopeFollowing &&
+			len(rule.Between(start, start, recurrenceID)) > 0
+
+		// since is where occurrences start being changed
+		since := time.Now()
+		target := series
+		if split {
+			since = recurrenceID
+			target.StartsAt = recurrenceID
+			rule = rule.From(start, recurrenceID)
+		} else if arg.Scope == SeriesScopeFollowing {
+			since = recurrenceID
+		}
+
+		target.Name = textOr(arg.Name, target.Name)
+		target.Place = textOr(arg.Place, target.Place)
+		if arg.GoalID.Valid {
+			target.GoalID = arg.GoalID
+		}
+		if arg.TicketPrice.Valid {
+			target.TicketPrice = arg.TicketPrice.Int64
+		}
+		if arg.VenueID.Valid {
+			target.VenueID = arg.VenueID
+		}
+		if arg.Rrule.Valid {
+			rule, err = recurrence.Parse(arg.Rrule.String)
+			if err != nil {
+				return err
+			}
+		}
+		target.Rrule = rule.String()
+		target.StartsAt = target.StartsAt.Add(shift)
+		target.EndsAt = target.StartsAt.Add(duration)
+		target.TimeZone = textOr(arg.TimeZone, target.TimeZone)
+		target.Exdates = []time.Time{}
+		for _, exdate := range series.Exdates {
+			if !split || !exdate.Before(since) {
+				target.Exdates = append(target.Exdates, exdate.Add(shift))
+			}
+		}
+		target.MaterializedUntil = later(series.MaterializedUntil.Add(shift), arg.Horizon)
+
+		existing, err := q.ListEventSeriesOccurrences(ctx, ListEventSeriesOccurrencesParams{
+			SeriesID: series.ID,
+			Since:    since,
+			RowLimit: maxSeriesOccurrences,
+		})
+		if err != nil {
+			return err
+		}
+
+		if split {
+			var exdates []time.Time
+			for _, exdate := range series.Exdates {
+				if exdate.Before(since) {
+					exdates = append(exdates, exdate)
+				}
+			}
+			series.Rrule = original.Until(start, recurrenceID).String()
+			series.Exdates = append([]time.Time{}, exdates...)
+			_, err = q.UpdateEventSeries(ctx, newUpdateEventSeriesParams(series))
+			if err != nil {
+				return err
+			}
+
+			target, err = q.CreateEventSeries(ctx, CreateEventSeriesParams{
+				Name:              target.Name,
+				Place:             target.Place,
+				GoalID:            target.GoalID,
+				TicketPrice:       target.TicketPrice,
+				StartsAt:          target.StartsAt,
+				Rrule:             target.Rrule,
+				Exdates:           target.Exdates,
+				MaterializedUntil: target.MaterializedUntil,
+				EndsAt:            target.EndsAt,
+				TimeZone:          target.TimeZone,
+				VenueID:           target.VenueID,
+			})
+		} else {
+			target, err = q.UpdateEventSeries(ctx, newUpdateEventSeriesParams(target))
+		}
+		if err != nil {
+			return err
+		}
+
+		// Occurrences the changed series still has, by their shifted start
+		shiftedSince := since.Add(shift)
+		if !split && arg.Scope == SeriesScopeAll {
+			shiftedSince = since
+		}
+		_, targetStart, err := seriesRule(target)
+		if err != nil {
+			return err
+		}
+		wanted := map[time.Time]bool{}
+		for _, start := range rule.Between(targetStart, shiftedSince, target.MaterializedUntil) {
+			if !isExdate(target, start) {
+				wanted[start.UTC()] = true
+			}
+		}
+
+		// Delete the occurrences that are gone first, so moved ones can take their place
+		var kept []Event
+		for _, occurrence := range existing {
+			if wanted[occurrence.RecurrenceID.Time.Add(shift).UTC()] {
+				kept = append(kept, occurrence)
+				continue
+			}
+			err = cancelEventOccurrence(ctx, q, occurrence)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Move the latest first when moving later, and the earliest first when moving earlier,
+		// so no occurrence takes the start of one that hasn't moved yet
+		if shift > 0 {
+			slices.Reverse(kept)
+		}
+		for _, occurrence := range kept {
+			start := occurrence.RecurrenceID.Time.Add(shift)
+			delete(wanted, start.UTC())
+
+			changed := newUpdateEventOccurrenceParams(occurrence)
+			changed.SeriesID = pgtype.Int8{Int64: target.ID, Valid: true}
+			changed.RecurrenceID = pgtype.Timestamptz{Time: start, Valid: true}
+			if !occurrence.Detached {
+				changed.Name = target.Name
+				changed.Place = target.Place
+				changed.Date = start
+				changed.GoalID = target.GoalID
+				changed.TicketPrice = target.TicketPrice
+				changed.EndsAt = start.Add(duration)
+				changed.TimeZone = target.TimeZone
+				changed.VenueID = target.VenueID
+			}
+			_, err = q.UpdateEventOccurrence(ctx, changed)
+			if err != nil {
+				return err
+			}
+		}
+
+		for start := range wanted {
+			_, err = q.CreateEventOccurrence(ctx, newEventOccurrenceParams(target, start))
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Series = target
+		result.Events, err = q.ListEventSeriesOccurrences(ctx, ListEventSeriesOccurrencesParams{
+			SeriesID: target.ID,
+			Since:    shiftedSince,
+			RowLimit: maxSeriesOccurrences,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// CancelEventSeriesTx deletes an occurrence of a series within a database transaction.
+// SeriesScopeThis deletes only the event, SeriesScopeFollowing ends the series before it
+// and SeriesScopeAll ends the series now, leaving occurrences that already started as they are.
+// A series left without occurrences is deleted. Deleted occurrences are cancelled with cancelEventOccurrence.
+func (store *SQLStore) CancelEventSeriesTx(ctx context.Context, arg CancelEventSeriesTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		event, series, err := getEventOccurrence(ctx, q, arg.EventID)
+		if err != nil {
+			return err
+		}
+
+		if arg.Scope == SeriesScopeThis {
+			// Remember the occurrence is cancelled, so changing the series doesn't bring it back
+			series.Exdates = append(series.Exdates, event.RecurrenceID.Time)
+			_, err = q.UpdateEventSeries(ctx, newUpdateEventSeriesParams(series))
+			if err != nil {
+				return err
+			}
+			return cancelEventOccurrence(ctx, q, event)
+		}
+
+		rule, start, err := seriesRule(series)
+		if err != nil {
+			return err
+		}
+		since := time.Now()
+		if arg.Scope == SeriesScopeFollowing {
+			since = event.RecurrenceID.Time
+		}
+
+		// Cancelled occurrences are deleted, so each batch lists the next ones
+		for {
+			occurrences, err := q.ListEventSeriesOccurrences(ctx, ListEventSeriesOccurrencesParams{
+				SeriesID: series.ID,
+				Since:    since,
+				RowLimit: maxSeriesOccurrences,
+			})
+			if err != nil {
+				return err
+			}
+			for _, occurrence := range occurrences {
+				err = cancelEventOccurrence(ctx, q, occurrence)
+				if err != nil {
+					return err
+				}
+			}
+			if len(occurrences) < maxSeriesOccurrences {
+				break
+			}
+		}
+
+		if len(rule.Between(start, start, since)) == 0 {
+			return q.DeleteEventSeries(ctx, series.ID)
+		}
+
+		series.Rrule = rule.Until(start, since).String()
+		_, err = q.UpdateEventSeries(ctx, newUpdateEventSeriesParams(series))
+		return err
+	})
+}
+
+// cancelEventOccurrence deletes an occurrence removed from its series. Its bookings are released
+// as when the event is cancelled, and an event.status_changed event tells the bookers.
+// Occurrences with logged volunteer hours are kept and reported as ErrVolunteerHoursLogged.
+func cancelEventOccurrence(ctx context.Context, q *Queries, event Event) error {
+	// Lock the event like ChangeEventStatusTx, so bookings made meanwhile wait and then fail
+	event, err := q.GetEventForUpdate(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+
+	bookers, _, _, err := releaseEventBookings(ctx, q, event.ID, true)
+	if err != nil {
+		return err
+	}
+	if len(bookers) > 0 {
+		err = writeOutboxEvent(ctx, q, EventStatusChanged, EventStatusChangedPayload{
+			EventID:          event.ID,
+			Name:             event.Name,
+			Place:            event.Place,
+			TimeZone:         event.TimeZone,
+			Status:           EventStatusCancelled,
+			PreviousDate:     event.Date,
+			Date:             event.Date,
+			BookingsReleased: true,
+			Bookers:          bookers,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return deleteEventAndRoles(ctx, q, event.ID)
+}
+
+// getEventOccurrence returns an event and its series, locked for changes
+func getEventOccurrence(ctx context.Context, q *Queries, eventID int64) (Event, EventSeries, error) {
+	event, err := q.GetEvent(ctx, eventID)
+	if err != nil {
+		return event, EventSeries{}, err
+	}
+	if !event.SeriesID.Valid || !event.RecurrenceID.Valid {
+		return event, EventSeries{}, ErrNotInSeries
+	}
+
+	series, err := q.GetEventSeriesForUpdate(ctx, event.SeriesID.Int64)
+	return event, series, err
+}
+
+// seriesRule returns the rule of a series and its start in the venue's time zone, which the rule
+// is expanded in so occurrences keep their local time of day across daylight saving changes
+func seriesRule(series EventSeries) (recurrence.Rule, time.Time, error) {
+	rule, err := recurrence.Parse(series.Rrule)
+	if err != nil {
+		return rule, series.StartsAt, err
+	}
+
+	location, err := time.LoadLocation(series.TimeZone)
+	if err != nil {
+		return rule, series.StartsAt, err
+	}
+	return rule, series.StartsAt.In(location), nil
+}
+
+func isExdate(series EventSeries, start time.Time) bool {
+	for _, exdate := range series.Exdates {
+		if exdate.Equal(start) {
+			return true
+		}
+	}
+	return false
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func textOr(text pgtype.Text, value string) string {
+	if text.Valid {
+		return text.String
+	}
+	return value
+}
+
+func newEventOccurrenceParams(series EventSeries, start time.Time) CreateEventOccurrenceParams {
+	return CreateEventOccurrenceParams{
+		Name:         series.Name,
+		Place:        series.Place,
+		Date:         start,
+		GoalID:       series.GoalID,
+		TicketPrice:  series.TicketPrice,
+		SeriesID:     series.ID,
+		RecurrenceID: start,
+		EndsAt:       start.Add(series.EndsAt.Sub(series.StartsAt)),
+		TimeZone:     series.TimeZone,
+		VenueID:      series.VenueID,
+	}
+}
+
+func newUpdateEventOccurrenceParams(event Event) UpdateEventOccurrenceParams {
+	return UpdateEventOccurrenceParams{
+		ID:           event.ID,
+		Name:         event.Name,
+		Place:        event.Place,
+		Date:         event.Date,
+		GoalID:       event.GoalID,
+		TicketPrice:  event.TicketPrice,
+		SeriesID:     event.SeriesID,
+		RecurrenceID: event.RecurrenceID,
+		Detached:     event.Detached,
+		EndsAt:       event.EndsAt,
+		TimeZone:     event.TimeZone,
+		VenueID:      event.VenueID,
+	}
+}
+
+func newUpdateEventSeriesParams(series EventSeries) UpdateEventSeriesParams {
+	return UpdateEventSeriesParams{
+		ID:                series.ID,
+		Name:              series.Name,
+		Place:             series.Place,
+		GoalID:            series.GoalID,
+		TicketPrice:       series.TicketPrice,
+		StartsAt:          series.StartsAt,
+		Rrule:             series.Rrule,
+		Exdates:           series.Exdates,
+		MaterializedUntil: series.MaterializedUntil,
+		EndsAt:            series.EndsAt,
+		TimeZone:          series.TimeZone,
+		VenueID:           series.VenueID,
+	}
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of an event
+const (
+	EventStatusScheduled   = "scheduled"
+	EventStatusPostponed   = "postponed"
+	EventStatusRescheduled = "rescheduled"
+	EventStatusCancelled   = "cancelled"
+)
+
+// ChangeEventStatusTxParams contains the input parameters of the event status change transaction
+type ChangeEventStatusTxParams struct {
+	EventID int64 `json:"event_id"`
+	// Status is EventStatusPostponed, EventStatusRescheduled or EventStatusCancelled
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+	// Date and EndsAt are the new times of a rescheduled event, and left invalid otherwise
+	Date   pgtype.Timestamptz `json:"date"`
+	EndsAt pgtype.Timestamptz `json:"ends_at"`
+	// ReleaseBookings cancels the bookings of a postponed or rescheduled event instead of
+	// keeping them. The bookings of a cancelled event are always released.
+	ReleaseBookings bool `json:"release_bookings"`
+}
+
+// ChangeEventStatusTxResult is the result of the event status change transaction
+type ChangeEventStatusTxResult struct {
+	Event Event `json:"event"`
+	// Released are the bookings cancelled by the change
+	Released []EventBooking `json:"released"`
+	// Refunds are the refunded tickets of released paid bookings
+	Refunds []TicketRefund `json:"refunds"`
+}
+
+// ChangeEventStatusTx postpones, reschedules or cancels an event and writes an event.status_changed
+// event naming every booker to the outbox within a database transaction. Released bookings are
+// cancelled and their paid tickets refunded whatever the refund cutoff, except tickets a receipt was
+// issued for, which stay donated to the goal. Cancelled events and events that have started can't be
+// changed, apart from postponed ones, which can be rescheduled or cancelled at any time.
+func (store *SQLStore) ChangeEventStatusTx(ctx context.Context, arg ChangeEventStatusTxParams) (ChangeEventStatusTxResult, error) {
+	var result ChangeEventStatusTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		event, err := q.GetEventForUpdate(ctx, arg.EventID)
+		if err != nil {
+			return err
+		}
+		if event.Status == EventStatusCancelled {
+			return ErrEventCancelled
+		}
+		if event.Status != EventStatusPostponed && !event.Date.After(time.Now()) {
+			return ErrEventStarted
+		}
+
+		result.Event, err = q.UpdateEventStatus(ctx, UpdateEventStatusParams{
+			Status:       arg.Status,
+			StatusReason: arg.Reason,
+			Date:         arg.Date,
+			EndsAt:       arg.EndsAt,
+			ID:           event.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		release := arg.ReleaseBookings || arg.Status == EventStatusCancelled
+		payload := EventStatusChangedPayload{
+			EventID:          event.ID,
+			Name:             result.Event.Name,
+			Place:            result.Event.Place,
+			TimeZone:         result.Event.TimeZone,
+			Status:           result.Event.Status,
+			Reason:           result.Event.StatusReason,
+			PreviousDate:     event.Date,
+			Date:             result.Event.Date,
+			BookingsReleased: release,
+		}
+		payload.Bookers, result.Released, result.Refunds, err = releaseEventBookings(ctx, q, event.ID, release)
+		if err != nil {
+			return err
+		}
+
+		return writeOutboxEvent(ctx, q, EventStatusChanged, payload)
+	})
+
+	return result, err
+}
+
+// releaseEventBookings returns the bookers of an event to tell about a change to it. With release,
+// their bookings are cancelled and paid tickets refunded whatever the refund cutoff, except tickets
+// a receipt was issued for, which stay donated to the goal.
+func releaseEventBookings(ctx context.Context, q *Queries, eventID int64, release bool) ([]EventBooker, []EventBooking, []TicketRefund, error) {
+	bookings, err := q.ListAllEventBookings(ctx, eventID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	bookers := []EventBooker{}
+	var released []EventBooking
+	var refunds []TicketRefund
+	for _, booking := range bookings {
+		// Guests who never confirmed their email aren't told, and lose the booking along with the rest
+		if !booking.ConfirmedAt.Valid {
+			if release {
+				err = q.DeleteEventBooking(ctx, booking.ID)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+			}
+			continue
+		}
+
+		booker := EventBooker{BookingID: booking.ID, UserID: booking.UserID.Int64}
+		if !booking.UserID.Valid {
+			booker.GuestName = booking.GuestName.String
+			booker.GuestEmail = booking.GuestEmail.String
+			booker.GuestLocale = booking.GuestLocale
+		}
+		bookers = append(bookers, booker)
+		if !release {
+			continue
+		}
+
+		err = q.DeleteEventBooking(ctx, booking.ID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		released = append(released, booking)
+
+		if !booking.DonationID.Valid {
+			continue
+		}
+		refund, err := refundTicket(ctx, q, booking)
+		if errors.Is(err, ErrTicketReceiptIssued) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		refunds = append(refunds, *refund)
+		bookers[len(bookers)-1].RefundedAmount = refund.Amount
+	}
+
+	return bookers, released, refunds, nil
+}
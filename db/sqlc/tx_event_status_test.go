@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelEventReleasesBookings(t *testing.T) {
+	paying := createRandomUser(t, testStore)
+	event, goal := createPaidEvent(t, 2500, time.Now().Add(30*24*time.Hour))
+
+	booking, err := testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  paying.ID,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+
+	result, err := testStore.ChangeEventStatusTx(context.Background(), ChangeEventStatusTxParams{
+		EventID: event.ID,
+		Status:  EventStatusCancelled,
+		Reason:  "Venue flooded",
+	})
+	require.NoError(t, err)
+	require.Equal(t, EventStatusCancelled, result.Event.Status)
+	require.Equal(t, "Venue flooded", result.Event.StatusReason)
+	require.True(t, result.Event.StatusChangedAt.Valid)
+	require.Equal(t, event.Sequence+1, result.Event.Sequence)
+	require.Len(t, result.Released, 1)
+	require.Equal(t, booking.ID, result.Released[0].ID)
+	require.Len(t, result.Refunds, 1)
+	require.Equal(t, event.TicketPrice, result.Refunds[0].Amount)
+
+	bookings, err := testStore.ListAllEventBookings(context.Background(), event.ID)
+	require.NoError(t, err)
+	require.Empty(t, bookings)
+
+	refunded, err := testStore.GetUser(context.Background(), paying.ID)
+	require.NoError(t, err)
+	require.Equal(t, paying.Balance, refunded.Balance)
+
+	goal, err = testStore.GetGoal(context.Background(), goal.ID)
+	require.NoError(t, err)
+	require.Zero(t, goal.CollectedAmount)
+
+	upcoming, err := testStore.ListUpcomingEvents(context.Background(), ListUpcomingEventsParams{
+		Limit:  1000,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	for _, listed := range upcoming {
+		require.NotEqual(t, event.ID, listed.ID)
+	}
+
+	// Cancelled events can't be booked or changed again
+	_, err = testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  paying.ID,
+		EventID: event.ID,
+	})
+	require.ErrorIs(t, err, ErrEventCancelled)
+
+	_, err = testStore.ChangeEventStatusTx(context.Background(), ChangeEventStatusTxParams{
+		EventID: event.ID,
+		Status:  EventStatusPostponed,
+	})
+	require.ErrorIs(t, err, ErrEventCancelled)
+}
+
+func TestRescheduleEventKeepsBookings(t *testing.T) {
+	user := createRandomUser(t, testStore)
+	event := createRandomEvent(t, testStore)
+
+	_, err := testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:  user.ID,
+		EventID: event.ID,
+	})
+	require.NoError(t, err)
+
+	postponed, err := testStore.ChangeEventStatusTx(context.Background(), ChangeEventStatusTxParams{
+		EventID: event.ID,
+		Status:  EventStatusPostponed,
+		Reason:  "Storm warning",
+	})
+	require.NoError(t, err)
+	require.Equal(t, EventStatusPostponed, postponed.Event.Status)
+	require.WithinDuration(t, event.Date, postponed.Event.Date, time.Second)
+	require.Empty(t, postponed.Released)
+
+	date := event.Date.Add(7 * 24 * time.Hour)
+	rescheduled, err := testStore.ChangeEventStatusTx(context.Background(), ChangeEventStatusTxParams{
+		EventID: event.ID,
+		Status:  EventStatusRescheduled,
+		Reason:  "Storm warning",
+		Date:    pgtype.Timestamptz{Time: date, Valid: true},
+		EndsAt:  pgtype.Timestamptz{Time: date.Add(2 * time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, EventStatusRescheduled, rescheduled.Event.Status)
+	require.WithinDuration(t, date, rescheduled.Event.Date, time.Second)
+	require.Empty(t, rescheduled.Released)
+
+	bookings, err := testStore.ListAllEventBookings(context.Background(), event.ID)
+	require.NoError(t, err)
+	require.Len(t, bookings, 1)
+	require.Equal(t, user.ID, bookings[0].UserID.Int64)
+}
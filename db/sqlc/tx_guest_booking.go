@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// CreateGuestBookingTx books a free event for a guest without an account and writes a
+// guest_booking.requested event to the outbox within a database transaction, so the guest is
+// emailed a link to confirm the booking. Until it is confirmed the booking isn't listed,
+// counted or told about changes to the event. Booking again before confirming keeps the
+// booking as it was and sends the link again, so nobody else can change it by knowing the
+// email. A guest who has already confirmed a booking of the event gets ErrGuestAlreadyBooked.
+func (store *SQLStore) CreateGuestBookingTx(ctx context.Context, arg CreateGuestBookingParams) (EventBooking, error) {
+	var booking EventBooking
+
+	if arg.PartySize == 0 {
+		arg.PartySize = 1
+	}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		event, err := q.GetEventForShare(ctx, arg.EventID)
+		if err != nil {
+			return err
+		}
+		if err := checkGuestBookable(event); err != nil {
+			return err
+		}
+		if event.TicketPrice > 0 {
+			return ErrGuestBookingPaid
+		}
+		if !fitsParty(event, arg.PartySize) {
+			return ErrPartyTooLarge
+		}
+
+		booking, err = q.CreateGuestBooking(ctx, arg)
+		if errors.Is(err, ErrRecordNotFound) {
+			// The email has booked already: the booking is left as it is, and only the link
+			// to confirm it is sent again
+			booking, err = q.GetGuestBookingByEmail(ctx, GetGuestBookingByEmailParams{
+				EventID:    arg.EventID,
+				GuestEmail: arg.GuestEmail,
+			})
+			if err != nil {
+				return err
+			}
+			if booking.ConfirmedAt.Valid {
+				return ErrGuestAlreadyBooked
+			}
+		} else if err != nil {
+			return err
+		}
+
+		return writeOutboxEvent(ctx, q, EventGuestBooked, GuestBookedPayload{
+			BookingID: booking.ID,
+			EventID:   booking.EventID,
+			BookedAt:  booking.BookedAt,
+		})
+	})
+
+	return booking, err
+}
+
+// GuestBookingTxParams identifies the guest booking a confirmation code was issued for
+type GuestBookingTxParams struct {
+	BookingID int64 `json:"booking_id"`
+	EventID   int64 `json:"event_id"`
+}
+
+// ConfirmGuestBookingTx confirms the email of a guest booking and writes a booking.created event
+// to the outbox within a database transaction. Confirming a booking again returns it unchanged.
+func (store *SQLStore) ConfirmGuestBookingTx(ctx context.Context, arg GuestBookingTxParams) (EventBooking, error) {
+	var booking EventBooking
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		event, err := q.GetEventForShare(ctx, arg.EventID)
+		if err != nil {
+			return err
+		}
+
+		booking, err = getGuestBooking(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+		if booking.ConfirmedAt.Valid {
+			return nil
+		}
+		if err := checkGuestBookable(event); err != nil {
+			return err
+		}
+
+		booking, err = q.ConfirmGuestBooking(ctx, booking.ID)
+		if err != nil {
+			return err
+		}
+
+		return writeOutboxEvent(ctx, q, EventBookingCreated, newBookingPayload(booking))
+	})
+
+	return booking, err
+}
+
+// CancelGuestBookingTx cancels a guest booking within a database transaction, writing a
+// booking.cancelled event to the outbox if it was confirmed. Cancelling a booking that
+// doesn't exist is a no-op.
+func (store *SQLStore) CancelGuestBookingTx(ctx context.Context, arg GuestBookingTxParams) (EventBooking, error) {
+	var booking EventBooking
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		booking, err = getGuestBooking(ctx, q, arg)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		err = q.DeleteEventBooking(ctx, booking.ID)
+		if err != nil {
+			return err
+		}
+
+		if !booking.ConfirmedAt.Valid {
+			return nil
+		}
+		return writeOutboxEvent(ctx, q, EventBookingCancelled, newBookingPayload(booking))
+	})
+
+	return booking, err
+}
+
+// getGuestBooking returns the guest booking of arg, and ErrRecordNotFound if the booking is
+// a user's or of another event
+func getGuestBooking(ctx context.Context, q *Queries, arg GuestBookingTxParams) (EventBooking, error) {
+	booking, err := q.GetEventBookingByID(ctx, arg.BookingID)
+	if err != nil {
+		return EventBooking{}, err
+	}
+	if booking.UserID.Valid || booking.EventID != arg.EventID {
+		return EventBooking{}, ErrRecordNotFound
+	}
+	return booking, nil
+}
+
+// checkGuestBookable returns an error unless guests can still book event
+func checkGuestBookable(event Event) error {
+	if event.Status == EventStatusCancelled {
+		return ErrEventCancelled
+	}
+	if !event.Date.After(time.Now()) {
+		return ErrEventStarted
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuestBooking(t *testing.T) {
+	event := createRandomEvent(t, testStore)
+	event, err := testStore.UpdateEvent(context.Background(), UpdateEventParams{
+		ID:           event.ID,
+		MaxPartySize: pgtype.Int4{Int32: 4, Valid: true},
+	})
+	require.NoError(t, err)
+
+	arg := CreateGuestBookingParams{
+		EventID:     event.ID,
+		PartySize:   2,
+		GuestName:   util.RandomName(),
+		GuestEmail:  util.RandomEmail(),
+		GuestLocale: "uk",
+	}
+	booking, err := testStore.CreateGuestBookingTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, booking.UserID.Valid)
+	require.False(t, booking.ConfirmedAt.Valid)
+	require.Equal(t, int32(2), booking.PartySize)
+	require.Empty(t, booking.PartyNames)
+
+	// Unconfirmed bookings aren't listed, and booking again leaves them as they are
+	listed, err := testStore.ListEventBookings(context.Background(), ListEventBookingsParams{
+		EventID: event.ID,
+		Limit:   10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, listed)
+
+	rebook := arg
+	rebook.PartySize = 3
+	rebook.PartyNames = []string{"Olena", "Taras"}
+	rebook.GuestName = util.RandomName()
+	rebook.GuestEmail = strings.ToUpper(arg.GuestEmail)
+	rebook.GuestLocale = "en"
+	rebooked, err := testStore.CreateGuestBookingTx(context.Background(), rebook)
+	require.NoError(t, err)
+	require.Equal(t, booking.ID, rebooked.ID)
+	require.Equal(t, booking.PartySize, rebooked.PartySize)
+	require.Empty(t, rebooked.PartyNames)
+	require.Equal(t, booking.GuestName, rebooked.GuestName)
+	require.Equal(t, booking.GuestLocale, rebooked.GuestLocale)
+
+	// Each booking sends the link to confirm it
+	links := 0
+	for _, outboxEvent := range dispatchAll(t, testStore) {
+		if outboxEvent.EventType != EventGuestBooked {
+			continue
+		}
+		var payload GuestBookedPayload
+		require.NoError(t, json.Unmarshal(outboxEvent.Payload, &payload))
+		if payload.BookingID == booking.ID {
+			links++
+		}
+	}
+	require.Equal(t, 2, links)
+
+	confirm := GuestBookingTxParams{BookingID: booking.ID, EventID: event.ID}
+	confirmed, err := testStore.ConfirmGuestBookingTx(context.Background(), confirm)
+	require.NoError(t, err)
+	require.True(t, confirmed.ConfirmedAt.Valid)
+
+	again, err := testStore.ConfirmGuestBookingTx(context.Background(), confirm)
+	require.NoError(t, err)
+	require.Equal(t, confirmed.ConfirmedAt, again.ConfirmedAt)
+
+	listed, err = testStore.ListEventBookings(context.Background(), ListEventBookingsParams{
+		EventID: event.ID,
+		Limit:   10,
+	})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, arg.GuestName, listed[0].UserName.String)
+	require.Equal(t, int32(2), listed[0].PartySize)
+
+	_, err = testStore.CreateGuestBookingTx(context.Background(), rebook)
+	require.ErrorIs(t, err, ErrGuestAlreadyBooked)
+
+	_, err = testStore.ConfirmGuestBookingTx(context.Background(), GuestBookingTxParams{
+		BookingID: booking.ID,
+		EventID:   event.ID + 1,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	cancelled, err := testStore.CancelGuestBookingTx(context.Background(), confirm)
+	require.NoError(t, err)
+	require.Equal(t, booking.ID, cancelled.ID)
+
+	_, err = testStore.GetEventBookingByID(context.Background(), booking.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestGuestBookingRejected(t *testing.T) {
+	event := createRandomEvent(t, testStore)
+	event, err := testStore.UpdateEvent(context.Background(), UpdateEventParams{
+		ID:           event.ID,
+		MaxPartySize: pgtype.Int4{Int32: 2, Valid: true},
+	})
+	require.NoError(t, err)
+
+	arg := CreateGuestBookingParams{
+		EventID:     event.ID,
+		PartySize:   3,
+		GuestName:   util.RandomName(),
+		GuestEmail:  util.RandomEmail(),
+		GuestLocale: "en",
+	}
+	_, err = testStore.CreateGuestBookingTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrPartyTooLarge)
+
+	paid, _ := createPaidEvent(t, 1000, time.Now().Add(24*time.Hour))
+	arg.EventID = paid.ID
+	arg.PartySize = 1
+	_, err = testStore.CreateGuestBookingTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrGuestBookingPaid)
+}
+
+func TestBookEventParty(t *testing.T) {
+	user := createRandomUser(t, testStore)
+	event, goal := createPaidEvent(t, 1500, time.Now().Add(24*time.Hour))
+
+	booking, err := testStore.BookEventTx(context.Background(), BookEventParams{
+		UserID:     user.ID,
+		EventID:    event.ID,
+		PartySize:  3,
+		PartyNames: []string{"Iryna", "Mykola"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), booking.PartySize)
+	require.Equal(t, []string{"Iryna", "Mykola"}, booking.PartyNames)
+
+	goal, err = testStore.GetGoal(context.Background(), goal.ID)
+	require.NoError(t, err)
+	require.Equal(t, 3*event.TicketPrice, goal.CollectedAmount)
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Payment methods of offline donations
+const (
+	OfflineMethodCash         = "cash"
+	OfflineMethodBankTransfer = "bank_transfer"
+)
+
+// ErrGoalNotFound is returned for import rows referencing a goal that doesn't exist
+var ErrGoalNotFound = errors.New("goal not found")
+
+// ImportDonationRow is a single offline donation to import
+type ImportDonationRow struct {
+	Line        int         `json:"line"`
+	ExternalRef string      `json:"external_ref"`
+	GoalID      int64       `json:"goal_id"`
+	Amount      int64       `json:"amount"`
+	DonatedAt   time.Time   `json:"donated_at"`
+	Method      string      `json:"method"`
+	DonorName   pgtype.Text `json:"donor_name"`
+	DonorEmail  pgtype.Text `json:"donor_email"`
+	IsAnonymous bool        `json:"is_anonymous"`
+	// DonorNamePublic is set if the donor agreed to be named in the public donation feed
+	DonorNamePublic bool `json:"donor_name_public"`
+}
+
+// ImportDonationsTxParams contains the input parameters of the import transaction
+type ImportDonationsTxParams struct {
+	ImportedBy int64               `json:"imported_by"`
+	Rows       []ImportDonationRow `json:"rows"`
+}
+
+// ImportDonationsTxResult is the result of the import transaction
+type ImportDonationsTxResult struct {
+	Donations []Donation `json:"donations"`
+	// Skipped holds the external references that were already imported
+	Skipped []string `json:"skipped"`
+}
+
+// ImportRowError reports which row made an import transaction fail
+type ImportRowError struct {
+	Line        int
+	ExternalRef string
+	Err         error
+}
+
+func (e *ImportRowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ImportRowError) Unwrap() error {
+	return e.Err
+}
+
+// ImportDonationsTx imports offline donations within a single database transaction.
+// Like DonateToGoalTx it only accepts active goals and adds each amount to the goal's collected amount,
+// but no user balance is charged. Rows whose external reference was already imported are skipped,
+// so a failed import can be resumed by running it again. If any row fails, nothing is imported and
+// the error is an *ImportRowError.
+func (store *SQLStore) ImportDonationsTx(ctx context.Context, arg ImportDonationsTxParams) (ImportDonationsTxResult, error) {
+	var result ImportDonationsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = ImportDonationsTxResult{}
+
+		for _, row := range arg.Rows {
+			donation, skipped, err := importDonation(ctx, q, arg.ImportedBy, row)
+			if err != nil {
+				return &ImportRowError{Line: row.Line, ExternalRef: row.ExternalRef, Err: err}
+			}
+
+			if skipped {
+				result.Skipped = append(result.Skipped, row.ExternalRef)
+				continue
+			}
+			result.Donations = append(result.Donations, donation)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+func importDonation(ctx context.Context, q *Queries, importedBy int64, row ImportDonationRow) (Donation, bool, error) {
+	if row.Amount <= 0 {
+		return Donation{}, false, errors.New("donation amount must be positive")
+	}
+
+	_, err := q.GetOfflineDonationByRef(ctx, row.ExternalRef)
+	if err == nil {
+		return Donation{}, true, nil
+	}
+	if !errors.Is(err, ErrRecordNotFound) {
+		return Donation{}, false, err
+	}
+
+	goal, err := q.GetGoal(ctx, row.GoalID)
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return Donation{}, false, ErrGoalNotFound
+		}
+		return Donation{}, false, err
+	}
+	if !goal.IsActive {
+		return Donation{}, false, ErrInactiveGoal
+	}
+
+	donation, err := q.CreateDonationAt(ctx, CreateDonationAtParams{
+		GoalID:      row.GoalID,
+		Amount:      row.Amount,
+		IsAnonymous: row.IsAnonymous,
+		CreatedAt:   row.DonatedAt,
+	})
+	if err != nil {
+		return Donation{}, false, err
+	}
+
+	_, err = q.CreateOfflineDonation(ctx, CreateOfflineDonationParams{
+		DonationID:      donation.ID,
+		ExternalRef:     row.ExternalRef,
+		Method:          row.Method,
+		DonorName:       row.DonorName,
+		DonorEmail:      row.DonorEmail,
+		DonorNamePublic: row.DonorNamePublic,
+		ImportedBy:      importedBy,
+	})
+	if err != nil {
+		return Donation{}, false, err
+	}
+
+	err = q.UpdateGoalCollectedAmount(ctx, UpdateGoalCollectedAmountParams{
+		ID:              row.GoalID,
+		CollectedAmount: row.Amount,
+	})
+	if err != nil {
+		return Donation{}, false, err
+	}
+
+	goal, err = q.GetGoal(ctx, row.GoalID)
+	if err != nil {
+		return Donation{}, false, err
+	}
+
+	err = createDonationEvents(ctx, q, donation, DonationSourceOffline, goal)
+	if err != nil {
+		return Donation{}, false, err
+	}
+
+	return donation, false, nil
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createGoalWithStatus(t *testing.T, store Store, isActive bool) Goal {
+	goal := createRandomGoal(t, store)
+
+	goal, err := store.UpdateGoal(context.Background(), UpdateGoalParams{
+		ID:           goal.ID,
+		TargetAmount: goal.TargetAmount,
+		IsActive:     isActive,
+	})
+	require.NoError(t, err)
+
+	return goal
+}
+
+func randomImportRow(line int, goalID int64) ImportDonationRow {
+	return ImportDonationRow{
+		Line:        line,
+		ExternalRef: util.RandomString(12),
+		GoalID:      goalID,
+		Amount:      util.RandomMoney(),
+		DonatedAt:   time.Now().Add(-24 * time.Hour).Truncate(time.Second),
+		Method:      OfflineMethodCash,
+	}
+}
+
+func TestImportDonationsTx(t *testing.T) {
+	admin := createRandomUser(t, testStore)
+	goal := createGoalWithStatus(t, testStore, true)
+
+	rows := []ImportDonationRow{
+		randomImportRow(2, goal.ID),
+		randomImportRow(3, goal.ID),
+	}
+
+	result, err := testStore.ImportDonationsTx(context.Background(), ImportDonationsTxParams{
+		ImportedBy: admin.ID,
+		Rows:       rows,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Donations, 2)
+	require.Empty(t, result.Skipped)
+
+	for i, donation := range result.Donations {
+		require.Equal(t, rows[i].Amount, donation.Amount)
+		require.Equal(t, goal.ID, donation.GoalID)
+		require.False(t, donation.UserID.Valid)
+		require.WithinDuration(t, rows[i].DonatedAt, donation.CreatedAt, time.Second)
+
+		offline, err := testStore.GetOfflineDonationByRef(context.Background(), rows[i].ExternalRef)
+		require.NoError(t, err)
+		require.Equal(t, donation.ID, offline.DonationID)
+		require.Equal(t, admin.ID, offline.ImportedBy)
+	}
+
+	updatedGoal, err := testStore.GetGoal(context.Background(), goal.ID)
+	require.NoError(t, err)
+	require.Equal(t, goal.CollectedAmount+rows[0].Amount+rows[1].Amount, updatedGoal.CollectedAmount)
+
+	// Running the same import again skips the imported rows
+	result, err = testStore.ImportDonationsTx(context.Background(), ImportDonationsTxParams{
+		ImportedBy: admin.ID,
+		Rows:       rows,
+	})
+	require.NoError(t, err)
+	require.Empty(t, result.Donations)
+	require.Equal(t, []string{rows[0].ExternalRef, rows[1].ExternalRef}, result.Skipped)
+
+	sameGoal, err := testStore.GetGoal(context.Background(), goal.ID)
+	require.NoError(t, err)
+	require.Equal(t, updatedGoal.CollectedAmount, sameGoal.CollectedAmount)
+}
+
+func TestImportDonationsTxDonorNamePublic(t *testing.T) {
+	admin := createRandomUser(t, testStore)
+	goal := createGoalWithStatus(t, testStore, true)
+
+	public := randomImportRow(2, goal.ID)
+	public.DonorName = pgtype.Text{String: util.RandomOwner(), Valid: true}
+	public.DonorNamePublic = true
+	private := randomImportRow(3, goal.ID)
+	private.DonorName = pgtype.Text{String: util.RandomOwner(), Valid: true}
+
+	result, err := testStore.ImportDonationsTx(context.Background(), ImportDonationsTxParams{
+		ImportedBy: admin.ID,
+		Rows:       []ImportDonationRow{public, private},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Donations, 2)
+
+	// The feed names offline donors only if the import made their name public
+	item, err := testStore.GetDonationFeedItem(context.Background(), result.Donations[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, public.DonorName, item.OfflineDonorName)
+
+	item, err = testStore.GetDonationFeedItem(context.Background(), result.Donations[1].ID)
+	require.NoError(t, err)
+	require.False(t, item.OfflineDonorName.Valid)
+
+	offline, err := testStore.GetOfflineDonationByRef(context.Background(), private.ExternalRef)
+	require.NoError(t, err)
+	require.Equal(t, private.DonorName, offline.DonorName)
+	require.False(t, offline.DonorNamePublic)
+}
+
+func TestImportDonationsTxRollback(t *testing.T) {
+	admin := createRandomUser(t, testStore)
+	goal := createGoalWithStatus(t, testStore, true)
+	inactiveGoal := createGoalWithStatus(t, testStore, false)
+
+	valid := randomImportRow(2, goal.ID)
+	invalid := randomImportRow(3, inactiveGoal.ID)
+
+	_, err := testStore.ImportDonationsTx(context.Background(), ImportDonationsTxParams{
+		ImportedBy: admin.ID,
+		Rows:       []ImportDonationRow{valid, invalid},
+	})
+	require.ErrorIs(t, err, ErrInactiveGoal)
+
+	var rowErr *ImportRowError
+	require.ErrorAs(t, err, &rowErr)
+	require.Equal(t, 3, rowErr.Line)
+
+	// The valid row was rolled back with the rest of the transaction
+	_, err = testStore.GetOfflineDonationByRef(context.Background(), valid.ExternalRef)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	sameGoal, err := testStore.GetGoal(context.Background(), goal.ID)
+	require.NoError(t, err)
+	require.Equal(t, goal.CollectedAmount, sameGoal.CollectedAmount)
+}
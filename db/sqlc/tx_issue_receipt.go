@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Kinds of receipts
+const (
+	ReceiptKindDonation = "donation"
+	ReceiptKindAnnual   = "annual"
+)
+
+// ErrNoDonations is returned when an annual receipt is requested for a year without donations
+var ErrNoDonations = errors.New("no donations found for the year")
+
+// IssueAnnualReceiptTxParams contains the input parameters of the annual receipt transaction
+type IssueAnnualReceiptTxParams struct {
+	UserID int64 `json:"user_id"`
+	Year   int32 `json:"year"`
+}
+
+// IssueAnnualReceiptTxResult is the result of the annual receipt transaction
+type IssueAnnualReceiptTxResult struct {
+	Receipt   Receipt    `json:"receipt"`
+	Donations []Donation `json:"donations"`
+}
+
+// IssueDonationReceiptTx returns the receipt of a donation, issuing it with the next receipt number on first use.
+// The donation row is locked so that concurrent requests issue a single receipt, and neither can a refund
+// commit meanwhile. The counter is incremented in the same transaction so that numbers stay gapless.
+// A refunded donation gets no receipt.
+func (store *SQLStore) IssueDonationReceiptTx(ctx context.Context, donationID int64) (Receipt, error) {
+	var result Receipt
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		donation, err := q.GetDonationForUpdate(ctx, donationID)
+		if err != nil {
+			return err
+		}
+		if !donation.UserID.Valid {
+			return errors.New("cannot issue a receipt for a donation without a donor")
+		}
+
+		_, err = q.GetTicketRefundByDonation(ctx, donationID)
+		if err == nil {
+			return ErrDonationRefunded
+		}
+		if !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+
+		result, err = q.GetReceiptByDonation(ctx, pgtype.Int8{Int64: donationID, Valid: true})
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+
+		year := int32(donation.CreatedAt.UTC().Year())
+		number, err := q.NextReceiptNumber(ctx, year)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.CreateReceipt(ctx, CreateReceiptParams{
+			ReceiptNumber: formatReceiptNumber(year, number),
+			UserID:        donation.UserID.Int64,
+			DonationID:    pgtype.Int8{Int64: donationID, Valid: true},
+			Year:          year,
+			Kind:          ReceiptKindDonation,
+			Amount:        donation.Amount,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// IssueAnnualReceiptTx returns the annual summary receipt of a user, issuing it on first use.
+// The user row is locked so that concurrent requests issue a single receipt.
+// The donations of the year are stored with the receipt, so it keeps itemising
+// the same donations as its total when donations are recorded for the year later.
+// Refunded donations are left out.
+func (store *SQLStore) IssueAnnualReceiptTx(ctx context.Context, arg IssueAnnualReceiptTxParams) (IssueAnnualReceiptTxResult, error) {
+	var result IssueAnnualReceiptTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		_, err := q.GetUserForUpdate(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		result.Receipt, err = q.GetAnnualReceipt(ctx, GetAnnualReceiptParams{
+			UserID: arg.UserID,
+			Year:   arg.Year,
+		})
+		if err == nil {
+			result.Donations, err = q.ListReceiptDonations(ctx, result.Receipt.ID)
+			return err
+		}
+		if !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+
+		result.Donations, err = q.ListUserDonationsBetween(ctx, ListUserDonationsBetweenParams{
+			UserID:   pgtype.Int8{Int64: arg.UserID, Valid: true},
+			FromTime: time.Date(int(arg.Year), time.January, 1, 0, 0, 0, 0, time.UTC),
+			ToTime:   time.Date(int(arg.Year)+1, time.January, 1, 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			return err
+		}
+		if len(result.Donations) == 0 {
+			return ErrNoDonations
+		}
+
+		var total int64
+		for _, donation := range result.Donations {
+			total += donation.Amount
+		}
+
+		number, err := q.NextReceiptNumber(ctx, arg.Year)
+		if err != nil {
+			return err
+		}
+
+		result.Receipt, err = q.CreateReceipt(ctx, CreateReceiptParams{
+			ReceiptNumber: formatReceiptNumber(arg.Year, number),
+			UserID:        arg.UserID,
+			Year:          arg.Year,
+			Kind:          ReceiptKindAnnual,
+			Amount:        total,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, donation := range result.Donations {
+			err = q.AddReceiptDonation(ctx, AddReceiptDonationParams{
+				ReceiptID:  result.Receipt.ID,
+				DonationID: donation.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// formatReceiptNumber formats a receipt number, e.g. "2024-000042"
+func formatReceiptNumber(year int32, number int64) string {
+	return fmt.Sprintf("%d-%06d", year, number)
+}
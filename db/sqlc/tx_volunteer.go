@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SignUpVolunteerTxParams contains the input parameters of the volunteer sign-up transaction
+type SignUpVolunteerTxParams struct {
+	EventID int64 `json:"event_id"`
+	RoleID  int64 `json:"role_id"`
+	UserID  int64 `json:"user_id"`
+}
+
+// SignUpVolunteerTx signs a user up for a volunteer role within a database transaction.
+// The role stays locked until the sign-up commits, so its slots are never overfilled.
+// Roles of another event are reported as ErrRecordNotFound.
+func (store *SQLStore) SignUpVolunteerTx(ctx context.Context, arg SignUpVolunteerTxParams) (VolunteerShift, error) {
+	var shift VolunteerShift
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		event, err := q.GetEventForShare(ctx, arg.EventID)
+		if err != nil {
+			return err
+		}
+		if err := checkVolunteerable(event); err != nil {
+			return err
+		}
+
+		role, err := q.GetVolunteerRoleForUpdate(ctx, arg.RoleID)
+		if err != nil {
+			return err
+		}
+		if role.EventID != arg.EventID {
+			return ErrRecordNotFound
+		}
+
+		signedUp, err := q.CountVolunteerShifts(ctx, role.ID)
+		if err != nil {
+			return err
+		}
+		if signedUp >= int64(role.Slots) {
+			return ErrVolunteerRoleFull
+		}
+
+		shift, err = q.CreateVolunteerShift(ctx, CreateVolunteerShiftParams{
+			RoleID: role.ID,
+			UserID: arg.UserID,
+		})
+		return err
+	})
+
+	return shift, err
+}
+
+// UpdateVolunteerRoleTxParams contains the input parameters of the volunteer role update transaction
+type UpdateVolunteerRoleTxParams struct {
+	EventID     int64       `json:"event_id"`
+	RoleID      int64       `json:"role_id"`
+	Name        pgtype.Text `json:"name"`
+	Description pgtype.Text `json:"description"`
+	Slots       pgtype.Int4 `json:"slots"`
+}
+
+// UpdateVolunteerRoleTx updates a volunteer role within a database transaction, refusing to
+// leave it with fewer slots than volunteers have signed up for.
+// Roles of another event are reported as ErrRecordNotFound.
+func (store *SQLStore) UpdateVolunteerRoleTx(ctx context.Context, arg UpdateVolunteerRoleTxParams) (VolunteerRole, error) {
+	var role VolunteerRole
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		role, err = q.GetVolunteerRoleForUpdate(ctx, arg.RoleID)
+		if err != nil {
+			return err
+		}
+		if role.EventID != arg.EventID {
+			return ErrRecordNotFound
+		}
+
+		if arg.Slots.Valid && arg.Slots.Int32 < role.Slots {
+			signedUp, err := q.CountVolunteerShifts(ctx, role.ID)
+			if err != nil {
+				return err
+			}
+			if signedUp > int64(arg.Slots.Int32) {
+				return ErrVolunteerSlotsTaken
+			}
+		}
+
+		role, err = q.UpdateVolunteerRole(ctx, UpdateVolunteerRoleParams{
+			ID:          role.ID,
+			Name:        arg.Name,
+			Description: arg.Description,
+			Slots:       arg.Slots,
+		})
+		return err
+	})
+
+	return role, err
+}
+
+// checkVolunteerable returns an error unless volunteers can still sign up for the roles of event.
+// Postponed events stay open until they are rescheduled.
+func checkVolunteerable(event Event) error {
+	if event.Status == EventStatusCancelled {
+		return ErrEventCancelled
+	}
+	if event.Status != EventStatusPostponed && !event.Date.After(time.Now()) {
+		return ErrEventStarted
+	}
+	return nil
+}
@@ -18,7 +18,7 @@ INSERT INTO users (
   hashed_password
 ) VALUES (
   $1, $2, $3
-) RETURNING id, email, name, balance, hashed_password, created_at
+) RETURNING id, email, name, balance, hashed_password, created_at, role, hide_from_leaderboards, locale
 `
 
 type CreateUserParams struct {
@@ -37,6 +37,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Balance,
 		&i.HashedPassword,
 		&i.CreatedAt,
+		&i.Role,
+		&i.HideFromLeaderboards,
+		&i.Locale,
 	)
 	return i, err
 }
@@ -52,7 +55,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, email, name, balance, hashed_password, created_at FROM users
+SELECT id, email, name, balance, hashed_password, created_at, role, hide_from_leaderboards, locale FROM users
 WHERE id = $1 LIMIT 1
 `
 
@@ -66,12 +69,15 @@ func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
 		&i.Balance,
 		&i.HashedPassword,
 		&i.CreatedAt,
+		&i.Role,
+		&i.HideFromLeaderboards,
+		&i.Locale,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, name, balance, hashed_password, created_at FROM users
+SELECT id, email, name, balance, hashed_password, created_at, role, hide_from_leaderboards, locale FROM users
 WHERE email = $1 LIMIT 1
 `
 
@@ -85,12 +91,80 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Balance,
 		&i.HashedPassword,
 		&i.CreatedAt,
+		&i.Role,
+		&i.HideFromLeaderboards,
+		&i.Locale,
 	)
 	return i, err
 }
 
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+SELECT id, email, name, balance, hashed_password, created_at, role, hide_from_leaderboards, locale FROM users
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetUserForUpdate(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRow(ctx, getUserForUpdate, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.Balance,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.Role,
+		&i.HideFromLeaderboards,
+		&i.Locale,
+	)
+	return i, err
+}
+
+const listGoalDonorContacts = `-- name: ListGoalDonorContacts :many
+SELECT DISTINCT u.id, u.email, u.name, u.locale
+FROM users u
+JOIN donations d ON d.user_id = u.id
+WHERE d.goal_id = $1
+  AND NOT EXISTS (SELECT 1 FROM ticket_refunds tr WHERE tr.donation_id = d.id)
+ORDER BY u.id
+`
+
+type ListGoalDonorContactsRow struct {
+	ID     int64       `json:"id"`
+	Email  string      `json:"email"`
+	Name   pgtype.Text `json:"name"`
+	Locale string      `json:"locale"`
+}
+
+// Registered donors of a goal, including anonymous ones since the email isn't shown to anyone
+func (q *Queries) ListGoalDonorContacts(ctx context.Context, goalID int64) ([]ListGoalDonorContactsRow, error) {
+	rows, err := q.db.Query(ctx, listGoalDonorContacts, goalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListGoalDonorContactsRow{}
+	for rows.Next() {
+		var i ListGoalDonorContactsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Name,
+			&i.Locale,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsers = `-- name: ListUsers :many
-SELECT id, email, name, balance, hashed_password, created_at FROM users
+SELECT id, email, name, balance, hashed_password, created_at, role, hide_from_leaderboards, locale FROM users
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -117,6 +191,9 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.Balance,
 			&i.HashedPassword,
 			&i.CreatedAt,
+			&i.Role,
+			&i.HideFromLeaderboards,
+			&i.Locale,
 		); err != nil {
 			return nil, err
 		}
@@ -130,18 +207,31 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
-SET name = $2
-WHERE id = $1
-RETURNING id, email, name, balance, hashed_password, created_at
+SET
+  name = CASE WHEN $1::boolean THEN $2 ELSE name END,
+  hide_from_leaderboards = COALESCE($3, hide_from_leaderboards),
+  locale = COALESCE($4, locale)
+WHERE id = $5
+RETURNING id, email, name, balance, hashed_password, created_at, role, hide_from_leaderboards, locale
 `
 
 type UpdateUserParams struct {
-	ID   int64       `json:"id"`
-	Name pgtype.Text `json:"name"`
+	SetName              bool        `json:"set_name"`
+	Name                 pgtype.Text `json:"name"`
+	HideFromLeaderboards pgtype.Bool `json:"hide_from_leaderboards"`
+	Locale               pgtype.Text `json:"locale"`
+	ID                   int64       `json:"id"`
 }
 
+// name is only changed when set_name is true, so it can also be cleared to NULL
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, updateUser, arg.ID, arg.Name)
+	row := q.db.QueryRow(ctx, updateUser,
+		arg.SetName,
+		arg.Name,
+		arg.HideFromLeaderboards,
+		arg.Locale,
+		arg.ID,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -150,6 +240,9 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Balance,
 		&i.HashedPassword,
 		&i.CreatedAt,
+		&i.Role,
+		&i.HideFromLeaderboards,
+		&i.Locale,
 	)
 	return i, err
 }
@@ -158,7 +251,7 @@ const updateUserBalance = `-- name: UpdateUserBalance :one
 UPDATE users
 SET balance = balance + $2
 WHERE id = $1
-RETURNING id, email, name, balance, hashed_password, created_at
+RETURNING id, email, name, balance, hashed_password, created_at, role, hide_from_leaderboards, locale
 `
 
 type UpdateUserBalanceParams struct {
@@ -176,6 +269,9 @@ func (q *Queries) UpdateUserBalance(ctx context.Context, arg UpdateUserBalancePa
 		&i.Balance,
 		&i.HashedPassword,
 		&i.CreatedAt,
+		&i.Role,
+		&i.HideFromLeaderboards,
+		&i.Locale,
 	)
 	return i, err
 }
@@ -100,14 +100,62 @@ func TestUpdateUser(t *testing.T) {
 	t.Run("Update user name", func(t *testing.T) {
 		newName := pgtype.Text{String: "Updated Name", Valid: true}
 		updatedUser, err := testStore.UpdateUser(context.Background(), UpdateUserParams{
-			ID:   user.ID,
-			Name: newName,
+			ID:      user.ID,
+			SetName: true,
+			Name:    newName,
 		})
 
 		require.NoError(t, err)
 		require.Equal(t, newName, updatedUser.Name)
 		require.Equal(t, user.Email, updatedUser.Email) // Email should not change
 	})
+
+	t.Run("Clear user name", func(t *testing.T) {
+		updatedUser, err := testStore.UpdateUser(context.Background(), UpdateUserParams{
+			ID:      user.ID,
+			SetName: true,
+		})
+
+		require.NoError(t, err)
+		require.False(t, updatedUser.Name.Valid)
+	})
+}
+
+func TestUpdateUserLocale(t *testing.T) {
+	user := createRandomUser(t, testStore)
+	require.Equal(t, "en", user.Locale)
+
+	updatedUser, err := testStore.UpdateUser(context.Background(), UpdateUserParams{
+		ID:     user.ID,
+		Locale: pgtype.Text{String: "uk", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "uk", updatedUser.Locale)
+	require.Equal(t, user.Name, updatedUser.Name)
+}
+
+func TestListGoalDonorContacts(t *testing.T) {
+	goal := createRandomGoal(t, testStore)
+	donor := createRandomUser(t, testStore)
+	anonymousDonor := createRandomUser(t, testStore)
+
+	for _, arg := range []DonateToGoalTxParams{
+		{UserID: pgtype.Int8{Int64: donor.ID, Valid: true}, GoalID: goal.ID, Amount: 100},
+		{UserID: pgtype.Int8{Int64: donor.ID, Valid: true}, GoalID: goal.ID, Amount: 200},
+		{UserID: pgtype.Int8{Int64: anonymousDonor.ID, Valid: true}, GoalID: goal.ID, Amount: 300, IsAnonymous: true},
+		{GoalID: goal.ID, Amount: 400, IsAnonymous: true},
+	} {
+		_, err := testStore.DonateToGoalTx(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	// Every registered donor is listed once
+	donors, err := testStore.ListGoalDonorContacts(context.Background(), goal.ID)
+	require.NoError(t, err)
+	require.Len(t, donors, 2)
+	require.Equal(t, donor.Email, donors[0].Email)
+	require.Equal(t, anonymousDonor.Email, donors[1].Email)
+	require.Equal(t, "en", donors[1].Locale)
 }
 
 func TestUpdateUserBalance(t *testing.T) {
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+// createRandomVenue creates a venue at a point far from every other test venue, so nearby
+// searches around it only find events at it
+func createRandomVenue(t *testing.T) Venue {
+	arg := CreateVenueParams{
+		Name:               "Hall " + util.RandomString(6),
+		AddressLine:        util.RandomString(8) + " Street 1",
+		City:               "Kyiv",
+		Country:            "UA",
+		Latitude:           float64(util.RandomInt(-80000, 80000)) / 1000,
+		Longitude:          float64(util.RandomInt(-179000, 179000)) / 1000,
+		TimeZone:           "Europe/Kyiv",
+		Capacity:           pgtype.Int4{Int32: 150, Valid: true},
+		AccessibilityNotes: "Step-free entrance",
+	}
+
+	venue, err := testStore.CreateVenue(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.NotZero(t, venue.ID)
+	require.Equal(t, arg.Name, venue.Name)
+	require.Equal(t, arg.Country, venue.Country)
+	require.Equal(t, arg.Latitude, venue.Latitude)
+	require.Equal(t, arg.Longitude, venue.Longitude)
+	require.Equal(t, arg.Capacity, venue.Capacity)
+	require.Empty(t, venue.Region)
+	require.NotZero(t, venue.CreatedAt)
+
+	return venue
+}
+
+// createEventAtVenue creates an event a number of days from now at a venue
+func createEventAtVenue(t *testing.T, venue Venue, days int) Event {
+	name, place, _ := util.RandomEventParams()
+	date := time.Now().AddDate(0, 0, days)
+
+	event, err := testStore.CreateEvent(context.Background(), CreateEventParams{
+		Name:     name,
+		Place:    place,
+		Date:     date,
+		EndsAt:   date.Add(2 * time.Hour),
+		TimeZone: venue.TimeZone,
+		VenueID:  pgtype.Int8{Int64: venue.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, venue.ID, event.VenueID.Int64)
+
+	return event
+}
+
+func TestCreateVenue(t *testing.T) {
+	createRandomVenue(t)
+}
+
+func TestUpdateVenue(t *testing.T) {
+	venue := createRandomVenue(t)
+
+	updated, err := testStore.UpdateVenue(context.Background(), UpdateVenueParams{
+		ID:       venue.ID,
+		Name:     pgtype.Text{String: "Renamed Hall", Valid: true},
+		Capacity: pgtype.Int4{Int32: 80, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Renamed Hall", updated.Name)
+	require.Equal(t, int32(80), updated.Capacity.Int32)
+	require.Equal(t, venue.AddressLine, updated.AddressLine)
+	require.Equal(t, venue.Latitude, updated.Latitude)
+}
+
+func TestDeleteVenueKeepsEvents(t *testing.T) {
+	venue := createRandomVenue(t)
+	event := createEventAtVenue(t, venue, 7)
+
+	err := testStore.DeleteVenue(context.Background(), venue.ID)
+	require.NoError(t, err)
+
+	_, err = testStore.GetVenue(context.Background(), venue.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	kept, err := testStore.GetEvent(context.Background(), event.ID)
+	require.NoError(t, err)
+	require.False(t, kept.VenueID.Valid)
+	require.Equal(t, event.Place, kept.Place)
+}
+
+func TestListEventsNear(t *testing.T) {
+	venue := createRandomVenue(t)
+	later := createEventAtVenue(t, venue, 14)
+	sooner := createEventAtVenue(t, venue, 7)
+	past := createEventAtVenue(t, venue, -7)
+
+	// About 5.5 km north of the venue
+	latitude := venue.Latitude + 0.05
+	arg := ListEventsNearParams{
+		Latitude:    latitude,
+		Longitude:   venue.Longitude,
+		MinLatitude: latitude - 0.1,
+		MaxLatitude: latitude + 0.1,
+		RadiusKm:    10,
+		RowLimit:    10,
+	}
+
+	events, err := testStore.ListEventsNear(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, past.ID, events[0].Event.ID)
+	require.Equal(t, sooner.ID, events[1].Event.ID)
+	require.Equal(t, later.ID, events[2].Event.ID)
+	for _, event := range events {
+		require.InDelta(t, 5.56, event.DistanceKm, 0.05)
+	}
+
+	arg.UpcomingOnly = true
+	events, err = testStore.ListEventsNear(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	arg.RadiusKm = 5
+	events, err = testStore.ListEventsNear(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
@@ -0,0 +1,299 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomVolunteerRole(t *testing.T, event Event, slots int32) VolunteerRole {
+	arg := CreateVolunteerRoleParams{
+		EventID:     event.ID,
+		Name:        "Role " + util.RandomString(8),
+		Description: util.RandomString(20),
+		Slots:       slots,
+	}
+
+	role, err := testStore.CreateVolunteerRole(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.EventID, role.EventID)
+	require.Equal(t, arg.Name, role.Name)
+	require.Equal(t, arg.Description, role.Description)
+	require.Equal(t, arg.Slots, role.Slots)
+	require.NotZero(t, role.CreatedAt)
+
+	return role
+}
+
+func TestCreateVolunteerRoleDuplicateName(t *testing.T) {
+	event := createRandomEvent(t, testStore)
+	role := createRandomVolunteerRole(t, event, 2)
+
+	_, err := testStore.CreateVolunteerRole(context.Background(), CreateVolunteerRoleParams{
+		EventID: event.ID,
+		Name:    strings.ToUpper(role.Name),
+		Slots:   1,
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+}
+
+func TestSignUpVolunteerTx(t *testing.T) {
+	event := createRandomEvent(t, testStore)
+	role := createRandomVolunteerRole(t, event, 1)
+	user := createRandomUser(t, testStore)
+
+	arg := SignUpVolunteerTxParams{EventID: event.ID, RoleID: role.ID, UserID: user.ID}
+	shift, err := testStore.SignUpVolunteerTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, role.ID, shift.RoleID)
+	require.Equal(t, user.ID, shift.UserID)
+	require.False(t, shift.Minutes.Valid)
+
+	_, err = testStore.SignUpVolunteerTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrVolunteerRoleFull)
+
+	other := createRandomUser(t, testStore)
+	_, err = testStore.SignUpVolunteerTx(context.Background(), SignUpVolunteerTxParams{
+		EventID: event.ID,
+		RoleID:  role.ID,
+		UserID:  other.ID,
+	})
+	require.ErrorIs(t, err, ErrVolunteerRoleFull)
+
+	// Roles are only found through their own event
+	_, err = testStore.SignUpVolunteerTx(context.Background(), SignUpVolunteerTxParams{
+		EventID: createRandomEvent(t, testStore).ID,
+		RoleID:  role.ID,
+		UserID:  other.ID,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	roles, err := testStore.ListEventVolunteerRoles(context.Background(), event.ID)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	require.Equal(t, int64(1), roles[0].SignedUp)
+
+	err = testStore.DeleteVolunteerShift(context.Background(), DeleteVolunteerShiftParams{
+		RoleID: role.ID,
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.SignUpVolunteerTx(context.Background(), SignUpVolunteerTxParams{
+		EventID: event.ID,
+		RoleID:  role.ID,
+		UserID:  other.ID,
+	})
+	require.NoError(t, err)
+}
+
+func TestSignUpVolunteerTxConcurrent(t *testing.T) {
+	event := createRandomEvent(t, testStore)
+	role := createRandomVolunteerRole(t, event, 3)
+
+	n := 8
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		user := createRandomUser(t, testStore)
+		go func() {
+			_, err := testStore.SignUpVolunteerTx(context.Background(), SignUpVolunteerTxParams{
+				EventID: event.ID,
+				RoleID:  role.ID,
+				UserID:  user.ID,
+			})
+			errs <- err
+		}()
+	}
+
+	full := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err != nil {
+			require.ErrorIs(t, err, ErrVolunteerRoleFull)
+			full++
+		}
+	}
+	require.Equal(t, n-int(role.Slots), full)
+
+	signedUp, err := testStore.CountVolunteerShifts(context.Background(), role.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(role.Slots), signedUp)
+}
+
+func TestSignUpVolunteerTxClosedEvent(t *testing.T) {
+	name, place, _ := util.RandomEventParams()
+	date := time.Now().Add(-time.Hour)
+	past, err := testStore.CreateEvent(context.Background(), CreateEventParams{
+		Name:     name,
+		Place:    place,
+		Date:     date,
+		EndsAt:   date.Add(2 * time.Hour),
+		TimeZone: "UTC",
+	})
+	require.NoError(t, err)
+	role := createRandomVolunteerRole(t, past, 2)
+	user := createRandomUser(t, testStore)
+
+	_, err = testStore.SignUpVolunteerTx(context.Background(), SignUpVolunteerTxParams{
+		EventID: past.ID,
+		RoleID:  role.ID,
+		UserID:  user.ID,
+	})
+	require.ErrorIs(t, err, ErrEventStarted)
+
+	event := createRandomEvent(t, testStore)
+	_, err = testStore.UpdateEventStatus(context.Background(), UpdateEventStatusParams{
+		ID:     event.ID,
+		Status: EventStatusCancelled,
+	})
+	require.NoError(t, err)
+	role = createRandomVolunteerRole(t, event, 2)
+
+	_, err = testStore.SignUpVolunteerTx(context.Background(), SignUpVolunteerTxParams{
+		EventID: event.ID,
+		RoleID:  role.ID,
+		UserID:  user.ID,
+	})
+	require.ErrorIs(t, err, ErrEventCancelled)
+}
+
+func TestUpdateVolunteerRoleTx(t *testing.T) {
+	event := createRandomEvent(t, testStore)
+	role := createRandomVolunteerRole(t, event, 3)
+	for i := 0; i < 2; i++ {
+		_, err := testStore.SignUpVolunteerTx(context.Background(), SignUpVolunteerTxParams{
+			EventID: event.ID,
+			RoleID:  role.ID,
+			UserID:  createRandomUser(t, testStore).ID,
+		})
+		require.NoError(t, err)
+	}
+
+	_, err := testStore.UpdateVolunteerRoleTx(context.Background(), UpdateVolunteerRoleTxParams{
+		EventID: event.ID,
+		RoleID:  role.ID,
+		Slots:   pgtype.Int4{Int32: 1, Valid: true},
+	})
+	require.ErrorIs(t, err, ErrVolunteerSlotsTaken)
+
+	updated, err := testStore.UpdateVolunteerRoleTx(context.Background(), UpdateVolunteerRoleTxParams{
+		EventID: event.ID,
+		RoleID:  role.ID,
+		Name:    pgtype.Text{String: "Registration desk", Valid: true},
+		Slots:   pgtype.Int4{Int32: 2, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Registration desk", updated.Name)
+	require.Equal(t, role.Description, updated.Description)
+	require.Equal(t, int32(2), updated.Slots)
+
+	_, err = testStore.UpdateVolunteerRoleTx(context.Background(), UpdateVolunteerRoleTxParams{
+		EventID: event.ID + 1,
+		RoleID:  role.ID,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestVolunteerHistory(t *testing.T) {
+	user := createRandomUser(t, testStore)
+	staff := createRandomUser(t, testStore)
+	event := createRandomEvent(t, testStore)
+	role := createRandomVolunteerRole(t, event, 2)
+
+	shift, err := testStore.CreateVolunteerShift(context.Background(), CreateVolunteerShiftParams{
+		RoleID: role.ID,
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.LogVolunteerMinutes(context.Background(), LogVolunteerMinutesParams{
+		Minutes:  90,
+		LoggedBy: staff.ID,
+		ID:       shift.ID,
+		EventID:  event.ID + 1,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	logged, err := testStore.LogVolunteerMinutes(context.Background(), LogVolunteerMinutesParams{
+		Minutes:  90,
+		LoggedBy: staff.ID,
+		ID:       shift.ID,
+		EventID:  event.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(90), logged.Minutes.Int32)
+	require.True(t, logged.LoggedAt.Valid)
+	require.Equal(t, staff.ID, logged.LoggedBy.Int64)
+
+	history, err := testStore.ListUserVolunteerShifts(context.Background(), ListUserVolunteerShiftsParams{
+		UserID: user.ID,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, role.Name, history[0].RoleName)
+	require.Equal(t, event.ID, history[0].EventID)
+	require.Equal(t, event.Name, history[0].EventName)
+
+	minutes, err := testStore.GetUserVolunteerMinutes(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(90), minutes)
+
+	volunteers, err := testStore.ListEventVolunteers(context.Background(), event.ID)
+	require.NoError(t, err)
+	require.Len(t, volunteers, 1)
+	require.Equal(t, user.Email, volunteers[0].UserEmail)
+
+	// Roles with logged hours are kept
+	_, err = testStore.DeleteVolunteerRole(context.Background(), role.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	empty := createRandomVolunteerRole(t, event, 1)
+	id, err := testStore.DeleteVolunteerRole(context.Background(), empty.ID)
+	require.NoError(t, err)
+	require.Equal(t, empty.ID, id)
+}
+
+func TestDeleteEventTxVolunteerHours(t *testing.T) {
+	user := createRandomUser(t, testStore)
+	staff := createRandomUser(t, testStore)
+	event := createRandomEvent(t, testStore)
+	role := createRandomVolunteerRole(t, event, 2)
+	createRandomVolunteerRole(t, event, 1)
+
+	shift, err := testStore.CreateVolunteerShift(context.Background(), CreateVolunteerShiftParams{
+		RoleID: role.ID,
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.LogVolunteerMinutes(context.Background(), LogVolunteerMinutesParams{
+		Minutes:  60,
+		LoggedBy: staff.ID,
+		ID:       shift.ID,
+		EventID:  event.ID,
+	})
+	require.NoError(t, err)
+
+	// Events with logged hours are kept with all of their roles
+	err = testStore.DeleteEventTx(context.Background(), event.ID)
+	require.ErrorIs(t, err, ErrVolunteerHoursLogged)
+
+	roles, err := testStore.ListEventVolunteerRoles(context.Background(), event.ID)
+	require.NoError(t, err)
+	require.Len(t, roles, 2)
+
+	other := createRandomEvent(t, testStore)
+	createRandomVolunteerRole(t, other, 1)
+
+	err = testStore.DeleteEventTx(context.Background(), other.ID)
+	require.NoError(t, err)
+
+	_, err = testStore.GetEvent(context.Background(), other.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
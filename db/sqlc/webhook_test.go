@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhookEndpoint(t *testing.T, store Store, eventTypes ...string) WebhookEndpoint {
+	admin := createRandomUser(t, store)
+
+	arg := CreateWebhookEndpointParams{
+		Url:        "https://example.com/hooks/" + util.RandomString(8),
+		Secret:     util.RandomString(32),
+		EventTypes: eventTypes,
+		CreatedBy:  admin.ID,
+	}
+
+	endpoint, err := store.CreateWebhookEndpoint(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Url, endpoint.Url)
+	require.Equal(t, arg.EventTypes, endpoint.EventTypes)
+	require.True(t, endpoint.IsActive)
+
+	return endpoint
+}
+
+func TestListWebhookEndpointsForEvent(t *testing.T) {
+	endpoint := createRandomWebhookEndpoint(t, testStore, EventBookingCreated)
+
+	endpoints, err := testStore.ListWebhookEndpointsForEvent(context.Background(), EventBookingCreated)
+	require.NoError(t, err)
+	require.Contains(t, endpoints, endpoint)
+
+	endpoints, err = testStore.ListWebhookEndpointsForEvent(context.Background(), EventGoalFunded)
+	require.NoError(t, err)
+	require.NotContains(t, endpoints, endpoint)
+}
+
+func TestCreateWebhookDelivery(t *testing.T) {
+	endpoint := createRandomWebhookEndpoint(t, testStore, EventGoalFunded)
+	event, err := testStore.CreateOutboxEvent(context.Background(), CreateOutboxEventParams{
+		EventType: EventGoalFunded,
+		Payload:   []byte(`{}`),
+	})
+	require.NoError(t, err)
+
+	arg := CreateWebhookDeliveryParams{EndpointID: endpoint.ID, EventID: event.ID}
+	require.NoError(t, testStore.CreateWebhookDelivery(context.Background(), arg))
+	// Queuing the same event again is a no-op
+	require.NoError(t, testStore.CreateWebhookDelivery(context.Background(), arg))
+
+	deliveries, err := testStore.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		EndpointID: endpoint.ID,
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, "pending", deliveries[0].Status)
+	require.Zero(t, deliveries[0].Attempts)
+}
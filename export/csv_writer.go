@@ -0,0 +1,60 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVWriter writes rows as comma-separated values
+type CSVWriter struct {
+	w *csv.Writer
+}
+
+// NewCSVWriter creates a new CSVWriter
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// Write appends a row
+func (writer *CSVWriter) Write(row []any) error {
+	record := make([]string, len(row))
+	for i, value := range row {
+		switch v := value.(type) {
+		case string:
+			record[i] = escapeFormula(v)
+		case int64:
+			record[i] = strconv.FormatInt(v, 10)
+		case int:
+			record[i] = strconv.Itoa(v)
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		case nil:
+			record[i] = ""
+		default:
+			record[i] = escapeFormula(fmt.Sprint(v))
+		}
+	}
+	return writer.w.Write(record)
+}
+
+// Flush pushes buffered rows to the underlying writer
+func (writer *CSVWriter) Flush() error {
+	writer.w.Flush()
+	return writer.w.Error()
+}
+
+// Close flushes the remaining rows
+func (writer *CSVWriter) Close() error {
+	return writer.Flush()
+}
+
+// escapeFormula prefixes text that spreadsheets would otherwise evaluate as a formula
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
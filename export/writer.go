@@ -0,0 +1,49 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Supported export formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// Writer writes rows of a tabular export. Cells may be strings, integers or booleans.
+type Writer interface {
+	// Write appends a row
+	Write(row []any) error
+	// Flush pushes buffered rows to the underlying writer where the format allows it
+	Flush() error
+	// Close finishes the file. It must be called once all rows are written.
+	Close() error
+}
+
+// NewWriter creates a Writer for the given format
+func NewWriter(format string, w io.Writer, sheet string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w, sheet)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
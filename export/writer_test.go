@@ -0,0 +1,44 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatCSV, &buf, "")
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Write([]any{"name", "amount", "anonymous"}))
+	require.NoError(t, writer.Write([]any{"=HYPERLINK(\"x\")", int64(1250), true}))
+	require.NoError(t, writer.Close())
+
+	require.Equal(t, "name,amount,anonymous\n\"'=HYPERLINK(\"\"x\"\")\",1250,true\n", buf.String())
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatXLSX, &buf, "Donations")
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Write([]any{"name", "amount"}))
+	require.NoError(t, writer.Write([]any{"=1+1", int64(1250)}))
+	require.NoError(t, writer.Close())
+
+	file, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := file.GetRows("Donations")
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"name", "amount"}, {"=1+1", "1250"}}, rows)
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	_, err := NewWriter("pdf", &bytes.Buffer{}, "")
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// XLSXWriter writes rows to a single-sheet Excel workbook. Rows are streamed
+// to a temporary file by excelize, so the workbook is only written out on Close.
+type XLSXWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+// NewXLSXWriter creates a new XLSXWriter with one sheet named sheet
+func NewXLSXWriter(w io.Writer, sheet string) (*XLSXWriter, error) {
+	file := excelize.NewFile()
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &XLSXWriter{w: w, file: file, stream: stream}, nil
+}
+
+// Write appends a row
+func (writer *XLSXWriter) Write(row []any) error {
+	writer.row++
+	cell, err := excelize.CoordinatesToCellName(1, writer.row)
+	if err != nil {
+		return err
+	}
+	return writer.stream.SetRow(cell, row)
+}
+
+// Flush is a no-op because a workbook can't be written out in parts
+func (writer *XLSXWriter) Flush() error {
+	return nil
+}
+
+// Close writes the workbook and removes its temporary files
+func (writer *XLSXWriter) Close() error {
+	defer writer.file.Close()
+
+	if err := writer.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := writer.file.WriteTo(writer.w)
+	return err
+}
@@ -0,0 +1,122 @@
+// Package ical writes iCalendar (RFC 5545) feeds that calendar apps can subscribe to.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of iCalendar feeds
+const ContentType = "text/calendar; charset=utf-8"
+
+// Event statuses
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+// maxLineLength is the longest a content line may be in octets, excluding the line break
+const maxLineLength = 75
+
+const dateTimeFormat = "20060102T150405Z"
+
+// Event is a VEVENT. Calendar apps match events across feed refreshes by UID,
+// and replace their copy when Sequence is higher.
+type Event struct {
+	UID      string
+	Sequence int32
+	Start    time.Time
+	End      time.Time
+	Summary  string
+	Location string
+	Status   string
+}
+
+// Calendar is a VCALENDAR holding events
+type Calendar struct {
+	// ProdID identifies the product that created the calendar, like "-//Charity//Events//EN"
+	ProdID string
+	// Name is shown by calendar apps that support the X-WR-CALNAME extension
+	Name   string
+	Events []Event
+}
+
+// Encode writes the calendar to w, stamping every event with stamp.
+// Times are written in UTC, which calendar apps convert to the user's time zone.
+func (calendar Calendar) Encode(w io.Writer, stamp time.Time) error {
+	writer := bufio.NewWriter(w)
+
+	writeLine(writer, "BEGIN:VCALENDAR")
+	writeLine(writer, "VERSION:2.0")
+	writeLine(writer, "PRODID:"+escapeText(calendar.ProdID))
+	writeLine(writer, "CALSCALE:GREGORIAN")
+	writeLine(writer, "METHOD:PUBLISH")
+	if calendar.Name != "" {
+		writeLine(writer, "X-WR-CALNAME:"+escapeText(calendar.Name))
+	}
+
+	for _, event := range calendar.Events {
+		writeLine(writer, "BEGIN:VEVENT")
+		writeLine(writer, "UID:"+escapeText(event.UID))
+		writeLine(writer, "DTSTAMP:"+formatDateTime(stamp))
+		writeLine(writer, fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+		writeLine(writer, "DTSTART:"+formatDateTime(event.Start))
+		if !event.End.IsZero() {
+			writeLine(writer, "DTEND:"+formatDateTime(event.End))
+		}
+		writeLine(writer, "SUMMARY:"+escapeText(event.Summary))
+		if event.Location != "" {
+			writeLine(writer, "LOCATION:"+escapeText(event.Location))
+		}
+		if event.Status != "" {
+			writeLine(writer, "STATUS:"+event.Status)
+		}
+		writeLine(writer, "END:VEVENT")
+	}
+
+	writeLine(writer, "END:VCALENDAR")
+	return writer.Flush()
+}
+
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// escapeText escapes a TEXT value. Carriage returns are dropped since line breaks are written as \n.
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(value)
+}
+
+// writeLine writes a content line, folding it into lines of at most maxLineLength octets.
+// Continuation lines start with a space, and lines are never split inside a UTF-8 character.
+// Write errors are kept by the bufio.Writer and returned by Flush.
+func writeLine(writer *bufio.Writer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		writer.WriteString(line[:cut])
+		writer.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the length of continuation lines
+		limit = maxLineLength - 1
+	}
+
+	writer.WriteString(line)
+	writer.WriteString("\r\n")
+}
@@ -0,0 +1,95 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+// unfold joins folded content lines back together
+func unfold(data string) []string {
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(data, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+func TestEncode(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	calendar := Calendar{
+		ProdID: "-//Charity//Events//EN",
+		Name:   "Charity events",
+		Events: []Event{
+			{
+				UID:      "event-1@charity.example",
+				Sequence: 2,
+				Start:    time.Date(2024, 5, 1, 13, 0, 0, 0, kyiv),
+				End:      time.Date(2024, 5, 1, 15, 0, 0, 0, kyiv),
+				Summary:  "Run; walk, and \\ talk",
+				Location: "Kyiv\nMain square",
+				Status:   StatusConfirmed,
+			},
+			{
+				UID:     "event-2@charity.example",
+				Start:   time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC),
+				Summary: "Concert",
+				Status:  StatusCancelled,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err = calendar.Encode(&buf, time.Date(2024, 4, 1, 8, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Charity//Events//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Charity events",
+		"BEGIN:VEVENT",
+		"UID:event-1@charity.example",
+		"DTSTAMP:20240401T083000Z",
+		"SEQUENCE:2",
+		"DTSTART:20240501T100000Z",
+		"DTEND:20240501T120000Z",
+		`SUMMARY:Run\; walk\, and \\ talk`,
+		`LOCATION:Kyiv\nMain square`,
+		"STATUS:CONFIRMED",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:event-2@charity.example",
+		"DTSTAMP:20240401T083000Z",
+		"SEQUENCE:0",
+		"DTSTART:20240601T100000Z",
+		"SUMMARY:Concert",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, unfold(buf.String()))
+}
+
+func TestEncodeFoldsLongLines(t *testing.T) {
+	summary := strings.Repeat("Благодійний забіг ", 20)
+	calendar := Calendar{
+		ProdID: "-//Charity//Events//EN",
+		Events: []Event{{UID: "event-1@charity.example", Summary: summary}},
+	}
+
+	var buf bytes.Buffer
+	err := calendar.Encode(&buf, time.Now())
+	require.NoError(t, err)
+
+	data := buf.String()
+	require.True(t, strings.HasSuffix(data, "\r\n"))
+	for _, line := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), maxLineLength)
+		require.True(t, utf8.ValidString(line), line)
+	}
+	require.Contains(t, unfold(data), "SUMMARY:"+summary)
+}
@@ -0,0 +1,20 @@
+package limiter
+
+import (
+	"context"
+	"time"
+)
+
+// Limiter is an interface for rate limiting requests by key
+type Limiter interface {
+	// Allow records a request identified by key and reports whether it fits the policy
+	Allow(ctx context.Context, key string, policy Policy) (Result, error)
+}
+
+// Result describes the outcome of a rate limit check
+type Result struct {
+	Allowed    bool
+	Limit      int           // requests allowed per window, including burst
+	Remaining  int           // requests that can be made right now
+	RetryAfter time.Duration // time until the next request is allowed, zero if allowed
+}
@@ -0,0 +1,145 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// visitor represents a client making requests
+type visitor struct {
+	requests []time.Time
+	window   time.Duration
+	tokens   float64   // burst token bucket, used by policies with a burst
+	refilled time.Time // when tokens were last brought up to date
+	mutex    sync.RWMutex
+}
+
+// refill adds the tokens gained at the sustained rate of policy since they were last refilled
+func (v *visitor) refill(now time.Time, policy Policy) {
+	if v.refilled.IsZero() {
+		v.tokens = float64(policy.Capacity())
+	} else {
+		v.tokens += float64(now.Sub(v.refilled)) / float64(policy.refillInterval())
+		v.tokens = min(v.tokens, float64(policy.Capacity()))
+	}
+	v.refilled = now
+}
+
+// MemoryLimiter is a sliding window rate limiter that keeps its state in memory.
+// The state is local to the process, so every replica enforces its own limit.
+type MemoryLimiter struct {
+	visitors map[string]*visitor
+	mutex    sync.RWMutex
+	now      func() time.Time
+}
+
+// NewMemoryLimiter creates a new in-memory rate limiter
+func NewMemoryLimiter() Limiter {
+	rl := &MemoryLimiter{
+		visitors: make(map[string]*visitor),
+		now:      time.Now,
+	}
+
+	// Start cleanup goroutine to remove old visitors
+	go rl.cleanupVisitors()
+
+	return rl
+}
+
+// Allow checks if a request from the given key is allowed by the policy
+func (rl *MemoryLimiter) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	v, exists := rl.visitors[key]
+	if !exists {
+		v = &visitor{
+			requests: make([]time.Time, 0),
+		}
+		rl.visitors[key] = v
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	now := rl.now()
+	cutoff := now.Add(-policy.Window)
+	v.window = policy.Window
+	if policy.Burst > 0 {
+		// Keep the visitor until its bucket is full again, or forgetting it would refill it early
+		v.window = max(v.window, time.Duration(policy.Capacity())*policy.refillInterval())
+	}
+
+	// Remove old requests outside the time window
+	validRequests := make([]time.Time, 0)
+	for _, reqTime := range v.requests {
+		if reqTime.After(cutoff) {
+			validRequests = append(validRequests, reqTime)
+		}
+	}
+	v.requests = validRequests
+
+	result := Result{
+		Limit: policy.Capacity(),
+	}
+	limited := false
+
+	// Check if we're under the rate limit
+	if len(v.requests) >= policy.Capacity() {
+		result.RetryAfter = v.requests[0].Add(policy.Window).Sub(now)
+		limited = true
+	}
+
+	// A burst is drawn from the token bucket, so it's only available again once the
+	// bucket has refilled at the sustained rate
+	if policy.Burst > 0 {
+		v.refill(now, policy)
+		if v.tokens < 1 {
+			wait := time.Duration((1 - v.tokens) * float64(policy.refillInterval()))
+			result.RetryAfter = max(result.RetryAfter, wait)
+			limited = true
+		}
+	}
+
+	if limited {
+		return result, nil
+	}
+
+	// Add current request
+	v.requests = append(v.requests, now)
+	result.Allowed = true
+	result.Remaining = policy.Capacity() - len(v.requests)
+	if policy.Burst > 0 {
+		v.tokens--
+		result.Remaining = min(result.Remaining, int(v.tokens))
+	}
+	return result, nil
+}
+
+// cleanupVisitors removes visitors that haven't made requests recently
+func (rl *MemoryLimiter) cleanupVisitors() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		rl.mutex.Lock()
+		now := rl.now()
+
+		for key, v := range rl.visitors {
+			v.mutex.RLock()
+			lastRequest := time.Time{}
+			if len(v.requests) > 0 {
+				lastRequest = v.requests[len(v.requests)-1]
+			}
+			window := v.window
+			v.mutex.RUnlock()
+
+			// Remove visitors with no requests left in their window
+			if lastRequest.Before(now.Add(-window)) {
+				delete(rl.visitors, key)
+			}
+		}
+		rl.mutex.Unlock()
+	}
+}
@@ -0,0 +1,43 @@
+package limiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLimiterBurst(t *testing.T) {
+	rl := &MemoryLimiter{visitors: make(map[string]*visitor)}
+	policy := Policy{Name: "test", Limit: 2, Burst: 1, Window: time.Minute}
+
+	now := time.Now()
+	rl.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		result, err := rl.Allow(context.Background(), "key", policy)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, 3, result.Limit)
+		require.Equal(t, 2-i, result.Remaining)
+	}
+
+	result, err := rl.Allow(context.Background(), "key", policy)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, time.Minute, result.RetryAfter)
+
+	// The next window only gets the sustained rate, as the burst was used up
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		result, err = rl.Allow(context.Background(), "key", policy)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+
+	result, err = rl.Allow(context.Background(), "key", policy)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 30*time.Second, result.RetryAfter)
+}
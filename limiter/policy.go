@@ -0,0 +1,87 @@
+package limiter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy is a named rate limit applied to a group of routes
+type Policy struct {
+	Name   string
+	Limit  int           // sustained requests per window
+	Burst  int           // extra requests allowed at once after a quiet period
+	Window time.Duration // sliding window length
+}
+
+// Capacity returns the most requests the policy allows within one window.
+// Only policies without a burst allow that many in every window; with a burst, a token
+// bucket refilled at Limit per Window keeps the sustained rate at Limit.
+func (policy Policy) Capacity() int {
+	return policy.Limit + policy.Burst
+}
+
+// refillInterval returns how long the token bucket of a policy with a burst takes to gain one request
+func (policy Policy) refillInterval() time.Duration {
+	return policy.Window / time.Duration(policy.Limit)
+}
+
+// ParsePolicies parses a comma separated list of policies in the form
+// "name=limit/window[+burst]", e.g. "donations=10/1m+5,events=5/1h".
+func ParsePolicies(spec string) (map[string]Policy, error) {
+	policies := make(map[string]Policy)
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, rule, ok := strings.Cut(item, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid rate limit policy %q: expected name=limit/window", item)
+		}
+
+		policy, err := parseRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit policy %q: %w", item, err)
+		}
+		policy.Name = name
+
+		policies[name] = policy
+	}
+
+	return policies, nil
+}
+
+func parseRule(rule string) (Policy, error) {
+	var policy Policy
+
+	rule, burst, hasBurst := strings.Cut(rule, "+")
+	if hasBurst {
+		n, err := strconv.Atoi(burst)
+		if err != nil || n < 0 {
+			return policy, fmt.Errorf("burst must be a non-negative integer")
+		}
+		policy.Burst = n
+	}
+
+	limit, window, ok := strings.Cut(rule, "/")
+	if !ok {
+		return policy, fmt.Errorf("expected limit/window")
+	}
+
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		return policy, fmt.Errorf("limit must be a positive integer")
+	}
+	policy.Limit = n
+
+	policy.Window, err = time.ParseDuration(window)
+	if err != nil || policy.Window <= 0 {
+		return policy, fmt.Errorf("window must be a positive duration")
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,43 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ParsePolicies("donations=10/1m+5, events=5/1h,bookings=20/30s")
+	require.NoError(t, err)
+	require.Len(t, policies, 3)
+
+	require.Equal(t, Policy{Name: "donations", Limit: 10, Burst: 5, Window: time.Minute}, policies["donations"])
+	require.Equal(t, Policy{Name: "events", Limit: 5, Window: time.Hour}, policies["events"])
+	require.Equal(t, Policy{Name: "bookings", Limit: 20, Window: 30 * time.Second}, policies["bookings"])
+	require.Equal(t, 15, policies["donations"].Capacity())
+}
+
+func TestParsePoliciesEmpty(t *testing.T) {
+	policies, err := ParsePolicies("")
+	require.NoError(t, err)
+	require.Empty(t, policies)
+}
+
+func TestParsePoliciesInvalid(t *testing.T) {
+	specs := []string{
+		"donations",
+		"=10/1m",
+		"donations=10",
+		"donations=0/1m",
+		"donations=ten/1m",
+		"donations=10/forever",
+		"donations=10/1m+x",
+		"donations=10/1m+-1",
+	}
+
+	for _, spec := range specs {
+		_, err := ParsePolicies(spec)
+		require.Error(t, err, spec)
+	}
+}
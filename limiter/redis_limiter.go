@@ -0,0 +1,117 @@
+package limiter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "ratelimit:"
+
+// slidingWindowScript trims the requests that fell out of the window, then
+// records the current request only if the key is still under the limit.
+// Policies with a burst also keep a token bucket in a hash, refilled at the
+// sustained rate, and a request needs a token as well as room in the window.
+// Running it as a script keeps check-and-add atomic across API replicas.
+// It returns {allowed, requests remaining, milliseconds until the next request is allowed}.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local bucketKey = KEYS[2]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local capacity = tonumber(ARGV[3])
+local refill = tonumber(ARGV[5])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+local retry = 0
+if count >= capacity then
+  local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+  local oldestScore = now
+  if oldest[2] then
+    oldestScore = tonumber(oldest[2])
+  end
+  retry = oldestScore + window - now
+end
+
+local tokens = capacity
+if refill > 0 then
+  local bucket = redis.call('HMGET', bucketKey, 'tokens', 'refilled')
+  if bucket[1] then
+    tokens = math.min(capacity, tonumber(bucket[1]) + (now - tonumber(bucket[2])) / refill)
+  end
+  if tokens < 1 then
+    retry = math.max(retry, math.ceil((1 - tokens) * refill))
+  end
+end
+
+if retry > 0 then
+  return {0, 0, retry}
+end
+
+redis.call('ZADD', key, now, ARGV[4])
+redis.call('PEXPIRE', key, window)
+
+local remaining = capacity - count - 1
+if refill > 0 then
+  tokens = tokens - 1
+  redis.call('HSET', bucketKey, 'tokens', tostring(tokens), 'refilled', now)
+  redis.call('PEXPIRE', bucketKey, math.ceil(capacity * refill))
+  remaining = math.min(remaining, math.floor(tokens))
+end
+return {1, remaining, 0}
+`)
+
+// RedisLimiter is a sliding window rate limiter backed by Redis sorted sets.
+// The state is shared, so all replicas enforce one limit and survive restarts.
+type RedisLimiter struct {
+	client redis.Scripter
+	now    func() time.Time
+}
+
+// NewRedisLimiter creates a new Redis-backed rate limiter
+func NewRedisLimiter(client redis.Scripter) Limiter {
+	return &RedisLimiter{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// Allow checks if a request from the given key is allowed by the policy
+func (rl *RedisLimiter) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	now := rl.now().UnixMilli()
+	member := fmt.Sprintf("%d-%s", now, uuid.NewString())
+
+	// Policies without a burst skip the token bucket
+	var refill int64
+	if policy.Burst > 0 {
+		refill = max(1, policy.refillInterval().Milliseconds())
+	}
+
+	values, err := slidingWindowScript.Run(
+		ctx,
+		rl.client,
+		[]string{redisKeyPrefix + key, redisKeyPrefix + key + ":burst"},
+		now,
+		policy.Window.Milliseconds(),
+		policy.Capacity(),
+		member,
+		refill,
+	).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("cannot run rate limit script: %w", err)
+	}
+	if len(values) != 3 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result %v", values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      policy.Capacity(),
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
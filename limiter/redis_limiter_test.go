@@ -0,0 +1,147 @@
+package limiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/kholodihor/charity/util"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisLimiter(t *testing.T) (*RedisLimiter, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	rl := NewRedisLimiter(client).(*RedisLimiter)
+	return rl, mr
+}
+
+func TestRedisLimiterAllow(t *testing.T) {
+	rl, _ := newTestRedisLimiter(t)
+	key := util.RandomString(8)
+	policy := Policy{Name: "test", Limit: 3, Window: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		result, err := rl.Allow(context.Background(), key, policy)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, 3, result.Limit)
+		require.Equal(t, 2-i, result.Remaining)
+	}
+
+	result, err := rl.Allow(context.Background(), key, policy)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Zero(t, result.Remaining)
+	require.Positive(t, result.RetryAfter)
+	require.LessOrEqual(t, result.RetryAfter, time.Minute)
+
+	// Other keys have their own window
+	result, err = rl.Allow(context.Background(), util.RandomString(8), policy)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+}
+
+func TestRedisLimiterBurst(t *testing.T) {
+	rl, _ := newTestRedisLimiter(t)
+	key := util.RandomString(8)
+	policy := Policy{Name: "test", Limit: 2, Burst: 1, Window: time.Minute}
+
+	now := time.Now()
+	rl.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		result, err := rl.Allow(context.Background(), key, policy)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, 3, result.Limit)
+		require.Equal(t, 2-i, result.Remaining)
+	}
+
+	result, err := rl.Allow(context.Background(), key, policy)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, time.Minute, result.RetryAfter)
+
+	// The next window only gets the sustained rate, as the burst was used up
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		result, err = rl.Allow(context.Background(), key, policy)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+
+	result, err = rl.Allow(context.Background(), key, policy)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 30*time.Second, result.RetryAfter)
+}
+
+func TestRedisLimiterSlidingWindow(t *testing.T) {
+	rl, _ := newTestRedisLimiter(t)
+	key := util.RandomString(8)
+	policy := Policy{Name: "test", Limit: 2, Window: time.Minute}
+
+	now := time.Now()
+	rl.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		result, err := rl.Allow(context.Background(), key, policy)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+
+	now = now.Add(30 * time.Second)
+	result, err := rl.Allow(context.Background(), key, policy)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 30*time.Second, result.RetryAfter)
+
+	// Requests leave the window one minute after they were made
+	now = now.Add(30*time.Second + time.Millisecond)
+	result, err = rl.Allow(context.Background(), key, policy)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+}
+
+func TestRedisLimiterSharedAcrossInstances(t *testing.T) {
+	mr := miniredis.RunT(t)
+	key := util.RandomString(8)
+	policy := Policy{Name: "test", Limit: 2, Window: time.Minute}
+
+	newLimiter := func() Limiter {
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+		return NewRedisLimiter(client)
+	}
+
+	replica1 := newLimiter()
+	replica2 := newLimiter()
+
+	result, err := replica1.Allow(context.Background(), key, policy)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	result, err = replica2.Allow(context.Background(), key, policy)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// The limit is enforced across both replicas
+	result, err = replica1.Allow(context.Background(), key, policy)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+}
+
+func TestRedisLimiterUnavailable(t *testing.T) {
+	rl, mr := newTestRedisLimiter(t)
+	mr.Close()
+
+	policy := Policy{Name: "test", Limit: 2, Window: time.Minute}
+	result, err := rl.Allow(context.Background(), util.RandomString(8), policy)
+	require.Error(t, err)
+	require.False(t, result.Allowed)
+}
@@ -2,12 +2,20 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"os"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kholodihor/charity/api"
 	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/notify"
+	"github.com/kholodihor/charity/outbox"
+	"github.com/kholodihor/charity/pubsub"
 	"github.com/kholodihor/charity/util"
+	"github.com/kholodihor/charity/webhook"
+	"github.com/kholodihor/charity/worker"
 )
 
 func main() {
@@ -23,13 +31,83 @@ func main() {
 	defer connPool.Close()
 
 	store := db.NewStore(connPool)
+
+	if config.DashboardRefreshInterval > 0 {
+		go worker.RunPeriodically(context.Background(), "refresh dashboard stats", config.DashboardRefreshInterval, store.RefreshDailyStats)
+	}
+
 	server, err := api.NewServer(config, store)
 	if err != nil {
 		log.Fatal("cannot create server:", err)
 	}
 
+	// All notification channels share one connection outside of the pool
+	listener := pubsub.NewListener(config.DBSource)
+	go server.StreamGoalProgress(context.Background(), listener.Receiver(db.GoalProgressChannel))
+	go server.StreamDonations(context.Background(), listener.Receiver(db.DonationChannel))
+
+	dispatcher := outbox.NewDispatcher(store)
+
+	if config.WebhookPollInterval > 0 {
+		webhookWorker := webhook.NewWorker(store, config.WebhookTimeout, config.WebhookMaxAttempts)
+		dispatcher.Subscribe("queue webhook deliveries", webhookWorker.FanOut)
+		go worker.RunPeriodically(context.Background(), "deliver webhooks", config.WebhookPollInterval, webhookWorker.Run)
+	}
+
+	if config.EventReminderInterval > 0 && len(config.EventReminderOffsets) > 0 {
+		go worker.RunPeriodically(context.Background(), "schedule event reminders", config.EventReminderInterval, func(ctx context.Context) error {
+			_, err := store.ScheduleEventRemindersTx(ctx, config.EventReminderOffsets)
+			return err
+		})
+	}
+
+	if config.EventSeriesInterval > 0 {
+		go worker.RunPeriodically(context.Background(), "materialize event series", config.EventSeriesInterval, func(ctx context.Context) error {
+			_, err := store.MaterializeEventSeriesTx(ctx, time.Now().Add(config.EventSeriesHorizon))
+			return err
+		})
+	}
+
+	mailer, err := newMailer(config)
+	if err != nil {
+		log.Fatal("cannot create mailer:", err)
+	}
+	if mailer != nil {
+		templates, err := notify.LoadTemplates(notify.DefaultLocale)
+		if err != nil {
+			log.Fatal("cannot load email templates:", err)
+		}
+
+		mailQueue := notify.NewQueue(mailer, config.MailQueueSize, config.MailTimeout)
+		notify.NewService(store, templates, mailQueue, config.Currency, server).Subscribe(dispatcher)
+		go mailQueue.Run(context.Background(), max(config.MailWorkers, 1))
+	}
+
+	if config.OutboxPollInterval > 0 {
+		go dispatcher.Run(context.Background(), config.OutboxPollInterval, outbox.NewPostgresNotifier(listener))
+	}
+
+	go listener.Run(context.Background())
+
 	err = server.Start(config.ServerAddress)
 	if err != nil {
 		log.Fatal("cannot start server:", err)
 	}
 }
+
+// newMailer creates the mailer for the driver selected in config.
+// It returns nil when emails are disabled.
+func newMailer(config util.Config) (notify.Mailer, error) {
+	switch config.MailDriver {
+	case "", util.MailDriverNone:
+		return nil, nil
+	case util.MailDriverLog:
+		return notify.NewLogMailer(os.Stdout), nil
+	case util.MailDriverFile:
+		return notify.NewFileMailer(config.MailDir, config.MailFrom)
+	case util.MailDriverSMTP:
+		return notify.NewSMTPMailer(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.MailFrom), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver %q", config.MailDriver)
+	}
+}
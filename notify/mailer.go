@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Message is an email with a plain text and an HTML body
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// LogMailer writes emails to a writer instead of sending them, for local development
+type LogMailer struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// NewLogMailer creates a LogMailer writing to writer
+func NewLogMailer(writer io.Writer) *LogMailer {
+	return &LogMailer{writer: writer}
+}
+
+// Send writes the headers and plain text body of message
+func (mailer *LogMailer) Send(ctx context.Context, message Message) error {
+	mailer.mutex.Lock()
+	defer mailer.mutex.Unlock()
+
+	_, err := fmt.Fprintf(mailer.writer, "To: %s\nSubject: %s\n\n%s\n\n", message.To, message.Subject, message.Text)
+	return err
+}
+
+// FileMailer saves every email as an .eml file in a directory, where mail clients can open it
+type FileMailer struct {
+	dir  string
+	from string
+
+	mutex sync.Mutex
+	count int
+}
+
+// NewFileMailer creates a FileMailer saving emails from from in dir, which is created if needed
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+// Send writes message to a new file named after the current time
+func (mailer *FileMailer) Send(ctx context.Context, message Message) error {
+	data, err := buildMIMEMessage(mailer.from, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	mailer.mutex.Lock()
+	mailer.count++
+	name := fmt.Sprintf("%s-%d.eml", time.Now().UTC().Format("20060102T150405.000000000"), mailer.count)
+	mailer.mutex.Unlock()
+
+	return os.WriteFile(filepath.Join(mailer.dir, name), data, 0o644)
+}
@@ -0,0 +1,172 @@
+package notify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomMessage() Message {
+	return Message{
+		To:      "olena@example.com",
+		Subject: "Дякуємо\r\nBcc: attacker@example.com",
+		Text:    "Thank you for your donation.",
+		HTML:    "<p>Thank you for your donation.</p>",
+	}
+}
+
+// requireMIMEMessage parses an email and checks it carries message
+func requireMIMEMessage(t *testing.T, data []byte, message Message) {
+	parsed, err := mail.ReadMessage(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	decoder := new(mime.WordDecoder)
+	subject, err := decoder.DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, message.Subject, subject)
+	// Header values can't add headers of their own
+	require.Empty(t, parsed.Header.Get("Bcc"))
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+	}
+	require.Equal(t, []string{message.Text, message.HTML}, bodies)
+}
+
+func TestBuildMIMEMessage(t *testing.T) {
+	message := randomMessage()
+
+	data, err := buildMIMEMessage("Charity <noreply@charity.example>", message, time.Now())
+	require.NoError(t, err)
+	requireMIMEMessage(t, data, message)
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer, err := NewFileMailer(dir, "noreply@charity.example")
+	require.NoError(t, err)
+
+	message := randomMessage()
+	require.NoError(t, mailer.Send(context.Background(), message))
+	require.NoError(t, mailer.Send(context.Background(), message))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	requireMIMEMessage(t, data, message)
+}
+
+func TestLogMailer(t *testing.T) {
+	var output bytes.Buffer
+	mailer := NewLogMailer(&output)
+
+	message := randomMessage()
+	require.NoError(t, mailer.Send(context.Background(), message))
+	require.Contains(t, output.String(), "To: olena@example.com")
+	require.Contains(t, output.String(), message.Text)
+}
+
+// serveSMTP accepts one connection on listener and speaks just enough SMTP to receive an email
+func serveSMTP(t *testing.T, listener net.Listener, received chan<- []byte) {
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, err := conn.Write([]byte(line + "\r\n"))
+		require.NoError(t, err)
+	}
+
+	reply("220 localhost ESMTP")
+	var data bytes.Buffer
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:<NOREPLY@CHARITY.EXAMPLE>"),
+			strings.HasPrefix(command, "RCPT TO:<OLENA@EXAMPLE.COM>"):
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 Go ahead")
+			for {
+				line, err := reader.ReadString('\n')
+				require.NoError(t, err)
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			reply("250 Queued")
+		case command == "QUIT":
+			reply("221 Bye")
+			received <- data.Bytes()
+			return
+		default:
+			reply("500 Unexpected " + command)
+		}
+	}
+}
+
+func TestSMTPMailer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []byte, 1)
+	go serveSMTP(t, listener, received)
+
+	address := listener.Addr().(*net.TCPAddr)
+	mailer := NewSMTPMailer("127.0.0.1", address.Port, "", "", "Charity <noreply@charity.example>")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	message := randomMessage()
+	require.NoError(t, mailer.Send(ctx, message))
+
+	select {
+	case data := <-received:
+		requireMIMEMessage(t, data, message)
+	case <-ctx.Done():
+		t.Fatal("no email received")
+	}
+
+	message.To = "not an address"
+	require.ErrorContains(t, mailer.Send(ctx, message), "invalid recipient")
+}
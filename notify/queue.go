@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// sendAttempts is how many times an email is sent before it is dropped
+	sendAttempts = 3
+	// sendRetryDelay is the wait before the first retry, doubled for every later one
+	sendRetryDelay = 5 * time.Second
+)
+
+// Queue sends emails in the background so callers don't wait on the mail server.
+// Queued emails live in memory only and are lost if the process stops.
+type Queue struct {
+	mailer     Mailer
+	messages   chan Message
+	timeout    time.Duration
+	retryDelay time.Duration
+}
+
+// NewQueue creates a Queue holding up to size emails, each given timeout to be sent
+func NewQueue(mailer Mailer, size int, timeout time.Duration) *Queue {
+	return &Queue{
+		mailer:     mailer,
+		messages:   make(chan Message, size),
+		timeout:    timeout,
+		retryDelay: sendRetryDelay,
+	}
+}
+
+// Send queues message to be sent, waiting for room while emails are queued faster than they
+// are sent. It only fails when ctx is done.
+func (queue *Queue) Send(ctx context.Context, message Message) error {
+	select {
+	case queue.messages <- message:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run sends queued emails with the given number of workers until ctx is done.
+// Failed sends are retried with backoff, then dropped.
+func (queue *Queue) Run(ctx context.Context, workers int) {
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case message := <-queue.messages:
+					queue.send(ctx, message)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (queue *Queue) send(ctx context.Context, message Message) {
+	delay := queue.retryDelay
+	for attempt := 1; ; attempt++ {
+		err := queue.sendOnce(ctx, message)
+		if err == nil {
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if attempt == sendAttempts {
+			log.Printf("send email %q to %s failed, giving up: %v", message.Subject, message.To, err)
+			return
+		}
+
+		log.Printf("send email %q to %s failed, retrying in %s: %v", message.Subject, message.To, delay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (queue *Queue) sendOnce(ctx context.Context, message Message) error {
+	if queue.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, queue.timeout)
+		defer cancel()
+	}
+
+	return queue.mailer.Send(ctx, message)
+}
@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMailer records sent emails and fails the first failures sends
+type fakeMailer struct {
+	mutex    sync.Mutex
+	failures int
+	attempts int
+	sent     []Message
+}
+
+func (mailer *fakeMailer) Send(ctx context.Context, message Message) error {
+	mailer.mutex.Lock()
+	defer mailer.mutex.Unlock()
+
+	mailer.attempts++
+	if mailer.attempts <= mailer.failures {
+		return errors.New("connection refused")
+	}
+	mailer.sent = append(mailer.sent, message)
+	return nil
+}
+
+func (mailer *fakeMailer) messages() []Message {
+	mailer.mutex.Lock()
+	defer mailer.mutex.Unlock()
+
+	return append([]Message(nil), mailer.sent...)
+}
+
+func TestQueue(t *testing.T) {
+	mailer := &fakeMailer{failures: 1}
+	queue := NewQueue(mailer, 1, time.Second)
+	queue.retryDelay = time.Millisecond
+
+	require.NoError(t, queue.Send(context.Background(), Message{To: "a@example.com"}))
+
+	// Sending waits for room in a full queue
+	full, cancelFull := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelFull()
+	require.ErrorIs(t, queue.Send(full, Message{To: "b@example.com"}), context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx, 2)
+
+	// The failed send is retried
+	require.Eventually(t, func() bool { return len(mailer.messages()) == 1 }, time.Second, time.Millisecond)
+	require.Equal(t, "a@example.com", mailer.messages()[0].To)
+}
+
+func TestQueueGivesUp(t *testing.T) {
+	mailer := &fakeMailer{failures: sendAttempts}
+	queue := NewQueue(mailer, 2, time.Second)
+	queue.retryDelay = time.Millisecond
+
+	require.NoError(t, queue.Send(context.Background(), Message{To: "a@example.com"}))
+	require.NoError(t, queue.Send(context.Background(), Message{To: "b@example.com"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx, 1)
+
+	// The first email is dropped after its attempts, the next one still goes out
+	require.Eventually(t, func() bool { return len(mailer.messages()) == 1 }, time.Second, time.Millisecond)
+	require.Equal(t, "b@example.com", mailer.messages()[0].To)
+}
@@ -0,0 +1,325 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/outbox"
+	"github.com/kholodihor/charity/util"
+)
+
+// Service emails users about domain events. Emails are rendered when the event is dispatched
+// and queued for sending. Queuing waits for room rather than failing, so an event is only retried,
+// and its recipients emailed again, when dispatching is interrupted.
+type Service struct {
+	store     db.Store
+	templates *Templates
+	queue     *Queue
+	currency  string
+	links     Links
+}
+
+// Links builds the links put in emails
+type Links interface {
+	// GuestBookingURL returns the link that confirms a guest booking
+	GuestBookingURL(booking db.EventBooking) string
+}
+
+// NewService creates a new Service formatting amounts in currency and linking to links
+func NewService(store db.Store, templates *Templates, queue *Queue, currency string, links Links) *Service {
+	return &Service{
+		store:     store,
+		templates: templates,
+		queue:     queue,
+		currency:  currency,
+		links:     links,
+	}
+}
+
+// Subscribe registers the service's domain event handlers with dispatcher
+func (service *Service) Subscribe(dispatcher *outbox.Dispatcher) {
+	dispatcher.Subscribe("email donation thank-you", service.ThankDonor, db.EventDonationCreated)
+	dispatcher.Subscribe("email goal funded", service.AnnounceGoalFunded, db.EventGoalFunded)
+	dispatcher.Subscribe("email booking confirmation", service.ConfirmBooking, db.EventBookingCreated)
+	dispatcher.Subscribe("email booking cancellation", service.ConfirmCancellation, db.EventBookingCancelled)
+	dispatcher.Subscribe("email event reminder", service.RemindBooking, db.EventBookingReminder)
+	dispatcher.Subscribe("email event status change", service.AnnounceEventStatus, db.EventStatusChanged)
+	dispatcher.Subscribe("email guest booking confirmation", service.AskGuestConfirmation, db.EventGuestBooked)
+}
+
+// eventStatusTemplates are the templates telling bookers about each event status
+var eventStatusTemplates = map[string]string{
+	db.EventStatusCancelled:   TemplateEventCancelled,
+	db.EventStatusPostponed:   TemplateEventPostponed,
+	db.EventStatusRescheduled: TemplateEventRescheduled,
+}
+
+// ThankDonor thanks a registered donor for an online donation, including an anonymous one
+func (service *Service) ThankDonor(ctx context.Context, event db.OutboxEvent) error {
+	var payload db.DonationCreatedPayload
+	err := json.Unmarshal(event.Payload, &payload)
+	if err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+	if payload.Source != db.DonationSourceOnline {
+		return nil
+	}
+
+	// The payload leaves out the donor of anonymous donations
+	donation, err := service.store.GetDonation(ctx, payload.DonationID)
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+	if !donation.UserID.Valid {
+		return nil
+	}
+
+	user, err := service.store.GetUser(ctx, donation.UserID.Int64)
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+	goal, err := service.store.GetGoal(ctx, donation.GoalID)
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+
+	return service.send(ctx, TemplateDonationThankYou, user.Locale, user.Email, DonationThankYouData{
+		Name:      user.Name.String,
+		GoalTitle: goal.Title,
+		Amount:    util.FormatCents(donation.Amount, service.currency),
+	})
+}
+
+// AnnounceGoalFunded tells every registered donor of a goal that it reached its target
+func (service *Service) AnnounceGoalFunded(ctx context.Context, event db.OutboxEvent) error {
+	var payload db.GoalFundedPayload
+	err := json.Unmarshal(event.Payload, &payload)
+	if err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+
+	donors, err := service.store.ListGoalDonorContacts(ctx, payload.GoalID)
+	if err != nil {
+		return err
+	}
+
+	messages := make([]Message, 0, len(donors))
+	for _, donor := range donors {
+		message, err := service.templates.Render(TemplateGoalFunded, donor.Locale, donor.Email, GoalFundedData{
+			Name:      donor.Name.String,
+			GoalTitle: payload.Title,
+			Amount:    util.FormatCents(payload.TargetAmount, service.currency),
+		})
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+	}
+	return service.sendAll(ctx, messages)
+}
+
+// ConfirmBooking confirms a new event booking
+func (service *Service) ConfirmBooking(ctx context.Context, event db.OutboxEvent) error {
+	return service.sendBooking(ctx, event, TemplateBookingConfirmation)
+}
+
+// ConfirmCancellation confirms a cancelled event booking
+func (service *Service) ConfirmCancellation(ctx context.Context, event db.OutboxEvent) error {
+	return service.sendBooking(ctx, event, TemplateBookingCancellation)
+}
+
+// RemindBooking reminds a user or a guest of an upcoming event they booked, unless they have
+// cancelled the booking or turned its reminders off since the reminder was scheduled
+func (service *Service) RemindBooking(ctx context.Context, event db.OutboxEvent) error {
+	var payload db.BookingReminderPayload
+	err := json.Unmarshal(event.Payload, &payload)
+	if err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+
+	// A cancelled booking is deleted, and booking again makes a new one
+	booking, err := service.store.GetEventBookingByID(ctx, payload.BookingID)
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+	if !booking.RemindersEnabled {
+		return nil
+	}
+
+	data := BookingData{Name: booking.GuestName.String, Guest: !booking.UserID.Valid}
+	locale, email := booking.GuestLocale, booking.GuestEmail.String
+	if booking.UserID.Valid {
+		user, err := service.store.GetUser(ctx, booking.UserID.Int64)
+		if err != nil {
+			return ignoreNotFound(err)
+		}
+		data.Name, locale, email = user.Name.String, user.Locale, user.Email
+	}
+
+	bookedEvent, err := service.store.GetEvent(ctx, booking.EventID)
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+	// A reminder dispatched late is no use once the event has started
+	if !bookedEvent.Date.After(time.Now()) {
+		return nil
+	}
+
+	data.EventName = bookedEvent.Name
+	data.EventPlace = bookedEvent.Place
+	data.EventDate = util.InTimeZone(bookedEvent.Date, bookedEvent.TimeZone)
+	return service.send(ctx, TemplateEventReminder, locale, email, data)
+}
+
+// AnnounceEventStatus tells everyone who booked an event that it was postponed, rescheduled
+// or cancelled, and whether their booking was kept
+func (service *Service) AnnounceEventStatus(ctx context.Context, event db.OutboxEvent) error {
+	var payload db.EventStatusChangedPayload
+	err := json.Unmarshal(event.Payload, &payload)
+	if err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+	template, ok := eventStatusTemplates[payload.Status]
+	if !ok {
+		return fmt.Errorf("invalid payload: unknown event status %q", payload.Status)
+	}
+
+	// Occurrences removed from a series are deleted, so the event is described by the payload.
+	// Payloads written before it carried the event name it by ID.
+	if payload.Name == "" {
+		changed, err := service.store.GetEvent(ctx, payload.EventID)
+		if err != nil {
+			return ignoreNotFound(err)
+		}
+		payload.Name, payload.Place, payload.TimeZone = changed.Name, changed.Place, changed.TimeZone
+	}
+
+	messages := make([]Message, 0, len(payload.Bookers))
+	for _, booker := range payload.Bookers {
+		name, locale, email := booker.GuestName, booker.GuestLocale, booker.GuestEmail
+		if booker.UserID != 0 {
+			user, err := service.store.GetUser(ctx, booker.UserID)
+			if err != nil {
+				if errors.Is(err, db.ErrRecordNotFound) {
+					continue
+				}
+				return err
+			}
+			name, locale, email = user.Name.String, user.Locale, user.Email
+		}
+
+		data := EventStatusData{
+			Name:            name,
+			EventName:       payload.Name,
+			EventPlace:      payload.Place,
+			EventDate:       util.InTimeZone(payload.PreviousDate, payload.TimeZone),
+			NewDate:         util.InTimeZone(payload.Date, payload.TimeZone),
+			Reason:          payload.Reason,
+			BookingReleased: payload.BookingsReleased,
+		}
+		if booker.RefundedAmount > 0 {
+			data.Refund = util.FormatCents(booker.RefundedAmount, service.currency)
+		}
+
+		message, err := service.templates.Render(template, locale, email, data)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+	}
+	return service.sendAll(ctx, messages)
+}
+
+// AskGuestConfirmation emails a guest the link that confirms their booking, unless it has been
+// confirmed or cancelled since
+func (service *Service) AskGuestConfirmation(ctx context.Context, event db.OutboxEvent) error {
+	var payload db.GuestBookedPayload
+	err := json.Unmarshal(event.Payload, &payload)
+	if err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+
+	booking, err := service.store.GetEventBookingByID(ctx, payload.BookingID)
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+	if booking.UserID.Valid || booking.ConfirmedAt.Valid {
+		return nil
+	}
+	bookedEvent, err := service.store.GetEvent(ctx, booking.EventID)
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+
+	return service.send(ctx, TemplateGuestBooking, booking.GuestLocale, booking.GuestEmail.String, GuestBookingData{
+		Name:       booking.GuestName.String,
+		EventName:  bookedEvent.Name,
+		EventPlace: bookedEvent.Place,
+		EventDate:  util.InTimeZone(bookedEvent.Date, bookedEvent.TimeZone),
+		PartySize:  booking.PartySize,
+		ConfirmURL: service.links.GuestBookingURL(booking),
+	})
+}
+
+func (service *Service) sendBooking(ctx context.Context, event db.OutboxEvent, name string) error {
+	var payload db.BookingPayload
+	err := json.Unmarshal(event.Payload, &payload)
+	if err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+
+	// Guests see their booking when they confirm it and cancel it themselves, so they aren't emailed
+	if payload.UserID == 0 {
+		return nil
+	}
+
+	user, err := service.store.GetUser(ctx, payload.UserID)
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+	bookedEvent, err := service.store.GetEvent(ctx, payload.EventID)
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+
+	return service.send(ctx, name, user.Locale, user.Email, BookingData{
+		Name:       user.Name.String,
+		EventName:  bookedEvent.Name,
+		EventPlace: bookedEvent.Place,
+		EventDate:  util.InTimeZone(bookedEvent.Date, bookedEvent.TimeZone),
+	})
+}
+
+func (service *Service) send(ctx context.Context, name, locale, to string, data any) error {
+	message, err := service.templates.Render(name, locale, to, data)
+	if err != nil {
+		return err
+	}
+
+	return service.queue.Send(ctx, message)
+}
+
+// sendAll queues emails rendered for every recipient of an event. Everything that can fail is done
+// before the first email is queued, and the dispatcher only retries subscribers that failed,
+// so recipients aren't emailed twice when another subscriber of the event fails.
+func (service *Service) sendAll(ctx context.Context, messages []Message) error {
+	for _, message := range messages {
+		err := service.queue.Send(ctx, message)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ignoreNotFound drops errors about records deleted since the event, which leave nobody to email
+func ignoreNotFound(err error) error {
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,499 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomUser() db.User {
+	return db.User{
+		ID:     util.RandomInt(1, 1000),
+		Email:  util.RandomEmail(),
+		Name:   pgtype.Text{String: util.RandomOwner(), Valid: true},
+		Locale: "en",
+	}
+}
+
+func newOutboxEvent(t *testing.T, eventType string, payload any) db.OutboxEvent {
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return db.OutboxEvent{
+		ID:        util.RandomInt(1, 1000),
+		EventType: eventType,
+		Payload:   data,
+	}
+}
+
+// testLinks links to an example site
+type testLinks struct{}
+
+func (testLinks) GuestBookingURL(booking db.EventBooking) string {
+	return fmt.Sprintf("https://charity.example.com/guest-bookings/confirm?code=G1.%d.%d.signature", booking.ID, booking.EventID)
+}
+
+func newTestService(t *testing.T, store db.Store) (*Service, *Queue) {
+	templates, err := LoadTemplates(DefaultLocale)
+	require.NoError(t, err)
+
+	queue := NewQueue(nil, 10, time.Second)
+	return NewService(store, templates, queue, "USD", testLinks{}), queue
+}
+
+// queued returns the emails waiting in queue
+func queued(queue *Queue) []Message {
+	var messages []Message
+	for {
+		select {
+		case message := <-queue.messages:
+			messages = append(messages, message)
+		default:
+			return messages
+		}
+	}
+}
+
+func TestThankDonor(t *testing.T) {
+	user := randomUser()
+	user.Locale = "uk"
+	goal := db.Goal{ID: util.RandomInt(1, 1000), Title: "Clean water"}
+	donation := db.Donation{
+		ID:          util.RandomInt(1, 1000),
+		UserID:      pgtype.Int8{Int64: user.ID, Valid: true},
+		GoalID:      goal.ID,
+		Amount:      2500,
+		IsAnonymous: true,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetDonation(gomock.Any(), gomock.Eq(donation.ID)).Times(1).Return(donation, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+	store.EXPECT().GetGoal(gomock.Any(), gomock.Eq(goal.ID)).Times(1).Return(goal, nil)
+
+	service, queue := newTestService(t, store)
+
+	// Anonymous donors are thanked too, since the payload leaves them out
+	err := service.ThankDonor(context.Background(), newOutboxEvent(t, db.EventDonationCreated, db.DonationCreatedPayload{
+		DonationID:  donation.ID,
+		GoalID:      goal.ID,
+		Amount:      donation.Amount,
+		IsAnonymous: true,
+		Source:      db.DonationSourceOnline,
+	}))
+	require.NoError(t, err)
+
+	messages := queued(queue)
+	require.Len(t, messages, 1)
+	require.Equal(t, user.Email, messages[0].To)
+	require.Contains(t, messages[0].Subject, "Clean water")
+	require.Contains(t, messages[0].Text, "25.00 USD")
+	require.Contains(t, messages[0].Text, user.Name.String)
+
+	// Offline donations have no registered donor to thank
+	err = service.ThankDonor(context.Background(), newOutboxEvent(t, db.EventDonationCreated, db.DonationCreatedPayload{
+		DonationID: donation.ID,
+		Source:     db.DonationSourceOffline,
+	}))
+	require.NoError(t, err)
+	require.Empty(t, queued(queue))
+}
+
+func TestThankGuestDonor(t *testing.T) {
+	donation := db.Donation{ID: util.RandomInt(1, 1000), IsAnonymous: true}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetDonation(gomock.Any(), gomock.Eq(donation.ID)).Times(1).Return(donation, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+
+	service, queue := newTestService(t, store)
+
+	err := service.ThankDonor(context.Background(), newOutboxEvent(t, db.EventDonationCreated, db.DonationCreatedPayload{
+		DonationID: donation.ID,
+		Source:     db.DonationSourceOnline,
+	}))
+	require.NoError(t, err)
+	require.Empty(t, queued(queue))
+}
+
+func TestAnnounceGoalFunded(t *testing.T) {
+	goalID := util.RandomInt(1, 1000)
+	donors := []db.ListGoalDonorContactsRow{
+		{ID: 1, Email: "a@example.com", Locale: "en"},
+		{ID: 2, Email: "b@example.com", Locale: "uk"},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListGoalDonorContacts(gomock.Any(), gomock.Eq(goalID)).Times(1).Return(donors, nil)
+
+	service, queue := newTestService(t, store)
+
+	err := service.AnnounceGoalFunded(context.Background(), newOutboxEvent(t, db.EventGoalFunded, db.GoalFundedPayload{
+		GoalID:          goalID,
+		Title:           "Clean water",
+		TargetAmount:    100000,
+		CollectedAmount: 100500,
+	}))
+	require.NoError(t, err)
+
+	messages := queued(queue)
+	require.Len(t, messages, 2)
+	require.Equal(t, "a@example.com", messages[0].To)
+	require.Contains(t, messages[0].Text, "1,000.00 USD")
+	require.Equal(t, "b@example.com", messages[1].To)
+	require.Contains(t, messages[1].Subject, "Збір")
+}
+
+func TestConfirmBooking(t *testing.T) {
+	user := randomUser()
+	event := db.Event{
+		ID:    util.RandomInt(1, 1000),
+		Name:  "Charity run",
+		Place: "Kyiv",
+		Date:  time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	payload := db.BookingPayload{
+		BookingID: util.RandomInt(1, 1000),
+		EventID:   event.ID,
+		UserID:    user.ID,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(2).Return(user, nil)
+	gomock.InOrder(
+		store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil),
+		// The event may be deleted by the time its cancellations are dispatched
+		store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(db.Event{}, db.ErrRecordNotFound),
+	)
+
+	service, queue := newTestService(t, store)
+
+	err := service.ConfirmBooking(context.Background(), newOutboxEvent(t, db.EventBookingCreated, payload))
+	require.NoError(t, err)
+
+	messages := queued(queue)
+	require.Len(t, messages, 1)
+	require.Equal(t, user.Email, messages[0].To)
+	require.Contains(t, messages[0].Subject, "Charity run")
+	require.Contains(t, messages[0].Text, "Kyiv")
+
+	err = service.ConfirmCancellation(context.Background(), newOutboxEvent(t, db.EventBookingCancelled, payload))
+	require.NoError(t, err)
+	require.Empty(t, queued(queue))
+
+	// Guests aren't emailed about bookings they confirmed or cancelled themselves
+	payload.UserID = 0
+	err = service.ConfirmBooking(context.Background(), newOutboxEvent(t, db.EventBookingCreated, payload))
+	require.NoError(t, err)
+	require.Empty(t, queued(queue))
+}
+
+func TestAskGuestConfirmation(t *testing.T) {
+	event := db.Event{
+		ID:       util.RandomInt(1, 1000),
+		Name:     "Charity run",
+		Place:    "Kyiv",
+		Date:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		TimeZone: "Europe/Kyiv",
+	}
+	booking := db.EventBooking{
+		ID:          util.RandomInt(1, 1000),
+		EventID:     event.ID,
+		PartySize:   3,
+		GuestName:   pgtype.Text{String: "Olena", Valid: true},
+		GuestEmail:  pgtype.Text{String: util.RandomEmail(), Valid: true},
+		GuestLocale: "uk",
+	}
+	payload := db.GuestBookedPayload{
+		BookingID: booking.ID,
+		EventID:   event.ID,
+	}
+
+	confirmed := booking
+	confirmed.ConfirmedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().GetEventBookingByID(gomock.Any(), gomock.Eq(booking.ID)).Times(1).Return(booking, nil),
+		// The guest confirmed before the email was dispatched
+		store.EXPECT().GetEventBookingByID(gomock.Any(), gomock.Eq(booking.ID)).Times(1).Return(confirmed, nil),
+	)
+	store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+
+	service, queue := newTestService(t, store)
+
+	err := service.AskGuestConfirmation(context.Background(), newOutboxEvent(t, db.EventGuestBooked, payload))
+	require.NoError(t, err)
+
+	messages := queued(queue)
+	require.Len(t, messages, 1)
+	require.Equal(t, booking.GuestEmail.String, messages[0].To)
+	require.Contains(t, messages[0].Subject, "Charity run")
+	require.Contains(t, messages[0].Text, "Olena")
+	require.Contains(t, messages[0].Text, "3 осіб")
+	require.Contains(t, messages[0].Text, testLinks{}.GuestBookingURL(booking))
+
+	err = service.AskGuestConfirmation(context.Background(), newOutboxEvent(t, db.EventGuestBooked, payload))
+	require.NoError(t, err)
+	require.Empty(t, queued(queue))
+}
+
+func TestRemindBooking(t *testing.T) {
+	user := randomUser()
+	event := db.Event{
+		ID:    util.RandomInt(1, 1000),
+		Name:  "Charity run",
+		Place: "Kyiv",
+		Date:  time.Now().Add(24 * time.Hour).UTC(),
+	}
+	booking := db.EventBooking{
+		ID:               util.RandomInt(1, 1000),
+		UserID:           pgtype.Int8{Int64: user.ID, Valid: true},
+		EventID:          event.ID,
+		RemindersEnabled: true,
+	}
+	payload := db.BookingReminderPayload{
+		BookingID:     booking.ID,
+		EventID:       event.ID,
+		UserID:        user.ID,
+		EventDate:     event.Date,
+		OffsetMinutes: 24 * 60,
+	}
+	disabled := booking
+	disabled.RemindersEnabled = false
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().GetEventBookingByID(gomock.Any(), gomock.Eq(booking.ID)).Times(1).Return(booking, nil),
+		store.EXPECT().GetEventBookingByID(gomock.Any(), gomock.Eq(booking.ID)).Times(1).Return(disabled, nil),
+		// The booking was cancelled, even if the event was booked again, after the reminder was scheduled
+		store.EXPECT().GetEventBookingByID(gomock.Any(), gomock.Eq(booking.ID)).Times(1).Return(db.EventBooking{}, db.ErrRecordNotFound),
+	)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+	store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+
+	service, queue := newTestService(t, store)
+
+	err := service.RemindBooking(context.Background(), newOutboxEvent(t, db.EventBookingReminder, payload))
+	require.NoError(t, err)
+
+	messages := queued(queue)
+	require.Len(t, messages, 1)
+	require.Equal(t, user.Email, messages[0].To)
+	require.Contains(t, messages[0].Subject, "Charity run")
+	require.Contains(t, messages[0].Text, "Kyiv")
+	require.Contains(t, messages[0].Text, "turn off reminders")
+
+	for range 2 {
+		err = service.RemindBooking(context.Background(), newOutboxEvent(t, db.EventBookingReminder, payload))
+		require.NoError(t, err)
+	}
+	require.Empty(t, queued(queue))
+}
+
+func TestRemindGuestBooking(t *testing.T) {
+	event := db.Event{
+		ID:    util.RandomInt(1, 1000),
+		Name:  "Charity run",
+		Place: "Kyiv",
+		Date:  time.Now().Add(24 * time.Hour).UTC(),
+	}
+	booking := db.EventBooking{
+		ID:               util.RandomInt(1, 1000),
+		EventID:          event.ID,
+		RemindersEnabled: true,
+		GuestName:        pgtype.Text{String: "Taras", Valid: true},
+		GuestEmail:       pgtype.Text{String: "taras@example.com", Valid: true},
+		GuestLocale:      "uk",
+		ConfirmedAt:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	payload := db.BookingReminderPayload{
+		BookingID:     booking.ID,
+		EventID:       event.ID,
+		EventDate:     event.Date,
+		OffsetMinutes: 24 * 60,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetEventBookingByID(gomock.Any(), gomock.Eq(booking.ID)).Times(1).Return(booking, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+
+	service, queue := newTestService(t, store)
+
+	err := service.RemindBooking(context.Background(), newOutboxEvent(t, db.EventBookingReminder, payload))
+	require.NoError(t, err)
+
+	// Guests are reminded at the address and in the language they booked with
+	messages := queued(queue)
+	require.Len(t, messages, 1)
+	require.Equal(t, "taras@example.com", messages[0].To)
+	require.Contains(t, messages[0].Subject, "Нагадування")
+	require.Contains(t, messages[0].Text, "Taras")
+	require.NotContains(t, messages[0].Text, "вимкнути")
+}
+
+func TestAnnounceEventStatus(t *testing.T) {
+	kept := randomUser()
+	refunded := randomUser()
+	refunded.Locale = "uk"
+	deleted := randomUser()
+	event := db.Event{
+		ID:       util.RandomInt(1, 1000),
+		Name:     "Charity run",
+		Place:    "Kyiv",
+		Date:     time.Date(2024, 5, 8, 10, 0, 0, 0, time.UTC),
+		TimeZone: "Europe/Kyiv",
+		Status:   db.EventStatusRescheduled,
+	}
+	payload := db.EventStatusChangedPayload{
+		EventID:      event.ID,
+		Name:         event.Name,
+		Place:        event.Place,
+		TimeZone:     event.TimeZone,
+		Status:       db.EventStatusRescheduled,
+		Reason:       "Storm warning",
+		PreviousDate: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Date:         event.Date,
+		Bookers: []db.EventBooker{
+			{BookingID: 1, UserID: kept.ID},
+			{BookingID: 2, UserID: deleted.ID},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	// Only the payload without the event name looks the event up
+	store.EXPECT().GetEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(kept.ID)).Times(1).Return(kept, nil)
+	// Bookers who deleted their account since are skipped
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(deleted.ID)).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(refunded.ID)).Times(1).Return(refunded, nil)
+
+	service, queue := newTestService(t, store)
+
+	err := service.AnnounceEventStatus(context.Background(), newOutboxEvent(t, db.EventStatusChanged, payload))
+	require.NoError(t, err)
+
+	messages := queued(queue)
+	require.Len(t, messages, 1)
+	require.Equal(t, kept.Email, messages[0].To)
+	require.Equal(t, "Charity run moves to May 8", messages[0].Subject)
+	require.Contains(t, messages[0].Text, "Wednesday, May 8, 2024 13:00 EEST")
+	require.Contains(t, messages[0].Text, "Storm warning")
+	require.Contains(t, messages[0].Text, "Your booking is kept")
+
+	payload.Name, payload.Place, payload.TimeZone = "", "", ""
+	payload.Status = db.EventStatusCancelled
+	payload.BookingsReleased = true
+	payload.Bookers = []db.EventBooker{
+		{BookingID: 3, UserID: refunded.ID, RefundedAmount: 2500},
+		// Guests are emailed at the address they booked with
+		{BookingID: 4, GuestName: "Taras", GuestEmail: "taras@example.com", GuestLocale: "en"},
+	}
+
+	err = service.AnnounceEventStatus(context.Background(), newOutboxEvent(t, db.EventStatusChanged, payload))
+	require.NoError(t, err)
+
+	messages = queued(queue)
+	require.Len(t, messages, 2)
+	require.Equal(t, refunded.Email, messages[0].To)
+	require.Contains(t, messages[0].Subject, "скасовано")
+	require.Contains(t, messages[0].Text, "25.00 USD")
+	require.Equal(t, "taras@example.com", messages[1].To)
+	require.Equal(t, "Charity run is cancelled", messages[1].Subject)
+	require.Contains(t, messages[1].Text, "Hi Taras")
+}
+
+func TestServiceWaitsForQueue(t *testing.T) {
+	goalID := util.RandomInt(1, 1000)
+	donors := make([]db.ListGoalDonorContactsRow, 11)
+	for i := range donors {
+		donors[i] = db.ListGoalDonorContactsRow{ID: int64(i + 1), Email: util.RandomEmail(), Locale: "en"}
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListGoalDonorContacts(gomock.Any(), gomock.Eq(goalID)).Times(1).Return(donors, nil)
+
+	service, queue := newTestService(t, store)
+
+	// More donors than the queue holds are all emailed once it drains
+	done := make(chan error, 1)
+	go func() {
+		done <- service.AnnounceGoalFunded(context.Background(), newOutboxEvent(t, db.EventGoalFunded, db.GoalFundedPayload{GoalID: goalID}))
+	}()
+
+	var messages []Message
+	for len(messages) < len(donors) {
+		messages = append(messages, <-queue.messages)
+	}
+	require.NoError(t, <-done)
+	for i, message := range messages {
+		require.Equal(t, donors[i].Email, message.To)
+	}
+}
+
+func TestAnnounceEventStatusFailsBeforeSending(t *testing.T) {
+	first := randomUser()
+	second := randomUser()
+	payload := db.EventStatusChangedPayload{
+		EventID:  util.RandomInt(1, 1000),
+		Name:     "Charity run",
+		TimeZone: "UTC",
+		Status:   db.EventStatusCancelled,
+		Bookers: []db.EventBooker{
+			{BookingID: 1, UserID: first.ID},
+			{BookingID: 2, UserID: second.ID},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(first.ID)).Times(1).Return(first, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(second.ID)).Times(1).Return(db.User{}, errors.New("connection reset"))
+
+	service, queue := newTestService(t, store)
+
+	// Nobody is emailed, so the retried event doesn't email the first booker twice
+	err := service.AnnounceEventStatus(context.Background(), newOutboxEvent(t, db.EventStatusChanged, payload))
+	require.Error(t, err)
+	require.Empty(t, queued(queue))
+}
@@ -0,0 +1,205 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Email templates, available in every locale
+const (
+	TemplateDonationThankYou    = "donation_thank_you"
+	TemplateBookingConfirmation = "booking_confirmation"
+	TemplateBookingCancellation = "booking_cancellation"
+	TemplateGoalFunded          = "goal_funded"
+	TemplateEventReminder       = "event_reminder"
+	TemplateEventCancelled      = "event_cancelled"
+	TemplateEventPostponed      = "event_postponed"
+	TemplateEventRescheduled    = "event_rescheduled"
+	TemplateGuestBooking        = "guest_booking_confirmation"
+)
+
+var templateNames = []string{
+	TemplateDonationThankYou,
+	TemplateBookingConfirmation,
+	TemplateBookingCancellation,
+	TemplateGoalFunded,
+	TemplateEventReminder,
+	TemplateEventCancelled,
+	TemplateEventPostponed,
+	TemplateEventRescheduled,
+	TemplateGuestBooking,
+}
+
+// DefaultLocale is used for recipients whose locale has no templates
+const DefaultLocale = "en"
+
+// Each template file is at templates/<locale>/<name>.tmpl and defines "subject", "text" and "html"
+//
+//go:embed templates
+var templateFiles embed.FS
+
+// Locales returns the locales with templates, sorted
+func Locales() []string {
+	entries, err := fs.ReadDir(templateFiles, "templates")
+	if err != nil {
+		// The directory is embedded, so it can't be missing
+		panic(err)
+	}
+
+	locales := make([]string, len(entries))
+	for i, entry := range entries {
+		locales[i] = entry.Name()
+	}
+	return locales
+}
+
+// DonationThankYouData fills the donation thank-you template
+type DonationThankYouData struct {
+	Name      string
+	GoalTitle string
+	Amount    string
+}
+
+// BookingData fills the booking confirmation, cancellation and event reminder templates
+type BookingData struct {
+	Name       string
+	EventName  string
+	EventPlace string
+	// EventDate is the start of the event in the venue's time zone
+	EventDate time.Time
+	// Guest is set for guest bookings, which have no account to manage reminders in
+	Guest bool
+}
+
+// EventStatusData fills the event cancelled, postponed and rescheduled templates
+type EventStatusData struct {
+	Name       string
+	EventName  string
+	EventPlace string
+	// EventDate is the start before the change and NewDate the start after it, in the venue's time zone
+	EventDate time.Time
+	NewDate   time.Time
+	Reason    string
+	// BookingReleased is set when the booking was cancelled, and Refund is the ticket price
+	// given back for it, if any
+	BookingReleased bool
+	Refund          string
+}
+
+// GuestBookingData fills the guest booking confirmation template
+type GuestBookingData struct {
+	Name       string
+	EventName  string
+	EventPlace string
+	// EventDate is the start of the event in the venue's time zone
+	EventDate time.Time
+	PartySize int32
+	// ConfirmURL is the link the guest follows to confirm the booking
+	ConfirmURL string
+}
+
+// GoalFundedData fills the goal funded template
+type GoalFundedData struct {
+	Name      string
+	GoalTitle string
+	Amount    string
+}
+
+type localizedTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Templates renders emails in the recipient's language
+type Templates struct {
+	defaultLocale string
+	// templates holds the templates of every locale, by locale and name
+	templates map[string]map[string]localizedTemplate
+}
+
+// LoadTemplates parses the embedded templates. Every locale must have every template,
+// and defaultLocale must be one of them.
+func LoadTemplates(defaultLocale string) (*Templates, error) {
+	templates := &Templates{
+		defaultLocale: defaultLocale,
+		templates:     make(map[string]map[string]localizedTemplate),
+	}
+	for _, locale := range Locales() {
+		templates.templates[locale] = make(map[string]localizedTemplate)
+
+		for _, name := range templateNames {
+			file := path.Join("templates", locale, name+".tmpl")
+
+			text, err := texttemplate.ParseFS(templateFiles, file)
+			if err != nil {
+				return nil, err
+			}
+			html, err := htmltemplate.ParseFS(templateFiles, file)
+			if err != nil {
+				return nil, err
+			}
+
+			templates.templates[locale][name] = localizedTemplate{text: text, html: html}
+		}
+	}
+
+	if templates.templates[defaultLocale] == nil {
+		return nil, fmt.Errorf("no templates for default locale %q", defaultLocale)
+	}
+	return templates, nil
+}
+
+// HasLocale reports whether there are templates for locale
+func (templates *Templates) HasLocale(locale string) bool {
+	return templates.templates[locale] != nil
+}
+
+// Render renders the named template for a recipient in locale. Regional locales like uk-UA
+// fall back to their language, and unknown locales to the default one.
+func (templates *Templates) Render(name, locale, to string, data any) (Message, error) {
+	template, ok := templates.templates[templates.resolve(locale)][name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	err := template.text.ExecuteTemplate(&subject, "subject", data)
+	if err != nil {
+		return Message{}, err
+	}
+	err = template.text.ExecuteTemplate(&text, "text", data)
+	if err != nil {
+		return Message{}, err
+	}
+	err = template.html.ExecuteTemplate(&html, "html", data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+func (templates *Templates) resolve(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if templates.HasLocale(locale) {
+		return locale
+	}
+
+	language, _, _ := strings.Cut(locale, "-")
+	if templates.HasLocale(language) {
+		return language
+	}
+	return templates.defaultLocale
+}
@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocales(t *testing.T) {
+	require.Equal(t, []string{"en", "uk"}, Locales())
+}
+
+func TestLoadTemplates(t *testing.T) {
+	_, err := LoadTemplates("xx")
+	require.ErrorContains(t, err, "no templates for default locale")
+}
+
+func TestRender(t *testing.T) {
+	templates, err := LoadTemplates(DefaultLocale)
+	require.NoError(t, err)
+
+	data := DonationThankYouData{
+		Name:      "Olena",
+		GoalTitle: "Clean <water>",
+		Amount:    "25.00 USD",
+	}
+
+	message, err := templates.Render(TemplateDonationThankYou, "en", "olena@example.com", data)
+	require.NoError(t, err)
+	require.Equal(t, "olena@example.com", message.To)
+	require.Equal(t, "Thank you for supporting Clean <water>", message.Subject)
+	require.Contains(t, message.Text, "Hi Olena,")
+	require.Contains(t, message.Text, "25.00 USD to Clean <water>")
+	// The HTML body is escaped
+	require.Contains(t, message.HTML, "Clean &lt;water&gt;")
+
+	// Regional locales fall back to their language
+	message, err = templates.Render(TemplateDonationThankYou, "uk_UA", "olena@example.com", data)
+	require.NoError(t, err)
+	require.Contains(t, message.Subject, "Дякуємо")
+
+	// Unknown locales fall back to the default one
+	message, err = templates.Render(TemplateDonationThankYou, "fr", "olena@example.com", data)
+	require.NoError(t, err)
+	require.Contains(t, message.Subject, "Thank you")
+
+	_, err = templates.Render("unknown", "en", "olena@example.com", data)
+	require.ErrorContains(t, err, "unknown email template")
+}
+
+func TestRenderEveryTemplate(t *testing.T) {
+	templates, err := LoadTemplates(DefaultLocale)
+	require.NoError(t, err)
+
+	booking := BookingData{
+		EventName:  "Charity run",
+		EventPlace: "Kyiv",
+		EventDate:  time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	status := EventStatusData{
+		EventName:  "Charity run",
+		EventPlace: "Kyiv",
+		EventDate:  time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		NewDate:    time.Date(2024, 5, 8, 10, 0, 0, 0, time.UTC),
+	}
+	data := map[string]any{
+		TemplateDonationThankYou:    DonationThankYouData{GoalTitle: "Clean water", Amount: "25.00 USD"},
+		TemplateBookingConfirmation: booking,
+		TemplateBookingCancellation: booking,
+		TemplateGoalFunded:          GoalFundedData{GoalTitle: "Clean water", Amount: "1,000.00 USD"},
+		TemplateEventCancelled:      status,
+		TemplateEventPostponed:      status,
+		TemplateEventRescheduled:    status,
+		TemplateGuestBooking: GuestBookingData{
+			EventName:  "Charity run",
+			EventPlace: "Kyiv",
+			EventDate:  time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			PartySize:  3,
+			ConfirmURL: "https://charity.example.com/guest-bookings/confirm?code=G1.1.2.signature",
+		},
+	}
+
+	for _, locale := range Locales() {
+		for _, name := range templateNames {
+			message, err := templates.Render(name, locale, "donor@example.com", data[name])
+			require.NoError(t, err, "%s/%s", locale, name)
+			require.NotEmpty(t, message.Subject, "%s/%s", locale, name)
+			require.NotContains(t, message.Subject, "\n", "%s/%s", locale, name)
+			require.NotEmpty(t, message.Text, "%s/%s", locale, name)
+			require.NotEmpty(t, message.HTML, "%s/%s", locale, name)
+		}
+	}
+
+	message, err := templates.Render(TemplateBookingConfirmation, "en", "donor@example.com", booking)
+	require.NoError(t, err)
+	// Recipients without a name are greeted without one
+	require.Contains(t, message.Text, "Hi,")
+	require.Contains(t, message.Text, "Wednesday, May 1, 2024 10:00 UTC")
+}
@@ -0,0 +1,243 @@
+package outbox
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/kholodihor/charity/db/sqlc"
+)
+
+const (
+	batchSize = 100
+
+	// claimDuration is how long claimed events are held back from other dispatchers while they are published.
+	// Events a dispatcher claimed but never marked, e.g. because its replica stopped, are published again after it.
+	claimDuration = 5 * time.Minute
+
+	// maxErrorLength caps the subscriber error stored with a failed event
+	maxErrorLength = 500
+
+	baseRetryDelay = 5 * time.Second
+	maxRetryDelay  = time.Hour
+
+	// reconnectDelay is how long to wait before listening again after the notifier failed
+	reconnectDelay = 5 * time.Second
+)
+
+// Handler handles a domain event. Events are delivered at least once, so handlers must be idempotent.
+// A handler only sees an event again if it failed, or if its success couldn't be recorded.
+type Handler func(ctx context.Context, event db.OutboxEvent) error
+
+type subscriber struct {
+	name       string
+	handler    Handler
+	eventTypes map[string]bool
+}
+
+// handles reports whether the subscriber is registered for events of eventType
+func (sub subscriber) handles(eventType string) bool {
+	return sub.eventTypes == nil || sub.eventTypes[eventType]
+}
+
+// Dispatcher publishes committed outbox events to in-process subscribers.
+// Every subscriber that handled an event is recorded, and the event is marked dispatched once
+// all of them did; if any subscriber fails, the event is published again later to the ones that failed.
+type Dispatcher struct {
+	store       db.Store
+	subscribers []subscriber
+}
+
+// NewDispatcher creates a new Dispatcher
+func NewDispatcher(store db.Store) *Dispatcher {
+	return &Dispatcher{store: store}
+}
+
+// RetryDelay returns how long to wait before publishing an event again after it failed attempts times.
+// The delay doubles with every attempt, from 5 seconds up to an hour.
+func RetryDelay(attempts int32) time.Duration {
+	delay := baseRetryDelay
+	for i := int32(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
+
+// Subscribe registers handler for the given event types, or for every event when none are given.
+// Subscribers must be registered before the dispatcher is run. The name records which events the
+// subscriber handled, so it must be unique and stay the same across releases.
+func (dispatcher *Dispatcher) Subscribe(name string, handler Handler, eventTypes ...string) {
+	for _, sub := range dispatcher.subscribers {
+		if sub.name == name {
+			panic("outbox: subscriber " + name + " registered twice")
+		}
+	}
+
+	sub := subscriber{name: name, handler: handler}
+	if len(eventTypes) > 0 {
+		sub.eventTypes = make(map[string]bool, len(eventTypes))
+		for _, eventType := range eventTypes {
+			sub.eventTypes[eventType] = true
+		}
+	}
+	dispatcher.subscribers = append(dispatcher.subscribers, sub)
+}
+
+// Publish hands event to every subscriber of its type and joins their errors
+func (dispatcher *Dispatcher) Publish(ctx context.Context, event db.OutboxEvent) error {
+	var errs []error
+	for _, sub := range dispatcher.subscribers {
+		if !sub.handles(event.EventType) {
+			continue
+		}
+		if err := sub.handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Dispatch publishes due events in batches until none are left.
+// Events are claimed first and published outside of any transaction, so subscribers are free to use the store.
+// An event is only marked dispatched after every subscriber handled it, and retries skip the
+// subscribers that already did.
+func (dispatcher *Dispatcher) Dispatch(ctx context.Context) error {
+	for {
+		events, err := dispatcher.store.ClaimDueOutboxEvents(ctx, db.ClaimDueOutboxEventsParams{
+			LockedUntil: time.Now().Add(claimDuration),
+			RowLimit:    batchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot claim outbox events: %w", err)
+		}
+
+		// Claimed events come back in no particular order
+		slices.SortFunc(events, func(a, b db.OutboxEvent) int {
+			return cmp.Compare(a.ID, b.ID)
+		})
+
+		failed := 0
+		for _, event := range events {
+			ok, err := dispatcher.dispatch(ctx, event)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				failed++
+			}
+		}
+		if failed > 0 {
+			log.Printf("%d outbox events failed and will be retried", failed)
+		}
+
+		if len(events) < batchSize {
+			return nil
+		}
+	}
+}
+
+// dispatch publishes a claimed event to the subscribers that haven't handled it yet and records the outcome.
+// It reports whether every subscriber handled it; events that failed are scheduled for another attempt
+// after RetryDelay.
+func (dispatcher *Dispatcher) dispatch(ctx context.Context, event db.OutboxEvent) (bool, error) {
+	delivered, err := dispatcher.store.ListOutboxDeliveries(ctx, event.ID)
+	if err != nil {
+		return false, fmt.Errorf("cannot list deliveries of outbox event %d: %w", event.ID, err)
+	}
+
+	var errs []error
+	for _, sub := range dispatcher.subscribers {
+		if !sub.handles(event.EventType) || slices.Contains(delivered, sub.name) {
+			continue
+		}
+		if err := sub.handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+			continue
+		}
+
+		err = dispatcher.store.RecordOutboxDelivery(ctx, db.RecordOutboxDeliveryParams{
+			EventID:    event.ID,
+			Subscriber: sub.name,
+		})
+		if err != nil {
+			return false, fmt.Errorf("cannot record delivery of outbox event %d: %w", event.ID, err)
+		}
+	}
+
+	publishErr := errors.Join(errs...)
+	if publishErr == nil {
+		return true, dispatcher.store.MarkOutboxEventDispatched(ctx, event.ID)
+	}
+	if ctx.Err() != nil {
+		// The event is published again once the claim runs out
+		return false, ctx.Err()
+	}
+
+	message := publishErr.Error()
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+	err = dispatcher.store.RecordOutboxEventFailure(ctx, db.RecordOutboxEventFailureParams{
+		ID:            event.ID,
+		NextAttemptAt: time.Now().Add(RetryDelay(event.Attempts + 1)),
+		LastError:     pgtype.Text{String: message, Valid: true},
+	})
+	return false, err
+}
+
+// Run dispatches events whenever notifier signals new ones, and every pollInterval to pick up
+// retries and anything a missed notification left behind. A nil notifier only polls.
+// It returns when ctx is done.
+func (dispatcher *Dispatcher) Run(ctx context.Context, pollInterval time.Duration, notifier Notifier) {
+	wakeups := make(chan struct{}, 1)
+	if notifier != nil {
+		go listen(ctx, notifier, wakeups)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := dispatcher.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("dispatch outbox events failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wakeups:
+		}
+	}
+}
+
+func listen(ctx context.Context, notifier Notifier, wakeups chan<- struct{}) {
+	for {
+		err := notifier.Wait(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("wait for outbox notifications failed: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(reconnectDelay):
+			}
+		}
+
+		// Wake the dispatcher up, also after a failure in case a notification was lost
+		select {
+		case wakeups <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryDelay(t *testing.T) {
+	require.Equal(t, 5*time.Second, RetryDelay(1))
+	require.Equal(t, 10*time.Second, RetryDelay(2))
+	require.Equal(t, time.Hour, RetryDelay(30))
+}
+
+func TestPublish(t *testing.T) {
+	dispatcher := NewDispatcher(nil)
+
+	var received []string
+	record := func(name string, err error) Handler {
+		return func(_ context.Context, event db.OutboxEvent) error {
+			received = append(received, name+":"+event.EventType)
+			return err
+		}
+	}
+	dispatcher.Subscribe("all", record("all", nil))
+	dispatcher.Subscribe("donations", record("donations", nil), db.EventDonationCreated)
+	dispatcher.Subscribe("bookings", record("bookings", errors.New("boom")), db.EventBookingCreated, db.EventBookingCancelled)
+
+	err := dispatcher.Publish(context.Background(), db.OutboxEvent{EventType: db.EventDonationCreated})
+	require.NoError(t, err)
+	require.Equal(t, []string{"all:donation.created", "donations:donation.created"}, received)
+
+	received = nil
+	err = dispatcher.Publish(context.Background(), db.OutboxEvent{EventType: db.EventBookingCreated})
+	require.ErrorContains(t, err, "bookings: boom")
+	// A failing subscriber doesn't keep the others from seeing the event
+	require.Equal(t, []string{"all:booking.created", "bookings:booking.created"}, received)
+}
+
+func claimBatch(ids ...int64) func(context.Context, db.ClaimDueOutboxEventsParams) ([]db.OutboxEvent, error) {
+	return func(_ context.Context, arg db.ClaimDueOutboxEventsParams) ([]db.OutboxEvent, error) {
+		events := make([]db.OutboxEvent, len(ids))
+		for i, id := range ids {
+			events[i] = db.OutboxEvent{ID: id, Attempts: 2}
+		}
+		return events, nil
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	dispatcher := NewDispatcher(store)
+
+	var published []int64
+	dispatcher.Subscribe("record", func(_ context.Context, event db.OutboxEvent) error {
+		published = append(published, event.ID)
+		return nil
+	})
+
+	full := make([]int64, batchSize)
+	for i := range full {
+		// Claimed events are published in order whatever order they come back in
+		full[i] = int64(batchSize - i)
+	}
+
+	// Full batches are followed by another one
+	gomock.InOrder(
+		store.EXPECT().
+			ClaimDueOutboxEvents(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(ctx context.Context, arg db.ClaimDueOutboxEventsParams) ([]db.OutboxEvent, error) {
+				require.Equal(t, int32(batchSize), arg.RowLimit)
+				require.WithinDuration(t, time.Now().Add(claimDuration), arg.LockedUntil, time.Second)
+				return claimBatch(full...)(ctx, arg)
+			}),
+		store.EXPECT().ClaimDueOutboxEvents(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(claimBatch(batchSize+1)),
+	)
+	store.EXPECT().ListOutboxDeliveries(gomock.Any(), gomock.Any()).Times(batchSize + 1).Return([]string{}, nil)
+	store.EXPECT().
+		RecordOutboxDelivery(gomock.Any(), gomock.Any()).
+		Times(batchSize + 1).
+		DoAndReturn(func(_ context.Context, arg db.RecordOutboxDeliveryParams) error {
+			require.Equal(t, "record", arg.Subscriber)
+			return nil
+		})
+	store.EXPECT().MarkOutboxEventDispatched(gomock.Any(), gomock.Any()).Times(batchSize + 1).Return(nil)
+
+	require.NoError(t, dispatcher.Dispatch(context.Background()))
+	require.Len(t, published, batchSize+1)
+	for i, id := range published {
+		require.Equal(t, int64(i+1), id)
+	}
+}
+
+func TestDispatchFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	dispatcher := NewDispatcher(store)
+	dispatcher.Subscribe("fail", func(context.Context, db.OutboxEvent) error {
+		return errors.New("subscriber failed")
+	})
+
+	store.EXPECT().ClaimDueOutboxEvents(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(claimBatch(7))
+	store.EXPECT().ListOutboxDeliveries(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return([]string{}, nil)
+	store.EXPECT().RecordOutboxDelivery(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().MarkOutboxEventDispatched(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().
+		RecordOutboxEventFailure(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.RecordOutboxEventFailureParams) error {
+			require.Equal(t, int64(7), arg.ID)
+			require.Equal(t, "fail: subscriber failed", arg.LastError.String)
+			// The event already failed twice
+			require.WithinDuration(t, time.Now().Add(RetryDelay(3)), arg.NextAttemptAt, time.Second)
+			return nil
+		})
+
+	require.NoError(t, dispatcher.Dispatch(context.Background()))
+}
+
+func TestDispatchRetriesFailedSubscribers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	dispatcher := NewDispatcher(store)
+
+	var received []string
+	record := func(name string, err error) Handler {
+		return func(context.Context, db.OutboxEvent) error {
+			received = append(received, name)
+			return err
+		}
+	}
+	dispatcher.Subscribe("emails", record("emails", nil))
+	dispatcher.Subscribe("webhooks", record("webhooks", errors.New("boom")))
+	dispatcher.Subscribe("stats", record("stats", nil))
+
+	// The emails were sent on an earlier attempt
+	store.EXPECT().ClaimDueOutboxEvents(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(claimBatch(7))
+	store.EXPECT().ListOutboxDeliveries(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return([]string{"emails"}, nil)
+	store.EXPECT().
+		RecordOutboxDelivery(gomock.Any(), gomock.Eq(db.RecordOutboxDeliveryParams{EventID: 7, Subscriber: "stats"})).
+		Times(1).
+		Return(nil)
+	store.EXPECT().MarkOutboxEventDispatched(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().
+		RecordOutboxEventFailure(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.RecordOutboxEventFailureParams) error {
+			require.Equal(t, "webhooks: boom", arg.LastError.String)
+			return nil
+		})
+
+	require.NoError(t, dispatcher.Dispatch(context.Background()))
+	require.Equal(t, []string{"webhooks", "stats"}, received)
+}
+
+func TestSubscribeTwice(t *testing.T) {
+	dispatcher := NewDispatcher(nil)
+	dispatcher.Subscribe("emails", func(context.Context, db.OutboxEvent) error { return nil })
+
+	require.Panics(t, func() {
+		dispatcher.Subscribe("emails", func(context.Context, db.OutboxEvent) error { return nil })
+	})
+}
+
+type fakeNotifier struct {
+	notifications chan struct{}
+}
+
+func (notifier *fakeNotifier) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-notifier.notifications:
+		return nil
+	}
+}
+
+func TestRunWakesUpOnNotification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	dispatcher := NewDispatcher(store)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dispatched := make(chan struct{}, 10)
+	store.EXPECT().
+		ClaimDueOutboxEvents(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(context.Context, db.ClaimDueOutboxEventsParams) ([]db.OutboxEvent, error) {
+			dispatched <- struct{}{}
+			return []db.OutboxEvent{}, nil
+		})
+
+	notifier := &fakeNotifier{notifications: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		// The poll interval is long enough that only notifications trigger dispatches
+		dispatcher.Run(ctx, time.Hour, notifier)
+		close(done)
+	}()
+
+	waitForDispatch := func() {
+		select {
+		case <-dispatched:
+		case <-time.After(5 * time.Second):
+			t.Fatal("events weren't dispatched")
+		}
+	}
+
+	// Pending events are dispatched right away, then on every notification
+	waitForDispatch()
+	notifier.notifications <- struct{}{}
+	waitForDispatch()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dispatcher didn't stop after the context was cancelled")
+	}
+}
@@ -0,0 +1,29 @@
+package outbox
+
+import (
+	"context"
+
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/pubsub"
+)
+
+// Notifier blocks until new outbox events may be available
+type Notifier interface {
+	Wait(ctx context.Context) error
+}
+
+// PostgresNotifier waits for notifications on db.OutboxChannel
+type PostgresNotifier struct {
+	receiver pubsub.Receiver
+}
+
+// NewPostgresNotifier creates a new PostgresNotifier receiving from listener
+func NewPostgresNotifier(listener *pubsub.Listener) *PostgresNotifier {
+	return &PostgresNotifier{receiver: listener.Receiver(db.OutboxChannel)}
+}
+
+// Wait blocks until an outbox event is committed
+func (notifier *PostgresNotifier) Wait(ctx context.Context) error {
+	_, err := notifier.receiver.Receive(ctx)
+	return err
+}
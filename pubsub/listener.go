@@ -0,0 +1,149 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// receiverBufferSize is how many payloads a receiver holds before it starts dropping them
+	receiverBufferSize = 256
+
+	// reconnectDelay is how long to wait before connecting again after the connection failed
+	reconnectDelay = 5 * time.Second
+)
+
+// ErrNotificationsLost is returned by Receive when notifications may have been dropped,
+// because the connection was lost or the receiver fell behind
+var ErrNotificationsLost = errors.New("notifications may have been lost")
+
+// Receiver receives the payloads of notifications
+type Receiver interface {
+	Receive(ctx context.Context) (string, error)
+}
+
+// Listener receives Postgres notifications using LISTEN on a dedicated connection, outside of the
+// connection pool, so listening never holds connections that queries need. One connection serves
+// every channel. Every replica listening on a channel receives every notification.
+type Listener struct {
+	connString string
+	receivers  map[string][]*receiver
+}
+
+// NewListener creates a new Listener connecting to the database at connString
+func NewListener(connString string) *Listener {
+	return &Listener{
+		connString: connString,
+		receivers:  make(map[string][]*receiver),
+	}
+}
+
+// Receiver returns a new Receiver of the notifications on channel. Each receiver gets every notification
+// and isn't safe for concurrent use. Receivers must be created before the listener is run.
+func (listener *Listener) Receiver(channel string) Receiver {
+	r := &receiver{
+		payloads: make(chan string, receiverBufferSize),
+		lost:     make(chan struct{}, 1),
+	}
+	listener.receivers[channel] = append(listener.receivers[channel], r)
+	return r
+}
+
+// Run listens on every channel with a receiver until ctx is done, connecting again after errors.
+// Notifications sent while reconnecting are lost, so every receiver returns ErrNotificationsLost
+// once the connection is back.
+func (listener *Listener) Run(ctx context.Context) {
+	reconnected := false
+	for {
+		err := listener.listen(ctx, reconnected)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("listen for notifications failed: %v", err)
+		reconnected = true
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (listener *Listener) listen(ctx context.Context, reconnected bool) error {
+	conn, err := pgx.Connect(ctx, listener.connString)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	for channel := range listener.receivers {
+		_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize())
+		if err != nil {
+			return err
+		}
+	}
+
+	if reconnected {
+		for _, receivers := range listener.receivers {
+			for _, r := range receivers {
+				r.markLost()
+			}
+		}
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		for _, r := range listener.receivers[notification.Channel] {
+			r.deliver(notification.Payload)
+		}
+	}
+}
+
+// receiver buffers the payloads of one channel for a single consumer
+type receiver struct {
+	payloads chan string
+	lost     chan struct{}
+}
+
+// Receive blocks until a notification arrives and returns its payload.
+// It returns ErrNotificationsLost once after notifications may have been dropped.
+func (r *receiver) Receive(ctx context.Context) (string, error) {
+	select {
+	case <-r.lost:
+		return "", ErrNotificationsLost
+	default:
+	}
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-r.lost:
+		return "", ErrNotificationsLost
+	case payload := <-r.payloads:
+		return payload, nil
+	}
+}
+
+// deliver hands payload to the consumer without blocking the connection, which all channels share
+func (r *receiver) deliver(payload string) {
+	select {
+	case r.payloads <- payload:
+	default:
+		r.markLost()
+	}
+}
+
+func (r *receiver) markLost() {
+	select {
+	case r.lost <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,46 @@
+package pubsub
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiver(t *testing.T) {
+	listener := NewListener("")
+	first := listener.Receiver("donations").(*receiver)
+	second := listener.Receiver("donations").(*receiver)
+	require.Len(t, listener.receivers["donations"], 2)
+
+	first.deliver("1")
+	second.deliver("1")
+	for _, r := range []*receiver{first, second} {
+		payload, err := r.Receive(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "1", payload)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := first.Receive(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestReceiverFallsBehind(t *testing.T) {
+	r := NewListener("").Receiver("donations").(*receiver)
+
+	for i := 0; i <= receiverBufferSize; i++ {
+		r.deliver(strconv.Itoa(i))
+	}
+
+	// The consumer learns that it missed a payload before reading the buffered ones
+	_, err := r.Receive(context.Background())
+	require.ErrorIs(t, err, ErrNotificationsLost)
+
+	payload, err := r.Receive(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "0", payload)
+}
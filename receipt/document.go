@@ -0,0 +1,46 @@
+package receipt
+
+import (
+	"time"
+
+	"github.com/kholodihor/charity/util"
+)
+
+// Organization identifies the charity issuing the receipt
+type Organization struct {
+	Name    string
+	Address string
+	TaxID   string
+	Email   string
+}
+
+// Line is a single donation listed on a receipt
+type Line struct {
+	Date        time.Time
+	Description string
+	Amount      int64 // in cents
+}
+
+// Document holds everything printed on a receipt
+type Document struct {
+	Number       string
+	Title        string
+	IssuedAt     time.Time
+	Organization Organization
+	DonorName    string
+	DonorEmail   string
+	Lines        []Line
+	Total        int64 // in cents, as stated when the receipt was issued
+	Currency     string
+}
+
+// FormatAmount formats an amount in cents in the receipt currency
+func (doc Document) FormatAmount(amount int64) string {
+	return util.FormatCents(amount, doc.Currency)
+}
+
+// TaxStatement returns the statement printed on every receipt for the donor's tax records
+func (doc Document) TaxStatement() string {
+	return "No goods or services were provided in exchange for these contributions. " +
+		"Please keep this receipt for your tax records."
+}
@@ -0,0 +1,51 @@
+package receipt
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlTemplate = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} {{.Number}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: 0.4em; text-align: left; }
+td.amount, th.amount { text-align: right; }
+.muted { color: #666; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>{{.Organization.Name}}</h1>
+<p class="muted">
+{{- with .Organization.Address}}{{.}}<br>{{end}}
+{{- with .Organization.TaxID}}Tax ID: {{.}}<br>{{end}}
+{{- with .Organization.Email}}{{.}}{{end}}
+</p>
+<h2>{{.Title}}</h2>
+<p>
+Receipt number: <strong>{{.Number}}</strong><br>
+Issued: {{.IssuedAt.Format "January 2, 2006"}}<br>
+Donor: {{.DonorName}}{{with .DonorEmail}} &lt;{{.}}&gt;{{end}}
+</p>
+<table>
+<thead><tr><th>Date</th><th>Description</th><th class="amount">Amount</th></tr></thead>
+<tbody>
+{{- range .Lines}}
+<tr><td>{{.Date.Format "2006-01-02"}}</td><td>{{.Description}}</td><td class="amount">{{$.FormatAmount .Amount}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot><tr><th colspan="2">Total</th><th class="amount">{{.FormatAmount .Total}}</th></tr></tfoot>
+</table>
+<p class="muted">{{.TaxStatement}}</p>
+</body>
+</html>
+`))
+
+// RenderHTML writes the receipt as an HTML page
+func RenderHTML(w io.Writer, doc Document) error {
+	return htmlTemplate.Execute(w, doc)
+}
@@ -0,0 +1,75 @@
+package receipt
+
+import (
+	"io"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// RenderPDF writes the receipt as an A4 PDF document
+func RenderPDF(w io.Writer, doc Document) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(doc.Title+" "+doc.Number, true)
+	pdf.SetAuthor(doc.Organization.Name, true)
+	pdf.AddPage()
+
+	// Core fonts only cover cp1252, so translate the UTF-8 text
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, tr(doc.Organization.Name), "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(100, 100, 100)
+	for _, line := range []string{doc.Organization.Address, taxIDLine(doc.Organization.TaxID), doc.Organization.Email} {
+		if line != "" {
+			pdf.CellFormat(0, 5, tr(line), "", 1, "L", false, 0, "")
+		}
+	}
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(6)
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 8, tr(doc.Title), "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, 6, tr("Receipt number: "+doc.Number), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, "Issued: "+doc.IssuedAt.Format("January 2, 2006"), "", 1, "L", false, 0, "")
+	donor := doc.DonorName
+	if doc.DonorEmail != "" {
+		donor += " <" + doc.DonorEmail + ">"
+	}
+	pdf.CellFormat(0, 6, tr("Donor: "+donor), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.SetFillColor(240, 240, 240)
+	pdf.CellFormat(35, 8, "Date", "B", 0, "L", true, 0, "")
+	pdf.CellFormat(100, 8, "Description", "B", 0, "L", true, 0, "")
+	pdf.CellFormat(45, 8, "Amount", "B", 1, "R", true, 0, "")
+
+	pdf.SetFont("Helvetica", "", 11)
+	for _, line := range doc.Lines {
+		pdf.CellFormat(35, 7, line.Date.Format("2006-01-02"), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(100, 7, tr(line.Description), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(45, 7, doc.FormatAmount(line.Amount), "B", 1, "R", false, 0, "")
+	}
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(135, 8, "Total", "", 0, "L", false, 0, "")
+	pdf.CellFormat(45, 8, doc.FormatAmount(doc.Total), "", 1, "R", false, 0, "")
+	pdf.Ln(8)
+
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.MultiCell(0, 5, doc.TaxStatement(), "", "L", false)
+
+	return pdf.Output(w)
+}
+
+func taxIDLine(taxID string) string {
+	if taxID == "" {
+		return ""
+	}
+	return "Tax ID: " + taxID
+}
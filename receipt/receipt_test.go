@@ -0,0 +1,57 @@
+package receipt
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testDocument() Document {
+	date := time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)
+	return Document{
+		Number:   "2024-000042",
+		Title:    "Donation Receipt",
+		IssuedAt: date,
+		Organization: Organization{
+			Name:    "Charity Foundation",
+			Address: "1 Main Street",
+			TaxID:   "12-3456789",
+			Email:   "receipts@charity.example",
+		},
+		DonorName:  "Олена <Test>",
+		DonorEmail: "donor@email.com",
+		Lines: []Line{
+			{Date: date, Description: "Donation to Shelter", Amount: 123456},
+			{Date: date, Description: "Donation to School", Amount: 44},
+		},
+		Total:    123500,
+		Currency: "USD",
+	}
+}
+
+func TestDocumentFormatAmount(t *testing.T) {
+	doc := testDocument()
+	require.Equal(t, "1,235.00 USD", doc.FormatAmount(doc.Total))
+}
+
+func TestRenderHTML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderHTML(&buf, testDocument()))
+
+	html := buf.String()
+	require.Contains(t, html, "2024-000042")
+	require.Contains(t, html, "Tax ID: 12-3456789")
+	require.Contains(t, html, "1,234.56 USD")
+	require.Contains(t, html, "0.44 USD")
+	require.Contains(t, html, "1,235.00 USD")
+	// Donor details are escaped
+	require.Contains(t, html, "Олена &lt;Test&gt;")
+}
+
+func TestRenderPDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderPDF(&buf, testDocument()))
+	require.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+}
@@ -0,0 +1,106 @@
+// Package recurrence expands RFC 5545 recurrence rules (RRULE) into the start times of
+// event occurrences.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// ErrInvalidRule is returned for rules that can't be parsed or aren't supported
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// Rule is a parsed RRULE. The first occurrence and the time of day of every occurrence
+// come from the start of the series, so rules repeat at most daily.
+type Rule struct {
+	option rrule.ROption
+}
+
+// Parse parses the value of an RRULE property, such as "FREQ=WEEKLY;BYDAY=MO,WE",
+// with or without the "RRULE:" name
+func Parse(value string) (Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" || strings.ContainsAny(value, "\r\n") {
+		return Rule{}, ErrInvalidRule
+	}
+
+	option, err := rrule.StrToROption(value)
+	if err != nil {
+		return Rule{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+
+	switch {
+	case option.Freq > rrule.DAILY:
+		return Rule{}, fmt.Errorf("%w: FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY", ErrInvalidRule)
+	case !option.Dtstart.IsZero():
+		return Rule{}, fmt.Errorf("%w: DTSTART is the start of the series", ErrInvalidRule)
+	case len(option.Byhour) > 0 || len(option.Byminute) > 0 || len(option.Bysecond) > 0:
+		return Rule{}, fmt.Errorf("%w: the time of day is taken from the start of the series", ErrInvalidRule)
+	case option.Interval < 0 || option.Count < 0:
+		return Rule{}, fmt.Errorf("%w: INTERVAL and COUNT must be positive", ErrInvalidRule)
+	case option.Count > 0 && !option.Until.IsZero():
+		return Rule{}, fmt.Errorf("%w: COUNT and UNTIL can't be combined", ErrInvalidRule)
+	}
+
+	if _, err := rrule.NewRRule(*option); err != nil {
+		return Rule{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+
+	return Rule{option: *option}, nil
+}
+
+// String returns the rule as the value of an RRULE property
+func (r Rule) String() string {
+	return r.option.RRuleString()
+}
+
+// Between returns the occurrences of a series starting at start that fall in [from, to)
+func (r Rule) Between(start, from, to time.Time) []time.Time {
+	if !from.Before(to) {
+		return nil
+	}
+
+	occurrences := r.build(start).Between(from, to, true)
+	if n := len(occurrences); n > 0 && !occurrences[n-1].Before(to) {
+		occurrences = occurrences[:n-1]
+	}
+	return occurrences
+}
+
+// Until returns the rule of a series starting at start cut short so its last
+// occurrence is before end
+func (r Rule) Until(start, end time.Time) Rule {
+	option := r.option
+	if option.Count > 0 {
+		option.Count = min(option.Count, len(r.Between(start, start, end)))
+		return Rule{option: option}
+	}
+
+	end = end.Add(-time.Second)
+	if option.Until.IsZero() || end.Before(option.Until) {
+		option.Until = end
+	}
+	return Rule{option: option}
+}
+
+// From returns the rule of the rest of a series starting at start, as a new series
+// starting at from. A COUNT is reduced by the occurrences before from.
+func (r Rule) From(start, from time.Time) Rule {
+	option := r.option
+	if option.Count > 0 {
+		option.Count = max(option.Count-len(r.Between(start, start, from)), 1)
+	}
+	return Rule{option: option}
+}
+
+func (r Rule) build(start time.Time) *rrule.RRule {
+	option := r.option
+	option.Dtstart = start
+	// Parse validated the options, so this can't fail
+	rule, _ := rrule.NewRRule(option)
+	return rule
+}
@@ -0,0 +1,95 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// start is a Monday
+var start = time.Date(2024, time.January, 1, 18, 0, 0, 0, time.UTC)
+
+func days(n ...int) []time.Time {
+	times := make([]time.Time, len(n))
+	for i, d := range n {
+		times[i] = start.AddDate(0, 0, d)
+	}
+	return times
+}
+
+func TestParse(t *testing.T) {
+	rule, err := Parse("RRULE:FREQ=WEEKLY;BYDAY=MO,WE")
+	require.NoError(t, err)
+	require.Equal(t, "FREQ=WEEKLY;BYDAY=MO,WE", rule.String())
+
+	for _, value := range []string{
+		"",
+		"BYDAY=MO",
+		"FREQ=FORTNIGHTLY",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;COUNT=3;UNTIL=20240110T000000Z",
+		"FREQ=DAILY;INTERVAL=-1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"DTSTART:20240101T000000Z\nRRULE:FREQ=DAILY",
+	} {
+		_, err := Parse(value)
+		require.ErrorIs(t, err, ErrInvalidRule, value)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=MO,WE")
+	require.NoError(t, err)
+
+	require.Equal(t, days(0, 2, 7, 9), rule.Between(start, start, start.AddDate(0, 0, 14)))
+	// from is inclusive and to exclusive
+	require.Equal(t, days(2, 7), rule.Between(start, start.AddDate(0, 0, 2), start.AddDate(0, 0, 9)))
+	require.Empty(t, rule.Between(start, start, start))
+
+	rule, err = Parse("FREQ=DAILY;COUNT=3")
+	require.NoError(t, err)
+	require.Equal(t, days(0, 1, 2), rule.Between(start, start, start.AddDate(1, 0, 0)))
+}
+
+func TestBetweenKeepsLocalTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	rule, err := Parse("FREQ=WEEKLY;COUNT=3")
+	require.NoError(t, err)
+
+	// Daylight saving time starts in Berlin on March 31, 2024
+	local := time.Date(2024, time.March, 24, 10, 0, 0, 0, berlin)
+	occurrences := rule.Between(local, local, local.AddDate(0, 1, 0))
+	require.Len(t, occurrences, 3)
+	for _, occurrence := range occurrences {
+		require.Equal(t, 10, occurrence.In(berlin).Hour())
+	}
+	require.Equal(t, 9, occurrences[0].UTC().Hour())
+	require.Equal(t, 8, occurrences[2].UTC().Hour())
+}
+
+func TestUntil(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY")
+	require.NoError(t, err)
+
+	ended := rule.Until(start, start.AddDate(0, 0, 3))
+	require.Equal(t, "FREQ=DAILY;UNTIL=20240104T175959Z", ended.String())
+	require.Equal(t, days(0, 1, 2), ended.Between(start, start, start.AddDate(1, 0, 0)))
+
+	rule, err = Parse("FREQ=DAILY;COUNT=10")
+	require.NoError(t, err)
+	require.Equal(t, "FREQ=DAILY;COUNT=3", rule.Until(start, start.AddDate(0, 0, 3)).String())
+}
+
+func TestFrom(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;COUNT=10")
+	require.NoError(t, err)
+
+	from := start.AddDate(0, 0, 4)
+	rest := rule.From(start, from)
+	require.Equal(t, "FREQ=DAILY;COUNT=6", rest.String())
+	require.Equal(t, rule.Between(start, from, start.AddDate(1, 0, 0)), rest.Between(from, from, start.AddDate(1, 0, 0)))
+}
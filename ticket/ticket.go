@@ -0,0 +1,119 @@
+// Package ticket issues signed event tickets that door staff scan as QR codes.
+package ticket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	minSecretKeySize = 32
+	// codePrefix versions the code format
+	codePrefix = "T1"
+	// confirmationPrefix versions the format of guest booking confirmation codes, and keeps them
+	// from being scanned as tickets
+	confirmationPrefix = "G1"
+	// signatureSize is how many bytes of the HMAC are kept, enough to make forging
+	// infeasible while keeping QR codes small
+	signatureSize = 16
+)
+
+// ErrInvalidTicket is returned for codes that weren't issued by the Signer
+var ErrInvalidTicket = errors.New("invalid ticket")
+
+// ErrInvalidConfirmation is returned for confirmation codes that weren't issued by the Signer
+var ErrInvalidConfirmation = errors.New("invalid confirmation code")
+
+// Ticket identifies the booking a ticket admits
+type Ticket struct {
+	BookingID int64
+	EventID   int64
+}
+
+// Signer issues and verifies ticket codes
+type Signer struct {
+	secretKey []byte
+}
+
+// NewSigner creates a Signer with a secret key of at least 32 characters
+func NewSigner(secretKey string) (*Signer, error) {
+	if len(secretKey) < minSecretKeySize {
+		return nil, fmt.Errorf("invalid key size: must be at least %d characters", minSecretKeySize)
+	}
+	return &Signer{secretKey: []byte(secretKey)}, nil
+}
+
+// Code returns the ticket code of ticket, of the form "T1.<booking id>.<event id>.<signature>"
+func (signer *Signer) Code(ticket Ticket) string {
+	return signer.code(codePrefix, ticket)
+}
+
+// Parse verifies code and returns the ticket it was issued for
+func (signer *Signer) Parse(code string) (Ticket, error) {
+	ticket, ok := signer.parse(codePrefix, code)
+	if !ok {
+		return Ticket{}, ErrInvalidTicket
+	}
+	return ticket, nil
+}
+
+// ConfirmationCode returns the code that confirms the email of a guest booking, of the form
+// "G1.<booking id>.<event id>.<signature>". It also lets the guest see and cancel the booking.
+func (signer *Signer) ConfirmationCode(booking Ticket) string {
+	return signer.code(confirmationPrefix, booking)
+}
+
+// ParseConfirmation verifies a confirmation code and returns the guest booking it was issued for
+func (signer *Signer) ParseConfirmation(code string) (Ticket, error) {
+	booking, ok := signer.parse(confirmationPrefix, code)
+	if !ok {
+		return Ticket{}, ErrInvalidConfirmation
+	}
+	return booking, nil
+}
+
+func (signer *Signer) code(prefix string, ticket Ticket) string {
+	payload := fmt.Sprintf("%s.%d.%d", prefix, ticket.BookingID, ticket.EventID)
+	return payload + "." + signer.sign(payload)
+}
+
+func (signer *Signer) parse(prefix string, code string) (Ticket, bool) {
+	parts := strings.Split(strings.TrimSpace(code), ".")
+	if len(parts) != 4 || parts[0] != prefix {
+		return Ticket{}, false
+	}
+
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(signer.sign(payload))) {
+		return Ticket{}, false
+	}
+
+	bookingID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return Ticket{}, false
+	}
+	eventID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return Ticket{}, false
+	}
+
+	return Ticket{BookingID: bookingID, EventID: eventID}, true
+}
+
+func (signer *Signer) sign(payload string) string {
+	mac := hmac.New(sha256.New, signer.secretKey)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:signatureSize])
+}
+
+// QRCode renders code as a size x size pixel PNG QR code
+func QRCode(code string, size int) ([]byte, error) {
+	return qrcode.Encode(code, qrcode.Medium, size)
+}
@@ -0,0 +1,88 @@
+package ticket
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	signer, err := NewSigner(util.RandomString(32))
+	require.NoError(t, err)
+
+	issued := Ticket{BookingID: util.RandomInt(1, 1000), EventID: util.RandomInt(1, 1000)}
+	code := signer.Code(issued)
+	require.True(t, strings.HasPrefix(code, "T1."))
+
+	parsed, err := signer.Parse(code)
+	require.NoError(t, err)
+	require.Equal(t, issued, parsed)
+
+	// Scanners may add surrounding whitespace
+	parsed, err = signer.Parse(" " + code + "\n")
+	require.NoError(t, err)
+	require.Equal(t, issued, parsed)
+}
+
+func TestSignerRejectsForgedCodes(t *testing.T) {
+	signer, err := NewSigner(util.RandomString(32))
+	require.NoError(t, err)
+	other, err := NewSigner(util.RandomString(32))
+	require.NoError(t, err)
+
+	code := signer.Code(Ticket{BookingID: 7, EventID: 3})
+	signature := code[strings.LastIndex(code, ".")+1:]
+
+	for _, forged := range []string{
+		"",
+		"T1.7.3",
+		"T1.8.3." + signature,
+		"T1.7.4." + signature,
+		"T2.7.3." + signature,
+		code + "x",
+		other.Code(Ticket{BookingID: 7, EventID: 3}),
+	} {
+		_, err := signer.Parse(forged)
+		require.ErrorIs(t, err, ErrInvalidTicket, forged)
+	}
+}
+
+func TestSignerConfirmationCode(t *testing.T) {
+	signer, err := NewSigner(util.RandomString(32))
+	require.NoError(t, err)
+
+	booking := Ticket{BookingID: util.RandomInt(1, 1000), EventID: util.RandomInt(1, 1000)}
+	code := signer.ConfirmationCode(booking)
+	require.True(t, strings.HasPrefix(code, "G1."))
+
+	parsed, err := signer.ParseConfirmation(code)
+	require.NoError(t, err)
+	require.Equal(t, booking, parsed)
+
+	// Confirmation codes and tickets can't stand in for each other
+	_, err = signer.Parse(code)
+	require.ErrorIs(t, err, ErrInvalidTicket)
+	_, err = signer.ParseConfirmation(signer.Code(booking))
+	require.ErrorIs(t, err, ErrInvalidConfirmation)
+	_, err = signer.ParseConfirmation("G1" + strings.TrimPrefix(signer.Code(booking), "T1"))
+	require.ErrorIs(t, err, ErrInvalidConfirmation)
+}
+
+func TestNewSignerShortKey(t *testing.T) {
+	_, err := NewSigner(util.RandomString(31))
+	require.Error(t, err)
+}
+
+func TestQRCode(t *testing.T) {
+	data, err := QRCode("T1.7.3.signature", 256)
+	require.NoError(t, err)
+
+	image, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, 256, image.Bounds().Dx())
+	require.Equal(t, 256, image.Bounds().Dy())
+}
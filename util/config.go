@@ -6,6 +6,34 @@ import (
 	"github.com/spf13/viper"
 )
 
+// Supported rate limiter backends
+const (
+	RateLimitBackendMemory = "memory"
+	RateLimitBackendRedis  = "redis"
+)
+
+// Supported anonymous donation challenge providers
+const (
+	ChallengeProviderNone        = "none"
+	ChallengeProviderProofOfWork = "pow"
+	ChallengeProviderHCaptcha    = "hcaptcha"
+	ChallengeProviderTurnstile   = "turnstile"
+)
+
+// Supported backends for remembering solved proof-of-work challenges
+const (
+	ChallengeBackendMemory = "memory"
+	ChallengeBackendRedis  = "redis"
+)
+
+// Supported email drivers
+const (
+	MailDriverNone = "none"
+	MailDriverLog  = "log"
+	MailDriverFile = "file"
+	MailDriverSMTP = "smtp"
+)
+
 // Config stores all configuration of the application.
 // The values are read by viper from a config file or environment variable.
 type Config struct {
@@ -22,6 +50,71 @@ type Config struct {
 	
 	// Rate limiting
 	RateLimitPerMinute   int           `mapstructure:"RATE_LIMIT_PER_MINUTE"`
+	RateLimitBurst       int           `mapstructure:"RATE_LIMIT_BURST"`
+	RateLimitPolicies    string        `mapstructure:"RATE_LIMIT_POLICIES"`
+	RateLimitBackend     string        `mapstructure:"RATE_LIMIT_BACKEND"`
+	RedisAddress         string        `mapstructure:"REDIS_ADDRESS"`
+
+	// Client IP resolution behind reverse proxies
+	TrustedProxies       []string      `mapstructure:"TRUSTED_PROXIES"`
+	RemoteIPHeaders      []string      `mapstructure:"REMOTE_IP_HEADERS"`
+
+	// Anonymous donation challenge
+	ChallengeProvider    string        `mapstructure:"CHALLENGE_PROVIDER"`
+	ChallengeSecret      string        `mapstructure:"CHALLENGE_SECRET"`
+	ChallengeThreshold   int64         `mapstructure:"CHALLENGE_THRESHOLD"`
+	ChallengeDifficulty  int           `mapstructure:"CHALLENGE_DIFFICULTY"`
+	ChallengeDuration    time.Duration `mapstructure:"CHALLENGE_DURATION"`
+	ChallengeBackend     string        `mapstructure:"CHALLENGE_BACKEND"`
+
+	// Organization details printed on donation receipts
+	OrganizationName     string        `mapstructure:"ORGANIZATION_NAME"`
+	OrganizationAddress  string        `mapstructure:"ORGANIZATION_ADDRESS"`
+	OrganizationTaxID    string        `mapstructure:"ORGANIZATION_TAX_ID"`
+	OrganizationEmail    string        `mapstructure:"ORGANIZATION_EMAIL"`
+	Currency             string        `mapstructure:"CURRENCY"`
+
+	// Background refresh of the admin dashboard summaries
+	DashboardRefreshInterval time.Duration `mapstructure:"DASHBOARD_REFRESH_INTERVAL"`
+
+	// How long public leaderboards are cached
+	LeaderboardCacheTTL  time.Duration `mapstructure:"LEADERBOARD_CACHE_TTL"`
+
+	// Outbox dispatcher fallback polling, new events also wake it up through LISTEN/NOTIFY
+	OutboxPollInterval   time.Duration `mapstructure:"OUTBOX_POLL_INTERVAL"`
+
+	// Webhook delivery worker
+	WebhookPollInterval  time.Duration `mapstructure:"WEBHOOK_POLL_INTERVAL"`
+	WebhookTimeout       time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	WebhookMaxAttempts   int           `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+
+	// Event reminders: how often due reminders are scheduled and how long before an event they are sent
+	EventReminderInterval time.Duration   `mapstructure:"EVENT_REMINDER_INTERVAL"`
+	EventReminderOffsets  []time.Duration `mapstructure:"EVENT_REMINDER_OFFSETS"`
+
+	// Email notifications
+	MailDriver           string        `mapstructure:"MAIL_DRIVER"`
+	MailFrom             string        `mapstructure:"MAIL_FROM"`
+	MailDir              string        `mapstructure:"MAIL_DIR"`
+	MailQueueSize        int           `mapstructure:"MAIL_QUEUE_SIZE"`
+	MailWorkers          int           `mapstructure:"MAIL_WORKERS"`
+	MailTimeout          time.Duration `mapstructure:"MAIL_TIMEOUT"`
+	SMTPHost             string        `mapstructure:"SMTP_HOST"`
+	SMTPPort             int           `mapstructure:"SMTP_PORT"`
+	SMTPUsername         string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword         string        `mapstructure:"SMTP_PASSWORD"`
+
+	// Public base URL of the API, used in calendar feed links and event UIDs
+	PublicURL            string        `mapstructure:"PUBLIC_URL"`
+
+	// Key signing event tickets, defaults to TOKEN_SYMMETRIC_KEY
+	TicketSecret         string        `mapstructure:"TICKET_SECRET"`
+	// How long before an event paid tickets stop being refunded on cancellation
+	TicketRefundCutoff   time.Duration `mapstructure:"TICKET_REFUND_CUTOFF"`
+
+	// Recurring event series: how far ahead occurrences are created and how often
+	EventSeriesHorizon   time.Duration `mapstructure:"EVENT_SERIES_HORIZON"`
+	EventSeriesInterval  time.Duration `mapstructure:"EVENT_SERIES_INTERVAL"`
 }
 
 // LoadConfig reads configuration from file or environment variables.
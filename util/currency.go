@@ -0,0 +1,66 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatCents formats an amount in the smallest currency unit, e.g. 123456 USD as "1,234.56 USD"
+func FormatCents(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	units := strconv.FormatInt(amount/100, 10)
+
+	// Group the whole units by thousands
+	var sb strings.Builder
+	for i, digit := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(digit)
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s%s.%02d %s", sign, sb.String(), amount%100, currency))
+}
+
+// ErrInvalidAmount is returned by ParseCents for text that isn't a decimal amount
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// ParseCents parses a decimal amount such as "1234.5" into the smallest currency unit (123450).
+// At most two decimal places are allowed and thousand separators are not.
+func ParseCents(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	units, fraction, hasFraction := strings.Cut(s, ".")
+	if units == "" || len(fraction) > 2 || (hasFraction && fraction == "") {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	for _, part := range []string{units, fraction} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+			}
+		}
+	}
+
+	whole, err := strconv.ParseInt(units, 10, 64)
+	if err != nil || whole > (1<<63-1)/100-1 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	cents := int64(0)
+	if fraction != "" {
+		cents, _ = strconv.ParseInt(fraction, 10, 64)
+		if len(fraction) == 1 {
+			cents *= 10
+		}
+	}
+
+	return whole*100 + cents, nil
+}
@@ -0,0 +1,28 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatCents(t *testing.T) {
+	require.Equal(t, "0.05 USD", FormatCents(5, "USD"))
+	require.Equal(t, "1,234,567.89 EUR", FormatCents(123456789, "EUR"))
+	require.Equal(t, "-10.00", FormatCents(-1000, ""))
+}
+
+func TestParseCents(t *testing.T) {
+	valid := map[string]int64{"0": 0, "1": 100, "1.5": 150, "12.34": 1234, " 7.05 ": 705}
+	for input, expected := range valid {
+		cents, err := ParseCents(input)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, cents, input)
+	}
+
+	for _, input := range []string{"", "1.", ".5", "1.234", "-1", "1,000", "abc", strings.Repeat("9", 20)} {
+		_, err := ParseCents(input)
+		require.ErrorIs(t, err, ErrInvalidAmount, input)
+	}
+}
@@ -0,0 +1,8 @@
+package util
+
+// User roles
+const (
+	DonorRole = "donor"
+	StaffRole = "staff"
+	AdminRole = "admin"
+)
@@ -0,0 +1,35 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	// Embedded so time zones load on hosts without a zoneinfo database
+	_ "time/tzdata"
+)
+
+// ErrInvalidTimeZone is returned by LoadTimeZone for names that aren't IANA time zones
+var ErrInvalidTimeZone = errors.New("invalid time zone")
+
+// LoadTimeZone loads an IANA time zone such as "Europe/Kyiv". Unlike time.LoadLocation it
+// rejects "" and "Local", which depend on the server rather than name a place.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeZone, name)
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeZone, name)
+	}
+	return location, nil
+}
+
+// InTimeZone returns t in the named time zone, or in UTC if the zone can't be loaded
+func InTimeZone(t time.Time, name string) time.Time {
+	location, err := LoadTimeZone(name)
+	if err != nil {
+		return t.UTC()
+	}
+	return t.In(location)
+}
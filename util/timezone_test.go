@@ -0,0 +1,29 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTimeZone(t *testing.T) {
+	location, err := LoadTimeZone("Europe/Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Kyiv", location.String())
+
+	for _, name := range []string{"", "Local", "Europe/Atlantis", "+02:00"} {
+		_, err := LoadTimeZone(name)
+		require.ErrorIs(t, err, ErrInvalidTimeZone, name)
+	}
+}
+
+func TestInTimeZone(t *testing.T) {
+	start := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	local := InTimeZone(start, "Europe/Kyiv")
+	require.True(t, start.Equal(local))
+	require.Equal(t, "2024-07-01T12:00:00+03:00", local.Format(time.RFC3339))
+
+	require.Equal(t, "2024-07-01T09:00:00Z", InTimeZone(start, "Nowhere").Format(time.RFC3339))
+}
@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSecret(t *testing.T) {
+	secret1, err := GenerateSecret()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(secret1, secretPrefix))
+	require.Len(t, secret1, len(secretPrefix)+64)
+
+	secret2, err := GenerateSecret()
+	require.NoError(t, err)
+	require.NotEqual(t, secret1, secret2)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	body := []byte(`{"id":1,"type":"donation.created"}`)
+	now := time.Now()
+	header := Sign(secret, now, body)
+	require.True(t, strings.HasPrefix(header, "t="))
+	require.Contains(t, header, ",v1=")
+
+	require.NoError(t, Verify(secret, header, body, 5*time.Minute, now))
+	require.NoError(t, Verify(secret, header, body, 0, now.Add(time.Hour)))
+
+	require.ErrorIs(t, Verify("other", header, body, 5*time.Minute, now), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, header, []byte(`{"id":2}`), 5*time.Minute, now), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, header, body, 5*time.Minute, now.Add(time.Hour)), ErrExpiredSignature)
+
+	for _, invalid := range []string{"", "t=1", "v1=abc", "t=abc,v1=abc", "garbage"} {
+		require.ErrorIs(t, Verify(secret, invalid, body, 0, now), ErrInvalidSignature, invalid)
+	}
+}
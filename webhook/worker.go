@@ -0,0 +1,203 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/kholodihor/charity/db/sqlc"
+)
+
+// Statuses of a webhook delivery
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+const (
+	batchSize = 100
+
+	baseRetryDelay = 30 * time.Second
+	maxRetryDelay  = 6 * time.Hour
+
+	// claimMargin is added to the HTTP timeout when claiming deliveries, so a delivery
+	// claimed by a worker that crashed is picked up again once the claim runs out
+	claimMargin = time.Minute
+
+	maxErrorLength = 500
+)
+
+// Envelope is the JSON body of a delivery
+type Envelope struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Worker queues deliveries of outbox events to subscribed endpoints and sends them
+type Worker struct {
+	store       db.Store
+	client      *http.Client
+	timeout     time.Duration
+	maxAttempts int
+	now         func() time.Time
+}
+
+// NewWorker creates a new Worker. A delivery is marked failed after maxAttempts unsuccessful attempts.
+func NewWorker(store db.Store, timeout time.Duration, maxAttempts int) *Worker {
+	return &Worker{
+		store:       store,
+		client:      &http.Client{Timeout: timeout},
+		timeout:     timeout,
+		maxAttempts: maxAttempts,
+		now:         time.Now,
+	}
+}
+
+// RetryDelay returns how long to wait before retrying a delivery that failed attempts times.
+// The delay doubles with every attempt, from 30 seconds up to 6 hours.
+func RetryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
+
+// FanOut queues a delivery of event for every active endpoint subscribed to its type.
+// It is an outbox.Handler; queuing the same event again is a no-op.
+func (worker *Worker) FanOut(ctx context.Context, event db.OutboxEvent) error {
+	endpoints, err := worker.store.ListWebhookEndpointsForEvent(ctx, event.EventType)
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		err = worker.store.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			EndpointID: endpoint.ID,
+			EventID:    event.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run sends every delivery that is due
+func (worker *Worker) Run(ctx context.Context) error {
+	for {
+		deliveries, err := worker.store.ClaimDueWebhookDeliveries(ctx, db.ClaimDueWebhookDeliveriesParams{
+			LockedUntil: worker.now().Add(worker.timeout + claimMargin),
+			RowLimit:    batchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot claim deliveries: %w", err)
+		}
+
+		for _, delivery := range deliveries {
+			if err := worker.Deliver(ctx, delivery); err != nil {
+				return fmt.Errorf("cannot deliver %d: %w", delivery.ID, err)
+			}
+		}
+
+		if len(deliveries) < batchSize || ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// Deliver sends a delivery once and records the outcome.
+// The returned error is only about loading or saving the delivery, failed requests are recorded and retried.
+func (worker *Worker) Deliver(ctx context.Context, delivery db.WebhookDelivery) error {
+	endpoint, err := worker.store.GetWebhookEndpoint(ctx, delivery.EndpointID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			// the endpoint was deleted along with its deliveries
+			return nil
+		}
+		return err
+	}
+
+	event, err := worker.store.GetOutboxEvent(ctx, delivery.EventID)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(Envelope{
+		ID:        event.ID,
+		Type:      event.EventType,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	statusCode, sendErr := worker.send(ctx, endpoint, delivery.ID, event.EventType, body)
+
+	now := worker.now()
+	arg := db.RecordWebhookDeliveryAttemptParams{
+		ID:            delivery.ID,
+		Status:        StatusSucceeded,
+		NextAttemptAt: now,
+	}
+	if statusCode != 0 {
+		arg.LastStatusCode = pgtype.Int4{Int32: int32(statusCode), Valid: true}
+	}
+	if sendErr != nil {
+		message := sendErr.Error()
+		if len(message) > maxErrorLength {
+			message = message[:maxErrorLength]
+		}
+		arg.LastError = pgtype.Text{String: message, Valid: true}
+
+		attempts := int(delivery.Attempts) + 1
+		if attempts >= worker.maxAttempts {
+			arg.Status = StatusFailed
+		} else {
+			arg.Status = StatusPending
+			arg.NextAttemptAt = now.Add(RetryDelay(attempts))
+		}
+	}
+
+	_, err = worker.store.RecordWebhookDeliveryAttempt(ctx, arg)
+	return err
+}
+
+func (worker *Worker) send(ctx context.Context, endpoint db.WebhookEndpoint, deliveryID int64, eventType string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "charity-webhooks/1.0")
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(deliveryID, 10))
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, worker.now(), body))
+
+	resp, err := worker.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,213 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/kholodihor/charity/db/mock"
+	db "github.com/kholodihor/charity/db/sqlc"
+	"github.com/kholodihor/charity/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryDelay(t *testing.T) {
+	require.Equal(t, 30*time.Second, RetryDelay(1))
+	require.Equal(t, time.Minute, RetryDelay(2))
+	require.Equal(t, 2*time.Minute, RetryDelay(3))
+	require.Equal(t, 6*time.Hour, RetryDelay(20))
+}
+
+func randomWebhookEndpoint(url string) db.WebhookEndpoint {
+	return db.WebhookEndpoint{
+		ID:         util.RandomInt(1, 1000),
+		Url:        url,
+		Secret:     util.RandomString(32),
+		EventTypes: []string{db.EventDonationCreated},
+		IsActive:   true,
+	}
+}
+
+func randomOutboxEvent() db.OutboxEvent {
+	return db.OutboxEvent{
+		ID:        util.RandomInt(1, 1000),
+		EventType: db.EventDonationCreated,
+		Payload:   []byte(`{"donation_id":1}`),
+		CreatedAt: time.Now().Truncate(time.Second),
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	event := randomOutboxEvent()
+
+	testCases := []struct {
+		name        string
+		statusCode  int
+		attempts    int32
+		checkRecord func(t *testing.T, arg db.RecordWebhookDeliveryAttemptParams)
+	}{
+		{
+			name:       "Succeeded",
+			statusCode: http.StatusOK,
+			checkRecord: func(t *testing.T, arg db.RecordWebhookDeliveryAttemptParams) {
+				require.Equal(t, StatusSucceeded, arg.Status)
+				require.Equal(t, int32(http.StatusOK), arg.LastStatusCode.Int32)
+				require.False(t, arg.LastError.Valid)
+			},
+		},
+		{
+			name:       "RetryLater",
+			statusCode: http.StatusInternalServerError,
+			attempts:   1,
+			checkRecord: func(t *testing.T, arg db.RecordWebhookDeliveryAttemptParams) {
+				require.Equal(t, StatusPending, arg.Status)
+				require.Equal(t, int32(http.StatusInternalServerError), arg.LastStatusCode.Int32)
+				require.True(t, arg.LastError.Valid)
+				require.WithinDuration(t, time.Now().Add(RetryDelay(2)), arg.NextAttemptAt, time.Second)
+			},
+		},
+		{
+			name:       "GiveUp",
+			statusCode: http.StatusBadRequest,
+			attempts:   2,
+			checkRecord: func(t *testing.T, arg db.RecordWebhookDeliveryAttemptParams) {
+				require.Equal(t, StatusFailed, arg.Status)
+				require.Equal(t, int32(http.StatusBadRequest), arg.LastStatusCode.Int32)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			received := make(chan *http.Request, 1)
+			var receivedBody []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedBody, _ = io.ReadAll(r.Body)
+				received <- r
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			endpoint := randomWebhookEndpoint(server.URL)
+			delivery := db.WebhookDelivery{
+				ID:         util.RandomInt(1, 1000),
+				EndpointID: endpoint.ID,
+				EventID:    event.ID,
+				Status:     StatusPending,
+				Attempts:   tc.attempts,
+			}
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+			store.EXPECT().GetOutboxEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+			store.EXPECT().
+				RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+					require.Equal(t, delivery.ID, arg.ID)
+					tc.checkRecord(t, arg)
+					return delivery, nil
+				})
+
+			worker := NewWorker(store, 5*time.Second, 3)
+			err := worker.Deliver(context.Background(), delivery)
+			require.NoError(t, err)
+
+			req := <-received
+			require.Equal(t, http.MethodPost, req.Method)
+			require.Equal(t, event.EventType, req.Header.Get(EventHeader))
+			require.Equal(t, strconv.FormatInt(delivery.ID, 10), req.Header.Get(DeliveryHeader))
+			require.NoError(t, Verify(endpoint.Secret, req.Header.Get(SignatureHeader), receivedBody, time.Minute, time.Now()))
+
+			var envelope Envelope
+			require.NoError(t, json.Unmarshal(receivedBody, &envelope))
+			require.Equal(t, event.ID, envelope.ID)
+			require.Equal(t, event.EventType, envelope.Type)
+			require.JSONEq(t, string(event.Payload), string(envelope.Data))
+		})
+	}
+}
+
+func TestDeliverUnreachable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	endpoint := randomWebhookEndpoint(server.URL)
+	event := randomOutboxEvent()
+	delivery := db.WebhookDelivery{ID: 1, EndpointID: endpoint.ID, EventID: event.ID}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+	store.EXPECT().GetOutboxEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+	store.EXPECT().
+		RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+			require.Equal(t, StatusPending, arg.Status)
+			require.False(t, arg.LastStatusCode.Valid)
+			require.True(t, arg.LastError.Valid)
+			return delivery, nil
+		})
+
+	worker := NewWorker(store, time.Second, 3)
+	require.NoError(t, worker.Deliver(context.Background(), delivery))
+}
+
+func TestDeliverDeletedEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Any()).Times(1).Return(db.WebhookEndpoint{}, db.ErrRecordNotFound)
+	store.EXPECT().GetOutboxEvent(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).Times(0)
+
+	worker := NewWorker(store, time.Second, 3)
+	require.NoError(t, worker.Deliver(context.Background(), db.WebhookDelivery{ID: 1, EndpointID: 2}))
+}
+
+func TestFanOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	event := randomOutboxEvent()
+	endpoints := []db.WebhookEndpoint{
+		randomWebhookEndpoint("https://one.example.com"),
+		randomWebhookEndpoint("https://two.example.com"),
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListWebhookEndpointsForEvent(gomock.Any(), gomock.Eq(event.EventType)).Times(1).Return(endpoints, nil)
+	for _, endpoint := range endpoints {
+		arg := db.CreateWebhookDeliveryParams{EndpointID: endpoint.ID, EventID: event.ID}
+		store.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Eq(arg)).Times(1).Return(nil)
+	}
+
+	worker := NewWorker(store, time.Second, 3)
+	require.NoError(t, worker.FanOut(context.Background(), event))
+}
+
+func TestRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ClaimDueWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return([]db.WebhookDelivery{}, nil)
+
+	worker := NewWorker(store, time.Second, 3)
+	require.NoError(t, worker.Run(context.Background()))
+}
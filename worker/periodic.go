@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunPeriodically runs fn right away and then every interval until ctx is done.
+// Errors are logged and don't stop later runs.
+func RunPeriodically(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := fn(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("%s failed: %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
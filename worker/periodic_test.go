@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPeriodically(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var runs atomic.Int32
+	done := make(chan struct{})
+	go func() {
+		RunPeriodically(ctx, "test job", time.Millisecond, func(context.Context) error {
+			// Errors don't stop the loop
+			if runs.Add(1) == 3 {
+				cancel()
+			}
+			return errors.New("boom")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job didn't stop after the context was cancelled")
+	}
+	require.Equal(t, int32(3), runs.Load())
+}